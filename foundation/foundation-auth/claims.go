@@ -4,7 +4,8 @@ import "github.com/golang-jwt/jwt/v5"
 
 type Claims struct {
 	jwt.RegisteredClaims
-	UserId int `json:"user_id"`
+	UserId  int    `json:"user_id"`
+	Session string `json:"session,omitempty"` // 登录会话标识，考试模式下只有最近一次登录的会话有效
 }
 
 func (c *Claims) IsValid() bool {
@@ -9,12 +9,12 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GetToken(userId int, nowTime time.Time, secret []byte) (*string, error) {
+func GetToken(userId int, session string, nowTime time.Time, secret []byte) (*string, error) {
 	duration := time.Hour * 24 * 7
-	return GetTokenExpiration(userId, nowTime, duration, secret)
+	return GetTokenExpiration(userId, session, nowTime, duration, secret)
 }
 
-func GetTokenExpiration(userId int, nowTime time.Time, duration time.Duration, secret []byte) (
+func GetTokenExpiration(userId int, session string, nowTime time.Time, duration time.Duration, secret []byte) (
 	*string,
 	error,
 ) {
@@ -23,7 +23,8 @@ func GetTokenExpiration(userId int, nowTime time.Time, duration time.Duration, s
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
-		UserId: userId,
+		UserId:  userId,
+		Session: session,
 	}
 	return auth.GetToken(claims, secret)
 }
@@ -39,3 +40,16 @@ func GetUserIdFromContext(c *gin.Context) (int, error) {
 	}
 	return claims.UserId, nil
 }
+
+// GetSessionFromContext 获取登录会话标识，旧版本签发的令牌没有会话标识时返回空
+func GetSessionFromContext(c *gin.Context) string {
+	claimsPtr := c.Value("claims")
+	if claimsPtr == nil {
+		return ""
+	}
+	claims, ok := claimsPtr.(Claims)
+	if !ok {
+		return ""
+	}
+	return claims.Session
+}
@@ -0,0 +1,93 @@
+package foundationcontest
+
+import (
+	"math"
+	"sort"
+)
+
+// RatingInitial 未参加过计分比赛的用户的初始Rating
+const RatingInitial = 1500
+
+// RatingParticipant 参与Rating计算的一行榜单，队伍使用成员的平均Rating
+type RatingParticipant struct {
+	Rating int
+	Rank   float64 // 名次，并列时取并列区间的平均名次
+}
+
+// getEloWinProbability a战胜b的概率
+func getEloWinProbability(a float64, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// getRatingSeed 以rating参赛时的期望名次，exclude为参赛者自身
+func getRatingSeed(participants []*RatingParticipant, rating float64, exclude int) float64 {
+	seed := 1.0
+	for i, participant := range participants {
+		if i == exclude {
+			continue
+		}
+		seed += getEloWinProbability(float64(participant.Rating), rating)
+	}
+	return seed
+}
+
+// getRatingToRank 二分查找期望名次恰好为rank时需要的Rating
+func getRatingToRank(participants []*RatingParticipant, rank float64, exclude int) int {
+	left, right := 1, 8000
+	for right-left > 1 {
+		mid := (left + right) / 2
+		if getRatingSeed(participants, float64(mid), exclude) < rank {
+			right = mid
+		} else {
+			left = mid
+		}
+	}
+	return left
+}
+
+// CalculateRatingChanges 按Codeforces的算法计算每个参赛者的Rating变化，返回值与传入顺序一致
+// 先根据期望名次与实际名次的几何平均求出目标Rating，变化量取差值的一半，
+// 再整体修正使总变化略小于0，并限制高分段的总变化，避免Rating膨胀
+func CalculateRatingChanges(participants []*RatingParticipant) []int {
+	n := len(participants)
+	deltas := make([]int, n)
+	if n <= 1 {
+		return deltas
+	}
+	for i, participant := range participants {
+		seed := getRatingSeed(participants, float64(participant.Rating), i)
+		midRank := math.Sqrt(seed * participant.Rank)
+		needRating := getRatingToRank(participants, midRank, i)
+		deltas[i] = (needRating - participant.Rating) / 2
+	}
+
+	sum := 0
+	for _, delta := range deltas {
+		sum += delta
+	}
+	inc := -sum/n - 1
+	for i := range deltas {
+		deltas[i] += inc
+	}
+
+	// 排名靠前的参赛者的总变化不应为正
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(
+		order, func(i, j int) bool {
+			return participants[order[i]].Rating > participants[order[j]].Rating
+		},
+	)
+	topCount := min(n, 4*int(math.Round(math.Sqrt(float64(n)))))
+	topSum := 0
+	for _, i := range order[:topCount] {
+		topSum += deltas[i]
+	}
+	inc = min(max(-topSum/topCount, -10), 0)
+	for i := range deltas {
+		deltas[i] += inc
+	}
+	return deltas
+}
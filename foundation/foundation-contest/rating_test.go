@@ -0,0 +1,142 @@
+package foundationcontest
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func newRatingParticipants(ratings []int, ranks []float64) []*RatingParticipant {
+	participants := make([]*RatingParticipant, len(ratings))
+	for i := range ratings {
+		participants[i] = &RatingParticipant{Rating: ratings[i], Rank: ranks[i]}
+	}
+	return participants
+}
+
+// newRatingField 前topCount名Rating为topRating，其余为RatingInitial，名次与传入顺序一致
+func newRatingField(n int, topCount int, topRating int) []*RatingParticipant {
+	participants := make([]*RatingParticipant, n)
+	for i := range participants {
+		rating := RatingInitial
+		if i < topCount {
+			rating = topRating
+		}
+		participants[i] = &RatingParticipant{Rating: rating, Rank: float64(i + 1)}
+	}
+	return participants
+}
+
+// newRatingLadder Rating从高到低间隔50，名次与Rating顺序一致
+func newRatingLadder(n int) []*RatingParticipant {
+	participants := make([]*RatingParticipant, n)
+	for i := range participants {
+		participants[i] = &RatingParticipant{Rating: 3000 - 50*i, Rank: float64(i + 1)}
+	}
+	return participants
+}
+
+// getRatingTopSum Rating最高的4*sqrt(n)人的变化总和
+func getRatingTopSum(participants []*RatingParticipant, deltas []int) (int, int) {
+	order := make([]int, len(participants))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(
+		order, func(i, j int) bool {
+			return participants[order[i]].Rating > participants[order[j]].Rating
+		},
+	)
+	topCount := min(len(participants), 4*int(math.Round(math.Sqrt(float64(len(participants))))))
+	topSum := 0
+	for _, i := range order[:topCount] {
+		topSum += deltas[i]
+	}
+	return topSum, topCount
+}
+
+// TestCalculateRatingChanges 测试固定场景下的Rating变化
+func TestCalculateRatingChanges(t *testing.T) {
+	cases := []struct {
+		ratings  []int
+		ranks    []float64
+		expected []int
+		name     string
+	}{
+		{nil, nil, []int{}, "没有参赛者"},
+		{[]int{1500}, []float64{1}, []int{0}, "只有一人时不变化"},
+		{[]int{1500, 1500}, []float64{1, 2}, []int{96, -98}, "两人同分，胜者上涨败者下降"},
+		{[]int{1500, 1500}, []float64{1.5, 1.5}, []int{-1, -1}, "两人并列，变化相同且略小于0"},
+		{[]int{2000, 1500}, []float64{2, 1}, []int{-260, 259}, "高分输给低分，变化幅度大"},
+		{[]int{1500, 1500, 1500}, []float64{1.5, 1.5, 3}, []int{43, 43, -88}, "并列第一的两人变化相同"},
+		{[]int{1500, 1500, 1500, 1500}, []float64{1, 2, 3, 4}, []int{112, 19, -39, -93}, "同分时按名次递减"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := CalculateRatingChanges(newRatingParticipants(tc.ratings, tc.ranks))
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("CalculateRatingChanges(%v, %v) = %v; want %v", tc.ratings, tc.ranks, result, tc.expected)
+			}
+		})
+	}
+}
+
+// TestCalculateRatingChangesSum 总变化不为正，且第二次修正每人最多减10
+func TestCalculateRatingChangesSum(t *testing.T) {
+	cases := []struct {
+		participants []*RatingParticipant
+		name         string
+	}{
+		{newRatingField(2, 0, 0), "两人"},
+		{newRatingField(9, 3, 1800), "少量高分选手"},
+		{newRatingField(30, 10, 2400), "高分选手名次靠前"},
+		{newRatingLadder(30), "名次与Rating一致"},
+		{newRatingField(100, 40, 1600), "高分选手全部名次靠前"},
+		{newRatingField(100, 0, 0), "全部为初始Rating"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := len(tc.participants)
+			sum := 0
+			for _, delta := range CalculateRatingChanges(tc.participants) {
+				sum += delta
+			}
+			if sum > 0 || sum <= -12*n {
+				t.Errorf("sum of deltas = %d; want in (%d, 0]", sum, -12*n)
+			}
+		})
+	}
+}
+
+// TestCalculateRatingChangesTop 测试高分段的总变化修正
+func TestCalculateRatingChangesTop(t *testing.T) {
+	cases := []struct {
+		participants []*RatingParticipant
+		capped       bool // 修正量是否达到每人10的上限
+		name         string
+	}{
+		{newRatingLadder(30), false, "名次与Rating一致时高分段总变化不为正"},
+		{newRatingField(100, 40, 2400), false, "高分选手名次靠前但符合预期"},
+		{newRatingField(100, 40, 1600), true, "高分选手超出预期时修正量限制为10"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deltas := CalculateRatingChanges(tc.participants)
+			topSum, topCount := getRatingTopSum(tc.participants, deltas)
+			if tc.capped {
+				// 修正受限时高分段仍然整体上涨
+				if topSum <= 0 {
+					t.Errorf("top %d sum = %d; want > 0", topCount, topSum)
+				}
+				return
+			}
+			if topSum/topCount > 0 {
+				t.Errorf("top %d sum = %d; want average <= 0", topCount, topSum)
+			}
+		})
+	}
+}
@@ -173,6 +173,19 @@ func (d *CollectionDao) GetCollectionMemberIds(ctx context.Context, id int) ([]i
 	return memberIds, nil
 }
 
+// GetCollectionLanguages 题集允许使用的语言标识，为空表示不限制
+func (d *CollectionDao) GetCollectionLanguages(ctx context.Context, id int) ([]string, error) {
+	var languages []string
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.CollectionLanguage{}).
+		Where("id = ?", id).
+		Pluck("language", &languages).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get collection languages, id:%d", id)
+	}
+	return languages, nil
+}
+
 func (d *CollectionDao) GetCollectionRank(
 	ctx context.Context,
 	collectionId int,
@@ -192,6 +205,9 @@ func (d *CollectionDao) GetCollectionRank(
 		Joins("JOIN collection_member AS cm ON cm.user_id = j.inserter AND cm.id = ?", collectionId).
 		Joins(`JOIN "user" AS u ON u.id = j.inserter`).
 		Where("j.problem_id IN ?", collection.Problems)
+	if len(collection.Languages) > 0 {
+		db = db.Where("j.language IN ?", collection.Languages)
+	}
 	if collection.StartTime != nil {
 		db = db.Where("j.insert_time >= ?", *collection.StartTime)
 	}
@@ -248,6 +264,7 @@ func (d *CollectionDao) UpdateCollection(
 	ctx context.Context,
 	collection *foundationmodel.Collection,
 	problemIds []int,
+	languages []string,
 	members []int,
 ) error {
 	if collection == nil {
@@ -283,6 +300,14 @@ func (d *CollectionDao) UpdateCollection(
 					return metaerror.Wrap(err, "insert collection problems")
 				}
 			}
+			if err := tx.Model(&foundationmodel.CollectionLanguage{}).
+				Where("id = ?", collection.Id).
+				Delete(&foundationmodel.CollectionLanguage{}).Error; err != nil {
+				return metaerror.Wrap(err, "delete old collection languages")
+			}
+			if err := insertCollectionLanguagesWithTx(tx, collection.Id, languages); err != nil {
+				return err
+			}
 			if err := tx.Model(&foundationmodel.CollectionMember{}).
 				Where("id = ?", collection.Id).
 				Delete(&foundationmodel.CollectionMember{}).Error; err != nil {
@@ -315,6 +340,7 @@ func (d *CollectionDao) InsertCollection(
 	ctx context.Context,
 	collection *foundationmodel.Collection,
 	problemIds []int,
+	languages []string,
 	members []int,
 ) error {
 	if collection == nil {
@@ -346,6 +372,9 @@ func (d *CollectionDao) InsertCollection(
 					return metaerror.Wrap(err, "insert collection problems")
 				}
 			}
+			if err := insertCollectionLanguagesWithTx(tx, collection.Id, languages); err != nil {
+				return err
+			}
 			if len(members) > 0 {
 				var collectionMembers []*foundationmodel.CollectionMember
 				for _, memberId := range members {
@@ -369,6 +398,25 @@ func (d *CollectionDao) InsertCollection(
 	return nil
 }
 
+func insertCollectionLanguagesWithTx(tx *gorm.DB, id int, languages []string) error {
+	if len(languages) == 0 {
+		return nil
+	}
+	var collectionLanguages []*foundationmodel.CollectionLanguage
+	for _, language := range languages {
+		collectionLanguages = append(
+			collectionLanguages, foundationmodel.NewCollectionLanguageBuilder().
+				Id(id).
+				Language(language).
+				Build(),
+		)
+	}
+	if err := tx.Model(&foundationmodel.CollectionLanguage{}).Create(collectionLanguages).Error; err != nil {
+		return metaerror.Wrap(err, "insert collection languages")
+	}
+	return nil
+}
+
 func (d *CollectionDao) PostJoin(ctx *gin.Context, collectionId int, userId int) error {
 	collectionMember := &foundationmodel.CollectionMember{
 		Id:     collectionId,
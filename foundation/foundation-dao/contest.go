@@ -114,6 +114,18 @@ func (d *ContestDao) HasContestViewAuth(ctx context.Context, id int, userId int)
 				Or("? IN (SELECT user_id FROM contest_member WHERE id = contest.id)", userId).
 				Or("? IN (SELECT user_id FROM contest_member_auth WHERE id = contest.id)", userId),
 		).
+		// 个人比赛窗口需要自行开始后才能查看，比赛结束后不再限制
+		Where(
+			d.db.Table("contest").
+				Where("personal_duration IS NULL").
+				Or("end_time <= ?", now).
+				Or("inserter = ?", userId).
+				Or("? IN (SELECT user_id FROM contest_member_auth WHERE id = contest.id)", userId).
+				Or(
+					"EXISTS (SELECT 1 FROM contest_member WHERE id = contest.id AND user_id = ? AND start_time IS NOT NULL)",
+					userId,
+				),
+		).
 		Limit(1).
 		Scan(&exists).Error
 
@@ -145,6 +157,23 @@ func (d *ContestDao) HasContestSubmitAuth(ctx context.Context, id int, userId in
 				Where("submit_anytime = ?", true).
 				Or("end_time >= ?", now),
 		).
+		// 个人比赛窗口只能在开始后的个人时长内提交，比赛结束后按submit_anytime处理
+		Where(
+			d.db.
+				Table("contest").
+				Where("personal_duration IS NULL").
+				Or("end_time < ?", now).
+				Or("inserter = ?", userId).
+				Or("? IN (SELECT user_id FROM contest_member_auth WHERE id = contest.id)", userId).
+				Or(
+					`EXISTS (
+						SELECT 1 FROM contest_member m
+						WHERE m.id = contest.id AND m.user_id = ? AND m.start_time <= ?
+						  AND m.start_time + make_interval(secs => contest.personal_duration / 1000000000.0) > ?
+					)`,
+					userId, now, now,
+				),
+		).
 		Limit(1).
 		Scan(&exists).Error
 	if err != nil {
@@ -211,7 +240,7 @@ func (d *ContestDao) GetContestViewLock(ctx context.Context, id int) (*foundatio
 	var contest foundationview.ContestViewLock
 	err := d.db.WithContext(ctx).
 		Table("contest").
-		Select("id, inserter, start_time, end_time, type, always_lock, lock_rank_duration").
+		Select("id, inserter, start_time, end_time, type, always_lock, lock_rank_duration, personal_duration").
 		Where("id = ?", id).
 		Take(&contest).Error
 	if err != nil {
@@ -231,7 +260,8 @@ func (d *ContestDao) GetContest(ctx context.Context, id int) (*foundationview.Co
 			`
 			c.id, c.title, c.description, c.notification, c.start_time, c.end_time,
 			c.inserter, c.modifier, c.insert_time, c.modify_time, c.password, c.private,
-			c.submit_anytime,
+			c.submit_anytime, c.personal_duration, c.print_quota, c.print_page_limit, c.rated, c.exam,
+			c.hack, c.hack_score, c.hack_penalty, c.pretest, c.system_test_time,
 			u1.username AS inserter_username, u1.nickname AS inserter_nickname,
 			u2.username AS modifier_username, u2.nickname AS modifier_nickname
 		`,
@@ -257,7 +287,9 @@ func (d *ContestDao) GetContestEdit(ctx context.Context, id int) (*foundationvie
 			`
 			c.id, c.title, c.description, c.notification, c.start_time, c.end_time,
 			c.inserter, c.modifier, c.insert_time, c.modify_time, c.password, c.private,
-			c.submit_anytime,
+			c.submit_anytime, c.penalty_minutes, c.personal_duration,
+			c.print_quota, c.print_page_limit, c.rated, c.exam, c.ip_whitelist,
+			c.hack, c.hack_score, c.hack_penalty, c.pretest, c.system_test_time,
 			c.always_lock, c.lock_rank_duration, c.type, c.score_type, c.discuss_type,
 			u1.username AS inserter_username, u1.nickname AS inserter_nickname,
 			u2.username AS modifier_username, u2.nickname AS modifier_nickname
@@ -302,7 +334,10 @@ func (d *ContestDao) GetContestViewRank(ctx context.Context, id int) (*foundatio
 	var contest foundationview.ContestRankDetail
 	if err := d.db.WithContext(ctx).
 		Model(&foundationmodel.Contest{}).
-		Select("id, title, start_time, end_time, lock_rank_duration, always_lock").
+		Select(
+			`id, title, start_time, end_time, lock_rank_duration, always_lock, type, score_type, penalty_minutes,
+			personal_duration, hack, hack_score, hack_penalty, pretest, system_test_time`,
+		).
 		Where("id = ?", id).
 		First(&contest).Error; err != nil {
 		return nil, err
@@ -347,6 +382,45 @@ func (d *ContestDao) GetContestInserter(ctx context.Context, id int) (int, error
 	return inserter, nil
 }
 
+func (d *ContestDao) GetContestVolunteerIds(ctx context.Context, id int) ([]int, error) {
+	var userIds []int
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestMemberVolunteer{}).
+		Where("id = ?", id).
+		Pluck("user_id", &userIds).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest volunteers, id:%d", id)
+	}
+	return userIds, nil
+}
+
+// GetContestLanguages 比赛允许使用的语言标识，为空表示不限制
+func (d *ContestDao) GetContestLanguages(ctx context.Context, id int) ([]string, error) {
+	var languages []string
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestLanguage{}).
+		Where("id = ?", id).
+		Pluck("language", &languages).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest languages, id:%d", id)
+	}
+	return languages, nil
+}
+
+func (d *ContestDao) IsContestVolunteer(ctx context.Context, id int, userId int) (bool, error) {
+	var exists int
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestMemberVolunteer{}).
+		Select("1").
+		Where("id = ? AND user_id = ?", id, userId).
+		Limit(1).
+		Scan(&exists).Error
+	if err != nil {
+		return false, metaerror.Wrap(err, "failed to check contest volunteer, id:%d", id)
+	}
+	return exists == 1, nil
+}
+
 func (d *ContestDao) GetProblemAttemptInfo(
 	ctx context.Context,
 	contestId int,
@@ -491,6 +565,17 @@ func (d *ContestDao) UpdateContest(
 					"lock_rank_duration":   contest.LockRankDuration,
 					"always_lock":          contest.AlwaysLock,
 					"submit_anytime":       contest.SubmitAnytime,
+					"penalty_minutes":      contest.PenaltyMinutes,
+					"personal_duration":    contest.PersonalDuration,
+					"print_quota":          contest.PrintQuota,
+					"print_page_limit":     contest.PrintPageLimit,
+					"rated":                contest.Rated,
+					"exam":                 contest.Exam,
+					"ip_whitelist":         contest.IpWhitelist,
+					"hack":                 contest.Hack,
+					"hack_score":           contest.HackScore,
+					"hack_penalty":         contest.HackPenalty,
+					"pretest":              contest.Pretest,
 					"modifier":             contest.Modifier,
 					"modify_time":          contest.ModifyTime,
 				})
@@ -507,6 +592,10 @@ func (d *ContestDao) UpdateContest(
 			if txResult.RowsAffected == 0 {
 				return metaerror.New("no contest updated: record may not exist")
 			}
+			// 比赛时间、题目、成员等变化后需要重建榜单
+			if err := insertContestRankRebuildWithTx(tx, contest.Id); err != nil {
+				return err
+			}
 
 			// contestProblems
 			if len(contestProblems) > 0 {
@@ -525,8 +614,13 @@ func (d *ContestDao) UpdateContest(
 				}
 				if err := tx.Model(&foundationmodel.ContestProblem{}).Clauses(
 					clause.OnConflict{
-						Columns:   []clause.Column{{Name: "id"}, {Name: "problem_id"}},
-						DoUpdates: clause.AssignmentColumns([]string{"index"}),
+						Columns: []clause.Column{{Name: "id"}, {Name: "problem_id"}},
+						DoUpdates: clause.AssignmentColumns(
+							[]string{
+								"index", "score", "score_decay", "score_penalty", "score_min",
+								"time_limit", "memory_limit",
+							},
+						),
 					},
 				).Create(&contestProblems).Error; err != nil {
 					return metaerror.Wrap(err, "upsert contest problems")
@@ -627,7 +721,7 @@ func (d *ContestDao) UpdateContest(
 				if err := tx.Clauses(
 					clause.OnConflict{
 						Columns:   []clause.Column{{Name: "id"}, {Name: "user_id"}},
-						DoUpdates: clause.AssignmentColumns([]string{"contest_name"}),
+						DoUpdates: clause.AssignmentColumns([]string{"contest_name", "location"}),
 					},
 				).Create(&members).Error; err != nil {
 					return metaerror.Wrap(err, "upsert members")
@@ -876,3 +970,146 @@ func (d *ContestDao) InsertContest(
 	}
 	return nil
 }
+
+// GetContestPrintSetting 获取比赛的打印配置，比赛不存在时返回nil
+func (d *ContestDao) GetContestPrintSetting(ctx context.Context, id int) (*foundationview.ContestPrintSetting, error) {
+	var setting foundationview.ContestPrintSetting
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.Contest{}).
+		Select("id, title, start_time, end_time, print_quota, print_page_limit").
+		Where("id = ?", id).
+		Take(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest print setting, id:%d", id)
+	}
+	return &setting, nil
+}
+
+// GetContestHackSetting 获取比赛的挑战配置，比赛不存在时返回nil
+func (d *ContestDao) GetContestHackSetting(ctx context.Context, id int) (*foundationview.ContestHackSetting, error) {
+	var setting foundationview.ContestHackSetting
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.Contest{}).
+		Select("id, start_time, end_time, hack, hack_score, hack_penalty").
+		Where("id = ?", id).
+		Take(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest hack setting, id:%d", id)
+	}
+	return &setting, nil
+}
+
+// GetContestSystemTestSetting 获取比赛的预测试配置，比赛不存在时返回nil
+func (d *ContestDao) GetContestSystemTestSetting(
+	ctx context.Context,
+	id int,
+) (*foundationview.ContestSystemTestSetting, error) {
+	var setting foundationview.ContestSystemTestSetting
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.Contest{}).
+		Select("id, start_time, end_time, pretest, system_test_time").
+		Where("id = ?", id).
+		Take(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest system test setting, id:%d", id)
+	}
+	return &setting, nil
+}
+
+// StartContestSystemTest 标记系统测试开始，并在同一事务中将需要系统测试的提交标记为重新评测
+// 已经开始过时返回false
+func (d *ContestDao) StartContestSystemTest(
+	ctx context.Context,
+	id int,
+	startTime time.Time,
+	endTime time.Time,
+	nowTime time.Time,
+) (int, bool, error) {
+	count := 0
+	started := false
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			txResult := tx.Model(&foundationmodel.Contest{}).
+				Where("id = ? AND pretest AND system_test_time IS NULL", id).
+				Update("system_test_time", nowTime)
+			if txResult.Error != nil {
+				return txResult.Error
+			}
+			if txResult.RowsAffected == 0 {
+				return nil
+			}
+			jobIds, err := getContestSystemTestJobIdsWithTx(tx, id, startTime, endTime)
+			if err != nil {
+				return err
+			}
+			for _, jobId := range jobIds {
+				if err := rejudgeJobWithTx(tx, jobId); err != nil {
+					return err
+				}
+			}
+			count = len(jobIds)
+			started = true
+			// 系统测试开始后未被重新评测的预测试通过记录不再计入榜单
+			return insertContestRankRebuildWithTx(tx, id)
+		},
+	)
+	if err != nil {
+		return 0, false, metaerror.Wrap(err, "failed to start contest system test, id:%d", id)
+	}
+	return count, started, nil
+}
+
+// GetContestExamSetting 获取比赛的考试限制，比赛不存在时返回nil
+func (d *ContestDao) GetContestExamSetting(ctx context.Context, id int) (*foundationview.ContestExamSetting, error) {
+	var setting foundationview.ContestExamSetting
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.Contest{}).
+		Select("id, start_time, end_time, exam, ip_whitelist").
+		Where("id = ?", id).
+		Take(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest exam setting, id:%d", id)
+	}
+	return &setting, nil
+}
+
+// GetContestLoginReports 获取比赛期间从多个IP登录的成员
+func (d *ContestDao) GetContestLoginReports(
+	ctx context.Context,
+	id int,
+	startTime time.Time,
+	endTime time.Time,
+) ([]*foundationview.ContestLoginReport, error) {
+	var reports []*foundationview.ContestLoginReport
+	err := d.db.WithContext(ctx).
+		Table("contest_member AS m").
+		Select(
+			`m.user_id, u.username, u.nickname, m.contest_name,
+			COUNT(*) AS login_count, COUNT(DISTINCT l.ip) AS ip_count,
+			STRING_AGG(DISTINCT HOST(l.ip), ',') AS ips,
+			MIN(l.insert_time) AS first_time, MAX(l.insert_time) AS last_time`,
+		).
+		Joins(`JOIN user_login AS l ON l.user_id = m.user_id AND l.insert_time >= ? AND l.insert_time < ?`, startTime, endTime).
+		Joins(`LEFT JOIN "user" AS u ON u.id = m.user_id`).
+		Where("m.id = ?", id).
+		Group("m.user_id, u.username, u.nickname, m.contest_name").
+		Having("COUNT(DISTINCT l.ip) > 1").
+		Order("ip_count DESC, m.user_id").
+		Scan(&reports).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest login reports, id:%d", id)
+	}
+	return reports, nil
+}
@@ -0,0 +1,169 @@
+package foundationdao
+
+import (
+	"context"
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContestBalloonDao struct {
+	db *gorm.DB
+}
+
+var singletonContestBalloonDao = singleton.Singleton[ContestBalloonDao]{}
+
+func GetContestBalloonDao() *ContestBalloonDao {
+	return singletonContestBalloonDao.GetInstance(
+		func() *ContestBalloonDao {
+			dao := &ContestBalloonDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// insertContestBalloonByJobWithTx 评测通过时为用户（队伍）生成该题的气球任务
+// 比赛时间外的提交不生成，同一用户（队伍）同一题目已存在且未被领取时保留更早的提交
+func insertContestBalloonByJobWithTx(tx *gorm.DB, id int) error {
+	err := tx.Exec(
+		`
+INSERT INTO contest_balloon (contest_id, problem_id, owner_id, user_id, team_id, judge_job_id, ac_time, status)
+SELECT j.contest_id, j.problem_id, COALESCE(-tm.team_id, j.inserter), j.inserter, tm.team_id, j.id, j.insert_time, ?
+FROM judge_job AS j
+JOIN contest AS c ON c.id = j.contest_id
+LEFT JOIN contest_team_member AS tm ON tm.contest_id = j.contest_id AND tm.user_id = j.inserter
+WHERE j.id = ? AND j.status = ? AND j.insert_time >= c.start_time AND j.insert_time < c.end_time
+ON CONFLICT (contest_id, problem_id, owner_id) DO UPDATE
+SET user_id = EXCLUDED.user_id, team_id = EXCLUDED.team_id, judge_job_id = EXCLUDED.judge_job_id, ac_time = EXCLUDED.ac_time
+WHERE contest_balloon.status = ? AND EXCLUDED.judge_job_id < contest_balloon.judge_job_id
+`,
+		foundationenum.ContestBalloonStatusPending,
+		id,
+		foundationjudge.JudgeStatusAC,
+		foundationenum.ContestBalloonStatusPending,
+	).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to insert contest balloon, judgeJobId:%d", id)
+	}
+	return nil
+}
+
+// GetContestBalloons 获取气球任务，status为空时返回全部，location按前缀筛选房间或位置
+func (d *ContestBalloonDao) GetContestBalloons(
+	ctx context.Context,
+	contestId int,
+	status *foundationenum.ContestBalloonStatus,
+	location string,
+) ([]*foundationview.ContestBalloon, error) {
+	// 首个通过在筛选之前计算，以免筛选后误判
+	db := d.db.WithContext(ctx).
+		Table(
+			`(
+			SELECT b.*, b.ac_time = MIN(b.ac_time) OVER (PARTITION BY b.problem_id) AS first_solve
+			FROM contest_balloon AS b
+			WHERE b.contest_id = ?
+		) AS b`, contestId,
+		).
+		Select(
+			`
+			b.id, cp.index AS problem_index, b.user_id, u.username, u.nickname,
+			m.contest_name, m.location, b.team_id, t.name AS team_name,
+			b.ac_time, b.first_solve, b.status, b.volunteer, v.username AS volunteer_username,
+			b.claim_time, b.deliver_time
+		`,
+		).
+		Joins("LEFT JOIN contest_problem AS cp ON cp.id = b.contest_id AND cp.problem_id = b.problem_id").
+		Joins(`LEFT JOIN "user" AS u ON u.id = b.user_id`).
+		Joins(`LEFT JOIN "user" AS v ON v.id = b.volunteer`).
+		Joins("LEFT JOIN contest_member AS m ON m.id = b.contest_id AND m.user_id = b.user_id").
+		Joins("LEFT JOIN contest_team AS t ON t.id = b.team_id")
+	if status != nil {
+		db = db.Where("b.status = ?", *status)
+	}
+	if location != "" {
+		db = db.Where("m.location LIKE ?", location+"%")
+	}
+	var balloons []*foundationview.ContestBalloon
+	if err := db.Order("b.ac_time, b.id").Scan(&balloons).Error; err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest balloons, id:%d", contestId)
+	}
+	return balloons, nil
+}
+
+// updateContestBalloonStatus 仅在当前状态匹配时修改，返回是否修改成功
+func (d *ContestBalloonDao) updateContestBalloonStatus(
+	ctx context.Context,
+	contestId int,
+	id int,
+	where *gorm.DB,
+	values map[string]interface{},
+) (bool, error) {
+	result := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestBalloon{}).
+		Where("id = ? AND contest_id = ?", id, contestId).
+		Where(where).
+		Updates(values)
+	if result.Error != nil {
+		return false, metaerror.Wrap(result.Error, "failed to update contest balloon, id:%d", id)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimContestBalloon 领取等待中的气球
+func (d *ContestBalloonDao) ClaimContestBalloon(
+	ctx context.Context,
+	contestId int,
+	id int,
+	volunteer int,
+	nowTime time.Time,
+) (bool, error) {
+	return d.updateContestBalloonStatus(
+		ctx, contestId, id,
+		d.db.Where("status = ?", foundationenum.ContestBalloonStatusPending),
+		map[string]interface{}{
+			"status":     foundationenum.ContestBalloonStatusClaimed,
+			"volunteer":  volunteer,
+			"claim_time": nowTime,
+		},
+	)
+}
+
+// ReleaseContestBalloon 放弃自己领取的气球，重新回到等待状态
+func (d *ContestBalloonDao) ReleaseContestBalloon(ctx context.Context, contestId int, id int, volunteer int) (bool, error) {
+	return d.updateContestBalloonStatus(
+		ctx, contestId, id,
+		d.db.Where("status = ? AND volunteer = ?", foundationenum.ContestBalloonStatusClaimed, volunteer),
+		map[string]interface{}{
+			"status":     foundationenum.ContestBalloonStatusPending,
+			"volunteer":  nil,
+			"claim_time": nil,
+		},
+	)
+}
+
+// DeliverContestBalloon 将自己领取的气球标记为已送达
+func (d *ContestBalloonDao) DeliverContestBalloon(
+	ctx context.Context,
+	contestId int,
+	id int,
+	volunteer int,
+	nowTime time.Time,
+) (bool, error) {
+	return d.updateContestBalloonStatus(
+		ctx, contestId, id,
+		d.db.Where("status = ? AND volunteer = ?", foundationenum.ContestBalloonStatusClaimed, volunteer),
+		map[string]interface{}{
+			"status":       foundationenum.ContestBalloonStatusDelivered,
+			"deliver_time": nowTime,
+		},
+	)
+}
@@ -0,0 +1,235 @@
+package foundationdao
+
+import (
+	"context"
+	"errors"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContestClarificationDao struct {
+	db *gorm.DB
+}
+
+var singletonContestClarificationDao = singleton.Singleton[ContestClarificationDao]{}
+
+func GetContestClarificationDao() *ContestClarificationDao {
+	return singletonContestClarificationDao.GetInstance(
+		func() *ContestClarificationDao {
+			dao := &ContestClarificationDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+func (d *ContestClarificationDao) InsertContestClarification(
+	ctx context.Context,
+	clarification *foundationmodel.ContestClarification,
+) error {
+	if err := d.db.WithContext(ctx).Create(clarification).Error; err != nil {
+		return metaerror.Wrap(err, "failed to insert contest clarification, contest:%d", clarification.ContestId)
+	}
+	return nil
+}
+
+func (d *ContestClarificationDao) GetContestClarification(
+	ctx context.Context,
+	contestId int,
+	id int,
+) (*foundationmodel.ContestClarification, error) {
+	var clarification foundationmodel.ContestClarification
+	err := d.db.WithContext(ctx).
+		Where("id = ? AND contest_id = ?", id, contestId).
+		Take(&clarification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest clarification, id:%d", id)
+	}
+	return &clarification, nil
+}
+
+// AnswerContestClarification 回复问题，已有回复时覆盖
+func (d *ContestClarificationDao) AnswerContestClarification(
+	ctx context.Context,
+	contestId int,
+	id int,
+	answer string,
+	public bool,
+	answerer int,
+	answerTime time.Time,
+) error {
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestClarification{}).
+		Where("id = ? AND contest_id = ?", id, contestId).
+		Updates(
+			map[string]interface{}{
+				"answer":      answer,
+				"public":      public,
+				"answerer":    answerer,
+				"answer_time": answerTime,
+			},
+		).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to answer contest clarification, id:%d", id)
+	}
+	return nil
+}
+
+func (d *ContestClarificationDao) DeleteContestClarification(ctx context.Context, contestId int, id int) error {
+	err := d.db.WithContext(ctx).
+		Where("id = ? AND contest_id = ?", id, contestId).
+		Delete(&foundationmodel.ContestClarification{}).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to delete contest clarification, id:%d", id)
+	}
+	return nil
+}
+
+// GetContestClarifications 获取答疑列表，all为false时只返回公开的和该用户自己提出的
+func (d *ContestClarificationDao) GetContestClarifications(
+	ctx context.Context,
+	contestId int,
+	userId int,
+	all bool,
+) ([]*foundationview.ContestClarification, error) {
+	db := d.db.WithContext(ctx).
+		Table("contest_clarification AS c").
+		Select(
+			`
+			c.id, cp.index AS problem_index, c.question, c.answer, c.public,
+			c.inserter, u.username AS inserter_username, u.nickname AS inserter_nickname,
+			c.insert_time, c.answerer, c.answer_time
+		`,
+		).
+		Joins(`LEFT JOIN "user" AS u ON u.id = c.inserter`).
+		Joins("LEFT JOIN contest_problem AS cp ON cp.id = c.contest_id AND cp.problem_id = c.problem_id").
+		Where("c.contest_id = ?", contestId)
+	if !all {
+		db = db.Where("c.public = TRUE OR c.inserter = ?", userId)
+	}
+	var clarifications []*foundationview.ContestClarification
+	if err := db.Order("c.id DESC").Scan(&clarifications).Error; err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest clarifications, contest:%d", contestId)
+	}
+	return clarifications, nil
+}
+
+// GetUnreadCount 选手的未读数量为上次查看后新增的公开回复和自己问题的回复
+func (d *ContestClarificationDao) GetUnreadCount(ctx context.Context, contestId int, userId int) (int, error) {
+	var count int64
+	err := d.db.WithContext(ctx).
+		Table("contest_clarification AS c").
+		Joins("LEFT JOIN contest_clarification_read AS r ON r.contest_id = c.contest_id AND r.user_id = ?", userId).
+		Where("c.contest_id = ? AND c.answer_time IS NOT NULL", contestId).
+		Where("c.public = TRUE OR c.inserter = ?", userId).
+		Where("r.read_time IS NULL OR c.answer_time > r.read_time").
+		Count(&count).Error
+	if err != nil {
+		return 0, metaerror.Wrap(err, "failed to get contest clarification unread count, contest:%d", contestId)
+	}
+	return int(count), nil
+}
+
+// GetUnansweredCount 裁判待回复的问题数量
+func (d *ContestClarificationDao) GetUnansweredCount(ctx context.Context, contestId int) (int, error) {
+	var count int64
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestClarification{}).
+		Where("contest_id = ? AND question IS NOT NULL AND answer_time IS NULL", contestId).
+		Count(&count).Error
+	if err != nil {
+		return 0, metaerror.Wrap(err, "failed to get contest clarification unanswered count, contest:%d", contestId)
+	}
+	return int(count), nil
+}
+
+func (d *ContestClarificationDao) UpdateReadTime(
+	ctx context.Context,
+	contestId int,
+	userId int,
+	readTime time.Time,
+) error {
+	err := d.db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "contest_id"}, {Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"read_time"}),
+			},
+		).
+		Create(
+			&foundationmodel.ContestClarificationRead{
+				ContestId: contestId,
+				UserId:    userId,
+				ReadTime:  readTime,
+			},
+		).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to update contest clarification read time, contest:%d", contestId)
+	}
+	return nil
+}
+
+func (d *ContestClarificationDao) GetTemplates(
+	ctx context.Context,
+	inserter int,
+) ([]*foundationmodel.ContestClarificationTemplate, error) {
+	var templates []*foundationmodel.ContestClarificationTemplate
+	err := d.db.WithContext(ctx).
+		Where("inserter = ?", inserter).
+		Order("id").
+		Find(&templates).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest clarification templates, user:%d", inserter)
+	}
+	return templates, nil
+}
+
+func (d *ContestClarificationDao) InsertTemplate(
+	ctx context.Context,
+	template *foundationmodel.ContestClarificationTemplate,
+) error {
+	if err := d.db.WithContext(ctx).Create(template).Error; err != nil {
+		return metaerror.Wrap(err, "failed to insert contest clarification template")
+	}
+	return nil
+}
+
+// UpdateTemplate 修改模板，只能修改自己的模板，不存在时返回false
+func (d *ContestClarificationDao) UpdateTemplate(
+	ctx context.Context,
+	template *foundationmodel.ContestClarificationTemplate,
+) (bool, error) {
+	result := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestClarificationTemplate{}).
+		Where("id = ? AND inserter = ?", template.Id, template.Inserter).
+		Updates(
+			map[string]interface{}{
+				"title":   template.Title,
+				"content": template.Content,
+			},
+		)
+	if result.Error != nil {
+		return false, metaerror.Wrap(result.Error, "failed to update contest clarification template, id:%d", template.Id)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (d *ContestClarificationDao) DeleteTemplate(ctx context.Context, id int, inserter int) error {
+	err := d.db.WithContext(ctx).
+		Where("id = ? AND inserter = ?", id, inserter).
+		Delete(&foundationmodel.ContestClarificationTemplate{}).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to delete contest clarification template, id:%d", id)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package foundationdao
+
+import (
+	"context"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+
+	"gorm.io/gorm"
+)
+
+type ContestGhostDao struct {
+	db *gorm.DB
+}
+
+var singletonContestGhostDao = singleton.Singleton[ContestGhostDao]{}
+
+func GetContestGhostDao() *ContestGhostDao {
+	return singletonContestGhostDao.GetInstance(
+		func() *ContestGhostDao {
+			dao := &ContestGhostDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// ReplaceContestGhosts 替换比赛的全部虚拟榜单，problems与ghosts一一对应
+func (d *ContestGhostDao) ReplaceContestGhosts(
+	ctx context.Context,
+	contestId int,
+	ghosts []*foundationmodel.ContestGhost,
+	problems [][]*foundationmodel.ContestGhostProblem,
+) error {
+	if len(ghosts) != len(problems) {
+		return metaerror.New("ghost problems size mismatch")
+	}
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Where(
+				"ghost_id IN (?)",
+				tx.Model(&foundationmodel.ContestGhost{}).Select("id").Where("contest_id = ?", contestId),
+			).Delete(&foundationmodel.ContestGhostProblem{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete contest ghost problems")
+			}
+			if err := tx.Where("contest_id = ?", contestId).
+				Delete(&foundationmodel.ContestGhost{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete contest ghosts")
+			}
+			if len(ghosts) == 0 {
+				return nil
+			}
+			for _, ghost := range ghosts {
+				ghost.ContestId = contestId
+			}
+			if err := tx.CreateInBatches(ghosts, 500).Error; err != nil {
+				return metaerror.Wrap(err, "failed to insert contest ghosts")
+			}
+			var ghostProblems []*foundationmodel.ContestGhostProblem
+			for i, ghost := range ghosts {
+				for _, problem := range problems[i] {
+					problem.GhostId = ghost.Id
+					ghostProblems = append(ghostProblems, problem)
+				}
+			}
+			if len(ghostProblems) > 0 {
+				if err := tx.CreateInBatches(ghostProblems, 500).Error; err != nil {
+					return metaerror.Wrap(err, "failed to insert contest ghost problems")
+				}
+			}
+			return nil
+		},
+	)
+}
+
+func (d *ContestGhostDao) GetContestGhosts(ctx context.Context, contestId int) ([]*foundationview.ContestGhost, error) {
+	var ghosts []*foundationview.ContestGhost
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestGhost{}).
+		Select("id, name, rank, score, penalty").
+		Where("contest_id = ?", contestId).
+		Order("rank, id").
+		Find(&ghosts).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest ghosts")
+	}
+	if len(ghosts) == 0 {
+		return ghosts, nil
+	}
+	ghostMap := make(map[int]*foundationview.ContestGhost, len(ghosts))
+	ghostIds := make([]int, 0, len(ghosts))
+	for _, ghost := range ghosts {
+		ghostMap[ghost.Id] = ghost
+		ghostIds = append(ghostIds, ghost.Id)
+	}
+	var problems []*foundationview.ContestGhostProblem
+	err = d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestGhostProblem{}).
+		Where("ghost_id IN ?", ghostIds).
+		Order("ghost_id, problem_index").
+		Find(&problems).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest ghost problems")
+	}
+	for _, problem := range problems {
+		if ghost, ok := ghostMap[problem.GhostId]; ok {
+			ghost.Problems = append(ghost.Problems, problem)
+		}
+	}
+	return ghosts, nil
+}
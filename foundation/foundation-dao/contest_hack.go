@@ -0,0 +1,408 @@
+package foundationdao
+
+import (
+	"context"
+	"errors"
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContestHackDao struct {
+	db *gorm.DB
+}
+
+var singletonContestHackDao = singleton.Singleton[ContestHackDao]{}
+
+func GetContestHackDao() *ContestHackDao {
+	return singletonContestHackDao.GetInstance(
+		func() *ContestHackDao {
+			dao := &ContestHackDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// InsertContestProblemLock 锁定题目，重复锁定时忽略
+func (d *ContestHackDao) InsertContestProblemLock(ctx context.Context, lock *foundationmodel.ContestProblemLock) error {
+	err := d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(lock).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to insert contest problem lock, id:%d", lock.Id)
+	}
+	return nil
+}
+
+// IsProblemLocked 判断用户是否已经锁定了题目
+func (d *ContestHackDao) IsProblemLocked(ctx context.Context, id int, problemId int, userId int) (bool, error) {
+	var count int64
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestProblemLock{}).
+		Where("id = ? AND problem_id = ? AND user_id = ?", id, problemId, userId).
+		Count(&count).Error
+	if err != nil {
+		return false, metaerror.Wrap(err, "failed to get contest problem lock, id:%d", id)
+	}
+	return count > 0, nil
+}
+
+// GetLockedProblemIds 获取用户已经锁定的题目
+func (d *ContestHackDao) GetLockedProblemIds(ctx context.Context, id int, userId int) ([]int, error) {
+	var problemIds []int
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestProblemLock{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Order("problem_id").
+		Pluck("problem_id", &problemIds).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest problem locks, id:%d", id)
+	}
+	return problemIds, nil
+}
+
+// HasContestProblemAccept 判断用户在比赛时间内是否通过了题目
+func (d *ContestHackDao) HasContestProblemAccept(
+	ctx context.Context,
+	id int,
+	problemId int,
+	userId int,
+	startTime time.Time,
+	endTime time.Time,
+) (bool, error) {
+	var count int64
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.JudgeJob{}).
+		Where("contest_id = ? AND problem_id = ? AND inserter = ? AND status = ?",
+			id, problemId, userId, foundationjudge.JudgeStatusAC).
+		Where("virtual_id IS NULL AND insert_time >= ? AND insert_time < ?", startTime, endTime).
+		Count(&count).Error
+	if err != nil {
+		return false, metaerror.Wrap(err, "failed to check contest problem accept, id:%d", id)
+	}
+	return count > 0, nil
+}
+
+func (d *ContestHackDao) getContestHackTargetQuery(
+	ctx context.Context,
+	id int,
+	startTime time.Time,
+	endTime time.Time,
+	fields string,
+) *gorm.DB {
+	return d.db.WithContext(ctx).
+		Table("judge_job AS j").
+		Select(
+			`
+			j.id AS judge_id, j.problem_id, j.inserter, u.username, u.nickname, m.contest_name,
+			j.language, j.code_length, j.insert_time`+fields,
+		).
+		Joins(`LEFT JOIN "user" AS u ON u.id = j.inserter`).
+		Joins("LEFT JOIN contest_member AS m ON m.id = j.contest_id AND m.user_id = j.inserter").
+		Where("j.contest_id = ? AND j.status = ?", id, foundationjudge.JudgeStatusAC).
+		Where("j.virtual_id IS NULL AND j.insert_time >= ? AND j.insert_time < ?", startTime, endTime)
+}
+
+// GetContestHackTargets 获取题目中其他用户最后一次通过的提交
+func (d *ContestHackDao) GetContestHackTargets(
+	ctx context.Context,
+	id int,
+	problemId int,
+	userId int,
+	startTime time.Time,
+	endTime time.Time,
+) ([]*foundationview.ContestHackTarget, error) {
+	var targets []*foundationview.ContestHackTarget
+	err := d.getContestHackTargetQuery(ctx, id, startTime, endTime, "").
+		Where("j.problem_id = ? AND j.inserter != ?", problemId, userId).
+		Where(
+			`NOT EXISTS (
+				SELECT 1 FROM judge_job AS l
+				WHERE l.contest_id = j.contest_id AND l.problem_id = j.problem_id AND l.inserter = j.inserter
+				AND l.status = ? AND l.virtual_id IS NULL AND l.id > j.id AND l.insert_time < ?
+			)`,
+			foundationjudge.JudgeStatusAC, endTime,
+		).
+		Order("j.id").
+		Scan(&targets).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest hack targets, id:%d", id)
+	}
+	return targets, nil
+}
+
+// GetContestHackTarget 获取可以被挑战的提交及代码，不是比赛时间内通过的提交时返回nil
+func (d *ContestHackDao) GetContestHackTarget(
+	ctx context.Context,
+	id int,
+	judgeId int,
+	startTime time.Time,
+	endTime time.Time,
+) (*foundationview.ContestHackTarget, error) {
+	var target foundationview.ContestHackTarget
+	err := d.getContestHackTargetQuery(ctx, id, startTime, endTime, ", j.code").
+		Where("j.id = ?", judgeId).
+		Take(&target).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest hack target, id:%d", judgeId)
+	}
+	return &target, nil
+}
+
+// InsertContestHackWithLimit 插入挑战，同一用户对同一提交的相同数据仍有效时返回duplicate，
+// 等待评测的挑战达到pendingLimit时返回tooMany，先锁定挑战者的用户记录避免并发提交绕过限制
+func (d *ContestHackDao) InsertContestHackWithLimit(
+	ctx context.Context,
+	hack *foundationmodel.ContestHack,
+	pendingLimit int,
+) (duplicate bool, tooMany bool, err error) {
+	err = d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var lockedIds []int
+			if err := tx.Table(`"user"`).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", hack.Hacker).
+				Pluck("id", &lockedIds).Error; err != nil {
+				return err
+			}
+			var count int64
+			if err := tx.Model(&foundationmodel.ContestHack{}).
+				Where(
+					"judge_id = ? AND hacker = ? AND input = ? AND status <> ?",
+					hack.JudgeId, hack.Hacker, hack.Input, foundationenum.ContestHackStatusError,
+				).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				duplicate = true
+				return nil
+			}
+			if err := tx.Model(&foundationmodel.ContestHack{}).
+				Where(
+					"contest_id = ? AND hacker = ? AND status IN ?",
+					hack.ContestId,
+					hack.Hacker,
+					[]foundationenum.ContestHackStatus{
+						foundationenum.ContestHackStatusPending,
+						foundationenum.ContestHackStatusRunning,
+					},
+				).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if int(count) >= pendingLimit {
+				tooMany = true
+				return nil
+			}
+			return tx.Create(hack).Error
+		},
+	)
+	if err != nil {
+		return false, false, metaerror.Wrap(err, "failed to insert contest hack, id:%d", hack.ContestId)
+	}
+	return duplicate, tooMany, nil
+}
+
+// GetContestHack 获取挑战任务，不存在时返回nil
+func (d *ContestHackDao) GetContestHack(ctx context.Context, id int) (*foundationmodel.ContestHack, error) {
+	var hack foundationmodel.ContestHack
+	err := d.db.WithContext(ctx).
+		Where("id = ?", id).
+		Take(&hack).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest hack, id:%d", id)
+	}
+	return &hack, nil
+}
+
+// GetContestHacks 获取比赛的挑战记录，problemId为0时不筛选题目
+func (d *ContestHackDao) GetContestHacks(
+	ctx context.Context,
+	id int,
+	problemId int,
+) ([]*foundationview.ContestHack, error) {
+	db := d.db.WithContext(ctx).
+		Table("contest_hack AS h").
+		Select(
+			`
+			h.id, h.problem_id, h.judge_id, h.hacker, u1.username AS hacker_username, u1.nickname AS hacker_nickname,
+			h.defendant, u2.username AS defendant_username, u2.nickname AS defendant_nickname,
+			h.status, h.message, h.insert_time, h.judge_time
+		`,
+		).
+		Joins(`LEFT JOIN "user" AS u1 ON u1.id = h.hacker`).
+		Joins(`LEFT JOIN "user" AS u2 ON u2.id = h.defendant`).
+		Where("h.contest_id = ?", id)
+	if problemId > 0 {
+		db = db.Where("h.problem_id = ?", problemId)
+	}
+	var hacks []*foundationview.ContestHack
+	if err := db.Order("h.id DESC").Scan(&hacks).Error; err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest hacks, id:%d", id)
+	}
+	return hacks, nil
+}
+
+// GetContestHackTests 获取题目在比赛中挑战成功的数据
+func (d *ContestHackDao) GetContestHackTests(
+	ctx context.Context,
+	id int,
+	problemId int,
+) ([]*foundationview.ContestHackTest, error) {
+	var tests []*foundationview.ContestHackTest
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestHack{}).
+		Select("id, input, output").
+		Where("contest_id = ? AND problem_id = ? AND status = ?", id, problemId, foundationenum.ContestHackStatusSuccess).
+		Order("id").
+		Scan(&tests).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest hack tests, id:%d", id)
+	}
+	return tests, nil
+}
+
+// GetJudgeJobHackTests 获取挑战某次提交成功的数据，预测试阶段重判被挑战的提交时使用
+func (d *ContestHackDao) GetJudgeJobHackTests(ctx context.Context, judgeId int) ([]*foundationview.ContestHackTest, error) {
+	var tests []*foundationview.ContestHackTest
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestHack{}).
+		Select("id, input, output").
+		Where("judge_id = ? AND status = ?", judgeId, foundationenum.ContestHackStatusSuccess).
+		Order("id").
+		Scan(&tests).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get judge job hack tests, id:%d", judgeId)
+	}
+	return tests, nil
+}
+
+// GetContestHackCounts 统计每个用户挑战成功与失败的次数
+func (d *ContestHackDao) GetContestHackCounts(ctx context.Context, id int) ([]*foundationview.ContestHackCount, error) {
+	var counts []*foundationview.ContestHackCount
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestHack{}).
+		Select(
+			"hacker, COUNT(*) FILTER (WHERE status = ?) AS success, COUNT(*) FILTER (WHERE status = ?) AS fail",
+			foundationenum.ContestHackStatusSuccess,
+			foundationenum.ContestHackStatusFailed,
+		).
+		Where("contest_id = ?", id).
+		Group("hacker").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest hack counts, id:%d", id)
+	}
+	return counts, nil
+}
+
+// RequestContestHackListPending 领取等待评测的挑战，多个评测机同时领取时不会重复
+func (d *ContestHackDao) RequestContestHackListPending(
+	ctx context.Context,
+	maxCount int,
+	judger string,
+) ([]*foundationmodel.ContestHack, error) {
+	var hacks []*foundationmodel.ContestHack
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var ids []int
+			execSql := `
+			SELECT h.id
+			FROM contest_hack AS h
+			WHERE h.status = ?
+			ORDER BY h.id
+			LIMIT ? FOR UPDATE SKIP LOCKED
+		`
+			if err := tx.Raw(execSql, foundationenum.ContestHackStatusPending, maxCount).Scan(&ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			if err := tx.Model(&foundationmodel.ContestHack{}).
+				Where("id IN ?", ids).
+				Updates(
+					map[string]interface{}{
+						"status": foundationenum.ContestHackStatusRunning,
+						"judger": judger,
+					},
+				).Error; err != nil {
+				return err
+			}
+			return tx.Where("id IN ?", ids).Find(&hacks).Error
+		},
+	)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to request contest hack list")
+	}
+	return hacks, nil
+}
+
+// FinishContestHack 记录挑战结果，挑战成功时重判被挑战的提交，并重建榜单
+func (d *ContestHackDao) FinishContestHack(
+	ctx context.Context,
+	hack *foundationmodel.ContestHack,
+	judger string,
+	status foundationenum.ContestHackStatus,
+	output *string,
+	message string,
+) error {
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			txResult := tx.Model(&foundationmodel.ContestHack{}).
+				Where("id = ? AND judger = ? AND status = ?", hack.Id, judger, foundationenum.ContestHackStatusRunning).
+				Updates(
+					map[string]interface{}{
+						"status":     status,
+						"output":     output,
+						"message":    message,
+						"judge_time": time.Now(),
+					},
+				)
+			if txResult.Error != nil {
+				return txResult.Error
+			}
+			if txResult.RowsAffected == 0 {
+				return nil
+			}
+			if status == foundationenum.ContestHackStatusSuccess {
+				// 挑战数据已加入评测，重判被挑战的提交，结果以重判为准
+				var count int64
+				if err := tx.Model(&foundationmodel.JudgeJob{}).
+					Where("id = ? AND status = ?", hack.JudgeId, foundationjudge.JudgeStatusAC).
+					Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					if err := rejudgeJobWithTx(tx, hack.JudgeId); err != nil {
+						return err
+					}
+				}
+			}
+			if status == foundationenum.ContestHackStatusSuccess || status == foundationenum.ContestHackStatusFailed {
+				return insertContestRankRebuildWithTx(tx, hack.ContestId)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to finish contest hack, id:%d", hack.Id)
+	}
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	foundationmodel "foundation/foundation-model"
 	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
 	metapostgresql "meta/meta-postgresql"
 	"meta/singleton"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -86,22 +88,174 @@ func (d *ContestMemberDao) GetUsersWithName(ctx context.Context, id int) (
 	return users, nil
 }
 
+// GetUsersWithInfo 获取填写了比赛名称或位置的成员，用于编辑比赛
+func (d *ContestMemberDao) GetUsersWithInfo(ctx context.Context, id int) (
+	[]*foundationview.ContestMember,
+	error,
+) {
+	var users []*foundationview.ContestMember
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestMember{}).
+		Select("user_id as id", "contest_name", "location").
+		Where("id = ?", id).
+		Where("(contest_name is not null and contest_name != '') or (location is not null and location != '')").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return users, nil
+}
+
 func (d *ContestMemberDao) PostContestMemberName(
 	ctx context.Context, userId int, contestId int, name string,
 ) error {
-	return d.db.WithContext(ctx).
-		Clauses(clause.OnConflict{
-			Columns: []clause.Column{
-				{Name: "id"},
-				{Name: "user_id"},
-			},
-			DoUpdates: clause.Assignments(map[string]interface{}{
-				"contest_name": name,
-			}),
-		}).
-		Create(&foundationmodel.ContestMember{
-			Id:          contestId,
-			UserId:      userId,
-			ContestName: name,
-		}).Error
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{
+					{Name: "id"},
+					{Name: "user_id"},
+				},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"contest_name": name,
+				}),
+			}).
+				Create(&foundationmodel.ContestMember{
+					Id:          contestId,
+					UserId:      userId,
+					ContestName: name,
+				}).Error
+			if err != nil {
+				return err
+			}
+			// 榜单缓存中包含成员的比赛名称
+			return insertContestRankRebuildWithTx(tx, contestId)
+		},
+	)
+}
+
+// GetStartTime 获取成员个人比赛窗口的开始时间，未开始时返回nil
+func (d *ContestMemberDao) GetStartTime(ctx context.Context, id int, userId int) (*time.Time, error) {
+	var startTimes []*time.Time
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestMember{}).
+		Where("id = ? AND user_id = ? AND start_time IS NOT NULL", id, userId).
+		Limit(1).
+		Pluck("start_time", &startTimes).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest member start time, id:%d", id)
+	}
+	if len(startTimes) == 0 {
+		return nil, nil
+	}
+	return startTimes[0], nil
+}
+
+// GetStartTimes 获取比赛中已开始个人窗口的成员及开始时间
+func (d *ContestMemberDao) GetStartTimes(ctx context.Context, id int) (map[int]time.Time, error) {
+	var members []*foundationmodel.ContestMember
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestMember{}).
+		Select("user_id, start_time").
+		Where("id = ? AND start_time IS NOT NULL", id).
+		Find(&members).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest member start times, id:%d", id)
+	}
+	startTimes := make(map[int]time.Time, len(members))
+	for _, member := range members {
+		startTimes[member.UserId] = *member.StartTime
+	}
+	return startTimes, nil
+}
+
+// StartContestMember 开始成员的个人比赛窗口，已开始时保持原开始时间，返回实际的开始时间
+func (d *ContestMemberDao) StartContestMember(
+	ctx context.Context, id int, userId int, startTime time.Time,
+) (*time.Time, error) {
+	member := &foundationmodel.ContestMember{
+		Id:        id,
+		UserId:    userId,
+		StartTime: &startTime,
+	}
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			// 冲突时保留已有的开始时间，通过RETURNING取回实际值
+			err := tx.Clauses(
+				clause.OnConflict{
+					Columns: []clause.Column{
+						{Name: "id"},
+						{Name: "user_id"},
+					},
+					DoUpdates: clause.Assignments(map[string]interface{}{
+						"start_time": gorm.Expr("COALESCE(contest_member.start_time, EXCLUDED.start_time)"),
+					}),
+				},
+				clause.Returning{Columns: []clause.Column{{Name: "start_time"}}},
+			).
+				Create(member).Error
+			if err != nil {
+				return metaerror.Wrap(err, "failed to start contest member, id:%d", id)
+			}
+			// 罚时从成员的开始时间计算
+			return insertContestRankRebuildWithTx(tx, id)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return member.StartTime, nil
+}
+
+// ImportContestMembers 批量创建账号并加入比赛，users与members一一对应
+// users中为nil的表示复用已有账号，此时member.UserId已经填写；已经是成员的更新比赛名称与位置
+func (d *ContestMemberDao) ImportContestMembers(
+	ctx context.Context,
+	contestId int,
+	users []*foundationmodel.User,
+	members []*foundationmodel.ContestMember,
+) error {
+	if len(users) != len(members) {
+		return metaerror.New("contest member users size mismatch")
+	}
+	var newUsers []*foundationmodel.User
+	for _, user := range users {
+		if user != nil {
+			newUsers = append(newUsers, user)
+		}
+	}
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if len(newUsers) > 0 {
+				if err := tx.CreateInBatches(newUsers, 500).Error; err != nil {
+					return err
+				}
+			}
+			for i, member := range members {
+				member.Id = contestId
+				if users[i] != nil {
+					member.UserId = users[i].Id
+				}
+			}
+			if len(members) > 0 {
+				err := tx.Clauses(
+					clause.OnConflict{
+						Columns:   []clause.Column{{Name: "id"}, {Name: "user_id"}},
+						DoUpdates: clause.AssignmentColumns([]string{"contest_name", "location"}),
+					},
+				).CreateInBatches(members, 500).Error
+				if err != nil {
+					return err
+				}
+			}
+			return insertContestRankRebuildWithTx(tx, contestId)
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to import contest members, id:%d", contestId)
+	}
+	return nil
 }
@@ -0,0 +1,232 @@
+package foundationdao
+
+import (
+	"context"
+	"errors"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContestPrintDao struct {
+	db *gorm.DB
+}
+
+var singletonContestPrintDao = singleton.Singleton[ContestPrintDao]{}
+
+func GetContestPrintDao() *ContestPrintDao {
+	return singletonContestPrintDao.GetInstance(
+		func() *ContestPrintDao {
+			dao := &ContestPrintDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// InsertContestPrintWithQuota 在配额内插入打印任务，超出配额时返回false
+// 队伍传入全部成员以共用配额，先锁定成员的用户记录，避免并发提交时重复使用剩余配额
+func (d *ContestPrintDao) InsertContestPrintWithQuota(
+	ctx context.Context,
+	contestPrint *foundationmodel.ContestPrint,
+	userIds []int,
+	quota int,
+) (bool, error) {
+	inserted := false
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var lockedIds []int
+			if err := tx.Table(`"user"`).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id IN ?", userIds).
+				Order("id").
+				Pluck("id", &lockedIds).Error; err != nil {
+				return metaerror.Wrap(err, "failed to lock contest print users, id:%d", contestPrint.ContestId)
+			}
+			var usedPages int
+			if err := tx.Model(&foundationmodel.ContestPrint{}).
+				Select("COALESCE(SUM(pages), 0)").
+				Where("contest_id = ? AND user_id IN ?", contestPrint.ContestId, userIds).
+				Scan(&usedPages).Error; err != nil {
+				return metaerror.Wrap(err, "failed to get contest print pages, id:%d", contestPrint.ContestId)
+			}
+			if usedPages+contestPrint.Pages > quota {
+				return nil
+			}
+			if err := tx.Create(contestPrint).Error; err != nil {
+				return metaerror.Wrap(err, "failed to insert contest print, id:%d", contestPrint.ContestId)
+			}
+			inserted = true
+			return nil
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return inserted, nil
+}
+
+func (d *ContestPrintDao) getContestPrintQuery(ctx context.Context, contestId int, fields string) *gorm.DB {
+	return d.db.WithContext(ctx).
+		Table("contest_print AS p").
+		Select(
+			`
+			p.id, p.user_id, u.username, u.nickname, m.contest_name, m.location,
+			t.id AS team_id, t.name AS team_name, p.language, p.pages, p.status, p.printer,
+			p.insert_time, p.claim_time, p.finish_time`+fields,
+		).
+		Joins(`LEFT JOIN "user" AS u ON u.id = p.user_id`).
+		Joins("LEFT JOIN contest_member AS m ON m.id = p.contest_id AND m.user_id = p.user_id").
+		Joins("LEFT JOIN contest_team_member AS tm ON tm.contest_id = p.contest_id AND tm.user_id = p.user_id").
+		Joins("LEFT JOIN contest_team AS t ON t.id = tm.team_id").
+		Where("p.contest_id = ?", contestId)
+}
+
+// GetContestPrints 获取打印任务，userIds不为空时只返回这些用户的任务，location按前缀筛选房间或位置
+func (d *ContestPrintDao) GetContestPrints(
+	ctx context.Context,
+	contestId int,
+	userIds []int,
+	status *foundationenum.ContestPrintStatus,
+	location string,
+) ([]*foundationview.ContestPrint, error) {
+	db := d.getContestPrintQuery(ctx, contestId, "")
+	if userIds != nil {
+		db = db.Where("p.user_id IN ?", userIds)
+	}
+	if status != nil {
+		db = db.Where("p.status = ?", *status)
+	}
+	if location != "" {
+		db = db.Where("m.location LIKE ?", location+"%")
+	}
+	var prints []*foundationview.ContestPrint
+	if err := db.Order("p.id").Scan(&prints).Error; err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest prints, id:%d", contestId)
+	}
+	return prints, nil
+}
+
+// GetContestPrint 获取包含内容的打印任务，不存在时返回nil
+func (d *ContestPrintDao) GetContestPrint(ctx context.Context, contestId int, id int) (*foundationview.ContestPrint, error) {
+	var contestPrint foundationview.ContestPrint
+	err := d.getContestPrintQuery(ctx, contestId, ", p.content").
+		Where("p.id = ?", id).
+		Take(&contestPrint).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest print, id:%d", id)
+	}
+	return &contestPrint, nil
+}
+
+// ClaimNextContestPrint 领取最早的等待中任务，多个打印程序同时领取时不会重复，没有任务时返回0
+func (d *ContestPrintDao) ClaimNextContestPrint(
+	ctx context.Context,
+	contestId int,
+	printer int,
+	location string,
+	nowTime time.Time,
+) (int, error) {
+	next := d.db.Table("contest_print AS p").
+		Select("p.id").
+		Where("p.contest_id = ? AND p.status = ?", contestId, foundationenum.ContestPrintStatusPending)
+	if location != "" {
+		next = next.
+			Joins("JOIN contest_member AS m ON m.id = p.contest_id AND m.user_id = p.user_id").
+			Where("m.location LIKE ?", location+"%")
+	}
+	next = next.Order("p.id").Limit(1).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	var ids []int
+	err := d.db.WithContext(ctx).
+		Raw(
+			`UPDATE contest_print SET status = ?, printer = ?, claim_time = ? WHERE id = (?) RETURNING id`,
+			foundationenum.ContestPrintStatusPrinting, printer, nowTime, next,
+		).
+		Scan(&ids).Error
+	if err != nil {
+		return 0, metaerror.Wrap(err, "failed to claim contest print, id:%d", contestId)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+// updateContestPrintStatus 仅在当前状态匹配时修改，返回是否修改成功
+func (d *ContestPrintDao) updateContestPrintStatus(
+	ctx context.Context,
+	contestId int,
+	id int,
+	where *gorm.DB,
+	values map[string]interface{},
+) (bool, error) {
+	result := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestPrint{}).
+		Where("id = ? AND contest_id = ?", id, contestId).
+		Where(where).
+		Updates(values)
+	if result.Error != nil {
+		return false, metaerror.Wrap(result.Error, "failed to update contest print, id:%d", id)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimContestPrint 领取指定的等待中任务
+func (d *ContestPrintDao) ClaimContestPrint(
+	ctx context.Context,
+	contestId int,
+	id int,
+	printer int,
+	nowTime time.Time,
+) (bool, error) {
+	return d.updateContestPrintStatus(
+		ctx, contestId, id,
+		d.db.Where("status = ?", foundationenum.ContestPrintStatusPending),
+		map[string]interface{}{
+			"status":     foundationenum.ContestPrintStatusPrinting,
+			"printer":    printer,
+			"claim_time": nowTime,
+		},
+	)
+}
+
+// ReleaseContestPrint 打印失败时放回队列
+func (d *ContestPrintDao) ReleaseContestPrint(ctx context.Context, contestId int, id int, printer int) (bool, error) {
+	return d.updateContestPrintStatus(
+		ctx, contestId, id,
+		d.db.Where("status = ? AND printer = ?", foundationenum.ContestPrintStatusPrinting, printer),
+		map[string]interface{}{
+			"status":     foundationenum.ContestPrintStatusPending,
+			"printer":    nil,
+			"claim_time": nil,
+		},
+	)
+}
+
+// FinishContestPrint 将自己领取的任务标记为已打印
+func (d *ContestPrintDao) FinishContestPrint(
+	ctx context.Context,
+	contestId int,
+	id int,
+	printer int,
+	nowTime time.Time,
+) (bool, error) {
+	return d.updateContestPrintStatus(
+		ctx, contestId, id,
+		d.db.Where("status = ? AND printer = ?", foundationenum.ContestPrintStatusPrinting, printer),
+		map[string]interface{}{
+			"status":      foundationenum.ContestPrintStatusDone,
+			"finish_time": nowTime,
+		},
+	)
+}
@@ -2,6 +2,7 @@ package foundationdao
 
 import (
 	"context"
+	"errors"
 	foundationmodel "foundation/foundation-model"
 	foundationview "foundation/foundation-view"
 	metaerror "meta/meta-error"
@@ -101,7 +102,7 @@ func (d *ContestProblemDao) GetProblemIds(ctx context.Context, contestId int) (
 func (d *ContestProblemDao) GetProblemsRank(ctx context.Context, id int) ([]*foundationview.ContestProblemRank, error) {
 	var results []*foundationview.ContestProblemRank
 	err := d.db.WithContext(ctx).Model(&foundationmodel.ContestProblem{}).
-		Select("problem_id,index,score").
+		Select("problem_id,index,score,score_decay,score_penalty,score_min").
 		Where("id = ?", id).
 		Scan(&results).Error
 
@@ -111,6 +112,41 @@ func (d *ContestProblemDao) GetProblemsRank(ctx context.Context, id int) ([]*fou
 	return results, nil
 }
 
+func (d *ContestProblemDao) GetProblemSettings(ctx context.Context, id int) (
+	[]*foundationview.ContestProblemSetting,
+	error,
+) {
+	var results []*foundationview.ContestProblemSetting
+	err := d.db.WithContext(ctx).Model(&foundationmodel.ContestProblem{}).
+		Select("problem_id,score,score_decay,score_penalty,score_min,time_limit,memory_limit").
+		Where("id = ?", id).
+		Order("index ASC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest problem settings, id:%d", id)
+	}
+	return results, nil
+}
+
+// GetProblemLimit 获取比赛中题目覆盖的评测限制，题目不在比赛中时返回nil
+func (d *ContestProblemDao) GetProblemLimit(ctx context.Context, id int, problemId int) (
+	*foundationview.ContestProblemLimit,
+	error,
+) {
+	var result foundationview.ContestProblemLimit
+	err := d.db.WithContext(ctx).Model(&foundationmodel.ContestProblem{}).
+		Select("time_limit,memory_limit").
+		Where("id = ? AND problem_id = ?", id, problemId).
+		Take(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest problem limit, id:%d, problemId:%d", id, problemId)
+	}
+	return &result, nil
+}
+
 func (d *ContestProblemDao) GetProblemsDetail(ctx context.Context, contestId int) (
 	[]*foundationview.ContestProblemDetail,
 	error,
@@ -138,6 +174,53 @@ func (d *ContestProblemDao) GetProblemsDetail(ctx context.Context, contestId int
 	return results, nil
 }
 
+func (d *ContestProblemDao) GetProblemsCcs(ctx context.Context, contestId int) (
+	[]*foundationview.ContestCcsProblemRow,
+	error,
+) {
+	var results []*foundationview.ContestCcsProblemRow
+	err := d.db.WithContext(ctx).
+		Table("contest_problem AS cp").
+		Select("cp.problem_id, cp.index, p.title, p.time_limit").
+		Joins("JOIN problem AS p ON cp.problem_id = p.id").
+		Where("cp.id = ?", contestId).
+		Order("cp.index").
+		Scan(&results).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest ccs problems, id:%d", contestId)
+	}
+	return results, nil
+}
+
+// GetProblemsStatement 按题目顺序获取打印题面需要的内容
+func (d *ContestProblemDao) GetProblemsStatement(ctx context.Context, contestId int) (
+	[]*foundationview.ContestProblemStatement,
+	error,
+) {
+	var results []*foundationview.ContestProblemStatement
+	err := d.db.WithContext(ctx).
+		Table("contest_problem AS cp").
+		Select(
+			`
+			cp.problem_id,
+			cp.index,
+			p.title,
+			p.description,
+			p.source,
+			COALESCE(cp.time_limit, p.time_limit) AS time_limit,
+			COALESCE(cp.memory_limit, p.memory_limit) AS memory_limit
+		`,
+		).
+		Joins("JOIN problem AS p ON cp.problem_id = p.id").
+		Where("cp.id = ?", contestId).
+		Order("cp.index").
+		Scan(&results).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest problem statements, id:%d", contestId)
+	}
+	return results, nil
+}
+
 func (d *ContestProblemDao) GetProblemIdByContest(ctx context.Context, id int, index int) (*int, error) {
 	var problemId int
 	err := d.db.WithContext(ctx).
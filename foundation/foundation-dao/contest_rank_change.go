@@ -0,0 +1,97 @@
+package foundationdao
+
+import (
+	"context"
+	foundationmodel "foundation/foundation-model"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ContestRankChangeDao struct {
+	db *gorm.DB
+}
+
+var singletonContestRankChangeDao = singleton.Singleton[ContestRankChangeDao]{}
+
+func GetContestRankChangeDao() *ContestRankChangeDao {
+	return singletonContestRankChangeDao.GetInstance(
+		func() *ContestRankChangeDao {
+			dao := &ContestRankChangeDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// insertContestRankChangeByJobsWithTx 记录评测所在比赛的榜单变更，不属于比赛的评测会被忽略
+// rebuild为true时只记录需要重建的比赛
+func insertContestRankChangeByJobsWithTx(tx *gorm.DB, ids []int, rebuild bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	execSql := `
+INSERT INTO contest_rank_change (contest_id, judge_job_id, insert_time)
+SELECT contest_id, id, NOW()
+FROM judge_job
+WHERE id IN ? AND contest_id IS NOT NULL
+`
+	if rebuild {
+		execSql = `
+INSERT INTO contest_rank_change (contest_id, judge_job_id, insert_time)
+SELECT DISTINCT contest_id, NULL::int8, NOW()
+FROM judge_job
+WHERE id IN ? AND contest_id IS NOT NULL
+`
+	}
+	if err := tx.Exec(execSql, ids).Error; err != nil {
+		return metaerror.Wrap(err, "failed to insert contest rank change")
+	}
+	return nil
+}
+
+// insertContestRankRebuildWithTx 记录需要重建榜单的比赛
+func insertContestRankRebuildWithTx(tx *gorm.DB, contestId int) error {
+	err := tx.Exec(
+		"INSERT INTO contest_rank_change (contest_id, judge_job_id, insert_time) VALUES (?, NULL, NOW())",
+		contestId,
+	).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to insert contest rank rebuild")
+	}
+	return nil
+}
+
+func (d *ContestRankChangeDao) InsertContestRankRebuild(ctx context.Context, contestId int) error {
+	return insertContestRankRebuildWithTx(d.db.WithContext(ctx), contestId)
+}
+
+// GetContestRankChangesAfter 获取序号大于id的变更，按序号排列
+func (d *ContestRankChangeDao) GetContestRankChangesAfter(
+	ctx context.Context,
+	id int,
+) ([]*foundationmodel.ContestRankChange, error) {
+	var changes []*foundationmodel.ContestRankChange
+	err := d.db.WithContext(ctx).
+		Where("id > ?", id).
+		Order("id").
+		Find(&changes).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest rank changes")
+	}
+	return changes, nil
+}
+
+// DeleteContestRankChanges 删除数据库时间keep之前的变更
+func (d *ContestRankChangeDao) DeleteContestRankChanges(ctx context.Context, keep time.Duration) error {
+	err := d.db.WithContext(ctx).
+		Where("insert_time < NOW() - ?::interval", keep.String()).
+		Delete(&foundationmodel.ContestRankChange{}).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to delete contest rank changes")
+	}
+	return nil
+}
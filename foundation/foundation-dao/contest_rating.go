@@ -0,0 +1,184 @@
+package foundationdao
+
+import (
+	"context"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContestRatingDao struct {
+	db *gorm.DB
+}
+
+var singletonContestRatingDao = singleton.Singleton[ContestRatingDao]{}
+
+func GetContestRatingDao() *ContestRatingDao {
+	return singletonContestRatingDao.GetInstance(
+		func() *ContestRatingDao {
+			dao := &ContestRatingDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// 计入Rating的比赛：标记为计分、已结束且没有保持封榜
+const contestRatedCondition = "c.rated AND NOT COALESCE(c.always_lock, false) AND c.end_time <= ?"
+
+// GetContestRatingDirtyTime 获取需要重新计算Rating的最早比赛结束时间，没有时返回nil
+// 新结束的比赛、修改过的比赛、结束后有重判的比赛需要计算，取消计分的比赛需要移除已有的Rating
+func (d *ContestRatingDao) GetContestRatingDirtyTime(ctx context.Context, nowTime time.Time) (*time.Time, error) {
+	var endTime *time.Time
+	err := d.db.WithContext(ctx).
+		Table("contest AS c").
+		Select("MIN(c.end_time)").
+		Where(
+			`(`+contestRatedCondition+` AND (
+				c.rating_time IS NULL OR c.rating_time < c.modify_time OR EXISTS (
+					SELECT 1 FROM contest_rank_change AS r
+					LEFT JOIN judge_job AS j ON j.id = r.judge_job_id
+					WHERE r.contest_id = c.id AND r.insert_time > c.rating_time
+					AND (r.judge_job_id IS NULL OR j.insert_time <= c.end_time)
+				)
+			)) OR (NOT (`+contestRatedCondition+`) AND c.rating_time IS NOT NULL)`,
+			nowTime, nowTime,
+		).
+		Scan(&endTime).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest rating dirty time")
+	}
+	return endTime, nil
+}
+
+// GetRatedContestIds 获取结束时间不早于fromTime的计分比赛，按结束时间排序
+func (d *ContestRatingDao) GetRatedContestIds(ctx context.Context, fromTime time.Time, nowTime time.Time) ([]int, error) {
+	var ids []int
+	err := d.db.WithContext(ctx).
+		Table("contest AS c").
+		Where(contestRatedCondition+" AND c.end_time >= ?", nowTime, fromTime).
+		Order("c.end_time, c.id").
+		Pluck("c.id", &ids).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get rated contest ids")
+	}
+	return ids, nil
+}
+
+// GetUserRatingsBefore 获取用户在fromTime之前结束的计分比赛后的Rating，没有参加过的用户不返回
+func (d *ContestRatingDao) GetUserRatingsBefore(
+	ctx context.Context,
+	userIds []int,
+	fromTime time.Time,
+) (map[int]int, error) {
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+	var rows []*foundationmodel.ContestRating
+	err := d.db.WithContext(ctx).
+		Raw(
+			`SELECT DISTINCT ON (r.user_id) r.user_id, r.new_rating
+			FROM contest_rating AS r
+			JOIN contest AS c ON c.id = r.contest_id
+			WHERE r.user_id IN ? AND c.end_time < ?
+			ORDER BY r.user_id, c.end_time DESC, c.id DESC`,
+			userIds, fromTime,
+		).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get user ratings before, time:%s", fromTime)
+	}
+	ratings := make(map[int]int, len(rows))
+	for _, row := range rows {
+		ratings[row.UserId] = row.NewRating
+	}
+	return ratings, nil
+}
+
+// ReplaceContestRatings 替换fromTime之后结束的比赛的Rating记录，并更新涉及用户的当前Rating
+// 整体在一个事务中完成，重复计算的结果相同
+func (d *ContestRatingDao) ReplaceContestRatings(
+	ctx context.Context,
+	fromTime time.Time,
+	contestIds []int,
+	ratings []*foundationmodel.ContestRating,
+	ratingTime time.Time,
+) error {
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var userIds []int
+			err := tx.Raw(
+				`DELETE FROM contest_rating
+				WHERE contest_id IN (SELECT id FROM contest WHERE end_time >= ?)
+				RETURNING user_id`,
+				fromTime,
+			).Scan(&userIds).Error
+			if err != nil {
+				return metaerror.Wrap(err, "failed to delete contest ratings, time:%s", fromTime)
+			}
+			if len(ratings) > 0 {
+				err = tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(ratings, 500).Error
+				if err != nil {
+					return metaerror.Wrap(err, "failed to insert contest ratings")
+				}
+			}
+			for _, rating := range ratings {
+				userIds = append(userIds, rating.UserId)
+			}
+			// 不再计分的比赛清空计算时间
+			ratedIds := append([]int{0}, contestIds...)
+			err = tx.Model(&foundationmodel.Contest{}).
+				Where("end_time >= ? AND (rating_time IS NOT NULL OR id IN ?)", fromTime, ratedIds).
+				Update("rating_time", gorm.Expr("CASE WHEN id IN ? THEN ?::timestamptz END", ratedIds, ratingTime)).
+				Error
+			if err != nil {
+				return metaerror.Wrap(err, "failed to update contest rating time")
+			}
+			if len(userIds) == 0 {
+				return nil
+			}
+			err = tx.Exec(
+				`UPDATE "user" AS u SET rating = (
+					SELECT r.new_rating FROM contest_rating AS r
+					JOIN contest AS c ON c.id = r.contest_id
+					WHERE r.user_id = u.id
+					ORDER BY c.end_time DESC, c.id DESC
+					LIMIT 1
+				)
+				WHERE u.id IN ?`,
+				userIds,
+			).Error
+			if err != nil {
+				return metaerror.Wrap(err, "failed to update user rating")
+			}
+			return nil
+		},
+	)
+}
+
+// GetUserRatingHistory 获取用户的Rating变化记录，按比赛结束时间排序
+func (d *ContestRatingDao) GetUserRatingHistory(
+	ctx context.Context,
+	userId int,
+) ([]*foundationview.UserRatingHistory, error) {
+	var history []*foundationview.UserRatingHistory
+	err := d.db.WithContext(ctx).
+		Table("contest_rating AS r").
+		Select(
+			`r.contest_id, c.title AS contest_title, c.end_time, r.rank, r.old_rating, r.new_rating`,
+		).
+		Joins("JOIN contest AS c ON c.id = r.contest_id").
+		Where("r.user_id = ?", userId).
+		Order("c.end_time, c.id").
+		Scan(&history).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get user rating history, id:%d", userId)
+	}
+	return history, nil
+}
@@ -0,0 +1,241 @@
+package foundationdao
+
+import (
+	"context"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContestTeamDao struct {
+	db *gorm.DB
+}
+
+var singletonContestTeamDao = singleton.Singleton[ContestTeamDao]{}
+
+func GetContestTeamDao() *ContestTeamDao {
+	return singletonContestTeamDao.GetInstance(
+		func() *ContestTeamDao {
+			dao := &ContestTeamDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// insertContestTeamMembersWithTx 写入队伍成员，并将成员加入比赛以便访问私有比赛
+func insertContestTeamMembersWithTx(tx *gorm.DB, team *foundationmodel.ContestTeam, userIds []int) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	members := make([]*foundationmodel.ContestTeamMember, 0, len(userIds))
+	contestMembers := make([]*foundationmodel.ContestMember, 0, len(userIds))
+	for _, userId := range userIds {
+		members = append(
+			members, &foundationmodel.ContestTeamMember{
+				TeamId:    team.Id,
+				ContestId: team.ContestId,
+				UserId:    userId,
+			},
+		)
+		contestMembers = append(
+			contestMembers,
+			foundationmodel.NewContestMemberBuilder().Id(team.ContestId).UserId(userId).Build(),
+		)
+	}
+	if err := tx.CreateInBatches(members, 500).Error; err != nil {
+		return metaerror.Wrap(err, "failed to insert contest team members, team:%d", team.Id)
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(contestMembers, 500).Error; err != nil {
+		return metaerror.Wrap(err, "failed to insert contest members, team:%d", team.Id)
+	}
+	return nil
+}
+
+// InsertContestTeam 创建队伍，成员已属于其他队伍时因唯一约束失败
+func (d *ContestTeamDao) InsertContestTeam(
+	ctx context.Context,
+	team *foundationmodel.ContestTeam,
+	userIds []int,
+) error {
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Create(team).Error; err != nil {
+				return metaerror.Wrap(err, "failed to insert contest team")
+			}
+			if err := insertContestTeamMembersWithTx(tx, team, userIds); err != nil {
+				return err
+			}
+			return insertContestRankRebuildWithTx(tx, team.ContestId)
+		},
+	)
+}
+
+// UpdateContestTeam 修改队伍名称并替换全部成员
+func (d *ContestTeamDao) UpdateContestTeam(
+	ctx context.Context,
+	team *foundationmodel.ContestTeam,
+	userIds []int,
+) error {
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			result := tx.Model(&foundationmodel.ContestTeam{}).
+				Where("id = ? AND contest_id = ?", team.Id, team.ContestId).
+				Update("name", team.Name)
+			if result.Error != nil {
+				return metaerror.Wrap(result.Error, "failed to update contest team, id:%d", team.Id)
+			}
+			if result.RowsAffected == 0 {
+				return metaerror.New("contest team not found, id:%d", team.Id)
+			}
+			if err := tx.Where("team_id = ?", team.Id).
+				Delete(&foundationmodel.ContestTeamMember{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete contest team members, id:%d", team.Id)
+			}
+			if err := insertContestTeamMembersWithTx(tx, team, userIds); err != nil {
+				return err
+			}
+			return insertContestRankRebuildWithTx(tx, team.ContestId)
+		},
+	)
+}
+
+func (d *ContestTeamDao) DeleteContestTeam(ctx context.Context, contestId int, teamId int) error {
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Where("team_id = ? AND contest_id = ?", teamId, contestId).
+				Delete(&foundationmodel.ContestTeamMember{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete contest team members, id:%d", teamId)
+			}
+			if err := tx.Where("id = ? AND contest_id = ?", teamId, contestId).
+				Delete(&foundationmodel.ContestTeam{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete contest team, id:%d", teamId)
+			}
+			return insertContestRankRebuildWithTx(tx, contestId)
+		},
+	)
+}
+
+// ReplaceContestTeams 批量导入时替换比赛的全部队伍，teams与members一一对应
+func (d *ContestTeamDao) ReplaceContestTeams(
+	ctx context.Context,
+	contestId int,
+	teams []*foundationmodel.ContestTeam,
+	members [][]int,
+) error {
+	if len(teams) != len(members) {
+		return metaerror.New("team members size mismatch")
+	}
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Where("contest_id = ?", contestId).
+				Delete(&foundationmodel.ContestTeamMember{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete contest team members")
+			}
+			if err := tx.Where("contest_id = ?", contestId).
+				Delete(&foundationmodel.ContestTeam{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete contest teams")
+			}
+			if len(teams) > 0 {
+				for _, team := range teams {
+					team.ContestId = contestId
+				}
+				if err := tx.CreateInBatches(teams, 500).Error; err != nil {
+					return metaerror.Wrap(err, "failed to insert contest teams")
+				}
+				for i, team := range teams {
+					if err := insertContestTeamMembersWithTx(tx, team, members[i]); err != nil {
+						return err
+					}
+				}
+			}
+			return insertContestRankRebuildWithTx(tx, contestId)
+		},
+	)
+}
+
+// GetContestTeams 获取比赛的全部队伍及成员
+func (d *ContestTeamDao) GetContestTeams(ctx context.Context, contestId int) ([]*foundationview.ContestTeam, error) {
+	var teams []*foundationview.ContestTeam
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestTeam{}).
+		Select("id, name").
+		Where("contest_id = ?", contestId).
+		Order("id").
+		Find(&teams).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest teams, id:%d", contestId)
+	}
+	if len(teams) == 0 {
+		return nil, nil
+	}
+	var members []*foundationview.ContestTeamMember
+	err = d.db.WithContext(ctx).
+		Table("contest_team_member AS m").
+		Select("m.team_id, m.user_id, u.username, u.nickname").
+		Joins(`JOIN "user" AS u ON u.id = m.user_id`).
+		Where("m.contest_id = ?", contestId).
+		Order("m.team_id, m.user_id").
+		Scan(&members).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest team members, id:%d", contestId)
+	}
+	teamMap := make(map[int]*foundationview.ContestTeam, len(teams))
+	for _, team := range teams {
+		teamMap[team.Id] = team
+	}
+	for _, member := range members {
+		if team, ok := teamMap[member.TeamId]; ok {
+			team.Members = append(team.Members, member)
+		}
+	}
+	return teams, nil
+}
+
+// GetContestTeamUserIds 获取用户所在队伍的全部成员，不在队伍中时返回nil
+func (d *ContestTeamDao) GetContestTeamUserIds(ctx context.Context, contestId int, userId int) ([]int, error) {
+	var userIds []int
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestTeamMember{}).
+		Where(
+			"team_id IN (?)",
+			d.db.Model(&foundationmodel.ContestTeamMember{}).
+				Select("team_id").
+				Where("contest_id = ? AND user_id = ?", contestId, userId),
+		).
+		Pluck("user_id", &userIds).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest team user ids, id:%d", contestId)
+	}
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+	return userIds, nil
+}
+
+// GetContestTeamConflictUserIds 获取已属于其他队伍的用户，excludeTeamId为修改中的队伍
+func (d *ContestTeamDao) GetContestTeamConflictUserIds(
+	ctx context.Context,
+	contestId int,
+	excludeTeamId int,
+	userIds []int,
+) ([]int, error) {
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+	var conflictIds []int
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ContestTeamMember{}).
+		Where("contest_id = ? AND team_id != ? AND user_id IN ?", contestId, excludeTeamId, userIds).
+		Pluck("user_id", &conflictIds).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest team conflict users, id:%d", contestId)
+	}
+	return conflictIds, nil
+}
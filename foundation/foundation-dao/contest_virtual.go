@@ -0,0 +1,54 @@
+package foundationdao
+
+import (
+	"context"
+	"errors"
+	foundationmodel "foundation/foundation-model"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+
+	"gorm.io/gorm"
+)
+
+type ContestVirtualDao struct {
+	db *gorm.DB
+}
+
+var singletonContestVirtualDao = singleton.Singleton[ContestVirtualDao]{}
+
+func GetContestVirtualDao() *ContestVirtualDao {
+	return singletonContestVirtualDao.GetInstance(
+		func() *ContestVirtualDao {
+			dao := &ContestVirtualDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// InsertContestVirtual 每个用户在一个比赛中只能虚拟参赛一次，重复时因唯一约束失败
+func (d *ContestVirtualDao) InsertContestVirtual(ctx context.Context, virtual *foundationmodel.ContestVirtual) error {
+	if err := d.db.WithContext(ctx).Create(virtual).Error; err != nil {
+		return metaerror.Wrap(err, "failed to insert contest virtual, id:%d", virtual.ContestId)
+	}
+	return nil
+}
+
+func (d *ContestVirtualDao) GetContestVirtual(
+	ctx context.Context,
+	contestId int,
+	userId int,
+) (*foundationmodel.ContestVirtual, error) {
+	var virtual foundationmodel.ContestVirtual
+	err := d.db.WithContext(ctx).
+		Where("contest_id = ? AND user_id = ?", contestId, userId).
+		First(&virtual).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get contest virtual, id:%d", contestId)
+	}
+	return &virtual, nil
+}
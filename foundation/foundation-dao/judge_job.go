@@ -2,8 +2,6 @@ package foundationdao
 
 import (
 	"context"
-	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	foundationenum "foundation/foundation-enum"
@@ -150,7 +148,7 @@ func (d *JudgeJobDao) GetJudgeJobList(
 
 	selectSql := `
 			j.id, j.insert_time, j.language, j.score, j.status,
-			j.time, j.memory, j.problem_id, j.inserter, j.code_length, j.private,
+			j.time, j.memory, j.problem_id, j.inserter, j.code_length, j.private, j.pretest,
 			u.username AS inserter_username, u.nickname AS inserter_nickname, u.email AS inserter_email`
 
 	selectSql += ", p.key as problem_key"
@@ -212,7 +210,15 @@ func (d *JudgeJobDao) GetProblemAttemptStatus(
 	ctx context.Context, inserter int, problemIds []int,
 	contestId int, startTime *time.Time, endTime *time.Time,
 ) (map[int]foundationenum.ProblemAttemptStatus, error) {
-	if len(problemIds) == 0 {
+	return d.GetProblemAttemptStatusByInserters(ctx, []int{inserter}, problemIds, contestId, startTime, endTime)
+}
+
+// GetProblemAttemptStatusByInserters 多个用户共同的尝试状态，用于队伍成员共享题目状态
+func (d *JudgeJobDao) GetProblemAttemptStatusByInserters(
+	ctx context.Context, inserters []int, problemIds []int,
+	contestId int, startTime *time.Time, endTime *time.Time,
+) (map[int]foundationenum.ProblemAttemptStatus, error) {
+	if len(inserters) == 0 || len(problemIds) == 0 {
 		return nil, nil
 	}
 	type Result struct {
@@ -225,7 +231,7 @@ func (d *JudgeJobDao) GetProblemAttemptStatus(
 			"problem_id, MAX(CASE WHEN status = ? THEN 1 ELSE 0 END) AS has_ac, MAX(CASE WHEN status != ? THEN 1 ELSE 0 END) AS has_attempt",
 			foundationjudge.JudgeStatusAC, foundationjudge.JudgeStatusAC,
 		).
-		Where("inserter = ?", inserter).
+		Where("inserter IN ?", inserters).
 		Where("problem_id IN ?", problemIds)
 	if contestId > 0 {
 		db = db.Where("contest_id = ?", contestId)
@@ -617,194 +623,98 @@ func (d *JudgeJobDao) GetUserJudgeJobCountStatics(
 	return statList, nil
 }
 
-func (d *JudgeJobDao) GetContestRanks(
+// GetContestRankSubmissions 获取比赛时间内的全部提交，按提交顺序排列
+func (d *JudgeJobDao) GetContestRankSubmissions(
 	ctx context.Context,
 	contestId int,
 	startTime time.Time,
-	lockTime *time.Time,
-	problemMap map[int]uint8,
-) ([]*foundationview.ContestRank, error) {
-
-	var execSql string
-	var rows *sql.Rows
-	var err error
-
-	if lockTime == nil {
-		// 无锁榜模式
-		execSql = `SELECT 
-    flat.inserter,
-    u.username,
-    u.nickname,
-    u.email,
-    JSON_AGG(
-        JSON_BUILD_OBJECT(
-            'id', flat.problem_id,
-            'attempt', flat.count,
-            'ac', flat.ac
-        )
-    ) AS problems
-FROM (
-    SELECT 
-        j.inserter,
-        j.problem_id,
-        SUM(
-            CASE
-                WHEN fa.ac_id IS NULL OR j.id < fa.ac_id THEN 1
-                ELSE 0
-            END
-        ) AS count,
-        MIN(ac.insert_time) AS ac
-    FROM judge_job j
-    LEFT JOIN (
-        SELECT 
-            inserter, 
-            problem_id, 
-            MIN(id) AS ac_id
-        FROM judge_job
-        WHERE contest_id = $1
-          AND status = $2
-          AND insert_time >= $4
-        GROUP BY inserter, problem_id
-    ) fa ON j.inserter = fa.inserter AND j.problem_id = fa.problem_id
-    LEFT JOIN judge_job ac ON ac.id = fa.ac_id
-    WHERE j.contest_id = $3
-      AND j.insert_time >= $4
-    GROUP BY j.inserter, j.problem_id
-) AS flat
-LEFT JOIN "user" u ON flat.inserter = u.id
-GROUP BY flat.inserter, u.username, u.nickname, u.email;`
-
-		rows, err = d.db.WithContext(ctx).
-			Raw(execSql, contestId, foundationjudge.JudgeStatusAC, contestId, startTime).
-			Rows()
-
-	} else {
-		// 锁榜模式
-		execSql = `SELECT 
-    flat.inserter,
-    u.username,
-    u.nickname,
-    u.email,
-    JSON_AGG(
-        JSON_BUILD_OBJECT(
-            'id', flat.problem_id,
-            'attempt', flat.count_before,
-            'lock', flat.count_lock,
-            'ac', flat.ac
-        )
-    ) AS problems
-FROM (
-    SELECT 
-        j.inserter,
-        j.problem_id,
-        SUM(
-            CASE
-                WHEN (fa.ac_id IS NULL AND j.insert_time < $1) OR (fa.ac_id IS NOT NULL AND j.id < fa.ac_id) THEN 1
-                ELSE 0
-            END
-        ) AS count_before,
-        SUM(
-            CASE
-                WHEN fa.ac_id IS NULL AND j.insert_time >= $2 THEN 1
-                ELSE 0
-            END
-        ) AS count_lock,
-        MIN(ac.insert_time) AS ac
-    FROM judge_job j
-    LEFT JOIN (
-        SELECT 
-            inserter, 
-            problem_id, 
-            MIN(id) AS ac_id
-        FROM judge_job
-        WHERE contest_id = $3
-          AND status = $4
-          AND insert_time >= $7
-          AND insert_time < $5
-        GROUP BY inserter, problem_id
-    ) fa ON j.inserter = fa.inserter AND j.problem_id = fa.problem_id
-    LEFT JOIN judge_job ac ON ac.id = fa.ac_id
-    WHERE j.contest_id = $6
-      AND j.insert_time >= $7
-    GROUP BY j.inserter, j.problem_id
-) AS flat
-LEFT JOIN "user" u ON flat.inserter = u.id
-GROUP BY flat.inserter, u.username, u.nickname, u.email;`
-
-		rows, err = d.db.WithContext(ctx).Raw(
-			execSql,
-			lockTime,
-			lockTime,
-			contestId,
-			foundationjudge.JudgeStatusAC,
-			lockTime,
-			contestId,
-			startTime,
-		).Rows()
-	}
-
+	endTime time.Time,
+) ([]*foundationview.ContestRankSubmission, error) {
+	var submissions []*foundationview.ContestRankSubmission
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.JudgeJob{}).
+		Select("id, inserter, problem_id, status, score, insert_time, pretest").
+		Where("contest_id = ? AND insert_time >= ? AND insert_time < ?", contestId, startTime, endTime).
+		Order("id").
+		Find(&submissions).Error
 	if err != nil {
-		return nil, metaerror.Wrap(err)
+		return nil, metaerror.Wrap(err, "failed to get contest rank submissions, id:%d", contestId)
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-			metapanic.ProcessError(metaerror.Wrap(err, "failed to close rows,id:%d"), contestId)
-		}
-	}(rows)
+	return submissions, nil
+}
 
+// GetContestRankUsers 获取比赛时间内有提交的用户信息
+func (d *JudgeJobDao) GetContestRankUsers(
+	ctx context.Context,
+	contestId int,
+	startTime time.Time,
+	endTime time.Time,
+) ([]*foundationview.ContestRank, error) {
 	var ranks []*foundationview.ContestRank
-
-	for rows.Next() {
-		var rank foundationview.ContestRank
-		var jsonProblems json.RawMessage
-		err := rows.Scan(
-			&rank.Inserter,
-			&rank.InserterUsername,
-			&rank.InserterNickname,
-			&rank.InserterEmail,
-			&jsonProblems,
-		)
-		if err != nil {
-			return nil, metaerror.Wrap(err, "failed to scan row")
-		}
-		err = json.Unmarshal(jsonProblems, &rank.Problems)
-		if err != nil {
-			return nil, metaerror.Wrap(err, "failed to unmarshal problems")
-		}
-		for _, problem := range rank.Problems {
-			problem.Index = problemMap[problem.Id]
-			problem.Id = 0
-		}
-		ranks = append(ranks, &rank)
+	err := d.db.WithContext(ctx).
+		Raw(
+			`
+SELECT u.id AS inserter, u.username AS inserter_username, u.nickname AS inserter_nickname, u.email AS inserter_email
+FROM "user" AS u
+WHERE u.id IN (
+	SELECT DISTINCT inserter
+	FROM judge_job
+	WHERE contest_id = ? AND insert_time >= ? AND insert_time < ?
+)
+`, contestId, startTime, endTime,
+		).
+		Scan(&ranks).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest rank users, id:%d", contestId)
 	}
 	return ranks, nil
 }
 
-func (d *JudgeJobDao) GetJudgeJobCountNotFinish(ctx context.Context) (int, error) {
-	var count int64
+// GetContestCcsSubmissions 获取比赛时间内的提交，用于导出CCS数据
+func (d *JudgeJobDao) GetContestCcsSubmissions(
+	ctx context.Context,
+	contestId int,
+	startTime time.Time,
+	endTime time.Time,
+) ([]*foundationview.ContestCcsSubmissionRow, error) {
+	var submissions []*foundationview.ContestCcsSubmissionRow
 	err := d.db.WithContext(ctx).
 		Model(&foundationmodel.JudgeJob{}).
-		Where("status <= ?", foundationjudge.JudgeStatusRunning).
-		Count(&count).Error
+		Select("id, inserter, problem_id, language, status, time, judge_time, insert_time").
+		Where("contest_id = ? AND insert_time >= ? AND insert_time < ?", contestId, startTime, endTime).
+		Order("id").
+		Find(&submissions).Error
 	if err != nil {
-		return 0, metaerror.Wrap(err, "failed to count judge jobs")
+		return nil, metaerror.Wrap(err, "failed to get contest ccs submissions, id:%d", contestId)
 	}
-	return int(count), nil
+	return submissions, nil
 }
 
-func (d *JudgeJobDao) ForeachContestAcCodes(
+// ForeachContestExportSubmissions 按提交顺序逐条读取比赛的全部提交，数据量大时不会一次加载到内存
+func (d *JudgeJobDao) ForeachContestExportSubmissions(
 	ctx context.Context,
 	contestId int,
-	handleCode func(judgeId int, code string, problemId string, createTime time.Time, authorId int) error,
+	handle func(submission *foundationview.ContestExportSubmission) error,
 ) error {
 	rows, err := d.db.WithContext(ctx).
-		Table("judge_job").
-		Select("id, code, problem_id, insert_time, inserter").
-		Where("contest_id = ? AND status = ?", contestId, foundationjudge.JudgeStatusAC).
+		Table("judge_job AS j").
+		Select(
+			`
+			j.id, j.inserter, u.username, u.nickname, m.contest_name, t.name AS team_name,
+			cp.index AS problem_index, j.language, j.status, COALESCE(j.score, 0) AS score,
+			COALESCE(j.time, 0) AS time, COALESCE(j.memory, 0) AS memory, j.code_length, j.insert_time,
+			j.virtual_id IS NOT NULL AS virtual`,
+		).
+		Joins(`LEFT JOIN "user" AS u ON u.id = j.inserter`).
+		Joins("LEFT JOIN contest_member AS m ON m.id = j.contest_id AND m.user_id = j.inserter").
+		Joins("LEFT JOIN contest_team_member AS tm ON tm.contest_id = j.contest_id AND tm.user_id = j.inserter").
+		Joins("LEFT JOIN contest_team AS t ON t.id = tm.team_id").
+		Joins("LEFT JOIN contest_problem AS cp ON cp.id = j.contest_id AND cp.problem_id = j.problem_id").
+		Where("j.contest_id = ?", contestId).
+		Order("j.id").
 		Rows()
 	if err != nil {
-		return fmt.Errorf("failed to query submissions: %w", err)
+		return metaerror.Wrap(err, "failed to query contest export submissions, id:%d", contestId)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -812,21 +722,212 @@ func (d *JudgeJobDao) ForeachContestAcCodes(
 		}
 	}()
 	for rows.Next() {
-		var (
-			id         int
-			code       string
-			problemId  string
-			insertTime time.Time
-			inserter   int
-		)
-		if err := rows.Scan(&id, &code, &problemId, &insertTime, &inserter); err != nil {
-			return metaerror.Wrap(err, "failed to scan row")
+		var submission foundationview.ContestExportSubmission
+		if err := d.db.ScanRows(rows, &submission); err != nil {
+			return metaerror.Wrap(err, "failed to scan contest export submission")
 		}
-		if err := handleCode(id, code, problemId, insertTime, inserter); err != nil {
-			return metaerror.Wrap(err, "failed to handle code")
+		if err := handle(&submission); err != nil {
+			return err
 		}
 	}
-	return nil
+	return rows.Err()
+}
+
+// ForeachContestExportCodes 逐条读取每个用户每道题的最后一次正式提交
+func (d *JudgeJobDao) ForeachContestExportCodes(
+	ctx context.Context,
+	contestId int,
+	handle func(code *foundationview.ContestExportCode) error,
+) error {
+	rows, err := d.db.WithContext(ctx).
+		Table("judge_job AS j").
+		Select(
+			`
+			DISTINCT ON (j.inserter, cp.index) j.id, j.inserter, u.username, cp.index AS problem_index,
+			j.language, j.status, j.code`,
+		).
+		Joins(`LEFT JOIN "user" AS u ON u.id = j.inserter`).
+		Joins("JOIN contest_problem AS cp ON cp.id = j.contest_id AND cp.problem_id = j.problem_id").
+		Where("j.contest_id = ? AND j.virtual_id IS NULL", contestId).
+		Order("j.inserter, cp.index, j.id DESC").
+		Rows()
+	if err != nil {
+		return metaerror.Wrap(err, "failed to query contest export codes, id:%d", contestId)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			metapanic.ProcessError(metaerror.Wrap(err, "failed to close rows"))
+		}
+	}()
+	for rows.Next() {
+		var code foundationview.ContestExportCode
+		if err := d.db.ScanRows(rows, &code); err != nil {
+			return metaerror.Wrap(err, "failed to scan contest export code")
+		}
+		if err := handle(&code); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetContestVirtualRankSubmissions 获取虚拟参赛的提交，提交时间换算为原比赛中的时间
+// elapsed不为空时只返回虚拟参赛开始后elapsed之内的提交
+func (d *JudgeJobDao) GetContestVirtualRankSubmissions(
+	ctx context.Context,
+	contestId int,
+	startTime time.Time,
+	elapsed *time.Duration,
+) ([]*foundationview.ContestRankSubmission, error) {
+	db := d.db.WithContext(ctx).
+		Table("judge_job AS j").
+		Select(
+			"j.id, j.inserter, j.problem_id, j.status, j.score, ?::timestamptz + (j.insert_time - v.start_time) AS insert_time, TRUE AS virtual",
+			startTime,
+		).
+		Joins("JOIN contest_virtual AS v ON v.id = j.virtual_id").
+		Where("j.contest_id = ? AND j.insert_time >= v.start_time AND j.insert_time < v.end_time", contestId)
+	if elapsed != nil {
+		db = db.Where("j.insert_time < v.start_time + ?::interval", elapsed.String())
+	}
+	var submissions []*foundationview.ContestRankSubmission
+	if err := db.Order("j.id").Scan(&submissions).Error; err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest virtual rank submissions, id:%d", contestId)
+	}
+	return submissions, nil
+}
+
+// getContestSystemTestJobIdsWithTx 获取每个用户每道题最后一次通过预测试的提交，系统测试时重新评测
+func getContestSystemTestJobIdsWithTx(tx *gorm.DB, contestId int, startTime time.Time, endTime time.Time) ([]int, error) {
+	var ids []int
+	err := tx.
+		Raw(
+			`
+			SELECT DISTINCT ON (inserter, problem_id) id
+			FROM judge_job
+			WHERE contest_id = ? AND virtual_id IS NULL AND pretest AND status = ?
+			AND insert_time >= ? AND insert_time < ?
+			ORDER BY inserter, problem_id, id DESC
+		`,
+			contestId, foundationjudge.JudgeStatusAC, startTime, endTime,
+		).
+		Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// HasContestRankSubmission 用户是否在比赛时间内有正式提交
+func (d *JudgeJobDao) HasContestRankSubmission(
+	ctx context.Context,
+	contestId int,
+	userId int,
+	startTime time.Time,
+	endTime time.Time,
+) (bool, error) {
+	var exists int
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.JudgeJob{}).
+		Select("1").
+		Where("contest_id = ? AND inserter = ? AND virtual_id IS NULL", contestId, userId).
+		Where("insert_time >= ? AND insert_time < ?", startTime, endTime).
+		Limit(1).
+		Scan(&exists).Error
+	if err != nil {
+		return false, metaerror.Wrap(err, "failed to check contest rank submission, id:%d", contestId)
+	}
+	return exists == 1, nil
+}
+
+// GetContestRankSubmissionsByIds 获取指定的提交，用于增量更新榜单
+func (d *JudgeJobDao) GetContestRankSubmissionsByIds(
+	ctx context.Context,
+	ids []int,
+) ([]*foundationview.ContestRankSubmission, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var submissions []*foundationview.ContestRankSubmission
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.JudgeJob{}).
+		Select("id, inserter, problem_id, status, score, insert_time, pretest").
+		Where("id IN ?", ids).
+		Find(&submissions).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest rank submissions by ids")
+	}
+	return submissions, nil
+}
+
+// GetContestRankUsersByIds 获取榜单中新出现的用户信息
+func (d *JudgeJobDao) GetContestRankUsersByIds(
+	ctx context.Context,
+	userIds []int,
+) ([]*foundationview.ContestRank, error) {
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+	var ranks []*foundationview.ContestRank
+	err := d.db.WithContext(ctx).
+		Raw(
+			`
+SELECT u.id AS inserter, u.username AS inserter_username, u.nickname AS inserter_nickname, u.email AS inserter_email
+FROM "user" AS u
+WHERE u.id IN ?
+`, userIds,
+		).
+		Scan(&ranks).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest rank users by ids")
+	}
+	return ranks, nil
+}
+
+func (d *JudgeJobDao) GetJudgeJobCountNotFinish(ctx context.Context) (int, error) {
+	var count int64
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.JudgeJob{}).
+		Where("status <= ?", foundationjudge.JudgeStatusRunning).
+		Count(&count).Error
+	if err != nil {
+		return 0, metaerror.Wrap(err, "failed to count judge jobs")
+	}
+	return int(count), nil
+}
+
+// GetSimilaritySubmissions 获取范围内每个用户每道题最后一次通过的代码，最多返回limit份
+func (d *JudgeJobDao) GetSimilaritySubmissions(
+	ctx context.Context,
+	scopeType foundationenum.SimilarityScopeType,
+	scopeId int,
+	limit int,
+) ([]*foundationview.SimilaritySubmission, error) {
+	db := d.db.WithContext(ctx).
+		Table("judge_job AS j").
+		Select("DISTINCT ON (j.problem_id, j.inserter) j.id, j.problem_id, j.inserter, j.language, j.code, j.insert_time").
+		Where("j.status = ?", foundationjudge.JudgeStatusAC)
+	switch scopeType {
+	case foundationenum.SimilarityScopeTypeProblem:
+		db = db.Where("j.problem_id = ?", scopeId)
+	case foundationenum.SimilarityScopeTypeContest:
+		db = db.Where("j.contest_id = ?", scopeId)
+	case foundationenum.SimilarityScopeTypeCollection:
+		db = db.
+			Joins("JOIN collection AS c ON c.id = ?", scopeId).
+			Where("j.problem_id IN (SELECT problem_id FROM collection_problem WHERE id = ?)", scopeId).
+			Where("j.inserter IN (SELECT user_id FROM collection_member WHERE id = ?)", scopeId).
+			Where("(c.start_time IS NULL OR j.insert_time >= c.start_time)").
+			Where("(c.end_time IS NULL OR j.insert_time <= c.end_time)")
+	default:
+		return nil, metaerror.New("unknown similarity scope type:%d", scopeType)
+	}
+	var submissions []*foundationview.SimilaritySubmission
+	err := db.Order("j.problem_id, j.inserter, j.id DESC").Limit(limit).Scan(&submissions).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get similarity submissions, scope:%d", scopeId)
+	}
+	return submissions, nil
 }
 
 // RequestLocalJudgeJobListPendingJudge 获取待本地评测的 JudgeJob 列表，优先取最小的
@@ -1035,6 +1136,18 @@ func (d *JudgeJobDao) MarkJudgeJobRemoteSubmit(
 	return nil
 }
 
+// MarkJudgeJobPretest 标记本次评测是否只评测了预测试
+func (d *JudgeJobDao) MarkJudgeJobPretest(ctx context.Context, id int, judger string, pretest bool) error {
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.JudgeJob{}).
+		Where("id = ? AND judger = ?", id, judger).
+		Update("pretest", pretest).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to mark judge job pretest")
+	}
+	return nil
+}
+
 func (d *JudgeJobDao) MarkJudgeJobTaskTotal(ctx context.Context, id int, judger string, taskTotalCount int) error {
 	err := d.db.WithContext(ctx).
 		Model(&foundationmodel.JudgeJob{}).
@@ -1136,92 +1249,111 @@ func (d *JudgeJobDao) MarkJudgeJobJudgeFinalStatus(
 					return metaerror.Wrap(err, "failed to update user accept count")
 				}
 
-				return nil
+				if err := insertContestBalloonByJobWithTx(tx, id); err != nil {
+					return err
+				}
+				return insertContestRankChangeByJobsWithTx(tx, []int{id}, false)
 			},
 		)
 	} else {
 		// 非 AC 情况下，只更新 judge_job 状态
-		return markStatusFunc(d.db.WithContext(ctx))
+		return d.db.WithContext(ctx).Transaction(
+			func(tx *gorm.DB) error {
+				if err := markStatusFunc(tx); err != nil {
+					return err
+				}
+				return insertContestRankChangeByJobsWithTx(tx, []int{id}, false)
+			},
+		)
 	}
 }
 
 func (d *JudgeJobDao) RejudgeJob(ctx context.Context, id int) error {
 	return d.db.WithContext(ctx).Transaction(
 		func(tx *gorm.DB) error {
-			// 1. 加锁查找 judge_job（防止并发修改）
-			var job struct {
-				ID        int                         `gorm:"column:id"`
-				ProblemId int                         `gorm:"column:problem_id"`
-				Inserter  int                         `gorm:"column:inserter"`
-				Status    foundationjudge.JudgeStatus `gorm:"column:status"`
-			}
-			if err := tx.Table("judge_job").
-				Select("id, problem_id, inserter, status").
-				Where("id = ?", id).
-				Clauses(clause.Locking{Strength: "UPDATE"}). // 加锁
-				First(&job).Error; err != nil {
-				return metaerror.Wrap(err, "find judge_job error")
-			}
+			return rejudgeJobWithTx(tx, id)
+		},
+	)
+}
 
-			// 2. 计算更新偏移
-			problemAcceptDelta := 0
-			userAcceptDelta := 0
-			if job.Status == foundationjudge.JudgeStatusAC {
-				problemAcceptDelta--
-				userAcceptDelta--
-			}
+// rejudgeJobWithTx 在事务中将提交改为等待重判，并撤销已计入的通过数
+func rejudgeJobWithTx(tx *gorm.DB, id int) error {
+	// 1. 加锁查找 judge_job（防止并发修改）
+	var job struct {
+		ID        int                         `gorm:"column:id"`
+		ProblemId int                         `gorm:"column:problem_id"`
+		Inserter  int                         `gorm:"column:inserter"`
+		Status    foundationjudge.JudgeStatus `gorm:"column:status"`
+	}
+	if err := tx.Table("judge_job").
+		Select("id, problem_id, inserter, status").
+		Where("id = ?", id).
+		Clauses(clause.Locking{Strength: "UPDATE"}). // 加锁
+		First(&job).Error; err != nil {
+		return metaerror.Wrap(err, "find judge_job error")
+	}
+
+	// 2. 计算更新偏移
+	problemAcceptDelta := 0
+	userAcceptDelta := 0
+	if job.Status == foundationjudge.JudgeStatusAC {
+		problemAcceptDelta--
+		userAcceptDelta--
+	}
+
+	// 3. 更新 judge_job
+	updateMap := map[string]interface{}{
+		"status": foundationjudge.JudgeStatusRejudge,
+		"score":  nil, "time": nil, "memory": nil,
+		"task_current":      nil,
+		"task_total":        nil,
+		"judger":            nil,
+		"judge_time":        nil,
+		"remote_judge_id":   nil,
+		"remote_account_id": nil,
+	}
+	if err := tx.Table("judge_job").
+		Where("id = ?", id).
+		Updates(updateMap).Error; err != nil {
+		return metaerror.Wrap(err, "failed to update judge_job")
+	}
 
-			// 3. 更新 judge_job
-			updateMap := map[string]interface{}{
-				"status": foundationjudge.JudgeStatusRejudge,
-				"score":  nil, "time": nil, "memory": nil,
-				"task_current":      nil,
-				"task_total":        nil,
-				"judger":            nil,
-				"judge_time":        nil,
-				"remote_judge_id":   nil,
-				"remote_account_id": nil,
-			}
-			if err := tx.Table("judge_job").
-				Where("id = ?", id).
-				Updates(updateMap).Error; err != nil {
-				return metaerror.Wrap(err, "failed to update judge_job")
-			}
+	// 4. 删除 judge_job_compile 中对应记录
+	if err := tx.Table("judge_job_compile").
+		Where("id = ?", id).
+		Delete(nil).Error; err != nil {
+		return metaerror.Wrap(err, "failed to delete compile message")
+	}
 
-			// 4. 删除 judge_job_compile 中对应记录
-			if err := tx.Table("judge_job_compile").
-				Where("id = ?", id).
-				Delete(nil).Error; err != nil {
-				return metaerror.Wrap(err, "failed to delete compile message")
-			}
+	if err := insertContestRankChangeByJobsWithTx(tx, []int{id}, true); err != nil {
+		return err
+	}
 
-			// 5. 删除 judge_task 中对应记录
-			if err := tx.Table("judge_task").
-				Where("id = ?", id).
-				Delete(nil).Error; err != nil {
-				return metaerror.Wrap(err, "failed to delete judge_task")
-			}
+	// 5. 删除 judge_task 中对应记录
+	if err := tx.Table("judge_task").
+		Where("id = ?", id).
+		Delete(nil).Error; err != nil {
+		return metaerror.Wrap(err, "failed to delete judge_task")
+	}
 
-			// 6. 更新 problem.accept
-			if problemAcceptDelta != 0 {
-				if err := tx.Table("problem").
-					Where("id = ?", job.ProblemId).
-					Update("accept", gorm.Expr("accept + ?", problemAcceptDelta)).Error; err != nil {
-					return metaerror.Wrap(err, "failed to update problem accept count")
-				}
-			}
+	// 6. 更新 problem.accept
+	if problemAcceptDelta != 0 {
+		if err := tx.Table("problem").
+			Where("id = ?", job.ProblemId).
+			Update("accept", gorm.Expr("accept + ?", problemAcceptDelta)).Error; err != nil {
+			return metaerror.Wrap(err, "failed to update problem accept count")
+		}
+	}
 
-			// 7. 更新 user.accept
-			if userAcceptDelta != 0 {
-				if err := tx.Table("user").
-					Where("id = ?", job.Inserter).
-					Update("accept", gorm.Expr("accept + ?", userAcceptDelta)).Error; err != nil {
-					return metaerror.Wrap(err, "failed to update user accept count")
-				}
-			}
-			return nil
-		},
-	)
+	// 7. 更新 user.accept
+	if userAcceptDelta != 0 {
+		if err := tx.Table("user").
+			Where("id = ?", job.Inserter).
+			Update("accept", gorm.Expr("accept + ?", userAcceptDelta)).Error; err != nil {
+			return metaerror.Wrap(err, "failed to update user accept count")
+		}
+	}
+	return nil
 }
 
 func (d *JudgeJobDao) RejudgeSearch(
@@ -1316,6 +1448,10 @@ func (d *JudgeJobDao) RejudgeSearch(
 					Delete(nil).Error; err != nil {
 					return metaerror.Wrap(err, "failed to delete judge_task batch")
 				}
+
+				if err := insertContestRankChangeByJobsWithTx(tx, batch, true); err != nil {
+					return err
+				}
 			}
 
 			// 4. 更新 problem 和 user 的 accept 计数
@@ -1412,6 +1548,10 @@ func (d *JudgeJobDao) RejudgeRecently(ctx context.Context) error {
 				return metaerror.Wrap(err, "failed to delete judge_task")
 			}
 
+			if err := insertContestRankChangeByJobsWithTx(tx, ids, true); err != nil {
+				return err
+			}
+
 			for pid, delta := range problemAcceptDelta {
 				if delta != 0 {
 					if err := tx.Table("problem").
@@ -1511,6 +1651,10 @@ func (d *JudgeJobDao) RejudgeAll(ctx context.Context) error {
 					Delete(nil).Error; err != nil {
 					return metaerror.Wrap(err, "failed to delete judge_task batch")
 				}
+
+				if err := insertContestRankChangeByJobsWithTx(tx, batch, true); err != nil {
+					return err
+				}
 			}
 
 			// 4. 更新 problem 和 user 的 accept 计数
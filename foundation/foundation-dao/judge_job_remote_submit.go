@@ -0,0 +1,117 @@
+package foundationdao
+
+import (
+	"context"
+	"errors"
+	foundationmodel "foundation/foundation-model"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	metatime "meta/meta-time"
+	"meta/singleton"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JudgeJobRemoteSubmitDao struct {
+	db *gorm.DB
+}
+
+var singletonJudgeJobRemoteSubmitDao = singleton.Singleton[JudgeJobRemoteSubmitDao]{}
+
+func GetJudgeJobRemoteSubmitDao() *JudgeJobRemoteSubmitDao {
+	return singletonJudgeJobRemoteSubmitDao.GetInstance(
+		func() *JudgeJobRemoteSubmitDao {
+			dao := &JudgeJobRemoteSubmitDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+func (d *JudgeJobRemoteSubmitDao) GetJudgeJobRemoteSubmit(
+	ctx context.Context,
+	id int,
+) (*foundationmodel.JudgeJobRemoteSubmit, error) {
+	var submit foundationmodel.JudgeJobRemoteSubmit
+	err := d.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&submit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get judge job remote submit")
+	}
+	return &submit, nil
+}
+
+// StartJudgeJobRemoteSubmit 在真正提交前记录指纹，指纹不变时累加提交次数，否则重新计数
+func (d *JudgeJobRemoteSubmitDao) StartJudgeJobRemoteSubmit(
+	ctx context.Context,
+	id int,
+	originOj string,
+	fingerprint string,
+	submitTime time.Time,
+) error {
+	err := d.db.WithContext(ctx).
+		Exec(
+			`
+INSERT INTO judge_job_remote_submit (id, origin_oj, fingerprint, attempt, submit_time, run_id, account, modify_time)
+VALUES (?, ?, ?, 1, ?, NULL, NULL, ?)
+ON CONFLICT (id) DO UPDATE
+SET origin_oj = EXCLUDED.origin_oj,
+    attempt = CASE WHEN judge_job_remote_submit.fingerprint = EXCLUDED.fingerprint
+        THEN judge_job_remote_submit.attempt + 1 ELSE 1 END,
+    fingerprint = EXCLUDED.fingerprint,
+    submit_time = EXCLUDED.submit_time,
+    run_id = NULL,
+    account = NULL,
+    modify_time = EXCLUDED.modify_time;
+			`, id, originOj, fingerprint, submitTime, metatime.GetTimeNow(),
+		).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to start judge job remote submit")
+	}
+	return nil
+}
+
+func (d *JudgeJobRemoteSubmitDao) MarkJudgeJobRemoteSubmitRunId(
+	ctx context.Context,
+	id int,
+	runId string,
+	account string,
+) error {
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.JudgeJobRemoteSubmit{}).
+		Where("id = ?", id).
+		Updates(
+			map[string]interface{}{
+				"run_id":      runId,
+				"account":     account,
+				"modify_time": metatime.GetTimeNow(),
+			},
+		).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to mark judge job remote submit run id")
+	}
+	return nil
+}
+
+// GetJudgeJobRemoteSubmitRunIds 获取某个时间之后其他评测任务已经占用的RunId
+func (d *JudgeJobRemoteSubmitDao) GetJudgeJobRemoteSubmitRunIds(
+	ctx context.Context,
+	originOj string,
+	since time.Time,
+	excludeId int,
+) ([]string, error) {
+	var runIds []string
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.JudgeJobRemoteSubmit{}).
+		Where("origin_oj = ? AND submit_time >= ? AND id <> ? AND run_id IS NOT NULL", originOj, since, excludeId).
+		Pluck("run_id", &runIds).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get judge job remote submit run ids")
+	}
+	return runIds, nil
+}
@@ -483,6 +483,54 @@ func (d *ProblemDao) GetProblemViewForRemoteJudge(ctx context.Context, id int) (
 	return &problem, nil
 }
 
+// GetProblemRemoteOriginIds 返回指定OJ下已经存在的远程题目Id
+func (d *ProblemDao) GetProblemRemoteOriginIds(ctx context.Context, originOj string, originIds []string) (
+	[]string,
+	error,
+) {
+	if len(originIds) == 0 {
+		return nil, nil
+	}
+	var existIds []string
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ProblemRemote{}).
+		Where("LOWER(origin_oj) = LOWER(?)", originOj).
+		Where("origin_id IN ?", originIds).
+		Pluck("origin_id", &existIds).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "find problem remote origin ids failed")
+	}
+	return existIds, nil
+}
+
+// GetProblemRemoteProblemIds 返回指定OJ下已经存在的远程题目Id与本站题目Id的对应关系
+func (d *ProblemDao) GetProblemRemoteProblemIds(ctx context.Context, originOj string, originIds []string) (
+	map[string]int,
+	error,
+) {
+	result := make(map[string]int)
+	if len(originIds) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		ProblemId int
+		OriginId  string
+	}
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ProblemRemote{}).
+		Select("problem_id, origin_id").
+		Where("LOWER(origin_oj) = LOWER(?)", originOj).
+		Where("origin_id IN ?", originIds).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "find problem remote problem ids failed")
+	}
+	for _, row := range rows {
+		result[row.OriginId] = row.ProblemId
+	}
+	return result, nil
+}
+
 func (d *ProblemDao) GetProblemViewJudgeData(ctx context.Context, id int) (*foundationview.ProblemJudgeData, error) {
 	db := d.db.WithContext(ctx).Table("problem AS p").
 		Select(
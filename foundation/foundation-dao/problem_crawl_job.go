@@ -0,0 +1,206 @@
+package foundationdao
+
+import (
+	"context"
+	"errors"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	metatime "meta/meta-time"
+	"meta/singleton"
+
+	"gorm.io/gorm"
+)
+
+type ProblemCrawlJobDao struct {
+	db *gorm.DB
+}
+
+var singletonProblemCrawlJobDao = singleton.Singleton[ProblemCrawlJobDao]{}
+
+func GetProblemCrawlJobDao() *ProblemCrawlJobDao {
+	return singletonProblemCrawlJobDao.GetInstance(
+		func() *ProblemCrawlJobDao {
+			dao := &ProblemCrawlJobDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+func (d *ProblemCrawlJobDao) InsertProblemCrawlJob(
+	ctx context.Context,
+	job *foundationmodel.ProblemCrawlJob,
+	items []*foundationmodel.ProblemCrawlJobItem,
+) error {
+	if job == nil {
+		return metaerror.New("job is nil")
+	}
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Create(job).Error; err != nil {
+				return metaerror.Wrap(err, "insert problem crawl job")
+			}
+			if len(items) == 0 {
+				return nil
+			}
+			for _, item := range items {
+				item.Id = job.Id
+			}
+			if err := tx.CreateInBatches(items, 500).Error; err != nil {
+				return metaerror.Wrap(err, "insert problem crawl job items")
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "transaction failed")
+	}
+	return nil
+}
+
+func (d *ProblemCrawlJobDao) GetProblemCrawlJob(ctx context.Context, id int) (*foundationview.ProblemCrawlJob, error) {
+	var job foundationview.ProblemCrawlJob
+	err := d.db.WithContext(ctx).Table("problem_crawl_job AS j").
+		Select(
+			`
+			j.*,
+			u.username AS inserter_username, u.nickname AS inserter_nickname
+		`,
+		).
+		Joins(`LEFT JOIN "user" u ON u.id = j.inserter`).
+		Where("j.id = ?", id).
+		Take(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "find problem crawl job error")
+	}
+	var statistics []struct {
+		Status foundationenum.ProblemCrawlStatus
+		Count  int
+	}
+	err = d.db.WithContext(ctx).
+		Model(&foundationmodel.ProblemCrawlJobItem{}).
+		Select("status, COUNT(*) AS count").
+		Where("id = ?", id).
+		Group("status").
+		Scan(&statistics).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "count problem crawl job items error")
+	}
+	job.Statistics = make(map[foundationenum.ProblemCrawlStatus]int)
+	for _, statistic := range statistics {
+		job.Statistics[statistic.Status] = statistic.Count
+	}
+	return &job, nil
+}
+
+func (d *ProblemCrawlJobDao) GetProblemCrawlJobItems(
+	ctx context.Context,
+	id int,
+	statuses []foundationenum.ProblemCrawlStatus,
+) ([]*foundationmodel.ProblemCrawlJobItem, error) {
+	db := d.db.WithContext(ctx).
+		Model(&foundationmodel.ProblemCrawlJobItem{}).
+		Where("id = ?", id)
+	if len(statuses) > 0 {
+		db = db.Where("status IN ?", statuses)
+	}
+	var items []*foundationmodel.ProblemCrawlJobItem
+	err := db.Order("LENGTH(origin_id) ASC, origin_id ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "find problem crawl job items error")
+	}
+	return items, nil
+}
+
+// RequestProblemCrawlJobItemPending 领取一个待爬取的题目，优先取最早的任务
+func (d *ProblemCrawlJobDao) RequestProblemCrawlJobItemPending(ctx context.Context) (
+	*foundationview.ProblemCrawlJobItemPending,
+	error,
+) {
+	var item *foundationview.ProblemCrawlJobItemPending
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var pending foundationview.ProblemCrawlJobItemPending
+			execSql := `
+			SELECT i.id, j.origin_oj, i.origin_id
+			FROM problem_crawl_job_item AS i
+			JOIN problem_crawl_job AS j ON j.id = i.id
+			WHERE i.status = ?
+			ORDER BY i.id, LENGTH(i.origin_id), i.origin_id
+			LIMIT 1 FOR UPDATE OF i SKIP LOCKED
+		`
+			res := tx.Raw(execSql, foundationenum.ProblemCrawlStatusInit).Scan(&pending)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return nil // 没有任务可领取
+			}
+			if err := tx.Model(&foundationmodel.ProblemCrawlJobItem{}).
+				Where("id = ? AND origin_id = ?", pending.Id, pending.OriginId).
+				Updates(
+					map[string]interface{}{
+						"status":      foundationenum.ProblemCrawlStatusRunning,
+						"modify_time": metatime.GetTimeNow(),
+					},
+				).Error; err != nil {
+				return err
+			}
+			item = &pending
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "request problem crawl job item failed")
+	}
+	return item, nil
+}
+
+func (d *ProblemCrawlJobDao) MarkProblemCrawlJobItemStatus(
+	ctx context.Context,
+	id int,
+	originId string,
+	status foundationenum.ProblemCrawlStatus,
+	problemKey *string,
+	message *string,
+) error {
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ProblemCrawlJobItem{}).
+		Where("id = ? AND origin_id = ?", id, originId).
+		Updates(
+			map[string]interface{}{
+				"status":      status,
+				"problem_key": problemKey,
+				"message":     message,
+				"modify_time": metatime.GetTimeNow(),
+			},
+		).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to mark problem crawl job item status")
+	}
+	return nil
+}
+
+// ResetProblemCrawlJobItemRunning 服务重启时把中断的爬取重新放回队列
+func (d *ProblemCrawlJobDao) ResetProblemCrawlJobItemRunning(ctx context.Context) error {
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.ProblemCrawlJobItem{}).
+		Where("status = ?", foundationenum.ProblemCrawlStatusRunning).
+		Updates(
+			map[string]interface{}{
+				"status":      foundationenum.ProblemCrawlStatusInit,
+				"modify_time": metatime.GetTimeNow(),
+			},
+		).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to reset problem crawl job items")
+	}
+	return nil
+}
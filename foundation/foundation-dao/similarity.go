@@ -0,0 +1,209 @@
+package foundationdao
+
+import (
+	"context"
+	"errors"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	metatime "meta/meta-time"
+	"meta/singleton"
+
+	"gorm.io/gorm"
+)
+
+type SimilarityDao struct {
+	db *gorm.DB
+}
+
+var singletonSimilarityDao = singleton.Singleton[SimilarityDao]{}
+
+func GetSimilarityDao() *SimilarityDao {
+	return singletonSimilarityDao.GetInstance(
+		func() *SimilarityDao {
+			dao := &SimilarityDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+func (d *SimilarityDao) InsertSimilarityReport(ctx context.Context, report *foundationmodel.SimilarityReport) error {
+	if err := d.db.WithContext(ctx).Create(report).Error; err != nil {
+		return metaerror.Wrap(err, "failed to insert similarity report, scope:%d", report.ScopeId)
+	}
+	return nil
+}
+
+// RequestSimilarityReportPending 领取一个待检测的报告，优先取最早的
+func (d *SimilarityDao) RequestSimilarityReportPending(ctx context.Context) (*foundationmodel.SimilarityReport, error) {
+	var report *foundationmodel.SimilarityReport
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var pending foundationmodel.SimilarityReport
+			execSql := `
+			SELECT *
+			FROM similarity_report
+			WHERE status = ?
+			ORDER BY id
+			LIMIT 1 FOR UPDATE SKIP LOCKED
+		`
+			res := tx.Raw(execSql, foundationenum.SimilarityReportStatusPending).Scan(&pending)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return nil // 没有任务可领取
+			}
+			if err := tx.Model(&foundationmodel.SimilarityReport{}).
+				Where("id = ?", pending.Id).
+				Update("status", foundationenum.SimilarityReportStatusRunning).Error; err != nil {
+				return err
+			}
+			pending.Status = foundationenum.SimilarityReportStatusRunning
+			report = &pending
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "request similarity report failed")
+	}
+	return report, nil
+}
+
+// ResetSimilarityReportRunning 服务重启时把中断的检测重新放回队列
+func (d *SimilarityDao) ResetSimilarityReportRunning(ctx context.Context) error {
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.SimilarityReport{}).
+		Where("status = ?", foundationenum.SimilarityReportStatusRunning).
+		Update("status", foundationenum.SimilarityReportStatusPending).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to reset similarity reports")
+	}
+	return nil
+}
+
+// FinishSimilarityReport 写入检测结果，重复执行时会先清理旧的代码对
+func (d *SimilarityDao) FinishSimilarityReport(
+	ctx context.Context,
+	id int,
+	status foundationenum.SimilarityReportStatus,
+	submissionCount int,
+	pairs []*foundationmodel.SimilarityPair,
+	message *string,
+) error {
+	err := d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Where("report_id = ?", id).Delete(&foundationmodel.SimilarityPair{}).Error; err != nil {
+				return err
+			}
+			if len(pairs) > 0 {
+				if err := tx.CreateInBatches(pairs, 500).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Model(&foundationmodel.SimilarityReport{}).
+				Where("id = ?", id).
+				Updates(
+					map[string]interface{}{
+						"status":           status,
+						"submission_count": submissionCount,
+						"pair_count":       len(pairs),
+						"message":          message,
+						"finish_time":      metatime.GetTimeNow(),
+					},
+				).Error
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to finish similarity report, id:%d", id)
+	}
+	return nil
+}
+
+func (d *SimilarityDao) getSimilarityReportQuery(ctx context.Context) *gorm.DB {
+	return d.db.WithContext(ctx).
+		Table("similarity_report AS r").
+		Select("r.*, u.username AS inserter_username, u.nickname AS inserter_nickname").
+		Joins(`LEFT JOIN "user" AS u ON u.id = r.inserter`)
+}
+
+func (d *SimilarityDao) GetSimilarityReport(ctx context.Context, id int) (*foundationview.SimilarityReport, error) {
+	var report foundationview.SimilarityReport
+	err := d.getSimilarityReportQuery(ctx).
+		Where("r.id = ?", id).
+		Take(&report).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get similarity report, id:%d", id)
+	}
+	return &report, nil
+}
+
+func (d *SimilarityDao) GetSimilarityReports(
+	ctx context.Context,
+	scopeType foundationenum.SimilarityScopeType,
+	scopeId int,
+) ([]*foundationview.SimilarityReport, error) {
+	var reports []*foundationview.SimilarityReport
+	err := d.getSimilarityReportQuery(ctx).
+		Where("r.scope_type = ? AND r.scope_id = ?", scopeType, scopeId).
+		Order("r.id DESC").
+		Scan(&reports).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get similarity reports, scope:%d", scopeId)
+	}
+	return reports, nil
+}
+
+func (d *SimilarityDao) getSimilarityPairQuery(ctx context.Context, fields string) *gorm.DB {
+	return d.db.WithContext(ctx).
+		Table("similarity_pair AS s").
+		Select(
+			`
+			s.id, s.report_id, s.problem_id, s.left_judge_id, s.left_user_id, s.right_judge_id,
+			s.right_user_id, s.similarity, p.key AS problem_key,
+			lu.username AS left_username, lu.nickname AS left_nickname,
+			ru.username AS right_username, ru.nickname AS right_nickname` + fields,
+		).
+		Joins("LEFT JOIN problem AS p ON p.id = s.problem_id").
+		Joins(`LEFT JOIN "user" AS lu ON lu.id = s.left_user_id`).
+		Joins(`LEFT JOIN "user" AS ru ON ru.id = s.right_user_id`)
+}
+
+// GetSimilarityPairs 获取报告中的代码对，按相似度从高到低排列
+func (d *SimilarityDao) GetSimilarityPairs(ctx context.Context, reportId int) ([]*foundationview.SimilarityPair, error) {
+	var pairs []*foundationview.SimilarityPair
+	err := d.getSimilarityPairQuery(ctx, "").
+		Where("s.report_id = ?", reportId).
+		Order("s.similarity DESC, s.id").
+		Scan(&pairs).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get similarity pairs, id:%d", reportId)
+	}
+	return pairs, nil
+}
+
+func (d *SimilarityDao) GetSimilarityPairDetail(ctx context.Context, id int) (*foundationview.SimilarityPairDetail, error) {
+	var detail foundationview.SimilarityPairDetail
+	err := d.getSimilarityPairQuery(
+		ctx,
+		`, s.regions, lj.language AS left_language, lj.code AS left_code,
+			rj.language AS right_language, rj.code AS right_code`,
+	).
+		Joins("LEFT JOIN judge_job AS lj ON lj.id = s.left_judge_id").
+		Joins("LEFT JOIN judge_job AS rj ON rj.id = s.right_judge_id").
+		Where("s.id = ?", id).
+		Take(&detail).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, metaerror.Wrap(err, "failed to get similarity pair, id:%d", id)
+	}
+	return &detail, nil
+}
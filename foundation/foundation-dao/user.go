@@ -83,7 +83,7 @@ func (d *UserDao) GetUserLogin(ctx context.Context, id int) (*foundationview.Use
 	var user foundationview.UserLogin
 	err := d.db.WithContext(ctx).
 		Model(&foundationmodel.User{}).
-		Select("id, username, nickname, password").
+		Select("id, username, nickname, password, disabled").
 		Where("id = ?", id).
 		First(&user).Error
 	if err != nil {
@@ -95,11 +95,24 @@ func (d *UserDao) GetUserLogin(ctx context.Context, id int) (*foundationview.Use
 	return &user, nil
 }
 
+// IsUserDisabled 账号被禁用或不存在时返回true
+func (d *UserDao) IsUserDisabled(ctx context.Context, id int) (bool, error) {
+	var disabled []bool
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.User{}).
+		Where("id = ?", id).
+		Pluck("disabled", &disabled).Error
+	if err != nil {
+		return false, metaerror.Wrap(err, "get user disabled, id:%d", id)
+	}
+	return len(disabled) == 0 || disabled[0], nil
+}
+
 func (d *UserDao) GetUserLoginByUsername(ctx context.Context, username string) (*foundationview.UserLogin, error) {
 	var user foundationview.UserLogin
 	err := d.db.WithContext(ctx).
 		Model(&foundationmodel.User{}).
-		Select("id, username, nickname, password").
+		Select("id, username, nickname, password, disabled").
 		Where("LOWER(username) = LOWER(?)", username).
 		First(&user).Error
 	if err != nil {
@@ -131,7 +144,7 @@ func (d *UserDao) GetModifyInfo(ctx context.Context, userId int) (*foundationvie
 		Select(
 			`id, username, username, nickname, real_name, 
 email, gender, number, slogan, organization, qq, blog,
-vjudge_id, github, codeforces`,
+vjudge_id, github, codeforces, hdu`,
 		).
 		Where("id = ?", userId).
 		First(&userInfo).Error
@@ -151,9 +164,9 @@ func (d *UserDao) GetInfoByUsername(ctx context.Context, username string) (*foun
 		Select(
 			`id, username, nickname, real_name, 
 email, gender, number, slogan, organization, qq, blog,
-			vjudge_id, github, codeforces, 
+			vjudge_id, github, codeforces, hdu,
 check_in_count, insert_time, modify_time, accept, attempt,
-			level, experience, coin`,
+			level, experience, coin, rating`,
 		).
 		Where("LOWER(username) = LOWER(?)", username).
 		First(&userInfo).Error
@@ -313,6 +326,31 @@ func (d *UserDao) GetRankAcAll(ctx context.Context, page int, size int) ([]*foun
 	return userRanks, int(total), nil
 }
 
+// GetRankRating 按比赛Rating排名，只包含参加过计分比赛的用户
+func (d *UserDao) GetRankRating(ctx context.Context, page int, size int) ([]*foundationview.UserRank, int, error) {
+	var userRanks []*foundationview.UserRank
+	var total int64
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.User{}).
+		Where("rating IS NOT NULL").
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, metaerror.Wrap(err, "count rated users")
+	}
+	err = d.db.WithContext(ctx).
+		Model(&foundationmodel.User{}).
+		Select("id, username, nickname, email, slogan, rating").
+		Where("rating IS NOT NULL").
+		Order("rating DESC, id ASC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&userRanks).Error
+	if err != nil {
+		return nil, 0, metaerror.Wrap(err, "get user rating ranks")
+	}
+	return userRanks, int(total), nil
+}
+
 func (d *UserDao) FilterValidUserIds(ctx context.Context, ids []int) ([]int, error) {
 	if len(ids) == 0 {
 		return nil, nil
@@ -373,6 +411,42 @@ func (d *UserDao) UpdateUserInfo(
 	return nil
 }
 
+// GetUsernamesByPrefix 获取以prefix开头的用户名，用于批量生成账号时避开已存在的用户名
+func (d *UserDao) GetUsernamesByPrefix(ctx context.Context, prefix string) ([]string, error) {
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(prefix)) + "%"
+	var usernames []string
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.User{}).
+		Where("LOWER(username) LIKE ?", pattern).
+		Pluck("username", &usernames).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "get usernames by prefix")
+	}
+	return usernames, nil
+}
+
+// UpdateContestOnlyUserDisabled 禁用或启用为比赛生成的全部临时账号，返回修改的账号数
+func (d *UserDao) UpdateContestOnlyUserDisabled(
+	ctx context.Context,
+	contestId int,
+	disabled bool,
+	nowTime time.Time,
+) (int, error) {
+	res := d.db.WithContext(ctx).
+		Model(&foundationmodel.User{}).
+		Where("contest_only_id = ?", contestId).
+		Updates(
+			map[string]interface{}{
+				"disabled":    disabled,
+				"modify_time": nowTime,
+			},
+		)
+	if res.Error != nil {
+		return 0, metaerror.Wrap(res.Error, "update contest only users disabled, id:%d", contestId)
+	}
+	return int(res.RowsAffected), nil
+}
+
 func (d *UserDao) InsertUser(ctx context.Context, user *foundationmodel.User) error {
 	if user == nil {
 		return metaerror.New("user is nil")
@@ -413,24 +487,6 @@ func (d *UserDao) UpdateUsername(ctx context.Context, userId int, username strin
 	return nil
 }
 
-func (d *UserDao) UpdateUserVjudgeUsername(ctx *gin.Context, id int, vjudgeId string, now time.Time) error {
-	db := d.db.WithContext(ctx).Model(&foundationmodel.User{})
-	res := db.Where("id = ?", id).
-		Updates(
-			map[string]interface{}{
-				"vjudge_id":   vjudgeId,
-				"modify_time": now,
-			},
-		)
-	if res.Error != nil {
-		return metaerror.Wrap(res.Error, "update user vjudge username")
-	}
-	if res.RowsAffected == 0 {
-		return metaerror.New("no rows affected, user not found")
-	}
-	return nil
-}
-
 func (d *UserDao) UpdateUserEmail(ctx context.Context, id int, email string, now time.Time) error {
 	db := d.db.WithContext(ctx).Model(&foundationmodel.User{})
 	res := db.Where("id = ?", id).
@@ -449,9 +505,16 @@ func (d *UserDao) UpdateUserEmail(ctx context.Context, id int, email string, now
 	return nil
 }
 
-func (d *UserDao) PostLoginLog(ctx context.Context, userId int, nowTime time.Time, ip string, agent string) error {
+func (d *UserDao) PostLoginLog(
+	ctx context.Context,
+	userId int,
+	nowTime time.Time,
+	ip string,
+	agent string,
+	session string,
+) error {
 	loginLog := foundationmodel.NewUserLoginBuilder().
-		UserId(userId).InsertTime(nowTime).IP(ip).UserAgent(agent).Build()
+		UserId(userId).InsertTime(nowTime).IP(ip).UserAgent(agent).Session(session).Build()
 	db := d.db.WithContext(ctx).Model(loginLog)
 	if err := db.Create(loginLog).Error; err != nil {
 		return metaerror.Wrap(err, "insert user login log")
@@ -459,6 +522,24 @@ func (d *UserDao) PostLoginLog(ctx context.Context, userId int, nowTime time.Tim
 	return nil
 }
 
+// GetLatestLoginSession 获取用户最近一次登录的会话标识，没有登录记录时返回空
+func (d *UserDao) GetLatestLoginSession(ctx context.Context, userId int) (string, error) {
+	var sessions []*string
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.UserLogin{}).
+		Where("user_id = ?", userId).
+		Order("id DESC").
+		Limit(1).
+		Pluck("session", &sessions).Error
+	if err != nil {
+		return "", metaerror.Wrap(err, "get latest login session")
+	}
+	if len(sessions) == 0 || sessions[0] == nil {
+		return "", nil
+	}
+	return *sessions[0], nil
+}
+
 // AddUserExperienceWithTx 在事务中更新用户经验值并自动更新等级
 // 使用数据库行级锁确保在高并发环境下的数据一致性
 func (d *UserDao) AddUserExperienceWithTx(tx *gorm.DB, userId int, expGain int, nowTime time.Time) (int, int, error) {
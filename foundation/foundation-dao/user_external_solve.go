@@ -0,0 +1,281 @@
+package foundationdao
+
+import (
+	"context"
+	"fmt"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapostgresql "meta/meta-postgresql"
+	"meta/singleton"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserExternalSolveDao struct {
+	db *gorm.DB
+}
+
+var singletonUserExternalSolveDao = singleton.Singleton[UserExternalSolveDao]{}
+
+func GetUserExternalSolveDao() *UserExternalSolveDao {
+	return singletonUserExternalSolveDao.GetInstance(
+		func() *UserExternalSolveDao {
+			dao := &UserExternalSolveDao{}
+			dao.db = metapostgresql.GetSubsystem().GetClient("didaoj")
+			return dao
+		},
+	)
+}
+
+// getAccountColumn 外部来源对应的用户表字段
+func (d *UserExternalSolveDao) getAccountColumn(source foundationenum.UserExternalSource) (string, error) {
+	switch source {
+	case foundationenum.UserExternalSourceCodeforces:
+		return "codeforces", nil
+	case foundationenum.UserExternalSourceVjudge:
+		return "vjudge_id", nil
+	case foundationenum.UserExternalSourceHdu:
+		return "hdu", nil
+	default:
+		return "", metaerror.New("unknown external source: %s", source)
+	}
+}
+
+// UpdateUserExternalAccount 修改用户绑定的外部账号
+func (d *UserExternalSolveDao) UpdateUserExternalAccount(
+	ctx context.Context,
+	userId int,
+	source foundationenum.UserExternalSource,
+	account string,
+	now time.Time,
+) error {
+	column, err := d.getAccountColumn(source)
+	if err != nil {
+		return err
+	}
+	res := d.db.WithContext(ctx).
+		Model(&foundationmodel.User{}).
+		Where("id = ?", userId).
+		Updates(
+			map[string]interface{}{
+				column:        account,
+				"modify_time": now,
+			},
+		)
+	if res.Error != nil {
+		return metaerror.Wrap(res.Error, "update user external account, source:%s", source)
+	}
+	if res.RowsAffected == 0 {
+		return metaerror.New("no rows affected, user not found")
+	}
+	return nil
+}
+
+// GetUserExternalSyncPending 获取需要同步的外部账号，包括从未同步、同步已过期、账号已变更或解绑的情况
+func (d *UserExternalSolveDao) GetUserExternalSyncPending(
+	ctx context.Context,
+	source foundationenum.UserExternalSource,
+	before time.Time,
+	limit int,
+) ([]*foundationview.UserExternalSyncPending, error) {
+	column, err := d.getAccountColumn(source)
+	if err != nil {
+		return nil, err
+	}
+	account := fmt.Sprintf("NULLIF(u.%s, '')", column)
+	execSql := fmt.Sprintf(
+		`
+SELECT u.id AS user_id, ? AS source, %s AS account
+FROM "user" AS u
+LEFT JOIN user_external_sync AS s ON s.user_id = u.id AND s.source = ?
+WHERE (%s IS NOT NULL AND (s.user_id IS NULL OR s.sync_time < ?))
+   OR (s.user_id IS NOT NULL AND s.account IS DISTINCT FROM %s)
+ORDER BY s.sync_time NULLS FIRST, u.id
+LIMIT ?
+`, account, account, account,
+	)
+	var pending []*foundationview.UserExternalSyncPending
+	err = d.db.WithContext(ctx).
+		Raw(execSql, source, source, before, limit).
+		Scan(&pending).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get user external sync pending")
+	}
+	return pending, nil
+}
+
+// ReplaceUserExternalSolves 用最新的同步结果替换该来源下的全部记录
+func (d *UserExternalSolveDao) ReplaceUserExternalSolves(
+	ctx context.Context,
+	userId int,
+	source foundationenum.UserExternalSource,
+	account string,
+	solves []*foundationmodel.UserExternalSolve,
+	syncTime time.Time,
+) error {
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Where("user_id = ? AND source = ?", userId, source).
+				Delete(&foundationmodel.UserExternalSolve{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete user external solves")
+			}
+			if len(solves) > 0 {
+				if err := tx.CreateInBatches(solves, 500).Error; err != nil {
+					return metaerror.Wrap(err, "failed to insert user external solves")
+				}
+			}
+			sync := foundationmodel.NewUserExternalSyncBuilder().
+				UserId(userId).
+				Source(source).
+				Account(account).
+				Status(foundationenum.UserExternalSyncStatusSuccess).
+				SolveCount(len(solves)).
+				SyncTime(syncTime).
+				Build()
+			if err := tx.Clauses(
+				clause.OnConflict{
+					Columns:   []clause.Column{{Name: "user_id"}, {Name: "source"}},
+					DoUpdates: clause.AssignmentColumns([]string{"account", "status", "message", "solve_count", "sync_time"}),
+				},
+			).Create(sync).Error; err != nil {
+				return metaerror.Wrap(err, "failed to save user external sync")
+			}
+			return nil
+		},
+	)
+}
+
+// MarkUserExternalSyncFail 记录同步失败，保留之前同步到的记录
+func (d *UserExternalSolveDao) MarkUserExternalSyncFail(
+	ctx context.Context,
+	userId int,
+	source foundationenum.UserExternalSource,
+	account string,
+	message string,
+	syncTime time.Time,
+) error {
+	if runes := []rune(message); len(runes) > 255 {
+		message = string(runes[:255])
+	}
+	sync := foundationmodel.NewUserExternalSyncBuilder().
+		UserId(userId).
+		Source(source).
+		Account(account).
+		Status(foundationenum.UserExternalSyncStatusFail).
+		Message(&message).
+		SyncTime(syncTime).
+		Build()
+	err := d.db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "source"}},
+				DoUpdates: clause.AssignmentColumns([]string{"account", "status", "message", "sync_time"}),
+			},
+		).
+		Create(sync).Error
+	if err != nil {
+		return metaerror.Wrap(err, "failed to mark user external sync fail")
+	}
+	return nil
+}
+
+// DeleteUserExternalSync 账号解绑后删除该来源的同步状态与记录
+func (d *UserExternalSolveDao) DeleteUserExternalSync(
+	ctx context.Context,
+	userId int,
+	source foundationenum.UserExternalSource,
+) error {
+	return d.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Where("user_id = ? AND source = ?", userId, source).
+				Delete(&foundationmodel.UserExternalSolve{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete user external solves")
+			}
+			if err := tx.Where("user_id = ? AND source = ?", userId, source).
+				Delete(&foundationmodel.UserExternalSync{}).Error; err != nil {
+				return metaerror.Wrap(err, "failed to delete user external sync")
+			}
+			return nil
+		},
+	)
+}
+
+func (d *UserExternalSolveDao) GetUserExternalSyncs(
+	ctx context.Context,
+	userId int,
+) ([]*foundationmodel.UserExternalSync, error) {
+	var syncs []*foundationmodel.UserExternalSync
+	err := d.db.WithContext(ctx).
+		Where("user_id = ?", userId).
+		Order("source").
+		Find(&syncs).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get user external syncs")
+	}
+	return syncs, nil
+}
+
+// GetUserExternalSolves 获取合并各来源后的外部AC题目，并关联本站对应的远程题目
+func (d *UserExternalSolveDao) GetUserExternalSolves(
+	ctx context.Context,
+	userId int,
+) ([]*foundationview.UserExternalSolve, error) {
+	var solves []*foundationview.UserExternalSolve
+	err := d.db.WithContext(ctx).
+		Raw(
+			`
+SELECT s.origin_oj, s.origin_id, MIN(s.solve_time) AS solve_time, MIN(p.key) AS problem_key
+FROM user_external_solve AS s
+LEFT JOIN problem_remote AS pr ON UPPER(pr.origin_oj) = s.origin_oj AND pr.origin_id = s.origin_id
+LEFT JOIN problem AS p ON p.id = pr.problem_id
+WHERE s.user_id = ?
+GROUP BY s.origin_oj, s.origin_id
+ORDER BY s.origin_oj, LENGTH(s.origin_id), s.origin_id
+`, userId,
+		).
+		Scan(&solves).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get user external solves")
+	}
+	return solves, nil
+}
+
+// GetRankExternalSolve 按外部AC题数排名，同一题目在多个来源中只计一次
+func (d *UserExternalSolveDao) GetRankExternalSolve(
+	ctx context.Context,
+	page int,
+	pageSize int,
+) ([]*foundationview.UserRank, int, error) {
+	var total int64
+	err := d.db.WithContext(ctx).
+		Model(&foundationmodel.UserExternalSolve{}).
+		Distinct("user_id").
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, metaerror.Wrap(err, "failed to count user external solve rank")
+	}
+	var list []*foundationview.UserRank
+	err = d.db.WithContext(ctx).
+		Raw(
+			`
+SELECT u.id, u.username, u.nickname, COALESCE(u.slogan, '') AS slogan, r.problem_count
+FROM (
+	SELECT user_id, COUNT(DISTINCT (origin_oj, origin_id)) AS problem_count
+	FROM user_external_solve
+	GROUP BY user_id
+) AS r
+JOIN "user" AS u ON u.id = r.user_id
+ORDER BY r.problem_count DESC, u.id
+OFFSET ? LIMIT ?
+`, (page-1)*pageSize, pageSize,
+		).
+		Scan(&list).Error
+	if err != nil {
+		return nil, 0, metaerror.Wrap(err, "failed to get user external solve rank")
+	}
+	return list, int(total), nil
+}
@@ -23,3 +23,38 @@ var (
 	ContestDiscussTypeSelf    ContestDiscussType = 1 // 仅能参与自己发表的讨论（管理员不受限制）
 	ContestDiscussTypeDisable ContestDiscussType = 2 // 不接受讨论
 )
+
+type ContestRankFilter int
+
+var (
+	ContestRankFilterAll     ContestRankFilter = 0 // 正式参赛与虚拟参赛合并排名
+	ContestRankFilterReal    ContestRankFilter = 1 // 仅正式参赛
+	ContestRankFilterVirtual ContestRankFilter = 2 // 仅虚拟参赛
+)
+
+type ContestBalloonStatus int
+
+var (
+	ContestBalloonStatusPending   ContestBalloonStatus = 0 // 等待领取
+	ContestBalloonStatusClaimed   ContestBalloonStatus = 1 // 志愿者已领取，正在配送
+	ContestBalloonStatusDelivered ContestBalloonStatus = 2 // 已送达
+)
+
+type ContestPrintStatus int
+
+var (
+	ContestPrintStatusPending  ContestPrintStatus = 0 // 等待打印
+	ContestPrintStatusPrinting ContestPrintStatus = 1 // 已被志愿者或打印程序领取
+	ContestPrintStatusDone     ContestPrintStatus = 2 // 已打印完成
+)
+
+type ContestHackStatus int
+
+var (
+	ContestHackStatusPending ContestHackStatus = 0 // 等待评测
+	ContestHackStatusRunning ContestHackStatus = 1 // 评测机已领取，正在运行
+	ContestHackStatusSuccess ContestHackStatus = 2 // 被挑战的代码未通过该输入，挑战成功
+	ContestHackStatusFailed  ContestHackStatus = 3 // 被挑战的代码通过了该输入，挑战失败
+	ContestHackStatusInvalid ContestHackStatus = 4 // 输入未通过校验器或标准输出过大，不计入成绩
+	ContestHackStatusError   ContestHackStatus = 5 // 评测失败，例如题目缺少校验器或标准程序，不计入成绩
+)
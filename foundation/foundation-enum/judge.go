@@ -3,8 +3,10 @@ package foundationenum
 type RemoteJudgeType string
 
 var (
-	RemoteJudgeTypeLocal RemoteJudgeType = "DidaOJ"
-	RemoteJudgeTypeHdu   RemoteJudgeType = "HDU"
-	RemoteJudgeTypePoj   RemoteJudgeType = "POJ"
-	RemoteJudgeTypeNyoj  RemoteJudgeType = "NYOJ"
+	RemoteJudgeTypeLocal      RemoteJudgeType = "DidaOJ"
+	RemoteJudgeTypeHdu        RemoteJudgeType = "HDU"
+	RemoteJudgeTypePoj        RemoteJudgeType = "POJ"
+	RemoteJudgeTypeNyoj       RemoteJudgeType = "NYOJ"
+	RemoteJudgeTypeCodeforces RemoteJudgeType = "CODEFORCES"
+	RemoteJudgeTypeAtcoder    RemoteJudgeType = "ATCODER"
 )
@@ -23,3 +23,13 @@ const (
 	ProblemRankTypeMemory     ProblemRankType = 1
 	ProblemRankTypeCodeLength ProblemRankType = 2
 )
+
+type ProblemCrawlStatus int
+
+const (
+	ProblemCrawlStatusInit    ProblemCrawlStatus = 0 // 等待爬取
+	ProblemCrawlStatusRunning ProblemCrawlStatus = 1 // 正在爬取
+	ProblemCrawlStatusSuccess ProblemCrawlStatus = 2 // 爬取成功
+	ProblemCrawlStatusSkip    ProblemCrawlStatus = 3 // 题目已存在，跳过
+	ProblemCrawlStatusFail    ProblemCrawlStatus = 4 // 爬取失败
+)
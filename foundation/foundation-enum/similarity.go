@@ -0,0 +1,18 @@
+package foundationenum
+
+type SimilarityScopeType int
+
+var (
+	SimilarityScopeTypeProblem    SimilarityScopeType = 0
+	SimilarityScopeTypeCollection SimilarityScopeType = 1
+	SimilarityScopeTypeContest    SimilarityScopeType = 2
+)
+
+type SimilarityReportStatus int
+
+var (
+	SimilarityReportStatusPending  SimilarityReportStatus = 0 // 等待检测
+	SimilarityReportStatusRunning  SimilarityReportStatus = 1 // 正在检测
+	SimilarityReportStatusFinished SimilarityReportStatus = 2 // 检测完成
+	SimilarityReportStatusFailed   SimilarityReportStatus = 3 // 检测失败
+)
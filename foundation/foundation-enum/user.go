@@ -18,3 +18,18 @@ func GetUserGender(gender string) UserGender {
 		return UserGenderUnknown
 	}
 }
+
+type UserExternalSource string
+
+const (
+	UserExternalSourceCodeforces UserExternalSource = "CODEFORCES"
+	UserExternalSourceVjudge     UserExternalSource = "VJUDGE"
+	UserExternalSourceHdu        UserExternalSource = "HDU"
+)
+
+type UserExternalSyncStatus int
+
+const (
+	UserExternalSyncStatusSuccess UserExternalSyncStatus = 0 // 同步成功
+	UserExternalSyncStatusFail    UserExternalSyncStatus = 1 // 同步失败
+)
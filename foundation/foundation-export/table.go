@@ -0,0 +1,106 @@
+package foundationexport
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayout 导出表格中时间的格式
+const TimeLayout = "2006-01-02 15:04:05"
+
+// TableWriter 逐行写出表格，单元格支持string、Plain、int、int64、float64与time.Time，nil为空单元格
+type TableWriter interface {
+	WriteRow(cells ...interface{}) error
+	Close() error
+}
+
+// Plain 程序生成的文本，如ACM榜单的"-2"，导出CSV时不做公式转义
+// 用户填写的内容应使用string，以免在Excel中被当作公式执行
+type Plain string
+
+func formatCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case Plain:
+		return string(v)
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(TimeLayout)
+	default:
+		return ""
+	}
+}
+
+type csvTableWriter struct {
+	writer *csv.Writer
+}
+
+// NewCsvTableWriter 写入UTF-8 BOM，使Excel可以直接正确打开中文内容
+func NewCsvTableWriter(w io.Writer) (TableWriter, error) {
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+	return &csvTableWriter{writer: csv.NewWriter(w)}, nil
+}
+
+// escapeCsvFormula 以公式字符开头的文本前加单引号，Excel打开时按文本显示
+func escapeCsvFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+func (t *csvTableWriter) WriteRow(cells ...interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = formatCell(cell)
+		switch cell.(type) {
+		case string, *string:
+			record[i] = escapeCsvFormula(record[i])
+		}
+	}
+	if err := t.writer.Write(record); err != nil {
+		return err
+	}
+	// 每行都刷新，大量数据时可以边查询边输出
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+func (t *csvTableWriter) Close() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+// NewTableWriter 根据格式创建表格，format为xlsx时导出Excel，其余情况导出CSV
+func NewTableWriter(format string, w io.Writer, sheetName string, location *time.Location) (TableWriter, error) {
+	if format == "xlsx" {
+		return NewXlsxTableWriter(w, sheetName, location)
+	}
+	return NewCsvTableWriter(w)
+}
+
+// GetTableContentType 与NewTableWriter对应的Content-Type与扩展名
+func GetTableContentType(format string) (string, string) {
+	if format == "xlsx" {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"
+	}
+	return "text/csv; charset=utf-8", "csv"
+}
@@ -0,0 +1,180 @@
+package foundationexport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// 样式1为日期时间格式，时间单元格使用
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+	`<borders count="1"><border/></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// Excel的日期从1899-12-30开始计数
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// getXlsxSheetName 工作表名称不能包含[]:*?/\，且最多31个字符
+func getXlsxSheetName(name string) string {
+	name = strings.Map(
+		func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return ' '
+			}
+			return r
+		}, name,
+	)
+	runes := []rune(strings.TrimSpace(name))
+	if len(runes) > 31 {
+		runes = runes[:31]
+	}
+	if len(runes) == 0 {
+		return "Sheet1"
+	}
+	return string(runes)
+}
+
+type xlsxTableWriter struct {
+	zipWriter *zip.Writer
+	sheet     *bufio.Writer
+	location  *time.Location
+}
+
+// NewXlsxTableWriter 流式写出只有一个工作表的xlsx，时间按location转换后写入
+func NewXlsxTableWriter(w io.Writer, sheetName string, location *time.Location) (TableWriter, error) {
+	zipWriter := zip.NewWriter(w)
+	var workbookName bytes.Buffer
+	if err := xml.EscapeText(&workbookName, []byte(getXlsxSheetName(sheetName))); err != nil {
+		return nil, err
+	}
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{
+			"xl/workbook.xml",
+			`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+				`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+				`<sheets><sheet name="` + workbookName.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		},
+	}
+	for _, file := range files {
+		fileWriter, err := zipWriter.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(fileWriter, file.content); err != nil {
+			return nil, err
+		}
+	}
+	// 工作表放在最后，之后的行可以直接追加写入
+	sheetWriter, err := zipWriter.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(sheetWriter)
+	_, err = sheet.WriteString(
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		location = time.Local
+	}
+	return &xlsxTableWriter{zipWriter: zipWriter, sheet: sheet, location: location}, nil
+}
+
+func (t *xlsxTableWriter) WriteRow(cells ...interface{}) error {
+	if _, err := t.sheet.WriteString("<row>"); err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		var err error
+		switch v := cell.(type) {
+		case int:
+			_, err = t.sheet.WriteString(`<c><v>` + strconv.Itoa(v) + `</v></c>`)
+		case int64:
+			_, err = t.sheet.WriteString(`<c><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+		case float64:
+			_, err = t.sheet.WriteString(`<c><v>` + strconv.FormatFloat(v, 'f', -1, 64) + `</v></c>`)
+		case time.Time:
+			// 按本地时间换算为Excel的日期序号
+			local := v.In(t.location)
+			wall := time.Date(
+				local.Year(), local.Month(), local.Day(),
+				local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC,
+			)
+			days := wall.Sub(xlsxEpoch).Hours() / 24
+			_, err = t.sheet.WriteString(`<c s="1"><v>` + strconv.FormatFloat(days, 'f', -1, 64) + `</v></c>`)
+		default:
+			text := formatCell(cell)
+			if text == "" {
+				_, err = t.sheet.WriteString(`<c/>`)
+				break
+			}
+			if _, err = t.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+				break
+			}
+			if err = xml.EscapeText(t.sheet, []byte(text)); err != nil {
+				break
+			}
+			_, err = t.sheet.WriteString(`</t></is></c>`)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := t.sheet.WriteString("</row>"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *xlsxTableWriter) Close() error {
+	if _, err := t.sheet.WriteString("</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	if err := t.sheet.Flush(); err != nil {
+		return err
+	}
+	return t.zipWriter.Close()
+}
@@ -54,9 +54,157 @@ func GetLanguageByKey(language string) JudgeLanguage {
 		return JudgeLanguagePascal
 	case "golang":
 		return JudgeLanguageGolang
+	case "lua":
+		return JudgeLanguageLua
+	case "typescript":
+		return JudgeLanguageTypeScript
 	case "rust":
 		return JudgeLanguageRust
 	default:
 		return JudgeLanguageUnknown
 	}
 }
+
+// GetLanguagesByKeys 批量转换语言标识，忽略未知的语言
+func GetLanguagesByKeys(keys []string) []JudgeLanguage {
+	languages := make([]JudgeLanguage, 0, len(keys))
+	for _, key := range keys {
+		language := GetLanguageByKey(key)
+		if language == JudgeLanguageUnknown {
+			continue
+		}
+		languages = append(languages, language)
+	}
+	return languages
+}
+
+// IsLanguageAllowed 判断语言是否在允许的语言标识中，未设置允许的语言时不限制
+func IsLanguageAllowed(keys []string, language JudgeLanguage) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	key := GetLanguageKey(language)
+	for _, allowed := range keys {
+		if allowed == key {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLanguageKey 与GetLanguageByKey相反，未知语言返回空字符串
+func GetLanguageKey(language JudgeLanguage) string {
+	switch language {
+	case JudgeLanguageC:
+		return "c"
+	case JudgeLanguageCpp:
+		return "cpp"
+	case JudgeLanguageJava:
+		return "java"
+	case JudgeLanguagePython:
+		return "python"
+	case JudgeLanguagePascal:
+		return "pascal"
+	case JudgeLanguageGolang:
+		return "golang"
+	case JudgeLanguageLua:
+		return "lua"
+	case JudgeLanguageTypeScript:
+		return "typescript"
+	case JudgeLanguageRust:
+		return "rust"
+	default:
+		return ""
+	}
+}
+
+// GetLanguageFileExtension 代码文件的扩展名，未知语言使用txt
+func GetLanguageFileExtension(language JudgeLanguage) string {
+	switch language {
+	case JudgeLanguageC:
+		return "c"
+	case JudgeLanguageCpp:
+		return "cpp"
+	case JudgeLanguageJava:
+		return "java"
+	case JudgeLanguagePython:
+		return "py"
+	case JudgeLanguagePascal:
+		return "pas"
+	case JudgeLanguageGolang:
+		return "go"
+	case JudgeLanguageLua:
+		return "lua"
+	case JudgeLanguageTypeScript:
+		return "ts"
+	case JudgeLanguageRust:
+		return "rs"
+	default:
+		return "txt"
+	}
+}
+
+// GetLanguageKeywords 打印代码时加粗显示的关键字，未知语言返回nil
+func GetLanguageKeywords(language JudgeLanguage) []string {
+	switch language {
+	case JudgeLanguageC:
+		return []string{
+			"break", "case", "char", "const", "continue", "default", "do", "double", "else", "enum", "extern",
+			"float", "for", "goto", "if", "int", "long", "return", "short", "signed", "sizeof", "static",
+			"struct", "switch", "typedef", "union", "unsigned", "void", "while",
+		}
+	case JudgeLanguageCpp:
+		return []string{
+			"auto", "bool", "break", "case", "catch", "char", "class", "const", "constexpr", "continue",
+			"default", "delete", "do", "double", "else", "enum", "false", "float", "for", "if", "inline", "int",
+			"long", "namespace", "new", "nullptr", "operator", "private", "public", "return", "short",
+			"signed", "sizeof", "static", "struct", "switch", "template", "this", "true", "typedef",
+			"typename", "unsigned", "using", "void", "while",
+		}
+	case JudgeLanguageJava:
+		return []string{
+			"boolean", "break", "byte", "case", "catch", "char", "class", "continue", "default", "do",
+			"double", "else", "extends", "false", "final", "finally", "float", "for", "if", "implements",
+			"import", "int", "interface", "long", "new", "null", "package", "private", "protected", "public",
+			"return", "short", "static", "super", "switch", "this", "throw", "throws", "true", "try", "void",
+			"while",
+		}
+	case JudgeLanguagePython:
+		return []string{
+			"False", "None", "True", "and", "as", "break", "class", "continue", "def", "del", "elif", "else",
+			"except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal",
+			"not", "or", "pass", "raise", "return", "try", "while", "with", "yield",
+		}
+	case JudgeLanguagePascal:
+		return []string{
+			"and", "array", "begin", "case", "const", "div", "do", "downto", "else", "end", "for", "function",
+			"if", "mod", "not", "of", "or", "procedure", "program", "record", "repeat", "then", "to", "type",
+			"until", "uses", "var", "while",
+		}
+	case JudgeLanguageGolang:
+		return []string{
+			"break", "case", "chan", "const", "continue", "default", "defer", "else", "for", "func", "go",
+			"goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+			"switch", "type", "var",
+		}
+	case JudgeLanguageLua:
+		return []string{
+			"and", "break", "do", "else", "elseif", "end", "false", "for", "function", "if", "in", "local",
+			"nil", "not", "or", "repeat", "return", "then", "true", "until", "while",
+		}
+	case JudgeLanguageTypeScript:
+		return []string{
+			"break", "case", "catch", "class", "const", "continue", "default", "else", "export", "extends",
+			"false", "for", "function", "if", "import", "in", "interface", "let", "new", "null", "of",
+			"return", "switch", "this", "throw", "true", "try", "type", "undefined", "var", "while",
+		}
+	case JudgeLanguageRust:
+		return []string{
+			"as", "break", "const", "continue", "else", "enum", "false", "fn", "for", "if", "impl", "in",
+			"let", "loop", "match", "mod", "mut", "pub", "ref", "return", "self", "static", "struct", "trait",
+			"true", "type", "use", "where", "while",
+		}
+	default:
+		return nil
+	}
+}
@@ -14,6 +14,9 @@ type JudgeTaskConfig struct {
 	OutFile     string `json:"out_file,omitempty" yaml:"out-file,omitempty"`           // 输出文件
 	OutFileSize int64  `json:"out_file_size,omitempty" yaml:"out-file-size,omitempty"` // 输出文件大小
 	OutLimit    int64  `json:"out_limit" yaml:"out-limit"`                             // 输出长度限制
+	Pretest     bool   `json:"pretest,omitempty" yaml:"pretest,omitempty"`             // 是否为预测试，开启预测试的比赛中只评测预测试
+	InContent   string `json:"-" yaml:"-"`                                             // 输入内容，不从文件读取时使用，例如比赛中挑战成功的数据
+	OutContent  string `json:"-" yaml:"-"`                                             // 输出内容，不从文件读取时使用
 }
 
 type SpecialJudgeConfig struct {
@@ -24,6 +27,8 @@ type SpecialJudgeConfig struct {
 type JudgeJobConfig struct {
 	Tasks        []*JudgeTaskConfig  `json:"tasks"`                                                  // 任务列表
 	SpecialJudge *SpecialJudgeConfig `json:"special_judge,omitempty" yaml:"special-judge,omitempty"` // 特判
+	Validator    *SpecialJudgeConfig `json:"validator,omitempty" yaml:"validator,omitempty"`         // 输入校验器，比赛挑战时校验输入，返回0表示合法
+	Standard     *SpecialJudgeConfig `json:"standard,omitempty" yaml:"standard,omitempty"`           // 标准程序，比赛挑战时生成输入对应的输出
 }
 
 // Scan 实现 sql.Scanner 接口，将数据库中的 JSON 数据转换为 JudgeJobConfig
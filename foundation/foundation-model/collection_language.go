@@ -1,34 +1,34 @@
 package foundationmodel
 
-type ContestLanguage struct {
+type CollectionLanguage struct {
 	Id       int    `gorm:"column:id;primaryKey"`
 	Language string `gorm:"column:language;primaryKey"`
 }
 
-func (p *ContestLanguage) TableName() string {
-	return "contest_language"
+func (p *CollectionLanguage) TableName() string {
+	return "collection_language"
 }
 
-type ContestLanguageBuilder struct {
-	item *ContestLanguage
+type CollectionLanguageBuilder struct {
+	item *CollectionLanguage
 }
 
-func NewContestLanguageBuilder() *ContestLanguageBuilder {
-	return &ContestLanguageBuilder{
-		item: &ContestLanguage{},
+func NewCollectionLanguageBuilder() *CollectionLanguageBuilder {
+	return &CollectionLanguageBuilder{
+		item: &CollectionLanguage{},
 	}
 }
 
-func (b *ContestLanguageBuilder) Id(id int) *ContestLanguageBuilder {
+func (b *CollectionLanguageBuilder) Id(id int) *CollectionLanguageBuilder {
 	b.item.Id = id
 	return b
 }
 
-func (b *ContestLanguageBuilder) Language(language string) *ContestLanguageBuilder {
+func (b *CollectionLanguageBuilder) Language(language string) *CollectionLanguageBuilder {
 	b.item.Language = language
 	return b
 }
 
-func (b *ContestLanguageBuilder) Build() *ContestLanguage {
+func (b *CollectionLanguageBuilder) Build() *CollectionLanguage {
 	return b.item
 }
@@ -25,6 +25,19 @@ type Contest struct {
 	LockRankDuration    *time.Duration                    `json:"lock_rank_duration,omitempty" gorm:"type:bigint"`
 	AlwaysLock          bool                              `json:"always_lock,omitempty" gorm:"type:tinyint(1)"`
 	DiscussType         foundationenum.ContestDiscussType `json:"discuss_type,omitempty" gorm:"type:tinyint;comment:'讨论类型，0正常讨论，1仅查看自己的讨论'"`
+	PenaltyMinutes      int                               `json:"penalty_minutes" gorm:"type:int;comment:'ACM模式下每次错误提交的罚时分钟数'"`
+	PersonalDuration    *time.Duration                    `json:"personal_duration,omitempty" gorm:"type:bigint;comment:'个人比赛时长，非空时成员在开放时间内自行开始，从开始时刻计时'"`
+	PrintQuota          *int                              `json:"print_quota,omitempty" gorm:"type:int;comment:'每个队伍可打印的总页数，空则不开放打印'"`
+	PrintPageLimit      *int                              `json:"print_page_limit,omitempty" gorm:"type:int;comment:'单次打印的页数上限，空则不限制'"`
+	Rated               bool                              `json:"rated,omitempty" gorm:"type:bool;comment:'是否计算Rating'"`
+	RatingTime          *time.Time                        `json:"rating_time,omitempty" gorm:"type:datetime;comment:'最近一次计算Rating的时间'"`
+	Exam                bool                              `json:"exam,omitempty" gorm:"type:bool;comment:'考试模式，比赛期间只有最近一次登录的会话有效'"`
+	IpWhitelist         *string                           `json:"ip_whitelist,omitempty" gorm:"type:text;comment:'允许访问的IP段，每行一个CIDR，空则不限制'"`
+	Hack                bool                              `json:"hack,omitempty" gorm:"type:bool;comment:'是否开放挑战，锁定题目后可以挑战他人已通过的代码'"`
+	HackScore           int                               `json:"hack_score,omitempty" gorm:"type:int;comment:'每次挑战成功获得的分数'"`
+	HackPenalty         int                               `json:"hack_penalty,omitempty" gorm:"type:int;comment:'每次挑战失败扣除的分数'"`
+	Pretest             bool                              `json:"pretest,omitempty" gorm:"type:bool;comment:'比赛期间只评测预测试，结束后进行系统测试'"`
+	SystemTestTime      *time.Time                        `json:"system_test_time,omitempty" gorm:"comment:'系统测试开始时间，空则尚未开始'"`
 }
 
 func (*Contest) TableName() string {
@@ -134,6 +147,61 @@ func (b *ContestBuilder) DiscussType(discussType foundationenum.ContestDiscussTy
 	return b
 }
 
+func (b *ContestBuilder) PenaltyMinutes(penaltyMinutes int) *ContestBuilder {
+	b.item.PenaltyMinutes = penaltyMinutes
+	return b
+}
+
+func (b *ContestBuilder) PrintQuota(printQuota *int) *ContestBuilder {
+	b.item.PrintQuota = printQuota
+	return b
+}
+
+func (b *ContestBuilder) PrintPageLimit(printPageLimit *int) *ContestBuilder {
+	b.item.PrintPageLimit = printPageLimit
+	return b
+}
+
+func (b *ContestBuilder) PersonalDuration(personalDuration *time.Duration) *ContestBuilder {
+	b.item.PersonalDuration = personalDuration
+	return b
+}
+
+func (b *ContestBuilder) Rated(rated bool) *ContestBuilder {
+	b.item.Rated = rated
+	return b
+}
+
+func (b *ContestBuilder) Exam(exam bool) *ContestBuilder {
+	b.item.Exam = exam
+	return b
+}
+
+func (b *ContestBuilder) IpWhitelist(ipWhitelist *string) *ContestBuilder {
+	b.item.IpWhitelist = ipWhitelist
+	return b
+}
+
+func (b *ContestBuilder) Hack(hack bool) *ContestBuilder {
+	b.item.Hack = hack
+	return b
+}
+
+func (b *ContestBuilder) HackScore(hackScore int) *ContestBuilder {
+	b.item.HackScore = hackScore
+	return b
+}
+
+func (b *ContestBuilder) HackPenalty(hackPenalty int) *ContestBuilder {
+	b.item.HackPenalty = hackPenalty
+	return b
+}
+
+func (b *ContestBuilder) Pretest(pretest bool) *ContestBuilder {
+	b.item.Pretest = pretest
+	return b
+}
+
 func (b *ContestBuilder) Build() *Contest {
 	return b.item
 }
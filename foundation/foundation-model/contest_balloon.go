@@ -0,0 +1,26 @@
+package foundationmodel
+
+import (
+	foundationenum "foundation/foundation-enum"
+	"time"
+)
+
+// ContestBalloon 气球配送任务，每个用户（队伍）每道题首次通过时生成
+type ContestBalloon struct {
+	Id          int                                 `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ContestId   int                                 `json:"contest_id" gorm:"column:contest_id"`
+	ProblemId   int                                 `json:"problem_id" gorm:"column:problem_id"`
+	OwnerId     int                                 `json:"-" gorm:"column:owner_id"` // 队伍为负的队伍Id，否则为用户Id，与榜单行的标识一致
+	UserId      int                                 `json:"user_id" gorm:"column:user_id"`
+	TeamId      *int                                `json:"team_id,omitempty" gorm:"column:team_id"`
+	JudgeJobId  int                                 `json:"judge_job_id" gorm:"column:judge_job_id"`
+	AcTime      time.Time                           `json:"ac_time" gorm:"column:ac_time"`
+	Status      foundationenum.ContestBalloonStatus `json:"status" gorm:"column:status"`
+	Volunteer   *int                                `json:"volunteer,omitempty" gorm:"column:volunteer"`
+	ClaimTime   *time.Time                          `json:"claim_time,omitempty" gorm:"column:claim_time"`
+	DeliverTime *time.Time                          `json:"deliver_time,omitempty" gorm:"column:deliver_time"`
+}
+
+func (*ContestBalloon) TableName() string {
+	return "contest_balloon"
+}
@@ -0,0 +1,140 @@
+package foundationmodel
+
+import "time"
+
+// ContestClarification 比赛答疑，选手向裁判提问，裁判私下回复或公开给所有人
+// Question为空时为裁判直接发布的公告
+type ContestClarification struct {
+	Id         int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ContestId  int        `json:"contest_id" gorm:"column:contest_id"`
+	ProblemId  *int       `json:"problem_id,omitempty" gorm:"column:problem_id"` // 为空时为比赛的通用问题
+	Question   *string    `json:"question,omitempty" gorm:"column:question;type:text"`
+	Answer     *string    `json:"answer,omitempty" gorm:"column:answer;type:text"`
+	Public     bool       `json:"public" gorm:"column:public"` // 是否对所有人可见
+	Inserter   int        `json:"inserter" gorm:"column:inserter"`
+	InsertTime time.Time  `json:"insert_time" gorm:"column:insert_time"`
+	Answerer   *int       `json:"answerer,omitempty" gorm:"column:answerer"`
+	AnswerTime *time.Time `json:"answer_time,omitempty" gorm:"column:answer_time"`
+}
+
+func (*ContestClarification) TableName() string {
+	return "contest_clarification"
+}
+
+type ContestClarificationBuilder struct {
+	item *ContestClarification
+}
+
+func NewContestClarificationBuilder() *ContestClarificationBuilder {
+	return &ContestClarificationBuilder{item: &ContestClarification{}}
+}
+
+func (b *ContestClarificationBuilder) ContestId(contestId int) *ContestClarificationBuilder {
+	b.item.ContestId = contestId
+	return b
+}
+
+func (b *ContestClarificationBuilder) ProblemId(problemId *int) *ContestClarificationBuilder {
+	b.item.ProblemId = problemId
+	return b
+}
+
+func (b *ContestClarificationBuilder) Question(question *string) *ContestClarificationBuilder {
+	b.item.Question = question
+	return b
+}
+
+func (b *ContestClarificationBuilder) Answer(answer *string) *ContestClarificationBuilder {
+	b.item.Answer = answer
+	return b
+}
+
+func (b *ContestClarificationBuilder) Public(public bool) *ContestClarificationBuilder {
+	b.item.Public = public
+	return b
+}
+
+func (b *ContestClarificationBuilder) Inserter(inserter int) *ContestClarificationBuilder {
+	b.item.Inserter = inserter
+	return b
+}
+
+func (b *ContestClarificationBuilder) InsertTime(insertTime time.Time) *ContestClarificationBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *ContestClarificationBuilder) Answerer(answerer *int) *ContestClarificationBuilder {
+	b.item.Answerer = answerer
+	return b
+}
+
+func (b *ContestClarificationBuilder) AnswerTime(answerTime *time.Time) *ContestClarificationBuilder {
+	b.item.AnswerTime = answerTime
+	return b
+}
+
+func (b *ContestClarificationBuilder) Build() *ContestClarification {
+	return b.item
+}
+
+// ContestClarificationRead 用户最后一次查看答疑的时间，用于计算未读数量
+type ContestClarificationRead struct {
+	ContestId int       `gorm:"column:contest_id;primaryKey"`
+	UserId    int       `gorm:"column:user_id;primaryKey"`
+	ReadTime  time.Time `gorm:"column:read_time"`
+}
+
+func (*ContestClarificationRead) TableName() string {
+	return "contest_clarification_read"
+}
+
+// ContestClarificationTemplate 裁判个人的常用回复模板，可以在不同比赛中复用
+type ContestClarificationTemplate struct {
+	Id         int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Title      string    `json:"title" gorm:"column:title;type:varchar(50)"`
+	Content    string    `json:"content" gorm:"column:content;type:text"`
+	Inserter   int       `json:"inserter" gorm:"column:inserter"`
+	InsertTime time.Time `json:"insert_time" gorm:"column:insert_time"`
+}
+
+func (*ContestClarificationTemplate) TableName() string {
+	return "contest_clarification_template"
+}
+
+type ContestClarificationTemplateBuilder struct {
+	item *ContestClarificationTemplate
+}
+
+func NewContestClarificationTemplateBuilder() *ContestClarificationTemplateBuilder {
+	return &ContestClarificationTemplateBuilder{item: &ContestClarificationTemplate{}}
+}
+
+func (b *ContestClarificationTemplateBuilder) Id(id int) *ContestClarificationTemplateBuilder {
+	b.item.Id = id
+	return b
+}
+
+func (b *ContestClarificationTemplateBuilder) Title(title string) *ContestClarificationTemplateBuilder {
+	b.item.Title = title
+	return b
+}
+
+func (b *ContestClarificationTemplateBuilder) Content(content string) *ContestClarificationTemplateBuilder {
+	b.item.Content = content
+	return b
+}
+
+func (b *ContestClarificationTemplateBuilder) Inserter(inserter int) *ContestClarificationTemplateBuilder {
+	b.item.Inserter = inserter
+	return b
+}
+
+func (b *ContestClarificationTemplateBuilder) InsertTime(insertTime time.Time) *ContestClarificationTemplateBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *ContestClarificationTemplateBuilder) Build() *ContestClarificationTemplate {
+	return b.item
+}
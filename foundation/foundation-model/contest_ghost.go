@@ -0,0 +1,112 @@
+package foundationmodel
+
+// ContestGhost 镜像比赛时导入的原比赛榜单，只用于展示，不参与本站排名
+type ContestGhost struct {
+	Id        int    `gorm:"column:id;primaryKey;autoIncrement"`
+	ContestId int    `gorm:"column:contest_id"`
+	Name      string `gorm:"column:name;type:varchar(100)"`
+	Rank      int    `gorm:"column:rank"`
+	Score     int    `gorm:"column:score"`
+	Penalty   int    `gorm:"column:penalty"` // 罚时，单位秒
+}
+
+func (*ContestGhost) TableName() string {
+	return "contest_ghost"
+}
+
+type ContestGhostBuilder struct {
+	item *ContestGhost
+}
+
+func NewContestGhostBuilder() *ContestGhostBuilder {
+	return &ContestGhostBuilder{item: &ContestGhost{}}
+}
+
+func (b *ContestGhostBuilder) Id(id int) *ContestGhostBuilder {
+	b.item.Id = id
+	return b
+}
+
+func (b *ContestGhostBuilder) ContestId(contestId int) *ContestGhostBuilder {
+	b.item.ContestId = contestId
+	return b
+}
+
+func (b *ContestGhostBuilder) Name(name string) *ContestGhostBuilder {
+	b.item.Name = name
+	return b
+}
+
+func (b *ContestGhostBuilder) Rank(rank int) *ContestGhostBuilder {
+	b.item.Rank = rank
+	return b
+}
+
+func (b *ContestGhostBuilder) Score(score int) *ContestGhostBuilder {
+	b.item.Score = score
+	return b
+}
+
+func (b *ContestGhostBuilder) Penalty(penalty int) *ContestGhostBuilder {
+	b.item.Penalty = penalty
+	return b
+}
+
+func (b *ContestGhostBuilder) Build() *ContestGhost {
+	return b.item
+}
+
+type ContestGhostProblem struct {
+	GhostId      int  `gorm:"column:ghost_id;primaryKey"`
+	ProblemIndex int  `gorm:"column:problem_index;primaryKey"` // 比赛中的题目索引，从1开始
+	Accepted     bool `gorm:"column:accepted"`
+	Attempt      int  `gorm:"column:attempt"` // 通过前的错误次数
+	Time         int  `gorm:"column:time"`    // 通过时距比赛开始的秒数
+	Score        int  `gorm:"column:score"`
+}
+
+func (*ContestGhostProblem) TableName() string {
+	return "contest_ghost_problem"
+}
+
+type ContestGhostProblemBuilder struct {
+	item *ContestGhostProblem
+}
+
+func NewContestGhostProblemBuilder() *ContestGhostProblemBuilder {
+	return &ContestGhostProblemBuilder{item: &ContestGhostProblem{}}
+}
+
+func (b *ContestGhostProblemBuilder) GhostId(ghostId int) *ContestGhostProblemBuilder {
+	b.item.GhostId = ghostId
+	return b
+}
+
+func (b *ContestGhostProblemBuilder) ProblemIndex(problemIndex int) *ContestGhostProblemBuilder {
+	b.item.ProblemIndex = problemIndex
+	return b
+}
+
+func (b *ContestGhostProblemBuilder) Accepted(accepted bool) *ContestGhostProblemBuilder {
+	b.item.Accepted = accepted
+	return b
+}
+
+func (b *ContestGhostProblemBuilder) Attempt(attempt int) *ContestGhostProblemBuilder {
+	b.item.Attempt = attempt
+	return b
+}
+
+func (b *ContestGhostProblemBuilder) Time(time int) *ContestGhostProblemBuilder {
+	b.item.Time = time
+	return b
+}
+
+func (b *ContestGhostProblemBuilder) Score(score int) *ContestGhostProblemBuilder {
+	b.item.Score = score
+	return b
+}
+
+func (b *ContestGhostProblemBuilder) Build() *ContestGhostProblem {
+	return b.item
+}
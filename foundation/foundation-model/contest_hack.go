@@ -0,0 +1,91 @@
+package foundationmodel
+
+import (
+	foundationenum "foundation/foundation-enum"
+	"time"
+)
+
+// ContestHack 比赛中对他人已通过代码的一次挑战
+type ContestHack struct {
+	Id         int                              `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ContestId  int                              `json:"contest_id" gorm:"column:contest_id"`
+	ProblemId  int                              `json:"problem_id" gorm:"column:problem_id"`
+	JudgeId    int                              `json:"judge_id" gorm:"column:judge_id"` // 被挑战的提交
+	Hacker     int                              `json:"hacker" gorm:"column:hacker"`
+	Defendant  int                              `json:"defendant" gorm:"column:defendant"` // 被挑战的用户
+	Input      string                           `json:"input" gorm:"column:input;type:text"`
+	Output     *string                          `json:"output,omitempty" gorm:"column:output;type:text"` // 标准程序的输出，挑战成功后作为新的测试数据
+	Status     foundationenum.ContestHackStatus `json:"status" gorm:"column:status"`
+	Message    *string                          `json:"message,omitempty" gorm:"column:message;type:text"`
+	Judger     *string                          `json:"judger,omitempty" gorm:"column:judger;type:varchar(10)"`
+	InsertTime time.Time                        `json:"insert_time" gorm:"column:insert_time"`
+	JudgeTime  *time.Time                       `json:"judge_time,omitempty" gorm:"column:judge_time"`
+}
+
+func (*ContestHack) TableName() string {
+	return "contest_hack"
+}
+
+type ContestHackBuilder struct {
+	item *ContestHack
+}
+
+func NewContestHackBuilder() *ContestHackBuilder {
+	return &ContestHackBuilder{item: &ContestHack{}}
+}
+
+func (b *ContestHackBuilder) ContestId(contestId int) *ContestHackBuilder {
+	b.item.ContestId = contestId
+	return b
+}
+
+func (b *ContestHackBuilder) ProblemId(problemId int) *ContestHackBuilder {
+	b.item.ProblemId = problemId
+	return b
+}
+
+func (b *ContestHackBuilder) JudgeId(judgeId int) *ContestHackBuilder {
+	b.item.JudgeId = judgeId
+	return b
+}
+
+func (b *ContestHackBuilder) Hacker(hacker int) *ContestHackBuilder {
+	b.item.Hacker = hacker
+	return b
+}
+
+func (b *ContestHackBuilder) Defendant(defendant int) *ContestHackBuilder {
+	b.item.Defendant = defendant
+	return b
+}
+
+func (b *ContestHackBuilder) Input(input string) *ContestHackBuilder {
+	b.item.Input = input
+	return b
+}
+
+func (b *ContestHackBuilder) Status(status foundationenum.ContestHackStatus) *ContestHackBuilder {
+	b.item.Status = status
+	return b
+}
+
+func (b *ContestHackBuilder) InsertTime(insertTime time.Time) *ContestHackBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *ContestHackBuilder) Build() *ContestHack {
+	return b.item
+}
+
+// ContestProblemLock 锁定题目后不能再提交该题，但可以查看他人已通过的代码并发起挑战
+type ContestProblemLock struct {
+	Id         int       `gorm:"column:id;primaryKey"`
+	ProblemId  int       `gorm:"column:problem_id;primaryKey"`
+	UserId     int       `gorm:"column:user_id;primaryKey"`
+	InsertTime time.Time `gorm:"column:insert_time"`
+}
+
+func (*ContestProblemLock) TableName() string {
+	return "contest_problem_lock"
+}
@@ -1,9 +1,13 @@
 package foundationmodel
 
+import "time"
+
 type ContestMember struct {
-	Id          int    `gorm:"column:id;primaryKey"`
-	UserId      int    `gorm:"column:user_id;primaryKey"`
-	ContestName string `gorm:"column:contest_name"`
+	Id          int        `gorm:"column:id;primaryKey"`
+	UserId      int        `gorm:"column:user_id;primaryKey"`
+	ContestName string     `gorm:"column:contest_name"`
+	StartTime   *time.Time `gorm:"column:start_time"` // 个人比赛窗口的开始时间，未开始为空
+	Location    *string    `gorm:"column:location"`   // 现场赛的房间或座位，用于配送气球
 }
 
 func (p *ContestMember) TableName() string {
@@ -35,6 +39,11 @@ func (b *ContestMemberBuilder) ContestName(contestName string) *ContestMemberBui
 	return b
 }
 
+func (b *ContestMemberBuilder) Location(location *string) *ContestMemberBuilder {
+	b.item.Location = location
+	return b
+}
+
 func (b *ContestMemberBuilder) Build() *ContestMember {
 	return b.item
 }
@@ -0,0 +1,68 @@
+package foundationmodel
+
+import (
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	"time"
+)
+
+// ContestPrint 现场赛的打印任务
+type ContestPrint struct {
+	Id         int                               `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ContestId  int                               `json:"contest_id" gorm:"column:contest_id"`
+	UserId     int                               `json:"user_id" gorm:"column:user_id"`
+	Language   foundationjudge.JudgeLanguage     `json:"language" gorm:"column:language"` // 用于代码高亮，未知语言按纯文本处理
+	Content    string                            `json:"content" gorm:"column:content;type:text"`
+	Pages      int                               `json:"pages" gorm:"column:pages"` // 按纯文本排版计算的页数，用于配额限制
+	Status     foundationenum.ContestPrintStatus `json:"status" gorm:"column:status"`
+	Printer    *int                              `json:"printer,omitempty" gorm:"column:printer"`
+	InsertTime time.Time                         `json:"insert_time" gorm:"column:insert_time"`
+	ClaimTime  *time.Time                        `json:"claim_time,omitempty" gorm:"column:claim_time"`
+	FinishTime *time.Time                        `json:"finish_time,omitempty" gorm:"column:finish_time"`
+}
+
+func (*ContestPrint) TableName() string {
+	return "contest_print"
+}
+
+type ContestPrintBuilder struct {
+	item *ContestPrint
+}
+
+func NewContestPrintBuilder() *ContestPrintBuilder {
+	return &ContestPrintBuilder{item: &ContestPrint{}}
+}
+
+func (b *ContestPrintBuilder) ContestId(contestId int) *ContestPrintBuilder {
+	b.item.ContestId = contestId
+	return b
+}
+
+func (b *ContestPrintBuilder) UserId(userId int) *ContestPrintBuilder {
+	b.item.UserId = userId
+	return b
+}
+
+func (b *ContestPrintBuilder) Language(language foundationjudge.JudgeLanguage) *ContestPrintBuilder {
+	b.item.Language = language
+	return b
+}
+
+func (b *ContestPrintBuilder) Content(content string) *ContestPrintBuilder {
+	b.item.Content = content
+	return b
+}
+
+func (b *ContestPrintBuilder) Pages(pages int) *ContestPrintBuilder {
+	b.item.Pages = pages
+	return b
+}
+
+func (b *ContestPrintBuilder) InsertTime(insertTime time.Time) *ContestPrintBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *ContestPrintBuilder) Build() *ContestPrint {
+	return b.item
+}
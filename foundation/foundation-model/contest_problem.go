@@ -5,7 +5,14 @@ type ContestProblem struct {
 	ProblemId int   `gorm:"column:problem_id;primaryKey"`
 	Index     uint8 `gorm:"column:index;type:tinyint(1) unsigned;"`
 	ViewId    *int  `gorm:"column:view_id;"`
-	Score     int   `gorm:"column:score;"`
+	Score     int   `gorm:"column:score;"` // 题目满分，0则按默认分数计算
+
+	ScoreDecay   int `gorm:"column:score_decay;not null;default:0"`   // 每分钟衰减得分的千分比
+	ScorePenalty int `gorm:"column:score_penalty;not null;default:0"` // 每次错误提交扣除的分数
+	ScoreMin     int `gorm:"column:score_min;not null;default:0"`     // 衰减与扣分后至少保留得分的百分比
+
+	TimeLimit   *int `gorm:"column:time_limit"`   // 比赛中覆盖题目的时间限制，单位ms
+	MemoryLimit *int `gorm:"column:memory_limit"` // 比赛中覆盖题目的内存限制，单位KB
 }
 
 func (p *ContestProblem) TableName() string {
@@ -47,6 +54,31 @@ func (b *ContestProblemBuilder) Score(score int) *ContestProblemBuilder {
 	return b
 }
 
+func (b *ContestProblemBuilder) ScoreDecay(scoreDecay int) *ContestProblemBuilder {
+	b.item.ScoreDecay = scoreDecay
+	return b
+}
+
+func (b *ContestProblemBuilder) ScorePenalty(scorePenalty int) *ContestProblemBuilder {
+	b.item.ScorePenalty = scorePenalty
+	return b
+}
+
+func (b *ContestProblemBuilder) ScoreMin(scoreMin int) *ContestProblemBuilder {
+	b.item.ScoreMin = scoreMin
+	return b
+}
+
+func (b *ContestProblemBuilder) TimeLimit(timeLimit *int) *ContestProblemBuilder {
+	b.item.TimeLimit = timeLimit
+	return b
+}
+
+func (b *ContestProblemBuilder) MemoryLimit(memoryLimit *int) *ContestProblemBuilder {
+	b.item.MemoryLimit = memoryLimit
+	return b
+}
+
 func (b *ContestProblemBuilder) Build() *ContestProblem {
 	return b.item
 }
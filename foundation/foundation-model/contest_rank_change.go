@@ -0,0 +1,16 @@
+package foundationmodel
+
+import "time"
+
+// ContestRankChange 比赛榜单的变更记录，用于各进程增量更新榜单缓存
+// JudgeJobId为空时表示需要重建整个比赛的榜单（如重判、修改比赛）
+type ContestRankChange struct {
+	Id         int       `gorm:"column:id;primaryKey;autoIncrement"`
+	ContestId  int       `gorm:"column:contest_id"`
+	JudgeJobId *int      `gorm:"column:judge_job_id"`
+	InsertTime time.Time `gorm:"column:insert_time"`
+}
+
+func (*ContestRankChange) TableName() string {
+	return "contest_rank_change"
+}
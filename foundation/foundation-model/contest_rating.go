@@ -0,0 +1,17 @@
+package foundationmodel
+
+import "time"
+
+// ContestRating 用户在计分比赛中的Rating变化
+type ContestRating struct {
+	ContestId  int       `json:"contest_id" gorm:"column:contest_id;primaryKey"`
+	UserId     int       `json:"user_id" gorm:"column:user_id;primaryKey"`
+	Rank       int       `json:"rank" gorm:"column:rank"`
+	OldRating  int       `json:"old_rating" gorm:"column:old_rating"`
+	NewRating  int       `json:"new_rating" gorm:"column:new_rating"`
+	InsertTime time.Time `json:"insert_time" gorm:"column:insert_time"`
+}
+
+func (*ContestRating) TableName() string {
+	return "contest_rating"
+}
@@ -0,0 +1,64 @@
+package foundationmodel
+
+import "time"
+
+// ContestTeam 比赛中的队伍，队伍成员的提交都计入队伍，在榜单中共用一行
+type ContestTeam struct {
+	Id         int       `gorm:"column:id;primaryKey;autoIncrement"`
+	ContestId  int       `gorm:"column:contest_id"`
+	Name       string    `gorm:"column:name;type:varchar(50)"`
+	Inserter   int       `gorm:"column:inserter"`
+	InsertTime time.Time `gorm:"column:insert_time"`
+}
+
+func (*ContestTeam) TableName() string {
+	return "contest_team"
+}
+
+type ContestTeamBuilder struct {
+	item *ContestTeam
+}
+
+func NewContestTeamBuilder() *ContestTeamBuilder {
+	return &ContestTeamBuilder{item: &ContestTeam{}}
+}
+
+func (b *ContestTeamBuilder) Id(id int) *ContestTeamBuilder {
+	b.item.Id = id
+	return b
+}
+
+func (b *ContestTeamBuilder) ContestId(contestId int) *ContestTeamBuilder {
+	b.item.ContestId = contestId
+	return b
+}
+
+func (b *ContestTeamBuilder) Name(name string) *ContestTeamBuilder {
+	b.item.Name = name
+	return b
+}
+
+func (b *ContestTeamBuilder) Inserter(inserter int) *ContestTeamBuilder {
+	b.item.Inserter = inserter
+	return b
+}
+
+func (b *ContestTeamBuilder) InsertTime(insertTime time.Time) *ContestTeamBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *ContestTeamBuilder) Build() *ContestTeam {
+	return b.item
+}
+
+// ContestTeamMember 队伍成员，同一比赛中一个用户只能属于一个队伍
+type ContestTeamMember struct {
+	TeamId    int `gorm:"column:team_id;primaryKey"`
+	ContestId int `gorm:"column:contest_id"`
+	UserId    int `gorm:"column:user_id;primaryKey"`
+}
+
+func (*ContestTeamMember) TableName() string {
+	return "contest_team_member"
+}
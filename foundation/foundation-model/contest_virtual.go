@@ -0,0 +1,54 @@
+package foundationmodel
+
+import "time"
+
+// ContestVirtual 虚拟参赛记录，用户在比赛结束后按原比赛时长开始自己的比赛时间
+type ContestVirtual struct {
+	Id         int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ContestId  int       `json:"contest_id" gorm:"column:contest_id"`
+	UserId     int       `json:"user_id" gorm:"column:user_id"`
+	StartTime  time.Time `json:"start_time" gorm:"column:start_time"`
+	EndTime    time.Time `json:"end_time" gorm:"column:end_time"`
+	InsertTime time.Time `json:"insert_time" gorm:"column:insert_time"`
+}
+
+func (*ContestVirtual) TableName() string {
+	return "contest_virtual"
+}
+
+type ContestVirtualBuilder struct {
+	item *ContestVirtual
+}
+
+func NewContestVirtualBuilder() *ContestVirtualBuilder {
+	return &ContestVirtualBuilder{item: &ContestVirtual{}}
+}
+
+func (b *ContestVirtualBuilder) ContestId(contestId int) *ContestVirtualBuilder {
+	b.item.ContestId = contestId
+	return b
+}
+
+func (b *ContestVirtualBuilder) UserId(userId int) *ContestVirtualBuilder {
+	b.item.UserId = userId
+	return b
+}
+
+func (b *ContestVirtualBuilder) StartTime(startTime time.Time) *ContestVirtualBuilder {
+	b.item.StartTime = startTime
+	return b
+}
+
+func (b *ContestVirtualBuilder) EndTime(endTime time.Time) *ContestVirtualBuilder {
+	b.item.EndTime = endTime
+	return b
+}
+
+func (b *ContestVirtualBuilder) InsertTime(insertTime time.Time) *ContestVirtualBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *ContestVirtualBuilder) Build() *ContestVirtual {
+	return b.item
+}
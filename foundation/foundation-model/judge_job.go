@@ -25,6 +25,8 @@ type JudgeJob struct {
 	RemoteAccountId *string                       `json:"remote_account_id,omitempty" gorm:"column:remote_account_id"`
 	Inserter        int                           `json:"inserter" gorm:"column:inserter;not null"`
 	InsertTime      time.Time                     `json:"insert_time" gorm:"column:insert_time;not null"`
+	VirtualId       *int                          `json:"virtual_id,omitempty" gorm:"column:virtual_id"` // 虚拟参赛记录Id，虚拟参赛期间的提交
+	Pretest         bool                          `json:"pretest,omitempty" gorm:"column:pretest"`       // 结果仅来自预测试，系统测试后才是最终结果
 }
 
 // TableName 重写表名
@@ -141,6 +143,15 @@ func (b *JudgeJobBuilder) InsertTime(t time.Time) *JudgeJobBuilder {
 	return b
 }
 
+func (b *JudgeJobBuilder) VirtualId(virtualId int) *JudgeJobBuilder {
+	if virtualId <= 0 {
+		b.item.VirtualId = nil
+	} else {
+		b.item.VirtualId = &virtualId
+	}
+	return b
+}
+
 func (b *JudgeJobBuilder) Build() *JudgeJob {
 	return b.item
 }
@@ -0,0 +1,73 @@
+package foundationmodel
+
+import (
+	"time"
+)
+
+// JudgeJobRemoteSubmit 远程提交记录，用于避免重复提交以及找回提交结果未知的RunId
+type JudgeJobRemoteSubmit struct {
+	Id          int       `json:"id" gorm:"column:id;primaryKey"`                         // 评测任务Id
+	OriginOj    string    `json:"origin_oj" gorm:"column:origin_oj;size:10;not null"`     // 来源OJ
+	Fingerprint string    `json:"fingerprint" gorm:"column:fingerprint;size:64;not null"` // 提交前根据题目、语言、代码生成的指纹
+	Attempt     int       `json:"attempt" gorm:"column:attempt;not null"`                 // 累计提交次数
+	SubmitTime  time.Time `json:"submit_time" gorm:"column:submit_time;not null"`         // 最近一次提交时间
+	RunId       *string   `json:"run_id,omitempty" gorm:"column:run_id;size:20"`          // 远程RunId，为空表示提交结果未知
+	Account     *string   `json:"account,omitempty" gorm:"column:account;size:20"`        // 远程账号
+	ModifyTime  time.Time `json:"modify_time" gorm:"column:modify_time;not null"`
+}
+
+func (*JudgeJobRemoteSubmit) TableName() string {
+	return "judge_job_remote_submit"
+}
+
+type JudgeJobRemoteSubmitBuilder struct {
+	item *JudgeJobRemoteSubmit
+}
+
+func NewJudgeJobRemoteSubmitBuilder() *JudgeJobRemoteSubmitBuilder {
+	return &JudgeJobRemoteSubmitBuilder{item: &JudgeJobRemoteSubmit{}}
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) Id(id int) *JudgeJobRemoteSubmitBuilder {
+	b.item.Id = id
+	return b
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) OriginOj(originOj string) *JudgeJobRemoteSubmitBuilder {
+	b.item.OriginOj = originOj
+	return b
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) Fingerprint(fingerprint string) *JudgeJobRemoteSubmitBuilder {
+	b.item.Fingerprint = fingerprint
+	return b
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) Attempt(attempt int) *JudgeJobRemoteSubmitBuilder {
+	b.item.Attempt = attempt
+	return b
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) SubmitTime(submitTime time.Time) *JudgeJobRemoteSubmitBuilder {
+	b.item.SubmitTime = submitTime
+	return b
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) RunId(runId *string) *JudgeJobRemoteSubmitBuilder {
+	b.item.RunId = runId
+	return b
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) Account(account *string) *JudgeJobRemoteSubmitBuilder {
+	b.item.Account = account
+	return b
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) ModifyTime(modifyTime time.Time) *JudgeJobRemoteSubmitBuilder {
+	b.item.ModifyTime = modifyTime
+	return b
+}
+
+func (b *JudgeJobRemoteSubmitBuilder) Build() *JudgeJobRemoteSubmit {
+	return b.item
+}
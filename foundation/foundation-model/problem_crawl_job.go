@@ -0,0 +1,112 @@
+package foundationmodel
+
+import (
+	foundationenum "foundation/foundation-enum"
+	"time"
+)
+
+// ProblemCrawlJob 批量爬取远程题目的任务
+type ProblemCrawlJob struct {
+	Id         int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	OriginOj   string    `json:"origin_oj" gorm:"column:origin_oj;size:10;not null"` // 来源OJ
+	Total      int       `json:"total" gorm:"column:total;not null"`                 // 题目总数
+	Inserter   int       `json:"inserter" gorm:"column:inserter;not null"`
+	InsertTime time.Time `json:"insert_time" gorm:"column:insert_time;not null"`
+}
+
+func (*ProblemCrawlJob) TableName() string {
+	return "problem_crawl_job"
+}
+
+type ProblemCrawlJobBuilder struct {
+	item *ProblemCrawlJob
+}
+
+func NewProblemCrawlJobBuilder() *ProblemCrawlJobBuilder {
+	return &ProblemCrawlJobBuilder{item: &ProblemCrawlJob{}}
+}
+
+func (b *ProblemCrawlJobBuilder) Id(id int) *ProblemCrawlJobBuilder {
+	b.item.Id = id
+	return b
+}
+
+func (b *ProblemCrawlJobBuilder) OriginOj(originOj string) *ProblemCrawlJobBuilder {
+	b.item.OriginOj = originOj
+	return b
+}
+
+func (b *ProblemCrawlJobBuilder) Total(total int) *ProblemCrawlJobBuilder {
+	b.item.Total = total
+	return b
+}
+
+func (b *ProblemCrawlJobBuilder) Inserter(inserter int) *ProblemCrawlJobBuilder {
+	b.item.Inserter = inserter
+	return b
+}
+
+func (b *ProblemCrawlJobBuilder) InsertTime(insertTime time.Time) *ProblemCrawlJobBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *ProblemCrawlJobBuilder) Build() *ProblemCrawlJob {
+	return b.item
+}
+
+// ProblemCrawlJobItem 批量爬取任务中的单个题目
+type ProblemCrawlJobItem struct {
+	Id         int                               `json:"id" gorm:"column:id;primaryKey"`                          // 任务Id
+	OriginId   string                            `json:"origin_id" gorm:"column:origin_id;size:20;primaryKey"`    // 来源OJ的题目Id
+	Status     foundationenum.ProblemCrawlStatus `json:"status" gorm:"column:status;not null"`                    // 爬取状态
+	ProblemKey *string                           `json:"problem_key,omitempty" gorm:"column:problem_key;size:18"` // 爬取成功后的题目Key
+	Message    *string                           `json:"message,omitempty" gorm:"column:message;type:text"`       // 失败原因
+	ModifyTime time.Time                         `json:"modify_time" gorm:"column:modify_time;not null"`
+}
+
+func (*ProblemCrawlJobItem) TableName() string {
+	return "problem_crawl_job_item"
+}
+
+type ProblemCrawlJobItemBuilder struct {
+	item *ProblemCrawlJobItem
+}
+
+func NewProblemCrawlJobItemBuilder() *ProblemCrawlJobItemBuilder {
+	return &ProblemCrawlJobItemBuilder{item: &ProblemCrawlJobItem{}}
+}
+
+func (b *ProblemCrawlJobItemBuilder) Id(id int) *ProblemCrawlJobItemBuilder {
+	b.item.Id = id
+	return b
+}
+
+func (b *ProblemCrawlJobItemBuilder) OriginId(originId string) *ProblemCrawlJobItemBuilder {
+	b.item.OriginId = originId
+	return b
+}
+
+func (b *ProblemCrawlJobItemBuilder) Status(status foundationenum.ProblemCrawlStatus) *ProblemCrawlJobItemBuilder {
+	b.item.Status = status
+	return b
+}
+
+func (b *ProblemCrawlJobItemBuilder) ProblemKey(problemKey *string) *ProblemCrawlJobItemBuilder {
+	b.item.ProblemKey = problemKey
+	return b
+}
+
+func (b *ProblemCrawlJobItemBuilder) Message(message *string) *ProblemCrawlJobItemBuilder {
+	b.item.Message = message
+	return b
+}
+
+func (b *ProblemCrawlJobItemBuilder) ModifyTime(modifyTime time.Time) *ProblemCrawlJobItemBuilder {
+	b.item.ModifyTime = modifyTime
+	return b
+}
+
+func (b *ProblemCrawlJobItemBuilder) Build() *ProblemCrawlJobItem {
+	return b.item
+}
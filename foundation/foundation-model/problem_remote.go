@@ -5,7 +5,7 @@ type ProblemRemote struct {
 	ProblemId int `json:"problem_id" bson:"problem_id" gorm:"column:problem_id;unique;not null"` // 题目Id
 
 	OriginOj     string  `json:"origin_oj" bson:"origin_oj,omitempty" gorm:"column:origin_oj;size:10;not null"`               // 来源OJ
-	OriginId     string  `json:"origin_id" bson:"origin_id,omitempty" gorm:"column:origin_id;size:8;not null"`                // 来源OJ
+	OriginId     string  `json:"origin_id" bson:"origin_id,omitempty" gorm:"column:origin_id;size:20;not null"`               // 来源OJ
 	OriginUrl    string  `json:"origin_url,omitempty" bson:"origin_url,omitempty" gorm:"column:origin_url;size:100;not null"` // 来源链接
 	OriginAuthor *string `json:"origin_author,omitempty" bson:"origin_author,omitempty" gorm:"column:origin_author;size:20"`  // 来源作者
 }
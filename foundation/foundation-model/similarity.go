@@ -0,0 +1,80 @@
+package foundationmodel
+
+import (
+	foundationenum "foundation/foundation-enum"
+	foundationsimilarity "foundation/foundation-similarity"
+	"time"
+)
+
+// SimilarityReport 一次代码相似度检测，覆盖题目、题集或比赛中通过的代码
+type SimilarityReport struct {
+	Id              int                                   `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ScopeType       foundationenum.SimilarityScopeType    `json:"scope_type" gorm:"column:scope_type"`
+	ScopeId         int                                   `json:"scope_id" gorm:"column:scope_id"`
+	Threshold       int                                   `json:"threshold" gorm:"column:threshold"` // 相似度阈值百分比，低于阈值的代码对不记录
+	Status          foundationenum.SimilarityReportStatus `json:"status" gorm:"column:status"`
+	SubmissionCount int                                   `json:"submission_count" gorm:"column:submission_count"`
+	PairCount       int                                   `json:"pair_count" gorm:"column:pair_count"`
+	Message         *string                               `json:"message,omitempty" gorm:"column:message"`
+	Inserter        int                                   `json:"inserter" gorm:"column:inserter"`
+	InsertTime      time.Time                             `json:"insert_time" gorm:"column:insert_time"`
+	FinishTime      *time.Time                            `json:"finish_time,omitempty" gorm:"column:finish_time"`
+}
+
+func (*SimilarityReport) TableName() string {
+	return "similarity_report"
+}
+
+type SimilarityReportBuilder struct {
+	item *SimilarityReport
+}
+
+func NewSimilarityReportBuilder() *SimilarityReportBuilder {
+	return &SimilarityReportBuilder{item: &SimilarityReport{}}
+}
+
+func (b *SimilarityReportBuilder) ScopeType(scopeType foundationenum.SimilarityScopeType) *SimilarityReportBuilder {
+	b.item.ScopeType = scopeType
+	return b
+}
+
+func (b *SimilarityReportBuilder) ScopeId(scopeId int) *SimilarityReportBuilder {
+	b.item.ScopeId = scopeId
+	return b
+}
+
+func (b *SimilarityReportBuilder) Threshold(threshold int) *SimilarityReportBuilder {
+	b.item.Threshold = threshold
+	return b
+}
+
+func (b *SimilarityReportBuilder) Inserter(inserter int) *SimilarityReportBuilder {
+	b.item.Inserter = inserter
+	return b
+}
+
+func (b *SimilarityReportBuilder) InsertTime(insertTime time.Time) *SimilarityReportBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *SimilarityReportBuilder) Build() *SimilarityReport {
+	return b.item
+}
+
+// SimilarityPair 相似度超过阈值的一对代码，Left为较早的提交
+type SimilarityPair struct {
+	Id           int                            `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ReportId     int                            `json:"report_id" gorm:"column:report_id"`
+	ProblemId    int                            `json:"problem_id" gorm:"column:problem_id"`
+	LeftJudgeId  int                            `json:"left_judge_id" gorm:"column:left_judge_id"`
+	LeftUserId   int                            `json:"left_user_id" gorm:"column:left_user_id"`
+	RightJudgeId int                            `json:"right_judge_id" gorm:"column:right_judge_id"`
+	RightUserId  int                            `json:"right_user_id" gorm:"column:right_user_id"`
+	Similarity   float64                        `json:"similarity" gorm:"column:similarity"`
+	Regions      []*foundationsimilarity.Region `json:"regions,omitempty" gorm:"column:regions;type:jsonb;serializer:json"`
+}
+
+func (*SimilarityPair) TableName() string {
+	return "similarity_pair"
+}
@@ -21,6 +21,7 @@ type User struct {
 	VjudgeId     *string                   `json:"vjudge_id,omitempty" gorm:"type:varchar(15);comment:VjudgeId"`
 	Github       *string                   `json:"github,omitempty" gorm:"type:varchar(15);comment:Github"`
 	Codeforces   *string                   `json:"codeforces,omitempty" gorm:"type:varchar(20)"`
+	Hdu          *string                   `json:"hdu,omitempty" gorm:"type:varchar(20)"`
 	CheckInCount int                       `json:"check_in_count,omitempty" gorm:"comment:签到次数"`
 	InsertTime   time.Time                 `json:"insert_time" gorm:"type:datetime;not null"`
 	ModifyTime   time.Time                 `json:"modify_time" gorm:"type:datetime;not null"`
@@ -29,6 +30,10 @@ type User struct {
 	Level        int                       `json:"level,omitempty" gorm:"comment:用户等级"`
 	Experience   int                       `json:"experience,omitempty" gorm:"comment:用户经验值"`
 	Coin         int                       `json:"coin,omitempty" gorm:"comment:用户金币"`
+	Rating       *int                      `json:"rating,omitempty" gorm:"comment:比赛Rating，未参加计分比赛为空"`
+
+	ContestOnlyId *int `json:"contest_only_id,omitempty" gorm:"comment:为比赛批量生成的临时账号所属的比赛"`
+	Disabled      bool `json:"disabled,omitempty" gorm:"comment:是否禁止登录"`
 }
 
 func (u *User) TableName() string {
@@ -113,6 +118,11 @@ func (b *UserBuilder) Codeforces(codeforces *string) *UserBuilder {
 	return b
 }
 
+func (b *UserBuilder) Hdu(hdu *string) *UserBuilder {
+	b.item.Hdu = hdu
+	return b
+}
+
 func (b *UserBuilder) CheckInCount(checkInCount int) *UserBuilder {
 	b.item.CheckInCount = checkInCount
 	return b
@@ -153,6 +163,11 @@ func (b *UserBuilder) Coin(coin int) *UserBuilder {
 	return b
 }
 
+func (b *UserBuilder) ContestOnlyId(contestOnlyId *int) *UserBuilder {
+	b.item.ContestOnlyId = contestOnlyId
+	return b
+}
+
 func (b *UserBuilder) Build() *User {
 	return b.item
 }
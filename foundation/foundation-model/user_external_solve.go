@@ -0,0 +1,124 @@
+package foundationmodel
+
+import (
+	foundationenum "foundation/foundation-enum"
+	"time"
+)
+
+// UserExternalSolve 从外部账号同步的AC记录，不计入本站的AC统计
+type UserExternalSolve struct {
+	UserId     int                               `json:"user_id" gorm:"column:user_id;primaryKey"`
+	Source     foundationenum.UserExternalSource `json:"source" gorm:"column:source;primaryKey;size:10"`       // 同步来源
+	OriginOj   string                            `json:"origin_oj" gorm:"column:origin_oj;primaryKey;size:20"` // 题目所在OJ
+	OriginId   string                            `json:"origin_id" gorm:"column:origin_id;primaryKey;size:20"` // 题目在OJ上的编号
+	SolveTime  *time.Time                        `json:"solve_time,omitempty" gorm:"column:solve_time"`        // 首次AC时间，部分来源无法获取
+	InsertTime time.Time                         `json:"insert_time" gorm:"column:insert_time;not null"`
+}
+
+func (*UserExternalSolve) TableName() string {
+	return "user_external_solve"
+}
+
+type UserExternalSolveBuilder struct {
+	item *UserExternalSolve
+}
+
+func NewUserExternalSolveBuilder() *UserExternalSolveBuilder {
+	return &UserExternalSolveBuilder{item: &UserExternalSolve{}}
+}
+
+func (b *UserExternalSolveBuilder) UserId(userId int) *UserExternalSolveBuilder {
+	b.item.UserId = userId
+	return b
+}
+
+func (b *UserExternalSolveBuilder) Source(source foundationenum.UserExternalSource) *UserExternalSolveBuilder {
+	b.item.Source = source
+	return b
+}
+
+func (b *UserExternalSolveBuilder) OriginOj(originOj string) *UserExternalSolveBuilder {
+	b.item.OriginOj = originOj
+	return b
+}
+
+func (b *UserExternalSolveBuilder) OriginId(originId string) *UserExternalSolveBuilder {
+	b.item.OriginId = originId
+	return b
+}
+
+func (b *UserExternalSolveBuilder) SolveTime(solveTime *time.Time) *UserExternalSolveBuilder {
+	b.item.SolveTime = solveTime
+	return b
+}
+
+func (b *UserExternalSolveBuilder) InsertTime(insertTime time.Time) *UserExternalSolveBuilder {
+	b.item.InsertTime = insertTime
+	return b
+}
+
+func (b *UserExternalSolveBuilder) Build() *UserExternalSolve {
+	return b.item
+}
+
+// UserExternalSync 外部账号的同步状态
+type UserExternalSync struct {
+	UserId     int                                   `json:"user_id" gorm:"column:user_id;primaryKey"`
+	Source     foundationenum.UserExternalSource     `json:"source" gorm:"column:source;primaryKey;size:10"`
+	Account    string                                `json:"account" gorm:"column:account;size:30;not null"` // 同步时使用的外部账号
+	Status     foundationenum.UserExternalSyncStatus `json:"status" gorm:"column:status;not null"`
+	Message    *string                               `json:"message,omitempty" gorm:"column:message;size:255"`
+	SolveCount int                                   `json:"solve_count" gorm:"column:solve_count;not null"`
+	SyncTime   time.Time                             `json:"sync_time" gorm:"column:sync_time;not null"`
+}
+
+func (*UserExternalSync) TableName() string {
+	return "user_external_sync"
+}
+
+type UserExternalSyncBuilder struct {
+	item *UserExternalSync
+}
+
+func NewUserExternalSyncBuilder() *UserExternalSyncBuilder {
+	return &UserExternalSyncBuilder{item: &UserExternalSync{}}
+}
+
+func (b *UserExternalSyncBuilder) UserId(userId int) *UserExternalSyncBuilder {
+	b.item.UserId = userId
+	return b
+}
+
+func (b *UserExternalSyncBuilder) Source(source foundationenum.UserExternalSource) *UserExternalSyncBuilder {
+	b.item.Source = source
+	return b
+}
+
+func (b *UserExternalSyncBuilder) Account(account string) *UserExternalSyncBuilder {
+	b.item.Account = account
+	return b
+}
+
+func (b *UserExternalSyncBuilder) Status(status foundationenum.UserExternalSyncStatus) *UserExternalSyncBuilder {
+	b.item.Status = status
+	return b
+}
+
+func (b *UserExternalSyncBuilder) Message(message *string) *UserExternalSyncBuilder {
+	b.item.Message = message
+	return b
+}
+
+func (b *UserExternalSyncBuilder) SolveCount(solveCount int) *UserExternalSyncBuilder {
+	b.item.SolveCount = solveCount
+	return b
+}
+
+func (b *UserExternalSyncBuilder) SyncTime(syncTime time.Time) *UserExternalSyncBuilder {
+	b.item.SyncTime = syncTime
+	return b
+}
+
+func (b *UserExternalSyncBuilder) Build() *UserExternalSync {
+	return b.item
+}
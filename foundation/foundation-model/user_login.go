@@ -9,6 +9,7 @@ type UserLogin struct {
 	UserId     int       `json:"user_id" gorm:"index;column:user_id"`
 	IP         string    `json:"ip" gorm:"type:inet;column:ip"` // PostgreSQL INET
 	UserAgent  string    `json:"user_agent" gorm:"type:text;column:user_agent"`
+	Session    string    `json:"session,omitempty" gorm:"type:varchar(32);column:session"` // 本次登录签发的会话标识
 	InsertTime time.Time `json:"insert_time" gorm:"column:insert_time"`
 }
 
@@ -44,6 +45,11 @@ func (b *UserLoginBuilder) UserAgent(userAgent string) *UserLoginBuilder {
 	return b
 }
 
+func (b *UserLoginBuilder) Session(session string) *UserLoginBuilder {
+	b.item.Session = session
+	return b
+}
+
 func (b *UserLoginBuilder) InsertTime(insertTime time.Time) *UserLoginBuilder {
 	b.item.InsertTime = insertTime
 	return b
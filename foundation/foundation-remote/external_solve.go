@@ -0,0 +1,239 @@
+package foundationremote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	foundationenum "foundation/foundation-enum"
+	"io"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	"meta/singleton"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RemoteExternalSolve 外部账号中的一道AC题目
+type RemoteExternalSolve struct {
+	OriginOj  string
+	OriginId  string
+	SolveTime *time.Time
+}
+
+// RemoteExternalSolveAgent 拉取用户在外部OJ上的AC记录
+type RemoteExternalSolveAgent struct {
+	client *http.Client
+}
+
+var singletonRemoteExternalSolveAgent = singleton.Singleton[RemoteExternalSolveAgent]{}
+
+func GetRemoteExternalSolveAgent() *RemoteExternalSolveAgent {
+	return singletonRemoteExternalSolveAgent.GetInstance(
+		func() *RemoteExternalSolveAgent {
+			s := &RemoteExternalSolveAgent{}
+			s.client = &http.Client{
+				Timeout: 60 * time.Second, // 请求整体超时
+			}
+			return s
+		},
+	)
+}
+
+func (s *RemoteExternalSolveAgent) GetAcProblems(
+	ctx context.Context,
+	source foundationenum.UserExternalSource,
+	account string,
+) ([]*RemoteExternalSolve, error) {
+	switch source {
+	case foundationenum.UserExternalSourceCodeforces:
+		return s.getCodeforcesAcProblems(ctx, account)
+	case foundationenum.UserExternalSourceVjudge:
+		return s.getVjudgeAcProblems(ctx, account)
+	case foundationenum.UserExternalSourceHdu:
+		return s.getHduAcProblems(ctx, account)
+	default:
+		return nil, metaerror.New("unknown external source: %s", source)
+	}
+}
+
+// IsAccountOwned 外部账号的公开页面中包含验证码时认为账号属于当前用户
+func (s *RemoteExternalSolveAgent) IsAccountOwned(
+	ctx context.Context,
+	source foundationenum.UserExternalSource,
+	account string,
+	code string,
+) (bool, error) {
+	var profileUrl string
+	switch source {
+	case foundationenum.UserExternalSourceCodeforces:
+		profileUrl = fmt.Sprintf("https://codeforces.com/api/user.info?handles=%s", url.QueryEscape(account))
+	case foundationenum.UserExternalSourceVjudge:
+		profileUrl = fmt.Sprintf("https://vjudge.net/user/%s", url.PathEscape(account))
+	case foundationenum.UserExternalSourceHdu:
+		profileUrl = fmt.Sprintf("https://acm.hdu.edu.cn/userstatus.php?user=%s", url.QueryEscape(account))
+	default:
+		return false, metaerror.New("unknown external source: %s", source)
+	}
+	body, err := s.request(ctx, profileUrl)
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(body), code), nil
+}
+
+func (s *RemoteExternalSolveAgent) request(ctx context.Context, requestUrl string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to create request")
+	}
+	req.Header.Add("Accept", "*/*")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "request failed")
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			metapanic.ProcessError(metaerror.Wrap(err))
+		}
+	}(res.Body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to read response body")
+	}
+	if res.StatusCode != http.StatusOK {
+		return body, metaerror.New("unexpected status code: %d", res.StatusCode)
+	}
+	return body, nil
+}
+
+func (s *RemoteExternalSolveAgent) getCodeforcesAcProblems(
+	ctx context.Context,
+	handle string,
+) ([]*RemoteExternalSolve, error) {
+	body, err := s.request(
+		ctx,
+		fmt.Sprintf("https://codeforces.com/api/user.status?handle=%s", url.QueryEscape(handle)),
+	)
+	// Codeforces 在用户不存在时也会返回JSON说明
+	if err != nil && body == nil {
+		return nil, err
+	}
+	var response struct {
+		Status  string `json:"status"`
+		Comment string `json:"comment"`
+		Result  []struct {
+			CreationTimeSeconds int64  `json:"creationTimeSeconds"`
+			Verdict             string `json:"verdict"`
+			Problem             struct {
+				ContestId int    `json:"contestId"`
+				Index     string `json:"index"`
+			} `json:"problem"`
+		} `json:"result"`
+	}
+	if jsonErr := json.Unmarshal(body, &response); jsonErr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, metaerror.Wrap(jsonErr, "failed to parse codeforces response")
+	}
+	if response.Status != "OK" {
+		return nil, metaerror.New("codeforces api failed: %s", response.Comment)
+	}
+	// 同一题目取最早的AC时间
+	solveMap := make(map[string]*RemoteExternalSolve)
+	for _, submission := range response.Result {
+		if submission.Verdict != "OK" || submission.Problem.ContestId <= 0 {
+			continue
+		}
+		originId := fmt.Sprintf("%d%s", submission.Problem.ContestId, submission.Problem.Index)
+		solveTime := time.Unix(submission.CreationTimeSeconds, 0)
+		solve, ok := solveMap[originId]
+		if !ok {
+			solveMap[originId] = &RemoteExternalSolve{
+				OriginOj:  "CODEFORCES",
+				OriginId:  originId,
+				SolveTime: &solveTime,
+			}
+		} else if solveTime.Before(*solve.SolveTime) {
+			solve.SolveTime = &solveTime
+		}
+	}
+	solves := make([]*RemoteExternalSolve, 0, len(solveMap))
+	for _, solve := range solveMap {
+		solves = append(solves, solve)
+	}
+	return solves, nil
+}
+
+func (s *RemoteExternalSolveAgent) getVjudgeAcProblems(
+	ctx context.Context,
+	username string,
+) ([]*RemoteExternalSolve, error) {
+	body, err := s.request(
+		ctx,
+		fmt.Sprintf("https://vjudge.net/user/solveDetail/%s", url.PathEscape(username)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		AcRecords map[string][]string `json:"acRecords"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, metaerror.Wrap(err, "failed to parse vjudge response")
+	}
+	var solves []*RemoteExternalSolve
+	for oj, ids := range response.AcRecords {
+		for _, id := range ids {
+			solves = append(
+				solves, &RemoteExternalSolve{
+					OriginOj: strings.ToUpper(oj),
+					OriginId: id,
+				},
+			)
+		}
+	}
+	return solves, nil
+}
+
+var hduSolvedProblemRegexp = regexp.MustCompile(`p\((\d+),`)
+
+func (s *RemoteExternalSolveAgent) getHduAcProblems(
+	ctx context.Context,
+	username string,
+) ([]*RemoteExternalSolve, error) {
+	body, err := s.request(
+		ctx,
+		fmt.Sprintf("https://acm.hdu.edu.cn/userstatus.php?user=%s", url.QueryEscape(username)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	bodyStr := string(body)
+	if strings.Contains(bodyStr, "No such user") {
+		return nil, metaerror.New("hdu user not found: %s", username)
+	}
+	// 只取已解决列表，未解决列表在其后
+	start := strings.Index(bodyStr, "List of solved problems")
+	if start < 0 {
+		return nil, metaerror.New("hdu solved list not found")
+	}
+	bodyStr = bodyStr[start:]
+	if end := strings.Index(bodyStr, "List of unsolved problems"); end >= 0 {
+		bodyStr = bodyStr[:end]
+	}
+	var solves []*RemoteExternalSolve
+	for _, match := range hduSolvedProblemRegexp.FindAllStringSubmatch(bodyStr, -1) {
+		solves = append(
+			solves, &RemoteExternalSolve{
+				OriginOj: "HDU",
+				OriginId: match[1],
+			},
+		)
+	}
+	return solves, nil
+}
@@ -2,9 +2,16 @@ package foundationremote
 
 import (
 	foundationenum "foundation/foundation-enum"
+	"math"
+	metaerror "meta/meta-error"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// 远程OJ状态表中的时间均为北京时间
+var remoteTimeLocation = time.FixedZone("CST", 8*60*60)
+
 func GetRemoteTypeByString(oj string) foundationenum.RemoteJudgeType {
 	oj = strings.ToLower(oj)
 	switch oj {
@@ -14,6 +21,10 @@ func GetRemoteTypeByString(oj string) foundationenum.RemoteJudgeType {
 		return foundationenum.RemoteJudgeTypePoj
 	case "nyoj":
 		return foundationenum.RemoteJudgeTypeNyoj
+	case "codeforces", "cf":
+		return foundationenum.RemoteJudgeTypeCodeforces
+	case "atcoder":
+		return foundationenum.RemoteJudgeTypeAtcoder
 	default:
 		return foundationenum.RemoteJudgeTypeLocal
 	}
@@ -25,8 +36,110 @@ func GetRemoteAgent(remoteType foundationenum.RemoteJudgeType) RemoteAgentBase {
 		return GetRemoteHduAgent()
 	case foundationenum.RemoteJudgeTypePoj:
 		return GetRemotePojAgent()
+	case foundationenum.RemoteJudgeTypeCodeforces:
+		return GetRemoteCodeforcesAgent()
+	case foundationenum.RemoteJudgeTypeAtcoder:
+		return GetRemoteAtcoderAgent()
 		//case foundationenum.RemoteJudgeTypeNyoj:
 		//	return GetRemoteNyojAgent()
 	}
 	return nil
 }
+
+// GetRemoteContestAgent 获取支持镜像比赛的远程OJ
+func GetRemoteContestAgent(remoteType foundationenum.RemoteJudgeType) RemoteContestAgentBase {
+	switch remoteType {
+	case foundationenum.RemoteJudgeTypeCodeforces:
+		return GetRemoteCodeforcesAgent()
+	case foundationenum.RemoteJudgeTypeAtcoder:
+		return GetRemoteAtcoderAgent()
+	}
+	return nil
+}
+
+// collectRemoteJudgeStatus 从最大的RunId开始向前翻页读取状态表，直到覆盖所有需要的RunId
+// requestPage 返回RunId不大于first的一页记录，按RunId降序排列
+func collectRemoteJudgeStatus(
+	ids []string,
+	maxPage int,
+	requestPage func(first int) ([]*RemoteJudgeStatus, error),
+) (map[string]*RemoteJudgeStatus, error) {
+	result := make(map[string]*RemoteJudgeStatus)
+	if len(ids) == 0 {
+		return result, nil
+	}
+	need := make(map[string]struct{}, len(ids))
+	minId, maxId := math.MaxInt, 0
+	for _, id := range ids {
+		runId, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, metaerror.Wrap(err, "invalid run id: %s", id)
+		}
+		need[id] = struct{}{}
+		minId = min(minId, runId)
+		maxId = max(maxId, runId)
+	}
+	first := maxId
+	for page := 0; page < maxPage; page++ {
+		rows, err := requestPage(first)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		lastId := first
+		for _, row := range rows {
+			if _, ok := need[row.RunId]; ok {
+				result[row.RunId] = row
+			}
+			if runId, err := strconv.Atoi(row.RunId); err == nil {
+				lastId = min(lastId, runId)
+			}
+		}
+		if len(result) >= len(need) || lastId <= minId {
+			break
+		}
+		first = lastId - 1
+	}
+	return result, nil
+}
+
+// pickSubmitRunId 选出题目、语言一致且提交时间不早于since的最早一条未被占用的提交
+func pickSubmitRunId(
+	rows []*remoteSubmitRow,
+	problemId string,
+	language string,
+	since time.Time,
+	excludeIds []string,
+) string {
+	exclude := make(map[string]struct{}, len(excludeIds))
+	for _, id := range excludeIds {
+		exclude[id] = struct{}{}
+	}
+	var picked *remoteSubmitRow
+	for _, row := range rows {
+		if row.ProblemId != problemId || row.Language != language || row.SubmitTime.Before(since) {
+			continue
+		}
+		if _, ok := exclude[row.RunId]; ok {
+			continue
+		}
+		if picked == nil || row.SubmitTime.Before(picked.SubmitTime) {
+			picked = row
+			continue
+		}
+		// 同一秒内的提交取RunId较小的
+		if row.SubmitTime.Equal(picked.SubmitTime) {
+			runId, _ := strconv.Atoi(row.RunId)
+			pickedRunId, _ := strconv.Atoi(picked.RunId)
+			if runId < pickedRunId {
+				picked = row
+			}
+		}
+	}
+	if picked == nil {
+		return ""
+	}
+	return picked.RunId
+}
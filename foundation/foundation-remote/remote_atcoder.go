@@ -0,0 +1,368 @@
+package foundationremote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	foundationdao "foundation/foundation-dao"
+	foundationjudge "foundation/foundation-judge"
+	foundationmodel "foundation/foundation-model"
+	foundationrender "foundation/foundation-render"
+	"io"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	metatime "meta/meta-time"
+	"meta/singleton"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// RemoteAtcoderAgent 目前只支持爬取题目与镜像比赛，不支持远程评测
+type RemoteAtcoderAgent struct {
+	goJudgeClient *http.Client
+}
+
+var singletonRemoteAtcoderAgent = singleton.Singleton[RemoteAtcoderAgent]{}
+
+func GetRemoteAtcoderAgent() *RemoteAtcoderAgent {
+	return singletonRemoteAtcoderAgent.GetInstance(
+		func() *RemoteAtcoderAgent {
+			s := &RemoteAtcoderAgent{}
+			s.goJudgeClient = &http.Client{
+				Timeout: 60 * time.Second, // 请求整体超时
+			}
+			return s
+		},
+	)
+}
+
+var atcoderIdRegexp = regexp.MustCompile(`^[0-9A-Za-z-]+$`)
+
+// getContestId AtCoder的题目Id一般为比赛Id加下划线与题号，如abc300_a
+func (s *RemoteAtcoderAgent) getContestId(problemId string) (string, bool) {
+	index := strings.LastIndex(problemId, "_")
+	if index <= 0 || index == len(problemId)-1 {
+		return "", false
+	}
+	contestId := problemId[:index]
+	if !atcoderIdRegexp.MatchString(contestId) || !atcoderIdRegexp.MatchString(problemId[index+1:]) {
+		return "", false
+	}
+	return contestId, true
+}
+
+func (s *RemoteAtcoderAgent) request(ctx context.Context, requestUrl string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to create request")
+	}
+	req.Header.Add("Accept", "*/*")
+	req.Header.Add("Accept-Language", "en-US,en;q=0.9")
+	res, err := s.goJudgeClient.Do(req)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "request failed")
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			metapanic.ProcessError(metaerror.Wrap(err))
+		}
+	}(res.Body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to read response body")
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, metaerror.New("unexpected status code: %d", res.StatusCode)
+	}
+	return body, nil
+}
+
+func (s *RemoteAtcoderAgent) IsSupportJudge(problemId string, language foundationjudge.JudgeLanguage) bool {
+	return false
+}
+
+func (s *RemoteAtcoderAgent) PostCrawlProblem(ctx context.Context, id string) (*string, error) {
+	id = strings.ToLower(id)
+	contestId, ok := s.getContestId(id)
+	if !ok {
+		return nil, nil
+	}
+	nowTime := metatime.GetTimeNow()
+	newProblemId := fmt.Sprintf("AT-%s", id)
+	baseURL := "https://atcoder.jp/"
+	problemUrl := fmt.Sprintf("https://atcoder.jp/contests/%s/tasks/%s", contestId, id)
+
+	body, err := s.request(ctx, problemUrl)
+	if err != nil {
+		return nil, err
+	}
+	// AtCoder 的公式使用var标签包裹
+	htmlStr := strings.ReplaceAll(string(body), "<var>", "$")
+	htmlStr = strings.ReplaceAll(htmlStr, "</var>", "$")
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlStr))
+	if err != nil {
+		return nil, err
+	}
+	titleSelection := doc.Find("span.h2").First()
+	if titleSelection.Length() == 0 {
+		return nil, nil
+	}
+	titleSelection.Find("a").Remove()
+	title := strings.TrimSpace(titleSelection.Text())
+	// 去掉标题前的题号
+	if _, after, found := strings.Cut(title, " - "); found {
+		title = after
+	}
+
+	// 优先使用英文题面，老比赛只有日文题面
+	statement := doc.Find("#task-statement span.lang-en").First()
+	if statement.Length() == 0 {
+		statement = doc.Find("#task-statement").First()
+	}
+	var parts []string
+	var finalErr error
+	statement.Find("div.part section").Each(
+		func(i int, section *goquery.Selection) {
+			if finalErr != nil {
+				return
+			}
+			heading := strings.TrimSpace(section.Find("h3").First().Text())
+			section.Find("h3").Remove()
+			htmlContent, _ := section.Html()
+			content, err := foundationrender.HTMLToMarkdown(newProblemId, htmlContent, baseURL)
+			if err != nil {
+				finalErr = err
+				return
+			}
+			parts = append(parts, fmt.Sprintf("## %s\n\n%s", heading, strings.TrimSpace(content)))
+		},
+	)
+	if finalErr != nil {
+		return nil, finalErr
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	timeLimit, memoryLimit := -1, -1
+	limitText := doc.Find("#main-container p").First().Text()
+	if m := regexp.MustCompile(`Time Limit:\s*([\d.]+)\s*sec`).FindStringSubmatch(limitText); len(m) > 1 {
+		if seconds, err := strconv.ParseFloat(m[1], 64); err == nil {
+			timeLimit = int(seconds * 1000)
+		}
+	}
+	if m := regexp.MustCompile(`Memory Limit:\s*(\d+)\s*(KB|MB|MiB|KiB)`).FindStringSubmatch(limitText); len(m) > 2 {
+		if value, err := strconv.Atoi(m[1]); err == nil {
+			memoryLimit = value
+			if strings.HasPrefix(m[2], "M") {
+				memoryLimit = value * 1024
+			}
+		}
+	}
+
+	source := strings.TrimSpace(doc.Find("a.contest-title").First().Text())
+	originAuthor := ""
+
+	problem := foundationmodel.NewProblemBuilder().
+		Title(title).
+		Description(strings.Join(parts, "\n\n")).
+		TimeLimit(timeLimit).
+		MemoryLimit(memoryLimit).
+		Source(&source).
+		InsertTime(nowTime).
+		ModifyTime(nowTime).
+		Build()
+	problemRemote := foundationmodel.NewProblemRemoteBuilder().
+		OriginOj("ATCODER").
+		OriginId(id).
+		OriginUrl(problemUrl).
+		OriginAuthor(&originAuthor).
+		Build()
+	err = foundationdao.GetProblemDao().UpdateProblemCrawl(ctx, newProblemId, problem, problemRemote)
+	if err != nil {
+		return nil, err
+	}
+	return &newProblemId, nil
+}
+
+func (s *RemoteAtcoderAgent) PostSubmitJudgeJob(
+	ctx context.Context,
+	problemId string,
+	language foundationjudge.JudgeLanguage,
+	code string,
+) (string, string, error) {
+	return "", "", metaerror.New("atcoder remote judge not support")
+}
+
+func (s *RemoteAtcoderAgent) FindSubmitRunId(
+	ctx context.Context,
+	problemId string,
+	language foundationjudge.JudgeLanguage,
+	since time.Time,
+	excludeIds []string,
+) (string, string, error) {
+	return "", "", metaerror.New("atcoder remote judge not support")
+}
+
+func (s *RemoteAtcoderAgent) GetJudgeJobStatus(ctx context.Context, id string) (
+	foundationjudge.JudgeStatus,
+	int,
+	int,
+	int,
+	error,
+) {
+	return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.New("atcoder remote judge not support")
+}
+
+func (s *RemoteAtcoderAgent) GetJudgeJobStatusList(ctx context.Context, account string, ids []string) (
+	map[string]*RemoteJudgeStatus,
+	error,
+) {
+	return nil, metaerror.New("atcoder remote judge not support")
+}
+
+func (s *RemoteAtcoderAgent) GetJudgeJobExtraMessage(
+	ctx context.Context,
+	id string,
+	status foundationjudge.JudgeStatus,
+) (string, error) {
+	return "", nil
+}
+
+func (s *RemoteAtcoderAgent) GetContest(ctx context.Context, contestId string) (*RemoteContest, error) {
+	contestId = strings.ToLower(contestId)
+	if !atcoderIdRegexp.MatchString(contestId) {
+		return nil, metaerror.New("invalid atcoder contest id: %s", contestId)
+	}
+	body, err := s.request(ctx, fmt.Sprintf("https://atcoder.jp/contests/%s", contestId))
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	contest := &RemoteContest{
+		Title: strings.TrimSpace(doc.Find("a.contest-title").First().Text()),
+	}
+	// 比赛时间为两个fixtime-full，分别为开始与结束时间
+	var times []time.Time
+	doc.Find("time.fixtime-full").Each(
+		func(i int, selection *goquery.Selection) {
+			t, err := time.Parse("2006-01-02 15:04:05-0700", strings.TrimSpace(selection.Text()))
+			if err == nil {
+				times = append(times, t)
+			}
+		},
+	)
+	if len(times) < 2 {
+		return nil, metaerror.New("atcoder contest time not found: %s", contestId)
+	}
+	contest.Duration = times[1].Sub(times[0])
+
+	body, err = s.request(ctx, fmt.Sprintf("https://atcoder.jp/contests/%s/tasks", contestId))
+	if err != nil {
+		return nil, err
+	}
+	doc, err = goquery.NewDocumentFromReader(strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	doc.Find("#main-container table tbody tr").Each(
+		func(i int, row *goquery.Selection) {
+			cells := row.Find("td")
+			if cells.Length() < 2 {
+				return
+			}
+			link := cells.Eq(1).Find("a").First()
+			href, _ := link.Attr("href")
+			taskId := href[strings.LastIndex(href, "/")+1:]
+			if taskId == "" {
+				return
+			}
+			contest.Problems = append(
+				contest.Problems, &RemoteContestProblem{
+					Index:    strings.TrimSpace(cells.Eq(0).Text()),
+					OriginId: taskId,
+					Title:    strings.TrimSpace(link.Text()),
+				},
+			)
+		},
+	)
+	if len(contest.Problems) == 0 {
+		return nil, metaerror.New("atcoder contest tasks not found: %s", contestId)
+	}
+	return contest, nil
+}
+
+type atcoderTaskResult struct {
+	Count   int   `json:"Count"`
+	Penalty int   `json:"Penalty"`
+	Score   int   `json:"Score"`
+	Elapsed int64 `json:"Elapsed"`
+}
+
+func (s *RemoteAtcoderAgent) GetContestStandings(
+	ctx context.Context,
+	contestId string,
+	contest *RemoteContest,
+	limit int,
+) ([]*RemoteContestStanding, error) {
+	contestId = strings.ToLower(contestId)
+	if !atcoderIdRegexp.MatchString(contestId) {
+		return nil, metaerror.New("invalid atcoder contest id: %s", contestId)
+	}
+	body, err := s.request(ctx, fmt.Sprintf("https://atcoder.jp/contests/%s/standings/json", contestId))
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		StandingsData []struct {
+			Rank           int                           `json:"Rank"`
+			UserScreenName string                        `json:"UserScreenName"`
+			TotalResult    atcoderTaskResult             `json:"TotalResult"`
+			TaskResults    map[string]*atcoderTaskResult `json:"TaskResults"`
+		} `json:"StandingsData"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, metaerror.Wrap(err, "failed to parse atcoder standings")
+	}
+	// 分数为实际分数的100倍，时间单位为纳秒，每次错误提交罚时5分钟
+	var standings []*RemoteContestStanding
+	for _, row := range response.StandingsData {
+		if len(standings) >= limit {
+			break
+		}
+		// 没有提交的参赛者不导入
+		if row.TotalResult.Count == 0 {
+			continue
+		}
+		standing := &RemoteContestStanding{
+			Name:    row.UserScreenName,
+			Rank:    row.Rank,
+			Score:   row.TotalResult.Score / 100,
+			Penalty: int(row.TotalResult.Elapsed/int64(time.Second)) + row.TotalResult.Penalty*300,
+		}
+		for _, problem := range contest.Problems {
+			result := &RemoteContestStandingProblem{}
+			if taskResult, ok := row.TaskResults[problem.OriginId]; ok {
+				result.Accepted = taskResult.Score > 0
+				result.Attempt = taskResult.Penalty
+				result.Score = taskResult.Score / 100
+				if result.Accepted {
+					result.Time = int(taskResult.Elapsed / int64(time.Second))
+				}
+			}
+			standing.Problems = append(standing.Problems, result)
+		}
+		standings = append(standings, standing)
+	}
+	return standings, nil
+}
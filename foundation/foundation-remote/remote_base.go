@@ -3,8 +3,26 @@ package foundationremote
 import (
 	"context"
 	foundationjudge "foundation/foundation-judge"
+	"time"
 )
 
+// RemoteJudgeStatus 远程状态表中的一条记录
+type RemoteJudgeStatus struct {
+	RunId  string
+	Status foundationjudge.JudgeStatus
+	Score  int
+	Time   int
+	Memory int
+}
+
+// remoteSubmitRow 远程状态表中用于找回RunId的提交信息
+type remoteSubmitRow struct {
+	RunId      string
+	ProblemId  string
+	Language   string
+	SubmitTime time.Time
+}
+
 type RemoteAgentBase interface {
 	IsSupportJudge(problemId string, language foundationjudge.JudgeLanguage) bool
 	PostCrawlProblem(ctx context.Context, id string) (*string, error)
@@ -14,6 +32,58 @@ type RemoteAgentBase interface {
 		language foundationjudge.JudgeLanguage,
 		code string,
 	) (string, string, error)
+	// FindSubmitRunId 在账号最近的提交中查找题目、语言一致且提交时间不早于since的最早一条，用于提交结果未知时找回RunId
+	// 返回RunId与账号，未找到时RunId为空
+	FindSubmitRunId(
+		ctx context.Context,
+		problemId string,
+		language foundationjudge.JudgeLanguage,
+		since time.Time,
+		excludeIds []string,
+	) (string, string, error)
 	GetJudgeJobStatus(ctx context.Context, id string) (foundationjudge.JudgeStatus, int, int, int, error)
+	// GetJudgeJobStatusList 通过账号的状态表一次性获取多个RunId的状态，未找到的RunId不会出现在结果中
+	GetJudgeJobStatusList(ctx context.Context, account string, ids []string) (map[string]*RemoteJudgeStatus, error)
 	GetJudgeJobExtraMessage(ctx context.Context, id string, status foundationjudge.JudgeStatus) (string, error)
 }
+
+// RemoteContestProblem 远程比赛中的题目，按比赛中的顺序排列
+type RemoteContestProblem struct {
+	Index    string // 远程比赛中的题号，如A、B1
+	OriginId string // 作为远程题目爬取时使用的Id
+	Title    string
+}
+
+// RemoteContestStandingProblem 榜单中某个参赛者在一道题上的结果
+type RemoteContestStandingProblem struct {
+	Accepted bool
+	Attempt  int // 通过前的错误次数
+	Time     int // 通过时距比赛开始的秒数
+	Score    int
+}
+
+// RemoteContestStanding 远程比赛最终榜单中的一行
+type RemoteContestStanding struct {
+	Name     string
+	Rank     int
+	Score    int
+	Penalty  int
+	Problems []*RemoteContestStandingProblem // 与RemoteContest.Problems一一对应
+}
+
+type RemoteContest struct {
+	Title    string
+	Duration time.Duration
+	Problems []*RemoteContestProblem
+}
+
+type RemoteContestAgentBase interface {
+	GetContest(ctx context.Context, contestId string) (*RemoteContest, error)
+	// GetContestStandings 获取公开的最终榜单，最多返回limit行
+	GetContestStandings(
+		ctx context.Context,
+		contestId string,
+		contest *RemoteContest,
+		limit int,
+	) ([]*RemoteContestStanding, error)
+}
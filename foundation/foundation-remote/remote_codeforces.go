@@ -0,0 +1,409 @@
+package foundationremote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	foundationdao "foundation/foundation-dao"
+	foundationjudge "foundation/foundation-judge"
+	foundationmodel "foundation/foundation-model"
+	foundationrender "foundation/foundation-render"
+	"io"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	metatime "meta/meta-time"
+	"meta/singleton"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"web/config"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// RemoteCodeforcesAgent 目前只支持爬取题目与镜像比赛，不支持远程评测
+type RemoteCodeforcesAgent struct {
+	goJudgeClient *http.Client
+}
+
+var singletonRemoteCodeforcesAgent = singleton.Singleton[RemoteCodeforcesAgent]{}
+
+func GetRemoteCodeforcesAgent() *RemoteCodeforcesAgent {
+	return singletonRemoteCodeforcesAgent.GetInstance(
+		func() *RemoteCodeforcesAgent {
+			s := &RemoteCodeforcesAgent{}
+			s.goJudgeClient = &http.Client{
+				Timeout: 60 * time.Second, // 请求整体超时
+			}
+			return s
+		},
+	)
+}
+
+var codeforcesProblemIdRegexp = regexp.MustCompile(`^(\d+)([A-Za-z][0-9A-Za-z]*)$`)
+
+// parseProblemId 将1234A形式的Id拆分为比赛Id与题号
+func (s *RemoteCodeforcesAgent) parseProblemId(id string) (int, string, bool) {
+	matches := codeforcesProblemIdRegexp.FindStringSubmatch(id)
+	if len(matches) < 3 {
+		return 0, "", false
+	}
+	contestId, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, "", false
+	}
+	return contestId, strings.ToUpper(matches[2]), true
+}
+
+func (s *RemoteCodeforcesAgent) getProblemUrl(contestId int, index string) string {
+	// Gym比赛的Id从100000开始
+	if contestId >= 100000 {
+		return fmt.Sprintf("https://codeforces.com/gym/%d/problem/%s", contestId, index)
+	}
+	return fmt.Sprintf("https://codeforces.com/problemset/problem/%d/%s", contestId, index)
+}
+
+func (s *RemoteCodeforcesAgent) request(ctx context.Context, requestUrl string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to create request")
+	}
+	req.Header.Add("Accept", "*/*")
+	res, err := s.goJudgeClient.Do(req)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "request failed")
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			metapanic.ProcessError(metaerror.Wrap(err))
+		}
+	}(res.Body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to read response body")
+	}
+	return body, nil
+}
+
+func (s *RemoteCodeforcesAgent) IsSupportJudge(problemId string, language foundationjudge.JudgeLanguage) bool {
+	return false
+}
+
+func (s *RemoteCodeforcesAgent) PostCrawlProblem(ctx context.Context, id string) (*string, error) {
+	contestId, index, ok := s.parseProblemId(id)
+	if !ok {
+		return nil, nil
+	}
+	id = fmt.Sprintf("%d%s", contestId, index)
+	nowTime := metatime.GetTimeNow()
+	newProblemId := fmt.Sprintf("CF-%s", id)
+	baseURL := "https://codeforces.com/"
+	problemUrl := s.getProblemUrl(contestId, index)
+
+	body, err := s.request(ctx, problemUrl)
+	if err != nil {
+		return nil, err
+	}
+	// Codeforces 的公式使用$$$包裹
+	htmlStr := strings.ReplaceAll(string(body), "$$$", "$")
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlStr))
+	if err != nil {
+		return nil, err
+	}
+	statement := doc.Find("div.problem-statement").First()
+	if statement.Length() == 0 {
+		return nil, nil
+	}
+
+	title := strings.TrimSpace(statement.Find("div.header div.title").First().Text())
+	// 去掉标题前的题号
+	if _, after, found := strings.Cut(title, ". "); found {
+		title = after
+	}
+
+	toMarkdown := func(selection *goquery.Selection) (string, error) {
+		selection.Find("div.section-title").Remove()
+		htmlContent, _ := selection.Html()
+		return foundationrender.HTMLToMarkdown(newProblemId, htmlContent, baseURL)
+	}
+
+	// 题面描述为header之后第一个没有class的div
+	description, err := toMarkdown(statement.Find("div.header").First().Next())
+	if err != nil {
+		return nil, err
+	}
+	input, err := toMarkdown(statement.Find("div.input-specification").First())
+	if err != nil {
+		return nil, err
+	}
+	output, err := toMarkdown(statement.Find("div.output-specification").First())
+	if err != nil {
+		return nil, err
+	}
+	var sampleInputs, sampleOutputs []string
+	statement.Find("div.sample-test div.input pre").Each(
+		func(i int, s *goquery.Selection) {
+			sampleInputs = append(sampleInputs, fmt.Sprintf("```\n%s\n```", strings.TrimSpace(getPreText(s))))
+		},
+	)
+	statement.Find("div.sample-test div.output pre").Each(
+		func(i int, s *goquery.Selection) {
+			sampleOutputs = append(sampleOutputs, fmt.Sprintf("```\n%s\n```", strings.TrimSpace(getPreText(s))))
+		},
+	)
+	hint := ""
+	if note := statement.Find("div.note").First(); note.Length() > 0 {
+		hint, err = toMarkdown(note)
+		if err != nil {
+			return nil, err
+		}
+		hint = fmt.Sprintf("\n\n## Note\n\n%s", hint)
+	}
+
+	timeLimit, memoryLimit := -1, -1
+	timeText := statement.Find("div.time-limit").First().Text()
+	if m := regexp.MustCompile(`([\d.]+)\s*seconds?`).FindStringSubmatch(timeText); len(m) > 1 {
+		if seconds, err := strconv.ParseFloat(m[1], 64); err == nil {
+			timeLimit = int(seconds * 1000)
+		}
+	}
+	memoryText := statement.Find("div.memory-limit").First().Text()
+	if m := regexp.MustCompile(`(\d+)\s*megabytes?`).FindStringSubmatch(memoryText); len(m) > 1 {
+		if megabytes, err := strconv.Atoi(m[1]); err == nil {
+			memoryLimit = megabytes * 1024
+		}
+	}
+
+	template := config.GetOjTemplateContent("codeforces")
+	descriptionRendered := foundationrender.Render(
+		template, map[string]string{
+			"description":  description,
+			"input":        input,
+			"output":       output,
+			"sampleInput":  strings.Join(sampleInputs, "\n\n"),
+			"sampleOutput": strings.Join(sampleOutputs, "\n\n"),
+			"hint":         hint,
+		},
+	)
+
+	source := strings.TrimSpace(doc.Find("#sidebar table.rtable a").First().Text())
+	originAuthor := ""
+
+	problem := foundationmodel.NewProblemBuilder().
+		Title(title).
+		Description(descriptionRendered).
+		TimeLimit(timeLimit).
+		MemoryLimit(memoryLimit).
+		Source(&source).
+		InsertTime(nowTime).
+		ModifyTime(nowTime).
+		Build()
+	problemRemote := foundationmodel.NewProblemRemoteBuilder().
+		OriginOj("CODEFORCES").
+		OriginId(id).
+		OriginUrl(problemUrl).
+		OriginAuthor(&originAuthor).
+		Build()
+	err = foundationdao.GetProblemDao().UpdateProblemCrawl(ctx, newProblemId, problem, problemRemote)
+	if err != nil {
+		return nil, err
+	}
+	return &newProblemId, nil
+}
+
+// getPreText 新版样例中每行是一个div
+func getPreText(s *goquery.Selection) string {
+	lines := s.Find("div")
+	if lines.Length() == 0 {
+		return s.Text()
+	}
+	var result []string
+	lines.Each(
+		func(i int, line *goquery.Selection) {
+			result = append(result, line.Text())
+		},
+	)
+	return strings.Join(result, "\n")
+}
+
+func (s *RemoteCodeforcesAgent) PostSubmitJudgeJob(
+	ctx context.Context,
+	problemId string,
+	language foundationjudge.JudgeLanguage,
+	code string,
+) (string, string, error) {
+	return "", "", metaerror.New("codeforces remote judge not support")
+}
+
+func (s *RemoteCodeforcesAgent) FindSubmitRunId(
+	ctx context.Context,
+	problemId string,
+	language foundationjudge.JudgeLanguage,
+	since time.Time,
+	excludeIds []string,
+) (string, string, error) {
+	return "", "", metaerror.New("codeforces remote judge not support")
+}
+
+func (s *RemoteCodeforcesAgent) GetJudgeJobStatus(ctx context.Context, id string) (
+	foundationjudge.JudgeStatus,
+	int,
+	int,
+	int,
+	error,
+) {
+	return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.New("codeforces remote judge not support")
+}
+
+func (s *RemoteCodeforcesAgent) GetJudgeJobStatusList(ctx context.Context, account string, ids []string) (
+	map[string]*RemoteJudgeStatus,
+	error,
+) {
+	return nil, metaerror.New("codeforces remote judge not support")
+}
+
+func (s *RemoteCodeforcesAgent) GetJudgeJobExtraMessage(
+	ctx context.Context,
+	id string,
+	status foundationjudge.JudgeStatus,
+) (string, error) {
+	return "", nil
+}
+
+type codeforcesStandingsResponse struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment"`
+	Result  struct {
+		Contest struct {
+			Id              int    `json:"id"`
+			Name            string `json:"name"`
+			Type            string `json:"type"`
+			DurationSeconds int64  `json:"durationSeconds"`
+		} `json:"contest"`
+		Problems []struct {
+			Index string `json:"index"`
+			Name  string `json:"name"`
+		} `json:"problems"`
+		Rows []struct {
+			Party struct {
+				TeamName        string `json:"teamName"`
+				ParticipantType string `json:"participantType"`
+				Members         []struct {
+					Handle string `json:"handle"`
+				} `json:"members"`
+			} `json:"party"`
+			Rank           int     `json:"rank"`
+			Points         float64 `json:"points"`
+			Penalty        int     `json:"penalty"`
+			ProblemResults []struct {
+				Points                    float64 `json:"points"`
+				RejectedAttemptCount      int     `json:"rejectedAttemptCount"`
+				BestSubmissionTimeSeconds *int    `json:"bestSubmissionTimeSeconds"`
+			} `json:"problemResults"`
+		} `json:"rows"`
+	} `json:"result"`
+}
+
+func (s *RemoteCodeforcesAgent) requestStandings(
+	ctx context.Context,
+	contestId string,
+	count int,
+) (*codeforcesStandingsResponse, error) {
+	id, err := strconv.Atoi(contestId)
+	if err != nil || id <= 0 {
+		return nil, metaerror.New("invalid codeforces contest id: %s", contestId)
+	}
+	body, err := s.request(
+		ctx,
+		fmt.Sprintf(
+			"https://codeforces.com/api/contest.standings?contestId=%d&from=1&count=%d&showUnofficial=false",
+			id,
+			count,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response codeforcesStandingsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, metaerror.Wrap(err, "failed to parse codeforces standings")
+	}
+	if response.Status != "OK" {
+		return nil, metaerror.New("codeforces api failed: %s", response.Comment)
+	}
+	return &response, nil
+}
+
+func (s *RemoteCodeforcesAgent) GetContest(ctx context.Context, contestId string) (*RemoteContest, error) {
+	response, err := s.requestStandings(ctx, contestId, 1)
+	if err != nil {
+		return nil, err
+	}
+	contest := &RemoteContest{
+		Title:    response.Result.Contest.Name,
+		Duration: time.Duration(response.Result.Contest.DurationSeconds) * time.Second,
+	}
+	for _, problem := range response.Result.Problems {
+		contest.Problems = append(
+			contest.Problems, &RemoteContestProblem{
+				Index:    problem.Index,
+				OriginId: fmt.Sprintf("%d%s", response.Result.Contest.Id, problem.Index),
+				Title:    problem.Name,
+			},
+		)
+	}
+	return contest, nil
+}
+
+func (s *RemoteCodeforcesAgent) GetContestStandings(
+	ctx context.Context,
+	contestId string,
+	contest *RemoteContest,
+	limit int,
+) ([]*RemoteContestStanding, error) {
+	response, err := s.requestStandings(ctx, contestId, limit)
+	if err != nil {
+		return nil, err
+	}
+	// ICPC赛制的罚时单位为分钟，其余赛制没有罚时
+	isIcpc := response.Result.Contest.Type == "ICPC"
+	var standings []*RemoteContestStanding
+	for _, row := range response.Result.Rows {
+		if row.Party.ParticipantType != "CONTESTANT" {
+			continue
+		}
+		name := row.Party.TeamName
+		if name == "" {
+			var handles []string
+			for _, member := range row.Party.Members {
+				handles = append(handles, member.Handle)
+			}
+			name = strings.Join(handles, ", ")
+		}
+		standing := &RemoteContestStanding{
+			Name:  name,
+			Rank:  row.Rank,
+			Score: int(row.Points),
+		}
+		if isIcpc {
+			standing.Penalty = row.Penalty * 60
+		}
+		for _, result := range row.ProblemResults {
+			problem := &RemoteContestStandingProblem{
+				Accepted: result.Points > 0,
+				Attempt:  result.RejectedAttemptCount,
+				Score:    int(result.Points),
+			}
+			if problem.Accepted && result.BestSubmissionTimeSeconds != nil {
+				problem.Time = *result.BestSubmissionTimeSeconds
+			}
+			standing.Problems = append(standing.Problems, problem)
+		}
+		standings = append(standings, standing)
+	}
+	return standings, nil
+}
@@ -77,6 +77,22 @@ func (s *RemoteHduAgent) getLanguageCode(language foundationjudge.JudgeLanguage)
 	}
 }
 
+// getLanguageName 状态表中显示的语言名称
+func (s *RemoteHduAgent) getLanguageName(language foundationjudge.JudgeLanguage) string {
+	switch language {
+	case foundationjudge.JudgeLanguageC:
+		return "GCC"
+	case foundationjudge.JudgeLanguageCpp:
+		return "G++"
+	case foundationjudge.JudgeLanguagePascal:
+		return "Pascal"
+	case foundationjudge.JudgeLanguageJava:
+		return "Java"
+	default:
+		return ""
+	}
+}
+
 func (s *RemoteHduAgent) GetJudgeStatus(status string) foundationjudge.JudgeStatus {
 	switch status {
 	case "Queuing":
@@ -342,21 +358,22 @@ func (s *RemoteHduAgent) getMaxRunId(ctx context.Context, username string, probl
 	return runId, nil
 }
 
-func (s *RemoteHduAgent) requestJudgeJobStatus(ctx context.Context, runId string, retryCount int) (
-	foundationjudge.JudgeStatus,
-	int,
-	int,
-	int,
-	error,
-) {
-	hduUrl := fmt.Sprintf("https://acm.hdu.edu.cn/status.php?first=%s", runId)
+// requestStatusPage 获取状态表中RunId不大于first的一页记录，username为空时不按用户筛选
+func (s *RemoteHduAgent) requestStatusPage(
+	ctx context.Context,
+	username string,
+	first string,
+	retryCount int,
+) ([]*RemoteJudgeStatus, error) {
+	hduUrl := fmt.Sprintf(
+		"https://acm.hdu.edu.cn/status.php?first=%s&user=%s",
+		first,
+		url.QueryEscape(username),
+	)
 	method := "GET"
 	req, err := http.NewRequestWithContext(ctx, method, hduUrl, nil)
 	if err != nil {
-		return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.Wrap(
-			err,
-			"failed to create GetJudgeJobStatus request",
-		)
+		return nil, metaerror.Wrap(err, "failed to create requestStatusPage request")
 	}
 	req.Header.Add("Cookie", s.cookie)
 	req.Header.Add("Accept", "*/*")
@@ -365,7 +382,7 @@ func (s *RemoteHduAgent) requestJudgeJobStatus(ctx context.Context, runId string
 	req.Header.Add("Referer", fmt.Sprintf("https://acm.hdu.edu.cn/status.php?first=&pid=&user=&lang=0&status=0"))
 	res, err := s.goJudgeClient.Do(req)
 	if err != nil {
-		return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.Wrap(err, "GetJudgeJobStatus request failed")
+		return nil, metaerror.Wrap(err, "requestStatusPage request failed")
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -375,42 +392,62 @@ func (s *RemoteHduAgent) requestJudgeJobStatus(ctx context.Context, runId string
 	}(res.Body)
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.Wrap(err, "failed to read response body")
+		return nil, metaerror.Wrap(err, "failed to read response body")
 	}
 	bodyStr := string(body)
 	if strings.Contains(bodyStr, "<title>User Login</title>") {
 		if retryCount > 0 {
-			return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.New("HDU remote judge login failed after retry")
+			return nil, metaerror.New("HDU remote judge login failed after retry")
 		}
 		// 重新登录
 		err := s.login(ctx)
 		if err != nil {
-			return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.Wrap(err, "failed to login")
+			return nil, metaerror.Wrap(err, "failed to login")
 		}
-		return s.requestJudgeJobStatus(ctx, runId, retryCount+1)
+		return s.requestStatusPage(ctx, username, first, retryCount+1)
 	}
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(bodyStr))
 	if err != nil {
-		return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.Wrap(err, "failed to parse response body")
+		return nil, metaerror.Wrap(err, "failed to parse response body")
 	}
-	var statusStr, exeTimeStr, exeMemoryStr string
+	var rows []*RemoteJudgeStatus
+	var finalErr error
 	doc.Find("div#fixed_table table").First().Find("tr").EachWithBreak(
 		func(i int, s *goquery.Selection) bool {
 			tds := s.Find("td")
 			if tds.Length() < 6 {
 				return true
 			}
-			firstTdText := strings.TrimSpace(tds.Eq(0).Text())
-			if firstTdText == "" || firstTdText == "Run ID" {
+			runId := strings.TrimSpace(tds.Eq(0).Text())
+			if runId == "" || runId == "Run ID" {
 				return true
 			}
-			statusStr = strings.TrimSpace(tds.Eq(2).Text())
-			exeTimeStr = strings.TrimSpace(tds.Eq(4).Text())
-			exeMemoryStr = strings.TrimSpace(tds.Eq(5).Text())
-			return false
+			row, err := parseHduStatusRow(
+				runId,
+				strings.TrimSpace(tds.Eq(2).Text()),
+				strings.TrimSpace(tds.Eq(4).Text()),
+				strings.TrimSpace(tds.Eq(5).Text()),
+			)
+			if err != nil {
+				finalErr = err
+				return false
+			}
+			rows = append(rows, row)
+			return true
 		},
 	)
-	status := s.GetJudgeStatus(statusStr)
+	if finalErr != nil {
+		return nil, finalErr
+	}
+	return rows, nil
+}
+
+func parseHduStatusRow(runId string, statusStr string, exeTimeStr string, exeMemoryStr string) (
+	*RemoteJudgeStatus,
+	error,
+) {
+	var err error
+	status := GetRemoteHduAgent().GetJudgeStatus(statusStr)
 	score := 0
 	exeTime := 0
 	if strings.HasSuffix(exeTimeStr, "MS") {
@@ -418,10 +455,7 @@ func (s *RemoteHduAgent) requestJudgeJobStatus(ctx context.Context, runId string
 		exeTimeStr = strings.TrimSpace(exeTimeStr)
 		exeTime, err = strconv.Atoi(exeTimeStr)
 		if err != nil {
-			return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.Wrap(
-				err,
-				"failed to parse execution time",
-			)
+			return nil, metaerror.Wrap(err, "failed to parse execution time")
 		}
 		exeTime = exeTime * 1000000
 	}
@@ -431,17 +465,20 @@ func (s *RemoteHduAgent) requestJudgeJobStatus(ctx context.Context, runId string
 		exeMemoryStr = strings.TrimSpace(exeMemoryStr)
 		exeMemory, err = strconv.Atoi(exeMemoryStr)
 		if err != nil {
-			return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.Wrap(
-				err,
-				"failed to parse execution memory",
-			)
+			return nil, metaerror.Wrap(err, "failed to parse execution memory")
 		}
 		exeMemory = exeMemory * 1024
 	}
 	if status == foundationjudge.JudgeStatusAC {
 		score = 1000
 	}
-	return status, score, exeTime, exeMemory, nil
+	return &RemoteJudgeStatus{
+		RunId:  runId,
+		Status: status,
+		Score:  score,
+		Time:   exeTime,
+		Memory: exeMemory,
+	}, nil
 }
 
 func (s *RemoteHduAgent) GetJudgeJobStatus(ctx context.Context, id string) (
@@ -451,7 +488,95 @@ func (s *RemoteHduAgent) GetJudgeJobStatus(ctx context.Context, id string) (
 	int,
 	error,
 ) {
-	return s.requestJudgeJobStatus(ctx, id, 0)
+	rows, err := s.requestStatusPage(ctx, "", id, 0)
+	if err != nil {
+		return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, err
+	}
+	for _, row := range rows {
+		if row.RunId == id {
+			return row.Status, row.Score, row.Time, row.Memory, nil
+		}
+	}
+	return foundationjudge.JudgeStatusJudgeFail, 0, 0, 0, metaerror.New("run id not found: %s", id)
+}
+
+func (s *RemoteHduAgent) GetJudgeJobStatusList(ctx context.Context, account string, ids []string) (
+	map[string]*RemoteJudgeStatus,
+	error,
+) {
+	return collectRemoteJudgeStatus(
+		ids, 5, func(first int) ([]*RemoteJudgeStatus, error) {
+			return s.requestStatusPage(ctx, account, strconv.Itoa(first), 0)
+		},
+	)
+}
+
+func (s *RemoteHduAgent) FindSubmitRunId(
+	ctx context.Context,
+	problemId string,
+	language foundationjudge.JudgeLanguage,
+	since time.Time,
+	excludeIds []string,
+) (string, string, error) {
+	username := foundationconfig.GetConfig().Remote.Hdu.Username
+	hduUrl := fmt.Sprintf(
+		"https://acm.hdu.edu.cn/status.php?first=&pid=%s&user=%s",
+		url.QueryEscape(problemId),
+		url.QueryEscape(username),
+	)
+	req, err := http.NewRequestWithContext(ctx, "GET", hduUrl, nil)
+	if err != nil {
+		return "", "", metaerror.Wrap(err, "failed to create FindSubmitRunId request")
+	}
+	req.Header.Add("Accept", "*/*")
+	req.Header.Add("Host", "acm.hdu.edu.cn")
+	req.Header.Add("Connection", "keep-alive")
+	req.Header.Add("Referer", "https://acm.hdu.edu.cn/status.php?first=&pid=&user=&lang=0&status=0")
+	res, err := s.goJudgeClient.Do(req)
+	if err != nil {
+		return "", "", metaerror.Wrap(err, "FindSubmitRunId request failed")
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			metapanic.ProcessError(metaerror.Wrap(err))
+		}
+	}(res.Body)
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return "", "", metaerror.Wrap(err, "failed to parse FindSubmitRunId response body")
+	}
+	var rows []*remoteSubmitRow
+	doc.Find("div#fixed_table table").First().Find("tr").Each(
+		func(i int, s *goquery.Selection) {
+			tds := s.Find("td")
+			if tds.Length() < 8 {
+				return
+			}
+			runId := strings.TrimSpace(tds.Eq(0).Text())
+			if runId == "" || runId == "Run ID" {
+				return
+			}
+			submitTime, err := time.ParseInLocation(
+				time.DateTime,
+				strings.TrimSpace(tds.Eq(1).Text()),
+				remoteTimeLocation,
+			)
+			if err != nil {
+				return
+			}
+			rows = append(
+				rows, &remoteSubmitRow{
+					RunId:      runId,
+					ProblemId:  strings.TrimSpace(tds.Eq(3).Text()),
+					Language:   strings.TrimSpace(tds.Eq(7).Text()),
+					SubmitTime: submitTime,
+				},
+			)
+		},
+	)
+	runId := pickSubmitRunId(rows, problemId, s.getLanguageName(language), since, excludeIds)
+	return runId, username, nil
 }
 
 func (s *RemoteHduAgent) GetJudgeJobExtraMessage(
@@ -74,6 +74,22 @@ func (s *RemotePojAgent) getLanguageCode(language foundationjudge.JudgeLanguage)
 	}
 }
 
+// getLanguageName 状态表中显示的语言名称
+func (s *RemotePojAgent) getLanguageName(language foundationjudge.JudgeLanguage) string {
+	switch language {
+	case foundationjudge.JudgeLanguageC:
+		return "GCC"
+	case foundationjudge.JudgeLanguageCpp:
+		return "G++"
+	case foundationjudge.JudgeLanguagePascal:
+		return "Pascal"
+	case foundationjudge.JudgeLanguageJava:
+		return "Java"
+	default:
+		return ""
+	}
+}
+
 func (s *RemotePojAgent) GetJudgeStatus(status string) foundationjudge.JudgeStatus {
 	switch status {
 	case "Waiting":
@@ -431,6 +447,175 @@ func (s *RemotePojAgent) GetJudgeJobStatus(ctx context.Context, id string) (
 	return s.requestJudgeJobStatus(ctx, id, 0)
 }
 
+// requestStatusPage 获取账号状态表中RunId不大于first的一页记录
+func (s *RemotePojAgent) requestStatusPage(ctx context.Context, username string, first int) (
+	[]*RemoteJudgeStatus,
+	error,
+) {
+	// POJ的top参数为开区间
+	pojUrl := fmt.Sprintf(
+		"http://poj.org/status?user_id=%s&top=%d",
+		url.QueryEscape(username),
+		first+1,
+	)
+	req, err := http.NewRequestWithContext(ctx, "GET", pojUrl, nil)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to create requestStatusPage request")
+	}
+	req.Header.Add("Accept", "*/*")
+	req.Header.Add("Host", "poj.org")
+	req.Header.Add("Connection", "keep-alive")
+	res, err := s.goJudgeClient.Do(req)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "requestStatusPage request failed")
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			metapanic.ProcessError(metaerror.Wrap(err))
+		}
+	}(res.Body)
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to parse requestStatusPage response body")
+	}
+	table := doc.Find("table.a").First()
+	if table.Length() == 0 {
+		return nil, metaerror.New("no status table found")
+	}
+	var rows []*RemoteJudgeStatus
+	var finalErr error
+	table.Find("tr").EachWithBreak(
+		func(i int, s *goquery.Selection) bool {
+			tds := s.Find("td")
+			if tds.Length() < 6 {
+				return true
+			}
+			runId := strings.TrimSpace(tds.Eq(0).Text())
+			if runId == "" || runId == "Run ID" {
+				return true
+			}
+			status := GetRemotePojAgent().GetJudgeStatus(strings.TrimSpace(tds.Eq(3).Text()))
+			exeMemoryStr := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(tds.Eq(4).Text()), "K"))
+			exeTimeStr := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(tds.Eq(5).Text()), "MS"))
+			exeMemory := 0
+			if exeMemoryStr != "" {
+				exeMemory, err = strconv.Atoi(exeMemoryStr)
+				if err != nil {
+					finalErr = metaerror.Wrap(err, "failed to parse execution memory")
+					return false
+				}
+				exeMemory *= 1024
+			}
+			exeTime := 0
+			if exeTimeStr != "" {
+				exeTime, err = strconv.Atoi(exeTimeStr)
+				if err != nil {
+					finalErr = metaerror.Wrap(err, "failed to parse execution time")
+					return false
+				}
+				exeTime *= 1000000
+			}
+			score := 0
+			if status == foundationjudge.JudgeStatusAC {
+				score = 1000
+			}
+			rows = append(
+				rows, &RemoteJudgeStatus{
+					RunId:  runId,
+					Status: status,
+					Score:  score,
+					Time:   exeTime,
+					Memory: exeMemory,
+				},
+			)
+			return true
+		},
+	)
+	if finalErr != nil {
+		return nil, finalErr
+	}
+	return rows, nil
+}
+
+func (s *RemotePojAgent) GetJudgeJobStatusList(ctx context.Context, account string, ids []string) (
+	map[string]*RemoteJudgeStatus,
+	error,
+) {
+	return collectRemoteJudgeStatus(
+		ids, 5, func(first int) ([]*RemoteJudgeStatus, error) {
+			return s.requestStatusPage(ctx, account, first)
+		},
+	)
+}
+
+func (s *RemotePojAgent) FindSubmitRunId(
+	ctx context.Context,
+	problemId string,
+	language foundationjudge.JudgeLanguage,
+	since time.Time,
+	excludeIds []string,
+) (string, string, error) {
+	username := foundationconfig.GetConfig().Remote.Poj.Username
+	pojUrl := fmt.Sprintf(
+		"http://poj.org/status?problem_id=%s&user_id=%s",
+		url.QueryEscape(problemId),
+		url.QueryEscape(username),
+	)
+	req, err := http.NewRequestWithContext(ctx, "GET", pojUrl, nil)
+	if err != nil {
+		return "", "", metaerror.Wrap(err, "failed to create FindSubmitRunId request")
+	}
+	req.Header.Add("Accept", "*/*")
+	req.Header.Add("Host", "poj.org")
+	req.Header.Add("Connection", "keep-alive")
+	res, err := s.goJudgeClient.Do(req)
+	if err != nil {
+		return "", "", metaerror.Wrap(err, "FindSubmitRunId request failed")
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			metapanic.ProcessError(metaerror.Wrap(err))
+		}
+	}(res.Body)
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return "", "", metaerror.Wrap(err, "failed to parse FindSubmitRunId response body")
+	}
+	var rows []*remoteSubmitRow
+	doc.Find("table.a").First().Find("tr").Each(
+		func(i int, s *goquery.Selection) {
+			tds := s.Find("td")
+			if tds.Length() < 9 {
+				return
+			}
+			runId := strings.TrimSpace(tds.Eq(0).Text())
+			if runId == "" || runId == "Run ID" {
+				return
+			}
+			submitTime, err := time.ParseInLocation(
+				time.DateTime,
+				strings.TrimSpace(tds.Eq(8).Text()),
+				remoteTimeLocation,
+			)
+			if err != nil {
+				return
+			}
+			rows = append(
+				rows, &remoteSubmitRow{
+					RunId:      runId,
+					ProblemId:  strings.TrimSpace(tds.Eq(2).Text()),
+					Language:   strings.TrimSpace(tds.Eq(6).Text()),
+					SubmitTime: submitTime,
+				},
+			)
+		},
+	)
+	runId := pickSubmitRunId(rows, problemId, s.getLanguageName(language), since, excludeIds)
+	return runId, username, nil
+}
+
 func (s *RemotePojAgent) GetJudgeJobExtraMessage(
 	ctx context.Context,
 	id string,
@@ -0,0 +1,362 @@
+package foundationrender
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Markdown转LaTeX，用于打印题面，只处理题面中常见的语法
+// 数学公式去掉读写文件等控制序列后保留，图片通过imagePath换成本地文件，返回空时只输出图片的说明文字
+
+const markdownPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+var (
+	latexHeadingRegex     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	latexOrderedItemRegex = regexp.MustCompile(`^\d+[.)]\s+`)
+	latexRuleRegex        = regexp.MustCompile(`^(\*\s*){3,}$|^(-\s*){3,}$|^(_\s*){3,}$`)
+	latexHtmlTagRegex     = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)[^>]*>`)
+)
+
+// latexMathControlRegex 公式中的控制序列
+var latexMathControlRegex = regexp.MustCompile(`\\[a-zA-Z]+`)
+
+// latexMathForbidden 公式中不允许出现的控制序列，题面作者不一定是比赛的管理员，
+// 编译时不能读写文件、修改定义或拼接出新的控制序列
+var latexMathForbidden = map[string]bool{
+	"input": true, "include": true, "includeonly": true, "InputIfFileExists": true, "IfFileExists": true,
+	"openin": true, "openout": true, "read": true, "readline": true, "write": true, "immediate": true,
+	"closein": true, "closeout": true, "newread": true, "newwrite": true, "special": true,
+	"includegraphics": true, "verbatiminput": true, "lstinputlisting": true,
+	"catcode": true, "csname": true, "endcsname": true, "scantokens": true, "makeatletter": true,
+	"def": true, "edef": true, "gdef": true, "xdef": true, "let": true, "futurelet": true,
+	"newcommand": true, "renewcommand": true, "providecommand": true, "DeclareRobustCommand": true,
+	"usepackage": true, "RequirePackage": true, "documentclass": true,
+	"directlua": true, "latelua": true, "ShellEscape": true,
+}
+
+// sanitizeLatexMath 去掉公式中不安全的控制序列，^^可以用字符编码拼出控制序列，一并替换
+func sanitizeLatexMath(formula string) string {
+	formula = latexMathControlRegex.ReplaceAllStringFunc(
+		formula, func(control string) string {
+			if latexMathForbidden[control[1:]] {
+				return "\\backslash\\mathrm{" + control[1:] + "}"
+			}
+			return control
+		},
+	)
+	return strings.ReplaceAll(formula, "^^", "^{\\wedge}")
+}
+
+var latexEscapeReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`^`, `\^{}`,
+	`~`, `\~{}`,
+)
+
+// EscapeLatex 转义LaTeX中的特殊字符
+func EscapeLatex(text string) string {
+	return latexEscapeReplacer.Replace(text)
+}
+
+type latexWriter struct {
+	buf       strings.Builder
+	imagePath func(url string) string
+	list      string   // 当前所在的列表环境
+	paragraph []string // 还未输出的段落
+}
+
+// MarkdownToLatex 将题面的Markdown转为LaTeX片段
+func MarkdownToLatex(markdown string, imagePath func(url string) string) string {
+	w := &latexWriter{imagePath: imagePath}
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			w.flush()
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			// 代码块与样例原样输出
+			fence := trimmed[:3]
+			w.flush()
+			w.buf.WriteString("\\begin{verbatim}\n")
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				// 代码中的结束标记会提前结束verbatim，加空格后不再匹配
+				code := strings.ReplaceAll(lines[i], "\\end{verbatim}", "\\end {verbatim}")
+				w.buf.WriteString(strings.ReplaceAll(code, "\t", "    "))
+				w.buf.WriteString("\n")
+			}
+			w.buf.WriteString("\\end{verbatim}\n\n")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "$$") {
+			w.flush()
+			formula := strings.TrimPrefix(trimmed, "$$")
+			for !strings.HasSuffix(strings.TrimSpace(formula), "$$") && i+1 < len(lines) {
+				i++
+				formula += "\n" + lines[i]
+			}
+			formula = strings.TrimSuffix(strings.TrimSpace(formula), "$$")
+			w.buf.WriteString("\\[\n" + sanitizeLatexMath(strings.TrimSpace(formula)) + "\n\\]\n\n")
+			continue
+		}
+		if match := latexHeadingRegex.FindStringSubmatch(trimmed); match != nil {
+			w.flush()
+			w.buf.WriteString("\\subsection*{" + w.inline(match[2]) + "}\n\n")
+			continue
+		}
+		if latexRuleRegex.MatchString(trimmed) {
+			w.flush()
+			w.buf.WriteString("\\noindent\\rule{\\linewidth}{0.4pt}\n\n")
+			continue
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			w.flush()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				content := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(content, " "))
+			}
+			i--
+			inner := MarkdownToLatex(strings.Join(quote, "\n"), w.imagePath)
+			w.buf.WriteString("\\begin{quote}\n" + inner + "\\end{quote}\n\n")
+			continue
+		}
+		if env, content, ok := getMarkdownListItem(trimmed); ok {
+			w.flushParagraph()
+			if w.list != env {
+				w.closeList()
+				w.buf.WriteString("\\begin{" + env + "}\n")
+				w.list = env
+			}
+			w.buf.WriteString("\\item " + w.inline(content) + "\n")
+			continue
+		}
+		if w.list != "" && (line[0] == ' ' || line[0] == '\t') {
+			// 列表项的续行
+			w.buf.WriteString(w.inline(trimmed) + "\n")
+			continue
+		}
+		w.closeList()
+		if strings.HasSuffix(line, "  ") {
+			trimmed += "<br>"
+		}
+		w.paragraph = append(w.paragraph, trimmed)
+	}
+	w.flush()
+	return w.buf.String()
+}
+
+// getMarkdownListItem 判断是否为列表项，返回对应的LaTeX环境与内容
+func getMarkdownListItem(line string) (string, string, bool) {
+	for _, mark := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(line, mark) {
+			return "itemize", strings.TrimSpace(line[len(mark):]), true
+		}
+	}
+	if loc := latexOrderedItemRegex.FindStringIndex(line); loc != nil {
+		return "enumerate", line[loc[1]:], true
+	}
+	return "", "", false
+}
+
+func (w *latexWriter) flushParagraph() {
+	if len(w.paragraph) == 0 {
+		return
+	}
+	w.buf.WriteString(w.inline(strings.Join(w.paragraph, "\n")) + "\n\n")
+	w.paragraph = nil
+}
+
+func (w *latexWriter) closeList() {
+	if w.list == "" {
+		return
+	}
+	w.buf.WriteString("\\end{" + w.list + "}\n\n")
+	w.list = ""
+}
+
+func (w *latexWriter) flush() {
+	w.flushParagraph()
+	w.closeList()
+}
+
+// inline 处理行内的公式、代码、图片、链接与强调
+func (w *latexWriter) inline(text string) string {
+	var buf strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		if c == '\\' && i+1 < len(text) && strings.IndexByte(markdownPunctuation, text[i+1]) >= 0 {
+			buf.WriteString(EscapeLatex(text[i+1 : i+2]))
+			i += 2
+			continue
+		}
+		if c == '$' {
+			delim := "$"
+			if strings.HasPrefix(text[i:], "$$") {
+				delim = "$$"
+			}
+			start := i + len(delim)
+			if end := strings.Index(text[start:], delim); end > 0 {
+				buf.WriteString(delim + sanitizeLatexMath(text[start:start+end]) + delim)
+				i = start + end + len(delim)
+				continue
+			}
+		}
+		if c == '`' {
+			count := 1
+			for i+count < len(text) && text[i+count] == '`' {
+				count++
+			}
+			delim := text[i : i+count]
+			start := i + count
+			if end := strings.Index(text[start:], delim); end >= 0 {
+				code := strings.TrimSpace(text[start : start+end])
+				buf.WriteString("\\texttt{" + EscapeLatex(code) + "}")
+				i = start + end + count
+				continue
+			}
+		}
+		if c == '!' && i+1 < len(text) && text[i+1] == '[' {
+			if alt, url, n, ok := parseMarkdownLink(text[i+1:]); ok {
+				buf.WriteString(w.image(alt, url))
+				i += 1 + n
+				continue
+			}
+		}
+		if c == '[' {
+			if label, _, n, ok := parseMarkdownLink(text[i:]); ok {
+				buf.WriteString(w.inline(label))
+				i += n
+				continue
+			}
+		}
+		if (c == '*' || c == '_') && !(c == '_' && i > 0 && isLatexWordByte(text[i-1])) {
+			delim := text[i : i+1]
+			command := "\\textit{"
+			if i+1 < len(text) && text[i+1] == c {
+				delim = text[i : i+2]
+				command = "\\textbf{"
+			}
+			start := i + len(delim)
+			end := strings.Index(text[start:], delim)
+			if end > 0 && text[start] != ' ' && text[start+end-1] != ' ' {
+				buf.WriteString(command + w.inline(text[start:start+end]) + "}")
+				i = start + end + len(delim)
+				continue
+			}
+		}
+		if c == '<' {
+			if match := latexHtmlTagRegex.FindStringSubmatch(text[i:]); match != nil {
+				// 表格等HTML只保留文字，换行标签转为换行
+				switch strings.ToLower(match[2]) {
+				case "br":
+					buf.WriteString("\\\\\n")
+				case "p", "tr", "table":
+					buf.WriteString("\n\n")
+				case "td", "th":
+					if match[1] == "" {
+						buf.WriteString(" ")
+					}
+				}
+				i += len(match[0])
+				continue
+			}
+		}
+		if c == '&' {
+			if end := strings.IndexByte(text[i:], ';'); end > 1 && end < 10 {
+				if entity := html.UnescapeString(text[i : i+end+1]); entity != text[i:i+end+1] {
+					buf.WriteString(EscapeLatex(entity))
+					i += end + 1
+					continue
+				}
+			}
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		buf.WriteString(EscapeLatex(text[i : i+size]))
+		i += size
+	}
+	return buf.String()
+}
+
+// isLatexWordByte 单词中的下划线不作为强调处理
+func isLatexWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// image 图片居中显示，过大时缩放到页面内
+func (w *latexWriter) image(alt string, url string) string {
+	path := ""
+	if w.imagePath != nil {
+		path = w.imagePath(url)
+	}
+	if path == "" {
+		return EscapeLatex(alt)
+	}
+	return "\n\n{\\centering\\includegraphics[max width=\\linewidth,max height=0.4\\textheight]{" + path + "}\\par}\n\n"
+}
+
+// parseMarkdownLink 解析以[开头的链接，返回文字、地址与消耗的长度
+func parseMarkdownLink(text string) (string, string, int, bool) {
+	depth := 0
+	labelEnd := -1
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] == '[' {
+			depth++
+		} else if text[i] == ']' {
+			depth--
+			if depth == 0 {
+				labelEnd = i
+				break
+			}
+		}
+	}
+	if labelEnd < 0 || labelEnd+1 >= len(text) || text[labelEnd+1] != '(' {
+		return "", "", 0, false
+	}
+	urlEnd := strings.IndexByte(text[labelEnd+2:], ')')
+	if urlEnd < 0 {
+		return "", "", 0, false
+	}
+	target := strings.Fields(text[labelEnd+2 : labelEnd+2+urlEnd])
+	url := ""
+	if len(target) > 0 {
+		url = strings.Trim(target[0], "<>")
+	}
+	return text[1:labelEnd], url, labelEnd + 2 + urlEnd + 1, true
+}
+
+// RenderLatexDocument 生成完整的文档，需要使用xelatex编译以支持中文
+func RenderLatexDocument(header string, body string) string {
+	var buf strings.Builder
+	buf.WriteString("\\documentclass[a4paper,11pt]{ctexart}\n")
+	buf.WriteString("\\usepackage[margin=2cm]{geometry}\n")
+	buf.WriteString("\\usepackage{amsmath,amssymb}\n")
+	buf.WriteString("\\usepackage{graphicx}\n")
+	buf.WriteString("\\usepackage[export]{adjustbox}\n")
+	buf.WriteString("\\usepackage{fancyhdr}\n")
+	buf.WriteString("\\pagestyle{fancy}\n")
+	buf.WriteString("\\fancyhf{}\n")
+	buf.WriteString("\\fancyhead[L]{" + EscapeLatex(header) + "}\n")
+	buf.WriteString("\\fancyfoot[C]{\\thepage}\n")
+	buf.WriteString("\\setlength{\\parindent}{0pt}\n")
+	buf.WriteString("\\setlength{\\parskip}{0.6em}\n")
+	buf.WriteString("\\begin{document}\n\n")
+	buf.WriteString(body)
+	buf.WriteString("\\end{document}\n")
+	return buf.String()
+}
@@ -0,0 +1,172 @@
+package foundationrender
+
+import (
+	"testing"
+)
+
+// TestEscapeLatex 测试LaTeX特殊字符的转义
+func TestEscapeLatex(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+		name     string
+	}{
+		{"hello 你好", "hello 你好", "普通文字不变"},
+		{`a\b`, `a\textbackslash{}b`, "反斜杠"},
+		{"{x}", `\{x\}`, "花括号"},
+		{"$5 & 10% #1", `\$5 \& 10\% \#1`, "美元、与、百分号和井号"},
+		{"snake_case_name", `snake\_case\_name`, "下划线"},
+		{"a^b~c", `a\^{}b\~{}c`, "脱字符与波浪号"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := EscapeLatex(tc.text); result != tc.expected {
+				t.Errorf("EscapeLatex(%q) = %q; want %q", tc.text, result, tc.expected)
+			}
+		})
+	}
+}
+
+// TestMarkdownToLatex 测试题面中常见Markdown语法的转换结果
+func TestMarkdownToLatex(t *testing.T) {
+	cases := []struct {
+		markdown string
+		expected string
+		name     string
+	}{
+		{
+			"输出 $a_i + b_i$ 的和，答案对 $10^9+7$ 取模。",
+			"输出 $a_i + b_i$ 的和，答案对 $10^9+7$ 取模。\n\n",
+			"行内公式原样保留",
+		},
+		{
+			"$$\n\\sum_{i=1}^{n} a_i\n$$",
+			"\\[\n\\sum_{i=1}^{n} a_i\n\\]\n\n",
+			"多行公式块",
+		},
+		{
+			"$$x^2$$",
+			"\\[\nx^2\n\\]\n\n",
+			"单行公式块",
+		},
+		{
+			"设 $$S = \\{1, 2\\}$$ 为集合",
+			"设 $$S = \\{1, 2\\}$$ 为集合\n\n",
+			"行内的双美元公式",
+		},
+		{
+			"$\\input{/etc/passwd} \\frac{1}{2}$",
+			"$\\backslash\\mathrm{input}{/etc/passwd} \\frac{1}{2}$\n\n",
+			"公式中不能读取文件",
+		},
+		{
+			"$$\n\\immediate\\write18{ls} \\inp^^75t x\n$$",
+			"\\[\n\\backslash\\mathrm{immediate}\\backslash\\mathrm{write}18{ls} \\inp^{\\wedge}75t x\n\\]\n\n",
+			"公式块中不能写文件或用字符编码拼出控制序列",
+		},
+		{
+			"```\n\\end{verbatim}\\input{x}\n```",
+			"\\begin{verbatim}\n\\end {verbatim}\\input{x}\n\\end{verbatim}\n\n",
+			"代码中的结束标记不会提前结束代码块",
+		},
+		{
+			"```cpp\nint main() {\n\treturn 0; // 50%\n}\n```",
+			"\\begin{verbatim}\nint main() {\n    return 0; // 50%\n}\n\\end{verbatim}\n\n",
+			"代码块原样输出",
+		},
+		{
+			"~~~\n1 2\n~~~\n样例说明",
+			"\\begin{verbatim}\n1 2\n\\end{verbatim}\n\n样例说明\n\n",
+			"波浪线代码块",
+		},
+		{
+			"- 第一项\n- 第二项\n\n1. 一\n2. 二",
+			"\\begin{itemize}\n\\item 第一项\n\\item 第二项\n\\end{itemize}\n\n" +
+				"\\begin{enumerate}\n\\item 一\n\\item 二\n\\end{enumerate}\n\n",
+			"无序与有序列表",
+		},
+		{
+			"* 数据范围\n  $n \\le 10^5$\n结束",
+			"\\begin{itemize}\n\\item 数据范围\n$n \\le 10^5$\n\\end{itemize}\n\n结束\n\n",
+			"列表项的续行",
+		},
+		{
+			"变量 max_value 和 snake_case_name",
+			"变量 max\\_value 和 snake\\_case\\_name\n\n",
+			"单词中的下划线不作为强调",
+		},
+		{
+			"_斜体_ 与 __粗体__ 与 **粗体** 与 *斜体*",
+			"\\textit{斜体} 与 \\textbf{粗体} 与 \\textbf{粗体} 与 \\textit{斜体}\n\n",
+			"强调",
+		},
+		{
+			"使用 `a_b & c` 与 \\* 与 100%",
+			"使用 \\texttt{a\\_b \\& c} 与 * 与 100\\%\n\n",
+			"行内代码与转义",
+		},
+		{
+			"## 输入格式 #",
+			"\\subsection*{输入格式}\n\n",
+			"标题",
+		},
+		{
+			"第一行  \n第二行",
+			"第一行\\\\\n\n第二行\n\n",
+			"行尾两个空格换行",
+		},
+		{
+			"> 提示：$a_i$",
+			"\\begin{quote}\n提示：$a_i$\n\n\\end{quote}\n\n",
+			"引用",
+		},
+		{
+			"见[链接](https://example.com) 1 &lt; 2",
+			"见链接 1 < 2\n\n",
+			"链接只保留文字，HTML实体转为字符",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := MarkdownToLatex(tc.markdown, nil); result != tc.expected {
+				t.Errorf("MarkdownToLatex(%q) = %q; want %q", tc.markdown, result, tc.expected)
+			}
+		})
+	}
+}
+
+// TestMarkdownToLatexImage 图片通过imagePath换成本地文件，不支持时只输出说明文字
+func TestMarkdownToLatexImage(t *testing.T) {
+	imagePath := func(url string) string {
+		if url == "https://r2.example.com/a.png" {
+			return "images/1.png"
+		}
+		return ""
+	}
+	cases := []struct {
+		markdown string
+		expected string
+		name     string
+	}{
+		{
+			"![图1](https://r2.example.com/a.png)",
+			"\n\n{\\centering\\includegraphics[max width=\\linewidth,max height=0.4\\textheight]{images/1.png}\\par}\n\n\n\n",
+			"支持的图片",
+		},
+		{
+			"![图_2](http://127.0.0.1/b.png)",
+			"图\\_2\n\n",
+			"不支持的图片只输出说明文字",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := MarkdownToLatex(tc.markdown, imagePath); result != tc.expected {
+				t.Errorf("MarkdownToLatex(%q) = %q; want %q", tc.markdown, result, tc.expected)
+			}
+		})
+	}
+}
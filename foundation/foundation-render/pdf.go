@@ -0,0 +1,258 @@
+package foundationrender
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// 纯文本的PDF排版，配置了字体时嵌入该字体，中文等宽字符按两列计算
+// 没有配置字体时使用PDF内置的Courier等宽字体，只支持Latin-1字符，其他字符输出为?
+
+const (
+	pdfPageWidth  = 595 // A4纸，单位pt
+	pdfPageHeight = 842
+	pdfMargin     = 36
+	pdfFontSize   = 9
+	pdfLineHeight = 11
+
+	PdfTextColumns = 95 // 每行字符数
+	PdfTextLines   = 68 // 每页正文行数
+)
+
+// getRuneColumns 中日韩文字与全角符号占两列
+func getRuneColumns(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
+
+// WrapText 展开制表符并按列宽折行
+func WrapText(content string, columns int) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		start, width := 0, 0
+		for i, r := range line {
+			runeColumns := getRuneColumns(r)
+			if width+runeColumns > columns {
+				lines = append(lines, line[start:i])
+				start, width = i, 0
+			}
+			width += runeColumns
+		}
+		lines = append(lines, line[start:])
+	}
+	return lines
+}
+
+// PaginateText 折行后按每页行数分页
+func PaginateText(content string, columns int, linesPerPage int) [][]string {
+	lines := WrapText(content, columns)
+	var pages [][]string
+	for len(lines) > linesPerPage {
+		pages = append(pages, lines[:linesPerPage])
+		lines = lines[linesPerPage:]
+	}
+	return append(pages, lines)
+}
+
+// IsPdfTextSupported 是否能完整显示文本，没有配置字体时只支持Latin-1字符
+func IsPdfTextSupported(text string) bool {
+	font := getPdfFont()
+	for _, r := range text {
+		if r < 32 {
+			continue
+		}
+		if font != nil {
+			if _, ok := font.cmap[r]; !ok {
+				return false
+			}
+		} else if r > 255 || (r >= 127 && r < 160) {
+			return false
+		}
+	}
+	return true
+}
+
+// writePdfString 输出PDF字符串常量，转义括号与反斜杠
+func writePdfString(buf *bytes.Buffer, text string) {
+	buf.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(byte(r))
+		case r < 32:
+			buf.WriteByte(' ')
+		case r < 127 || (r >= 160 && r <= 255):
+			buf.WriteByte(byte(r))
+		default:
+			buf.WriteByte('?')
+		}
+	}
+	buf.WriteByte(')')
+}
+
+// pdfTextRun 一行中字体样式相同的一段文字
+type pdfTextRun struct {
+	text string
+	bold bool
+}
+
+// splitPdfKeywords 将一行按标识符切分，属于关键字的标识符单独成段并加粗
+func splitPdfKeywords(line string, keywords map[string]bool) []pdfTextRun {
+	if len(keywords) == 0 {
+		return []pdfTextRun{{text: line}}
+	}
+	isWord := func(r rune) bool {
+		return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+	}
+	var runs []pdfTextRun
+	start := 0
+	for i := 0; i < len(line); {
+		if !isWord(rune(line[i])) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(line) && isWord(rune(line[j])) {
+			j++
+		}
+		if keywords[line[i:j]] {
+			if start < i {
+				runs = append(runs, pdfTextRun{text: line[start:i]})
+			}
+			runs = append(runs, pdfTextRun{text: line[i:j], bold: true})
+			start = j
+		}
+		i = j
+	}
+	if start < len(line) || len(runs) == 0 {
+		runs = append(runs, pdfTextRun{text: line[start:]})
+	}
+	return runs
+}
+
+// RenderTextPdf 将分页后的文本输出为PDF，每页顶部为加粗的页眉和页码
+func RenderTextPdf(header string, pages [][]string) []byte {
+	return RenderCodePdf(header, pages, nil)
+}
+
+// RenderCodePdf 与RenderTextPdf相同，正文中的关键字加粗显示
+func RenderCodePdf(header string, pages [][]string, keywords []string) []byte {
+	keywordSet := make(map[string]bool, len(keywords))
+	for _, keyword := range keywords {
+		keywordSet[keyword] = true
+	}
+	if len(pages) == 0 {
+		pages = [][]string{nil}
+	}
+	font := getPdfFont()
+	usedGlyphs := make(map[uint16]rune)
+	writeText := func(buf *bytes.Buffer, text string) {
+		if font == nil {
+			writePdfString(buf, text)
+		} else {
+			writePdfFontString(buf, font, text, usedGlyphs)
+		}
+	}
+	var buf bytes.Buffer
+	var offsets []int
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		_, _ = fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	// 1 目录，2 页面树，3、4 字体，之后每页依次为页面对象和内容流
+	// 嵌入字体时3、4为同一字体，页眉使用描边加粗，字体用到的对象放在所有页面之后
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+i*2))
+	}
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	fontObject := 5 + len(pages)*2
+	// 没有嵌入字体时加粗使用Courier-Bold，与Courier等宽，切换字体不影响对齐
+	// 描边加粗的文字渲染模式在文本对象之间保持，正文开始时需要恢复
+	headerStyle, bodyStyle := "", ""
+	boldStyle, normalStyle := fmt.Sprintf("/F2 %d Tf ", pdfFontSize), fmt.Sprintf("/F1 %d Tf ", pdfFontSize)
+	if font == nil {
+		writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+		writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	} else {
+		fontDict := fmt.Sprintf(
+			"<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+				"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			pdfFontName, fontObject, fontObject+3,
+		)
+		writeObject(fontDict)
+		writeObject(fontDict)
+		headerStyle, bodyStyle = "2 Tr 0.3 w ", "0 Tr "
+		boldStyle, normalStyle = "2 Tr 0.3 w ", "0 Tr "
+	}
+
+	top := pdfPageHeight - pdfMargin - pdfFontSize
+	for i, lines := range pages {
+		var content bytes.Buffer
+		_, _ = fmt.Fprintf(&content, "BT /F2 %d Tf %s%d %d Td ", pdfFontSize, headerStyle, pdfMargin, top)
+		writeText(&content, fmt.Sprintf("%s  Page %d/%d", header, i+1, len(pages)))
+		content.WriteString(" Tj ET\n")
+		_, _ = fmt.Fprintf(
+			&content,
+			"BT /F1 %d Tf %s%d TL %d %d Td\n",
+			pdfFontSize,
+			bodyStyle,
+			pdfLineHeight,
+			pdfMargin,
+			top-pdfLineHeight*2,
+		)
+		for _, line := range lines {
+			for _, run := range splitPdfKeywords(line, keywordSet) {
+				if run.bold {
+					content.WriteString(boldStyle)
+				}
+				writeText(&content, run.text)
+				content.WriteString(" Tj ")
+				if run.bold {
+					content.WriteString(normalStyle)
+				}
+			}
+			content.WriteString("T*\n")
+		}
+		content.WriteString("ET")
+
+		writeObject(
+			fmt.Sprintf(
+				"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+					"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 6+i*2,
+			),
+		)
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+	if font != nil {
+		for _, object := range renderPdfFontObjects(font, fontObject, usedGlyphs) {
+			writeObject(object)
+		}
+	}
+
+	xrefOffset := buf.Len()
+	_, _ = fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		_, _ = fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	_, _ = fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+	return buf.Bytes()
+}
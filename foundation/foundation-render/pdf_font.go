@@ -0,0 +1,457 @@
+package foundationrender
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"web/config"
+)
+
+// PDF中嵌入的TrueType字体，用于输出中文等内置字体不支持的字符
+// 只支持glyf轮廓的字体（.ttf或.ttc中的第一个），输出时只保留用到的字形
+
+const pdfFontName = "DIDAOJ+Text"
+
+type pdfFont struct {
+	tables     map[string][]byte // 表名 -> 表数据
+	unitsPerEm int
+	numGlyphs  int
+	longLoca   bool
+	advances   []int // 字形的前进宽度
+	cmap       map[rune]uint16
+	ascent     int
+	descent    int
+	bbox       [4]int
+}
+
+var (
+	pdfFontOnce   sync.Once
+	pdfFontLoaded *pdfFont
+)
+
+// getPdfFont 读取配置的字体，没有配置或读取失败时返回nil，使用内置字体
+func getPdfFont() *pdfFont {
+	pdfFontOnce.Do(
+		func() {
+			fontPath := config.GetPdfFont()
+			if fontPath == "" {
+				return
+			}
+			data, err := os.ReadFile(fontPath)
+			if err != nil {
+				metapanic.ProcessError(metaerror.Wrap(err, "failed to read pdf font, path:%s", fontPath))
+				return
+			}
+			font, err := parsePdfFont(data)
+			if err != nil {
+				metapanic.ProcessError(metaerror.Wrap(err, "failed to parse pdf font, path:%s", fontPath))
+				return
+			}
+			pdfFontLoaded = font
+		},
+	)
+	return pdfFontLoaded
+}
+
+// ttfUint16 越界时返回0，避免损坏的字体文件导致panic
+func ttfUint16(b []byte, offset int) int {
+	if offset < 0 || offset+2 > len(b) {
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(b[offset:]))
+}
+
+func ttfInt16(b []byte, offset int) int {
+	return int(int16(ttfUint16(b, offset)))
+}
+
+func ttfUint32(b []byte, offset int) int {
+	if offset < 0 || offset+4 > len(b) {
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(b[offset:]))
+}
+
+func parsePdfFont(data []byte) (*pdfFont, error) {
+	offset := 0
+	if len(data) >= 16 && string(data[:4]) == "ttcf" {
+		offset = ttfUint32(data, 12)
+	}
+	if offset+12 > len(data) {
+		return nil, metaerror.New("invalid font file")
+	}
+	if version := ttfUint32(data, offset); version != 0x00010000 && string(data[offset:offset+4]) != "true" {
+		return nil, metaerror.New("only TrueType outlines are supported")
+	}
+	font := &pdfFont{tables: make(map[string][]byte)}
+	numTables := ttfUint16(data, offset+4)
+	for i := 0; i < numTables; i++ {
+		record := offset + 12 + 16*i
+		if record+16 > len(data) {
+			return nil, metaerror.New("invalid font table directory")
+		}
+		tableOffset := ttfUint32(data, record+8)
+		tableLength := ttfUint32(data, record+12)
+		if tableOffset+tableLength > len(data) {
+			return nil, metaerror.New("invalid font table, tag:%s", string(data[record:record+4]))
+		}
+		font.tables[string(data[record:record+4])] = data[tableOffset : tableOffset+tableLength]
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "loca", "glyf", "cmap"} {
+		if _, ok := font.tables[tag]; !ok {
+			return nil, metaerror.New("font table missing, tag:%s", tag)
+		}
+	}
+
+	head := font.tables["head"]
+	if len(head) < 54 {
+		return nil, metaerror.New("invalid font head table")
+	}
+	font.unitsPerEm = ttfUint16(head, 18)
+	if font.unitsPerEm == 0 {
+		return nil, metaerror.New("invalid font units per em")
+	}
+	for i := range font.bbox {
+		font.bbox[i] = ttfInt16(head, 36+2*i)
+	}
+	font.longLoca = ttfInt16(head, 50) == 1
+	hhea := font.tables["hhea"]
+	font.ascent = ttfInt16(hhea, 4)
+	font.descent = ttfInt16(hhea, 6)
+	font.numGlyphs = ttfUint16(font.tables["maxp"], 4)
+	if font.numGlyphs == 0 {
+		return nil, metaerror.New("font has no glyphs")
+	}
+
+	// 超出numberOfHMetrics的字形使用最后一个宽度
+	numMetrics := ttfUint16(hhea, 34)
+	font.advances = make([]int, font.numGlyphs)
+	for i := range font.advances {
+		if i < numMetrics {
+			font.advances[i] = ttfUint16(font.tables["hmtx"], 4*i)
+		} else if i > 0 {
+			font.advances[i] = font.advances[i-1]
+		}
+	}
+	font.cmap = parsePdfFontCmap(font.tables["cmap"], font.numGlyphs)
+	if len(font.cmap) == 0 {
+		return nil, metaerror.New("font has no unicode cmap")
+	}
+	return font, nil
+}
+
+// parsePdfFontCmap 读取Unicode字符到字形的映射，优先使用支持全部平面的format 12
+func parsePdfFontCmap(cmap []byte, numGlyphs int) map[rune]uint16 {
+	var format4, format12 []byte
+	for i := 0; i < ttfUint16(cmap, 2); i++ {
+		record := 4 + 8*i
+		platformId := ttfUint16(cmap, record)
+		encodingId := ttfUint16(cmap, record+2)
+		if platformId != 0 && !(platformId == 3 && (encodingId == 1 || encodingId == 10)) {
+			continue
+		}
+		subtableOffset := ttfUint32(cmap, record+4)
+		if subtableOffset >= len(cmap) {
+			continue
+		}
+		subtable := cmap[subtableOffset:]
+		switch ttfUint16(subtable, 0) {
+		case 4:
+			format4 = subtable
+		case 12:
+			format12 = subtable
+		}
+	}
+	result := make(map[rune]uint16)
+	add := func(r int, glyph int) {
+		if glyph > 0 && glyph < numGlyphs && r <= 0x10FFFF {
+			result[rune(r)] = uint16(glyph)
+		}
+	}
+	if format12 != nil {
+		for i := 0; i < ttfUint32(format12, 12); i++ {
+			group := 16 + 12*i
+			if group+12 > len(format12) {
+				break
+			}
+			start := ttfUint32(format12, group)
+			end := ttfUint32(format12, group+4)
+			glyph := ttfUint32(format12, group+8)
+			for c := start; c <= end && c <= 0x10FFFF; c++ {
+				add(c, glyph+c-start)
+			}
+		}
+		return result
+	}
+	if format4 != nil {
+		segCount := ttfUint16(format4, 6) / 2
+		for i := 0; i < segCount; i++ {
+			end := ttfUint16(format4, 14+2*i)
+			start := ttfUint16(format4, 16+2*segCount+2*i)
+			delta := ttfUint16(format4, 16+4*segCount+2*i)
+			rangeOffsetPos := 16 + 6*segCount + 2*i
+			rangeOffset := ttfUint16(format4, rangeOffsetPos)
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				if rangeOffset == 0 {
+					add(c, (c+delta)&0xFFFF)
+					continue
+				}
+				glyph := ttfUint16(format4, rangeOffsetPos+rangeOffset+2*(c-start))
+				if glyph != 0 {
+					add(c, (glyph+delta)&0xFFFF)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// glyph 获取字形数据
+func (f *pdfFont) glyph(id int) []byte {
+	loca := f.tables["loca"]
+	var start, end int
+	if f.longLoca {
+		start, end = ttfUint32(loca, 4*id), ttfUint32(loca, 4*id+4)
+	} else {
+		start, end = ttfUint16(loca, 2*id)*2, ttfUint16(loca, 2*id+2)*2
+	}
+	glyf := f.tables["glyf"]
+	if start >= end || end > len(glyf) {
+		return nil
+	}
+	return glyf[start:end]
+}
+
+// glyphId 字体中没有的字符显示为?
+func (f *pdfFont) glyphId(r rune) uint16 {
+	if id, ok := f.cmap[r]; ok {
+		return id
+	}
+	return f.cmap['?']
+}
+
+// width 字形宽度，换算为PDF使用的千分之一字号
+func (f *pdfFont) width(id uint16) int {
+	return f.advances[id] * 1000 / f.unitsPerEm
+}
+
+// scale 将字体单位换算为PDF使用的千分之一字号
+func (f *pdfFont) scale(value int) int {
+	return value * 1000 / f.unitsPerEm
+}
+
+// subset 生成只包含用到字形的字体文件，字形编号保持不变
+func (f *pdfFont) subset(used map[uint16]bool) []byte {
+	used[0] = true
+	queue := make([]int, 0, len(used))
+	for id := range used {
+		queue = append(queue, int(id))
+	}
+	// 组合字形引用的字形也需要保留
+	for len(queue) > 0 {
+		id := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		data := f.glyph(id)
+		if len(data) < 10 || ttfInt16(data, 0) >= 0 {
+			continue
+		}
+		for offset := 10; offset+4 <= len(data); {
+			flags := ttfUint16(data, offset)
+			component := ttfUint16(data, offset+2)
+			if component < f.numGlyphs && !used[uint16(component)] {
+				used[uint16(component)] = true
+				queue = append(queue, component)
+			}
+			offset += 4
+			if flags&0x1 != 0 {
+				offset += 4
+			} else {
+				offset += 2
+			}
+			switch {
+			case flags&0x8 != 0:
+				offset += 2
+			case flags&0x40 != 0:
+				offset += 4
+			case flags&0x80 != 0:
+				offset += 8
+			}
+			if flags&0x20 == 0 {
+				break
+			}
+		}
+	}
+
+	var glyf bytes.Buffer
+	loca := make([]byte, 4*(f.numGlyphs+1))
+	for id := 0; id < f.numGlyphs; id++ {
+		binary.BigEndian.PutUint32(loca[4*id:], uint32(glyf.Len()))
+		if !used[uint16(id)] {
+			continue
+		}
+		glyf.Write(f.glyph(id))
+		for glyf.Len()%4 != 0 {
+			glyf.WriteByte(0)
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*f.numGlyphs:], uint32(glyf.Len()))
+
+	// 改为长格式的loca，校验和调整值最后重新计算
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+	tables := map[string][]byte{
+		"head": head,
+		"hhea": f.tables["hhea"],
+		"maxp": f.tables["maxp"],
+		"hmtx": f.tables["hmtx"],
+		"loca": loca,
+		"glyf": glyf.Bytes(),
+	}
+	for _, tag := range []string{"cvt ", "fpgm", "prep"} {
+		if table, ok := f.tables[tag]; ok {
+			tables[tag] = table
+		}
+	}
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	var buf bytes.Buffer
+	_ = binary.Write(
+		&buf, binary.BigEndian,
+		[]uint16{
+			1, 0,
+			uint16(len(tags)),
+			uint16(16 << entrySelector),
+			uint16(entrySelector),
+			uint16(len(tags)*16 - 16<<entrySelector),
+		},
+	)
+	offset := 12 + 16*len(tags)
+	headOffset := 0
+	for _, tag := range tags {
+		buf.WriteString(tag)
+		_ = binary.Write(
+			&buf, binary.BigEndian,
+			[]uint32{pdfFontChecksum(tables[tag]), uint32(offset), uint32(len(tables[tag]))},
+		)
+		if tag == "head" {
+			headOffset = offset
+		}
+		offset += (len(tables[tag]) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		buf.Write(tables[tag])
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	result := buf.Bytes()
+	binary.BigEndian.PutUint32(result[headOffset+8:], 0xB1B0AFBA-pdfFontChecksum(result))
+	return result
+}
+
+func pdfFontChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// writePdfFontString 输出字形编号组成的十六进制字符串，记录用到的字形
+func writePdfFontString(buf *bytes.Buffer, font *pdfFont, text string, used map[uint16]rune) {
+	buf.WriteByte('<')
+	for _, r := range text {
+		if r < 32 {
+			r = ' '
+		}
+		id := font.glyphId(r)
+		if _, ok := used[id]; !ok {
+			used[id] = r
+		}
+		_, _ = fmt.Fprintf(buf, "%04X", id)
+	}
+	buf.WriteByte('>')
+}
+
+// renderPdfFontObjects 生成字体的对象，first为第一个对象的编号，依次为字体、字体描述、字体文件与ToUnicode
+func renderPdfFontObjects(font *pdfFont, first int, used map[uint16]rune) []string {
+	ids := make([]int, 0, len(used))
+	glyphs := make(map[uint16]bool, len(used))
+	for id := range used {
+		ids = append(ids, int(id))
+		glyphs[id] = true
+	}
+	sort.Ints(ids)
+
+	var widths strings.Builder
+	for _, id := range ids {
+		_, _ = fmt.Fprintf(&widths, "%d [%d] ", id, font.width(uint16(id)))
+	}
+	cidFont := fmt.Sprintf(
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW 1000 /W [%s] >>",
+		pdfFontName, first+1, strings.TrimSpace(widths.String()),
+	)
+	descriptor := fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [%d %d %d %d] "+
+			"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		pdfFontName,
+		font.scale(font.bbox[0]), font.scale(font.bbox[1]), font.scale(font.bbox[2]), font.scale(font.bbox[3]),
+		font.scale(font.ascent), font.scale(font.descent), font.scale(font.ascent), first+2,
+	)
+
+	fontData := font.subset(glyphs)
+	var compressed bytes.Buffer
+	zlibWriter := zlib.NewWriter(&compressed)
+	_, _ = zlibWriter.Write(fontData)
+	_ = zlibWriter.Close()
+	fontFile := fmt.Sprintf(
+		"<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+		compressed.Len(), len(fontData), compressed.String(),
+	)
+
+	// ToUnicode用于复制与搜索文字，每段最多100项
+	var cmap strings.Builder
+	cmap.WriteString(
+		"/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+			"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+			"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n",
+	)
+	for start := 0; start < len(ids); start += 100 {
+		end := min(start+100, len(ids))
+		_, _ = fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, id := range ids[start:end] {
+			_, _ = fmt.Fprintf(&cmap, "<%04X> <", id)
+			for _, unit := range utf16.Encode([]rune{used[uint16(id)]}) {
+				_, _ = fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	toUnicode := fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", cmap.Len(), cmap.String())
+
+	return []string{cidFont, descriptor, fontFile, toUnicode}
+}
@@ -0,0 +1,37 @@
+package foundationrender
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSplitPdfKeywords 测试打印代码时关键字的切分
+func TestSplitPdfKeywords(t *testing.T) {
+	keywords := map[string]bool{"int": true, "return": true}
+	cases := []struct {
+		line     string
+		expected []pdfTextRun
+		name     string
+	}{
+		{"", []pdfTextRun{{text: ""}}, "空行"},
+		{"a = b;", []pdfTextRun{{text: "a = b;"}}, "没有关键字"},
+		{
+			"int main() {",
+			[]pdfTextRun{{text: "int", bold: true}, {text: " main() {"}},
+			"行首关键字",
+		},
+		{
+			"    return x_int + 1int;",
+			[]pdfTextRun{{text: "    "}, {text: "return", bold: true}, {text: " x_int + 1int;"}},
+			"标识符中的关键字不加粗",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := splitPdfKeywords(tc.line, keywords); !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("splitPdfKeywords(%q) = %v; want %v", tc.line, result, tc.expected)
+			}
+		})
+	}
+}
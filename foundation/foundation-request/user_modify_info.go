@@ -22,3 +22,10 @@ type UserModifyVjudge struct {
 	Approved bool   `json:"approved"`
 	Username string `json:"username"`
 }
+
+// UserModifyExternal 绑定Codeforces与HDU账号，与Vjudge相同需要验证账号归属
+type UserModifyExternal struct {
+	Source   string `json:"source"`
+	Approved bool   `json:"approved"`
+	Username string `json:"username"`
+}
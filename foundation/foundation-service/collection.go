@@ -5,6 +5,7 @@ import (
 	foundationauth "foundation/foundation-auth"
 	foundationdao "foundation/foundation-dao"
 	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
 	foundationmodel "foundation/foundation-model"
 	foundationview "foundation/foundation-view"
 	"meta/singleton"
@@ -91,6 +92,12 @@ func (s *CollectionService) GetCollection(ctx context.Context, id int, userId in
 		return
 	}
 	collection.Password = nil // 不真正返回密码
+	var languages []string
+	languages, err = foundationdao.GetCollectionDao().GetCollectionLanguages(ctx, id)
+	if err != nil {
+		return
+	}
+	collection.Languages = foundationjudge.GetLanguagesByKeys(languages)
 	if userId > 0 {
 		joined, err = foundationdao.GetCollectionDao().CheckUserJoin(ctx, id, userId)
 		if err != nil {
@@ -178,9 +185,23 @@ func (s *CollectionService) GetCollectionEdit(ctx context.Context, id int) (
 	if err != nil {
 		return nil, err
 	}
+	languages, err := foundationdao.GetCollectionDao().GetCollectionLanguages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	collection.Languages = foundationjudge.GetLanguagesByKeys(languages)
 	return collection, nil
 }
 
+// CheckLanguage 判断语言是否允许在题集中提交
+func (s *CollectionService) CheckLanguage(ctx context.Context, id int, language foundationjudge.JudgeLanguage) (bool, error) {
+	languages, err := foundationdao.GetCollectionDao().GetCollectionLanguages(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return foundationjudge.IsLanguageAllowed(languages, language), nil
+}
+
 func (s *CollectionService) GetCollectionList(
 	ctx context.Context,
 	page int,
@@ -208,6 +229,12 @@ func (s *CollectionService) GetCollectionRanks(ctx context.Context, id int) (
 	if err != nil {
 		return
 	}
+	var languages []string
+	languages, err = foundationdao.GetCollectionDao().GetCollectionLanguages(ctx, id)
+	if err != nil {
+		return
+	}
+	collection.Languages = foundationjudge.GetLanguagesByKeys(languages)
 	ranks, err = foundationdao.GetCollectionDao().GetCollectionRank(ctx, id, collection)
 	if err != nil {
 		return
@@ -228,16 +255,18 @@ func (s *CollectionService) InsertCollection(
 	ctx context.Context,
 	collection *foundationmodel.Collection,
 	problemIds []int,
+	languages []string,
 	members []int,
 ) error {
-	return foundationdao.GetCollectionDao().InsertCollection(ctx, collection, problemIds, members)
+	return foundationdao.GetCollectionDao().InsertCollection(ctx, collection, problemIds, languages, members)
 }
 
 func (s *CollectionService) UpdateCollection(
 	ctx context.Context,
 	collection *foundationmodel.Collection,
 	problemIds []int,
+	languages []string,
 	members []int,
 ) error {
-	return foundationdao.GetCollectionDao().UpdateCollection(ctx, collection, problemIds, members)
+	return foundationdao.GetCollectionDao().UpdateCollection(ctx, collection, problemIds, languages, members)
 }
@@ -1,28 +1,17 @@
 package foundationservice
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	foundationauth "foundation/foundation-auth"
 	foundationcontest "foundation/foundation-contest"
 	foundationdao "foundation/foundation-dao"
 	foundationenum "foundation/foundation-enum"
 	foundationjudge "foundation/foundation-judge"
 	foundationmodel "foundation/foundation-model"
+	foundationremote "foundation/foundation-remote"
 	foundationview "foundation/foundation-view"
-	"io"
 	metaerror "meta/meta-error"
-	metapanic "meta/meta-panic"
-	metapath "meta/meta-path"
-	metazip "meta/meta-zip"
 	"meta/singleton"
-	"mime/multipart"
-	"net/http"
-	"os"
-	"path"
-	"path/filepath"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -76,7 +65,10 @@ func (s *ContestService) CheckViewAuthWithoutStartTime(ctx *gin.Context, id int)
 		if err != nil {
 			return userId, false, err
 		}
-		return userId, hasAuth, nil
+		if hasAuth {
+			hasAuth, err = s.checkContestExamAccess(ctx, id, userId)
+		}
+		return userId, hasAuth, err
 	}
 	return userId, true, nil
 }
@@ -91,7 +83,10 @@ func (s *ContestService) CheckViewAuthWithEnd(ctx *gin.Context, id int) (int, bo
 		if err != nil {
 			return userId, false, err
 		}
-		return userId, hasAuth, nil
+		if hasAuth {
+			hasAuth, err = s.checkContestExamAccess(ctx, id, userId)
+		}
+		return userId, hasAuth, err
 	}
 	return userId, true, nil
 }
@@ -107,7 +102,10 @@ func (s *ContestService) CheckViewAuth(ctx *gin.Context, id int) (int, bool, err
 		if err != nil {
 			return userId, false, err
 		}
-		return userId, hasAuth, nil
+		if hasAuth {
+			hasAuth, err = s.checkContestExamAccess(ctx, id, userId)
+		}
+		return userId, hasAuth, err
 	}
 	return userId, true, nil
 }
@@ -125,7 +123,10 @@ func (s *ContestService) CheckSubmitAuth(ctx *gin.Context, id int) (int, bool, e
 		if err != nil {
 			return userId, false, err
 		}
-		return userId, hasAuth, nil
+		if hasAuth {
+			hasAuth, err = s.checkContestExamAccess(ctx, id, userId)
+		}
+		return userId, hasAuth, err
 	}
 	return userId, true, nil
 }
@@ -165,10 +166,30 @@ func (s *ContestService) GetContest(ctx *gin.Context, id int, nowTime time.Time)
 	if !hasAuth {
 		return
 	}
+	var languages []string
+	languages, err = foundationdao.GetContestDao().GetContestLanguages(ctx, id)
+	if err != nil {
+		return
+	}
+	contest.Languages = foundationjudge.GetLanguagesByKeys(languages)
 	// 如果还没开始，则不需要获取问题和提交状态了
 	if nowTime.Before(contest.StartTime) {
 		return
 	}
+	if contest.PersonalDuration != nil {
+		if userId > 0 {
+			contest.PersonalStartTime, err = GetContestService().GetContestWindowStartTime(ctx, id, userId)
+			if err != nil {
+				return
+			}
+		}
+		// 个人比赛窗口开始前不展示题目
+		var canView bool
+		_, canView, err = GetContestService().CheckViewAuth(ctx, id)
+		if err != nil || !canView {
+			return
+		}
+	}
 	contest.Problems, err = foundationdao.GetContestProblemDao().GetProblemsDetail(ctx, id)
 	if err != nil {
 		return
@@ -265,13 +286,35 @@ func (s *ContestService) GetContestEdit(ctx context.Context, id int) (*foundatio
 	if err != nil {
 		return nil, err
 	}
-	contest.Members, err = foundationdao.GetContestMemberDao().GetUsersWithName(ctx, id)
+	contest.ProblemSettings, err = foundationdao.GetContestProblemDao().GetProblemSettings(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	contest.Members, err = foundationdao.GetContestMemberDao().GetUsersWithInfo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	contest.Volunteers, err = foundationdao.GetContestDao().GetContestVolunteerIds(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	languages, err := foundationdao.GetContestDao().GetContestLanguages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	contest.Languages = foundationjudge.GetLanguagesByKeys(languages)
 	return contest, err
 }
 
+// CheckLanguage 判断语言是否允许在比赛中提交
+func (s *ContestService) CheckLanguage(ctx context.Context, id int, language foundationjudge.JudgeLanguage) (bool, error) {
+	languages, err := foundationdao.GetContestDao().GetContestLanguages(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return foundationjudge.IsLanguageAllowed(languages, language), nil
+}
+
 func (s *ContestService) GetContestTime(ctx *gin.Context, id int) (
 	startTime *time.Time,
 	endTime *time.Time,
@@ -342,9 +385,17 @@ func (s *ContestService) GetContestProblemsWithAttemptStatus(ctx *gin.Context, i
 		for _, problem := range problems {
 			problemIds = append(problemIds, problem.ProblemId)
 		}
-		attemptStatuses, err := foundationdao.GetJudgeJobDao().GetProblemAttemptStatus(
+		// 队伍成员共享题目的尝试状态
+		inserters, err := foundationdao.GetContestTeamDao().GetContestTeamUserIds(ctx, id, userId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(inserters) == 0 {
+			inserters = []int{userId}
+		}
+		attemptStatuses, err := foundationdao.GetJudgeJobDao().GetProblemAttemptStatusByInserters(
 			ctx,
-			userId,
+			inserters,
 			problemIds,
 			id,
 			nil,
@@ -402,6 +453,14 @@ func (s *ContestService) GetProblemIdByContestIndex(ctx context.Context, id int,
 	return foundationdao.GetContestProblemDao().GetProblemId(ctx, id, problemIndex)
 }
 
+// GetProblemLimit 比赛中覆盖的评测限制，未设置时返回nil
+func (s *ContestService) GetProblemLimit(ctx context.Context, id int, problemId int) (
+	*foundationview.ContestProblemLimit,
+	error,
+) {
+	return foundationdao.GetContestProblemDao().GetProblemLimit(ctx, id, problemId)
+}
+
 func (s *ContestService) GetProblemIdByContestIndexKey(ctx context.Context, id int, problemKey string) (int, error) {
 	constProblemIndex := foundationcontest.GetContestProblemIndex(problemKey)
 	return foundationdao.GetContestProblemDao().GetProblemId(ctx, id, constProblemIndex)
@@ -411,60 +470,27 @@ func (s *ContestService) GetContestProblemIndexById(ctx context.Context, id int,
 	return foundationdao.GetContestProblemDao().GetProblemIndex(ctx, id, problemId)
 }
 
-func (s *ContestService) GetContestRanks(ctx context.Context, id int, nowTime time.Time) (
+// GetContestRanks 从榜单缓存中获取比赛榜单
+// 锁榜期间返回锁榜的榜单，showUnfrozen为true时始终返回真实榜单
+func (s *ContestService) GetContestRanks(ctx context.Context, id int, nowTime time.Time, showUnfrozen bool) (
 	contest *foundationview.ContestRankDetail,
 	ranks []*foundationview.ContestRank,
 	isLocked bool,
 	err error,
 ) {
-	contest, err = foundationdao.GetContestDao().GetContestViewRank(ctx, id)
-	if err != nil {
+	snapshot, err := GetContestRankCacheService().GetSnapshot(ctx, id)
+	if err != nil || snapshot == nil {
 		return
 	}
-	contest.Members, err = foundationdao.GetContestMemberDao().GetUsersWithName(ctx, id)
-	if err != nil {
-		return
-	}
-	var contestProblems []*foundationview.ContestProblemRank
-	contestProblems, err = foundationdao.GetContestProblemDao().GetProblemsRank(ctx, id)
-	if err != nil {
-		return
-	}
-
-	var problemIndexes []int
-	problemMap := make(map[int]uint8)
-	for _, problem := range contestProblems {
-		problemMap[problem.ProblemId] = problem.Index
-		problemIndexes = append(problemIndexes, int(problem.Index))
-	}
-
+	contest = snapshot.contest
 	isEnd := nowTime.After(contest.EndTime)
-	hasLockDuration := contest.LockRankDuration != nil && *contest.LockRankDuration > 0
-	isLocked = hasLockDuration &&
+	isLocked = snapshot.lockTime != nil &&
 		(contest.AlwaysLock || !isEnd) &&
-		nowTime.After(contest.EndTime.Add(-*contest.LockRankDuration))
-
-	var lockTimePtr *time.Time
-	if isLocked {
-		lockTime := contest.EndTime.Add(-*contest.LockRankDuration)
-		lockTimePtr = &lockTime
-	} else {
-		lockTimePtr = nil
+		nowTime.After(*snapshot.lockTime)
+	if isLocked && !showUnfrozen {
+		return contest, snapshot.frozen, true, nil
 	}
-
-	contestRanks, err := foundationdao.GetJudgeJobDao().GetContestRanks(
-		ctx, id,
-		contest.StartTime,
-		lockTimePtr,
-		problemMap,
-	)
-	if err != nil {
-		return
-	}
-
-	contest.Problems = problemIndexes
-
-	return contest, contestRanks, isLocked, nil
+	return contest, snapshot.unfrozen, false, nil
 }
 
 func (s *ContestService) GetContestMember(
@@ -527,130 +553,128 @@ func (s *ContestService) InsertContest(
 	)
 }
 
-func (s *ContestService) DolosContest(ctx context.Context, id int) (*string, error) {
-
-	tempDir, err := os.MkdirTemp("", "didaoj-contest-data-*")
-	if err != nil {
-		return nil, metaerror.Wrap(err, "创建临时目录失败")
-	}
-	tempDir = filepath.ToSlash(tempDir)
-	defer func(path string) {
-		err := os.RemoveAll(path)
-		if err != nil {
-			metapanic.ProcessError(metaerror.Wrap(err, "<UNK>: "+path))
-		}
-	}(tempDir)
-
-	csvContent := "filename,label,created_at,full_name\n"
-
-	cacheNickname := make(map[int]string)
+func (s *ContestService) PostContestMemberName(
+	ctx context.Context, userId int, contestId int, name string,
+) error {
+	return foundationdao.GetContestMemberDao().PostContestMemberName(ctx, userId, contestId, name)
+}
 
-	err = foundationdao.GetJudgeJobDao().ForeachContestAcCodes(
-		ctx, id, func(judgeId int, code string, problemId string, createTime time.Time, authorId int) error {
-			// 保存到临时目录
-			fileName := strconv.Itoa(judgeId) + ".cpp"
-			filePath := path.Join(tempDir, fileName)
-			err := os.WriteFile(filePath, []byte(code), 0644)
-			if err != nil {
-				return metaerror.Wrap(err, "写入代码到临时文件失败")
-			}
-			authorNickname, ok := cacheNickname[authorId]
-			if !ok {
-				user, err := foundationdao.GetUserDao().GetUserAccountInfo(ctx, authorId)
-				if err != nil {
-					return metaerror.Wrap(err, "获取用户信息失败")
-				}
-				if user == nil {
-					return metaerror.New("用户不存在")
-				}
-				authorNickname = user.Nickname
-				cacheNickname[authorId] = authorNickname
-			}
-			// 添加到CSV内容
-			csvContent += fileName + "," +
-				problemId + "," +
-				createTime.Format(time.RFC3339) + "," +
-				authorNickname + "\n"
-			return nil
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
-	csvFilePath := path.Join(tempDir, "info.csv")
-	err = os.WriteFile(csvFilePath, []byte(csvContent), 0644)
-	if err != nil {
-		return nil, metaerror.Wrap(err, "写入CSV文件失败")
+// MirrorRemoteContest 镜像远程比赛，题目不存在时先爬取，比赛时长与原比赛一致
+// ghostLimit大于0时同时导入原比赛的最终榜单
+// 远程OJ不支持评测时提交会被拒绝，比赛只用于练习题目与对照原比赛榜单
+func (s *ContestService) MirrorRemoteContest(
+	ctx context.Context,
+	remoteType foundationenum.RemoteJudgeType,
+	remoteContestId string,
+	contest *foundationmodel.Contest,
+	ghostLimit int,
+) error {
+	contestAgent := foundationremote.GetRemoteContestAgent(remoteType)
+	problemAgent := foundationremote.GetRemoteAgent(remoteType)
+	if contestAgent == nil || problemAgent == nil {
+		return metaerror.New("remote contest not support: %s", remoteType)
 	}
-	// 将临时目录打包成ZIP文件
-	zipName := path.Base(tempDir) + ".zip"
-	err = metazip.PackagePath(tempDir, zipName)
+	remoteContest, err := contestAgent.GetContest(ctx, remoteContestId)
 	if err != nil {
-		return nil, metaerror.Wrap(err, "打包临时目录失败")
+		return err
 	}
-	zipFilePath := path.Join(tempDir, zipName)
-	zipFile, err := os.Open(zipFilePath)
-	if err != nil {
-		return nil, metaerror.Wrap(err, "打开ZIP文件失败")
+	if len(remoteContest.Problems) == 0 {
+		return metaerror.New("remote contest has no problem: %s", remoteContestId)
 	}
-	defer func(zipFile *os.File) {
-		err := zipFile.Close()
+	// 榜单在创建比赛前获取，避免比赛创建后导入失败
+	var standings []*foundationremote.RemoteContestStanding
+	if ghostLimit > 0 {
+		standings, err = contestAgent.GetContestStandings(ctx, remoteContestId, remoteContest, ghostLimit)
 		if err != nil {
-			metapanic.ProcessError(metaerror.Wrap(err, "关闭ZIP文件失败"))
+			return err
 		}
-	}(zipFile)
-	// 创建表单
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-	fileField, err := writer.CreateFormFile("dataset[zipfile]", filepath.Base(zipFilePath))
-	if err != nil {
-		return nil, metaerror.Wrap(err, "<UNK>")
 	}
-	_, err = io.Copy(fileField, zipFile)
-	if err != nil {
-		return nil, metaerror.Wrap(err, "复制ZIP文件内容到表单失败")
+
+	originIds := make([]string, 0, len(remoteContest.Problems))
+	for _, problem := range remoteContest.Problems {
+		originIds = append(originIds, problem.OriginId)
 	}
-	// 添加数据字段
-	err = writer.WriteField("dataset[name]", metapath.GetBaseName(zipName))
+	problemIdMap, err := foundationdao.GetProblemDao().GetProblemRemoteProblemIds(ctx, string(remoteType), originIds)
 	if err != nil {
-		return nil, metaerror.Wrap(err, "写入表单字段失败")
+		return err
 	}
-	err = writer.Close()
-	if err != nil {
-		return nil, metaerror.Wrap(err, "关闭表单失败")
+	var contestProblems []*foundationmodel.ContestProblem
+	for _, problem := range remoteContest.Problems {
+		problemId, ok := problemIdMap[problem.OriginId]
+		if !ok {
+			problemKey, err := problemAgent.PostCrawlProblem(ctx, problem.OriginId)
+			if err != nil {
+				return err
+			}
+			if problemKey == nil {
+				return metaerror.New("remote problem not found: %s", problem.OriginId)
+			}
+			problemId, err = foundationdao.GetProblemDao().GetProblemIdByKey(ctx, *problemKey)
+			if err != nil {
+				return err
+			}
+			if problemId <= 0 {
+				return metaerror.New("crawled problem not found: %s", *problemKey)
+			}
+		}
+		contestProblems = append(
+			contestProblems, foundationmodel.NewContestProblemBuilder().
+				ProblemId(problemId).
+				Index(uint8(len(contestProblems)+1)).
+				Build(),
+		)
 	}
-	req, err := http.NewRequest("POST", "https://dolos.ugent.be/api/reports", &buf)
-	if err != nil {
-		return nil, metaerror.Wrap(err, "创建HTTP请求失败")
+
+	if contest.Title == "" {
+		contest.Title = remoteContest.Title
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, metaerror.Wrap(err, "发送HTTP请求失败")
+	if titleRunes := []rune(contest.Title); len(titleRunes) > 75 {
+		contest.Title = string(titleRunes[:75])
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			metapanic.ProcessError(metaerror.Wrap(err, "关闭响应体失败"))
-		}
-	}(resp.Body)
-	body, err := io.ReadAll(resp.Body)
+	contest.EndTime = contest.StartTime.Add(remoteContest.Duration)
+	err = s.InsertContest(ctx, contest, contestProblems, nil, nil, nil, nil, nil, nil)
 	if err != nil {
-		return nil, metaerror.Wrap(err, "读取HTTP响应失败")
+		return err
 	}
-	type response struct {
-		HTMLUrl string `json:"html_url"`
+	if len(standings) == 0 {
+		return nil
 	}
-	var res response
-	err = json.Unmarshal(body, &res)
-	if err != nil {
-		return nil, metaerror.Wrap(err, "解析HTTP响应失败")
+
+	ghosts := make([]*foundationmodel.ContestGhost, 0, len(standings))
+	ghostProblems := make([][]*foundationmodel.ContestGhostProblem, 0, len(standings))
+	for _, standing := range standings {
+		name := standing.Name
+		if nameRunes := []rune(name); len(nameRunes) > 100 {
+			name = string(nameRunes[:100])
+		}
+		ghosts = append(
+			ghosts, foundationmodel.NewContestGhostBuilder().
+				Name(name).
+				Rank(standing.Rank).
+				Score(standing.Score).
+				Penalty(standing.Penalty).
+				Build(),
+		)
+		var problems []*foundationmodel.ContestGhostProblem
+		for i, problem := range standing.Problems {
+			if !problem.Accepted && problem.Attempt == 0 && problem.Score == 0 {
+				continue
+			}
+			problems = append(
+				problems, foundationmodel.NewContestGhostProblemBuilder().
+					ProblemIndex(i+1).
+					Accepted(problem.Accepted).
+					Attempt(problem.Attempt).
+					Time(problem.Time).
+					Score(problem.Score).
+					Build(),
+			)
+		}
+		ghostProblems = append(ghostProblems, problems)
 	}
-	return &res.HTMLUrl, nil
+	return foundationdao.GetContestGhostDao().ReplaceContestGhosts(ctx, contest.Id, ghosts, ghostProblems)
 }
 
-func (s *ContestService) PostContestMemberName(
-	ctx context.Context, userId int, contestId int, name string,
-) error {
-	return foundationdao.GetContestMemberDao().PostContestMemberName(ctx, userId, contestId, name)
+func (s *ContestService) GetContestGhosts(ctx context.Context, id int) ([]*foundationview.ContestGhost, error) {
+	return foundationdao.GetContestGhostDao().GetContestGhosts(ctx, id)
 }
@@ -0,0 +1,86 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	"time"
+	weberrorcode "web/error-code"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckVolunteerAuth 检查气球配送权限，裁判和比赛志愿者均可配送
+func (s *ContestService) CheckVolunteerAuth(ctx *gin.Context, id int) (int, bool, error) {
+	userId, hasAuth, err := s.CheckJuryAuth(ctx, id)
+	if err != nil {
+		return userId, false, err
+	}
+	if userId <= 0 {
+		return userId, false, nil
+	}
+	if !hasAuth {
+		hasAuth, err = foundationdao.GetContestDao().IsContestVolunteer(ctx, id, userId)
+		if err != nil {
+			return userId, false, err
+		}
+	}
+	return userId, hasAuth, nil
+}
+
+// GetContestBalloons 获取气球队列，按通过时间排序，气球任务在评测通过时生成
+func (s *ContestService) GetContestBalloons(
+	ctx context.Context,
+	id int,
+	status *foundationenum.ContestBalloonStatus,
+	location string,
+) ([]*foundationview.ContestBalloon, error) {
+	return foundationdao.GetContestBalloonDao().GetContestBalloons(ctx, id, status, location)
+}
+
+func (s *ContestService) ClaimContestBalloon(
+	ctx context.Context,
+	id int,
+	balloonId int,
+	userId int,
+	nowTime time.Time,
+) error {
+	ok, err := foundationdao.GetContestBalloonDao().ClaimContestBalloon(ctx, id, balloonId, userId, nowTime)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return metaerror.NewCode(weberrorcode.ContestBalloonStatusError)
+	}
+	return nil
+}
+
+func (s *ContestService) ReleaseContestBalloon(ctx context.Context, id int, balloonId int, userId int) error {
+	ok, err := foundationdao.GetContestBalloonDao().ReleaseContestBalloon(ctx, id, balloonId, userId)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return metaerror.NewCode(weberrorcode.ContestBalloonStatusError)
+	}
+	return nil
+}
+
+func (s *ContestService) DeliverContestBalloon(
+	ctx context.Context,
+	id int,
+	balloonId int,
+	userId int,
+	nowTime time.Time,
+) error {
+	ok, err := foundationdao.GetContestBalloonDao().DeliverContestBalloon(ctx, id, balloonId, userId, nowTime)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return metaerror.NewCode(weberrorcode.ContestBalloonStatusError)
+	}
+	return nil
+}
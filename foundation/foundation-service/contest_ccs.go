@@ -0,0 +1,486 @@
+package foundationservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	foundationcontest "foundation/foundation-contest"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	"meta/singleton"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CLICS规定的时间格式
+const ccsTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// ccsJudgementTypes 导出的评测结果类型，与JudgeStatus的对应见getCcsJudgementTypeId
+var ccsJudgementTypes = []*foundationview.CcsJudgementType{
+	{Id: "AC", Name: "correct", Penalty: false, Solved: true},
+	{Id: "WA", Name: "wrong answer", Penalty: true, Solved: false},
+	{Id: "PE", Name: "presentation error", Penalty: true, Solved: false},
+	{Id: "TLE", Name: "time limit exceeded", Penalty: true, Solved: false},
+	{Id: "MLE", Name: "memory limit exceeded", Penalty: true, Solved: false},
+	{Id: "OLE", Name: "output limit exceeded", Penalty: true, Solved: false},
+	{Id: "RTE", Name: "run-time error", Penalty: true, Solved: false},
+	{Id: "CE", Name: "compiler error", Penalty: false, Solved: false},
+}
+
+// getCcsJudgementTypeId 评测中或评测系统失败的提交没有对应的结果类型
+func getCcsJudgementTypeId(status foundationjudge.JudgeStatus) string {
+	switch status {
+	case foundationjudge.JudgeStatusAC:
+		return "AC"
+	case foundationjudge.JudgeStatusWA:
+		return "WA"
+	case foundationjudge.JudgeStatusPE:
+		return "PE"
+	case foundationjudge.JudgeStatusTLE:
+		return "TLE"
+	case foundationjudge.JudgeStatusMLE:
+		return "MLE"
+	case foundationjudge.JudgeStatusOLE:
+		return "OLE"
+	case foundationjudge.JudgeStatusRE:
+		return "RTE"
+	case foundationjudge.JudgeStatusCE, foundationjudge.JudgeStatusCLE:
+		return "CE"
+	default:
+		return ""
+	}
+}
+
+func getCcsLanguageId(language foundationjudge.JudgeLanguage) string {
+	switch language {
+	case foundationjudge.JudgeLanguageC:
+		return "c"
+	case foundationjudge.JudgeLanguageCpp:
+		return "cpp"
+	case foundationjudge.JudgeLanguageJava:
+		return "java"
+	case foundationjudge.JudgeLanguagePython:
+		return "python3"
+	case foundationjudge.JudgeLanguagePascal:
+		return "pascal"
+	case foundationjudge.JudgeLanguageGolang:
+		return "go"
+	case foundationjudge.JudgeLanguageLua:
+		return "lua"
+	case foundationjudge.JudgeLanguageTypeScript:
+		return "typescript"
+	case foundationjudge.JudgeLanguageRust:
+		return "rust"
+	default:
+		return "unknown"
+	}
+}
+
+func formatCcsTime(t time.Time) string {
+	return t.Format(ccsTimeLayout)
+}
+
+// formatCcsRelTime 将时长格式化为h:mm:ss.uuu
+func formatCcsRelTime(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	milliseconds := d.Milliseconds()
+	return fmt.Sprintf(
+		"%s%d:%02d:%02d.%03d",
+		sign,
+		milliseconds/3600000,
+		milliseconds/60000%60,
+		milliseconds/1000%60,
+		milliseconds%1000,
+	)
+}
+
+const (
+	// 共享的CCS数据在该间隔内直接复用，不再检查变化
+	contestCcsCacheInterval = 5 * time.Second
+	// 榜单没有变化时也定期重新查询，覆盖榜单缓存之外的数据变化
+	contestCcsCacheMaxAge = time.Minute
+	// 缓存多久未被访问后释放
+	contestCcsCacheIdle = 10 * time.Minute
+)
+
+type contestCcsCacheKey struct {
+	id      int
+	isAdmin bool
+}
+
+// contestCcsCacheItem 同一比赛、同一可见范围的event-feed连接共用的CCS数据
+type contestCcsCacheItem struct {
+	mutex      sync.Mutex
+	ccs        *foundationview.ContestCcs
+	snapshot   *contestRankSnapshot // 查询时的榜单快照，快照未变时比赛数据没有变化
+	isEnd      bool                 // 查询时比赛是否已结束，结束后可见的评测结果会变化
+	loadTime   time.Time
+	checkTime  time.Time
+	accessTime atomic.Int64
+}
+
+type ContestCcsService struct {
+	cacheMap    sync.Map // contestCcsCacheKey -> *contestCcsCacheItem
+	lastCleanup atomic.Int64
+}
+
+var singletonContestCcsService = singleton.Singleton[ContestCcsService]{}
+
+func GetContestCcsService() *ContestCcsService {
+	return singletonContestCcsService.GetInstance(
+		func() *ContestCcsService {
+			return &ContestCcsService{}
+		},
+	)
+}
+
+// cleanupCache 释放长时间未访问的共享数据
+func (s *ContestCcsService) cleanupCache(nowTime time.Time) {
+	lastCleanup := s.lastCleanup.Load()
+	if nowTime.Unix()-lastCleanup < int64(contestCcsCacheIdle.Seconds()) ||
+		!s.lastCleanup.CompareAndSwap(lastCleanup, nowTime.Unix()) {
+		return
+	}
+	idleBefore := nowTime.Add(-contestCcsCacheIdle).Unix()
+	s.cacheMap.Range(
+		func(key, value any) bool {
+			if value.(*contestCcsCacheItem).accessTime.Load() < idleBefore {
+				s.cacheMap.Delete(key)
+			}
+			return true
+		},
+	)
+}
+
+// GetSharedContestCcs 供event-feed使用的共享CCS数据，多个连接在同一间隔内只查询一次
+// 榜单缓存在持续更新时，根据榜单快照判断数据是否变化，没有变化则复用上次的结果
+func (s *ContestCcsService) GetSharedContestCcs(
+	ctx context.Context,
+	id int,
+	nowTime time.Time,
+	isAdmin bool,
+) (*foundationview.ContestCcs, error) {
+	s.cleanupCache(nowTime)
+	val, _ := s.cacheMap.LoadOrStore(contestCcsCacheKey{id: id, isAdmin: isAdmin}, &contestCcsCacheItem{})
+	item := val.(*contestCcsCacheItem)
+	item.accessTime.Store(nowTime.Unix())
+
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	if item.ccs != nil && nowTime.Sub(item.checkTime) < contestCcsCacheInterval {
+		return item.ccs, nil
+	}
+	// 共享的查询不随单个连接断开而取消
+	ctx = context.WithoutCancel(ctx)
+	var snapshot *contestRankSnapshot
+	rankCacheService := GetContestRankCacheService()
+	if rankCacheService.isChangeProcessing() {
+		var err error
+		snapshot, err = rankCacheService.GetSnapshot(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil && snapshot == item.snapshot &&
+			nowTime.Sub(item.loadTime) < contestCcsCacheMaxAge &&
+			nowTime.After(snapshot.contest.EndTime) == item.isEnd {
+			item.checkTime = nowTime
+			return item.ccs, nil
+		}
+	}
+	ccs, err := s.GetContestCcs(ctx, id, nowTime, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	item.ccs = ccs
+	item.snapshot = snapshot
+	item.isEnd = snapshot != nil && nowTime.After(snapshot.contest.EndTime)
+	item.loadTime = nowTime
+	item.checkTime = nowTime
+	return ccs, nil
+}
+
+// GetContestCcs 导出比赛的CCS对象
+// 非管理员在锁榜期间看不到锁榜时间之后提交的评测结果，与榜单的可见性保持一致
+func (s *ContestCcsService) GetContestCcs(
+	ctx context.Context,
+	id int,
+	nowTime time.Time,
+	isAdmin bool,
+) (*foundationview.ContestCcs, error) {
+	contest, err := foundationdao.GetContestDao().GetContestViewRank(ctx, id)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to get contest, id:%d", id)
+	}
+	members, err := foundationdao.GetContestMemberDao().GetUsersWithName(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	problems, err := foundationdao.GetContestProblemDao().GetProblemsCcs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := foundationdao.GetJudgeJobDao().GetContestCcsSubmissions(
+		ctx,
+		id,
+		contest.StartTime,
+		contest.EndTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var lockTime *time.Time
+	if contest.LockRankDuration != nil && *contest.LockRankDuration > 0 {
+		t := contest.EndTime.Add(-*contest.LockRankDuration)
+		lockTime = &t
+	}
+	isEnd := nowTime.After(contest.EndTime)
+	hideAfterLock := !isAdmin && lockTime != nil && (contest.AlwaysLock || !isEnd)
+
+	ccs := &foundationview.ContestCcs{
+		Contest: &foundationview.CcsContest{
+			Id:             strconv.Itoa(contest.Id),
+			Name:           contest.Title,
+			FormalName:     contest.Title,
+			StartTime:      formatCcsTime(contest.StartTime),
+			Duration:       formatCcsRelTime(contest.EndTime.Sub(contest.StartTime)),
+			ScoreboardType: "pass-fail",
+			PenaltyTime:    contest.PenaltyMinutes,
+		},
+		JudgementTypes: ccsJudgementTypes,
+		Finished:       isEnd,
+	}
+	if contest.Type != foundationenum.ContestTypeAcm {
+		ccs.Contest.ScoreboardType = "score"
+	}
+	if lockTime != nil {
+		freezeDuration := formatCcsRelTime(*contest.LockRankDuration)
+		ccs.Contest.ScoreboardFreezeDuration = &freezeDuration
+	}
+
+	problemIds := make(map[int]string, len(problems))
+	for i, problem := range problems {
+		problemId := strconv.Itoa(problem.ProblemId)
+		problemIds[problem.ProblemId] = problemId
+		ccs.Problems = append(
+			ccs.Problems, &foundationview.CcsProblem{
+				Id:        problemId,
+				Label:     foundationcontest.GetContestProblemIndexStr(int(problem.Index)),
+				Name:      problem.Title,
+				Ordinal:   i,
+				TimeLimit: float64(problem.TimeLimit) / 1000,
+			},
+		)
+	}
+
+	// 队伍为比赛的队伍，以及不在队伍中的比赛成员与有提交的用户
+	teams, err := foundationdao.GetContestTeamDao().GetContestTeams(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ignoreSet := make(map[int]bool, len(contest.MembersIgnore))
+	for _, userId := range contest.MembersIgnore {
+		ignoreSet[userId] = true
+	}
+	teamIds := make(map[int]string)
+	for _, team := range teams {
+		teamId := "team-" + strconv.Itoa(team.Id)
+		ccsTeam := &foundationview.CcsTeam{
+			Id:          teamId,
+			Name:        team.Name,
+			DisplayName: team.Name,
+		}
+		for _, member := range team.Members {
+			teamIds[member.UserId] = teamId
+			ccsTeam.Hidden = ccsTeam.Hidden || ignoreSet[member.UserId]
+		}
+		ccs.Teams = append(ccs.Teams, ccsTeam)
+	}
+	contestNames := make(map[int]string, len(members))
+	var userIds []int
+	userIdSet := make(map[int]bool)
+	for _, member := range members {
+		contestNames[member.Id] = member.ContestName
+		if _, ok := teamIds[member.Id]; !ok && !userIdSet[member.Id] {
+			userIdSet[member.Id] = true
+			userIds = append(userIds, member.Id)
+		}
+	}
+	for _, submission := range submissions {
+		if _, ok := teamIds[submission.Inserter]; !ok && !userIdSet[submission.Inserter] {
+			userIdSet[submission.Inserter] = true
+			userIds = append(userIds, submission.Inserter)
+		}
+	}
+	users, err := foundationdao.GetJudgeJobDao().GetContestRankUsersByIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(
+		users, func(i, j int) bool {
+			return users[i].Inserter < users[j].Inserter
+		},
+	)
+	for _, user := range users {
+		name := strconv.Itoa(user.Inserter)
+		if user.InserterUsername != nil {
+			name = *user.InserterUsername
+		}
+		displayName := name
+		if contestName := contestNames[user.Inserter]; contestName != "" {
+			displayName = contestName
+		} else if user.InserterNickname != nil && *user.InserterNickname != "" {
+			displayName = *user.InserterNickname
+		}
+		teamIds[user.Inserter] = strconv.Itoa(user.Inserter)
+		ccs.Teams = append(
+			ccs.Teams, &foundationview.CcsTeam{
+				Id:          strconv.Itoa(user.Inserter),
+				Name:        name,
+				DisplayName: displayName,
+				Hidden:      ignoreSet[user.Inserter],
+			},
+		)
+	}
+
+	for _, submission := range submissions {
+		problemId, ok := problemIds[submission.ProblemId]
+		if !ok {
+			continue
+		}
+		submissionId := strconv.Itoa(submission.Id)
+		teamId, ok := teamIds[submission.Inserter]
+		if !ok {
+			teamId = strconv.Itoa(submission.Inserter)
+		}
+		ccs.Submissions = append(
+			ccs.Submissions, &foundationview.CcsSubmission{
+				Id:          submissionId,
+				LanguageId:  getCcsLanguageId(submission.Language),
+				ProblemId:   problemId,
+				TeamId:      teamId,
+				Time:        formatCcsTime(submission.InsertTime),
+				ContestTime: formatCcsRelTime(submission.InsertTime.Sub(contest.StartTime)),
+			},
+		)
+		judgement := &foundationview.CcsJudgement{
+			Id:               submissionId,
+			SubmissionId:     submissionId,
+			StartTime:        formatCcsTime(submission.InsertTime),
+			StartContestTime: formatCcsRelTime(submission.InsertTime.Sub(contest.StartTime)),
+		}
+		ccs.Judgements = append(ccs.Judgements, judgement)
+		if hideAfterLock && !submission.InsertTime.Before(*lockTime) {
+			continue
+		}
+		judgementTypeId := getCcsJudgementTypeId(submission.Status)
+		if judgementTypeId == "" {
+			if foundationjudge.IsJudgeStatusRunning(submission.Status) {
+				ccs.Finished = false
+			}
+			continue
+		}
+		endTime := submission.InsertTime
+		if submission.JudgeTime != nil && submission.JudgeTime.After(endTime) {
+			endTime = *submission.JudgeTime
+		}
+		endTimeStr := formatCcsTime(endTime)
+		endContestTime := formatCcsRelTime(endTime.Sub(contest.StartTime))
+		maxRunTime := float64(submission.Time) / 1000
+		judgement.JudgementTypeId = &judgementTypeId
+		judgement.EndTime = &endTimeStr
+		judgement.EndContestTime = &endContestTime
+		judgement.MaxRunTime = &maxRunTime
+	}
+	return ccs, nil
+}
+
+// ContestCcsEventFeed 生成event-feed，每次只返回新增或有变化的对象
+type ContestCcsEventFeed struct {
+	sent map[string][]byte
+}
+
+func NewContestCcsEventFeed() *ContestCcsEventFeed {
+	return &ContestCcsEventFeed{
+		sent: make(map[string][]byte),
+	}
+}
+
+// Next 按contest、judgement-types、problems、teams、按提交顺序的submissions与judgements的顺序返回事件
+// 已经发送过且内容未变的对象不再重复返回，不再存在的对象以data为null的事件表示删除
+func (f *ContestCcsEventFeed) Next(ccs *foundationview.ContestCcs) ([]*foundationview.CcsEvent, error) {
+	var events []*foundationview.CcsEvent
+	current := make(map[string]bool)
+	add := func(eventType string, id *string, data any) error {
+		key := eventType
+		if id != nil {
+			key += "/" + *id
+		}
+		current[key] = true
+		content, err := json.Marshal(data)
+		if err != nil {
+			return metaerror.Wrap(err, "failed to marshal ccs event, key:%s", key)
+		}
+		if last, ok := f.sent[key]; ok && bytes.Equal(last, content) {
+			return nil
+		}
+		f.sent[key] = content
+		events = append(events, &foundationview.CcsEvent{Type: eventType, Id: id, Data: data})
+		return nil
+	}
+
+	if err := add("contest", nil, ccs.Contest); err != nil {
+		return nil, err
+	}
+	for _, judgementType := range ccs.JudgementTypes {
+		if err := add("judgement-types", &judgementType.Id, judgementType); err != nil {
+			return nil, err
+		}
+	}
+	for _, problem := range ccs.Problems {
+		if err := add("problems", &problem.Id, problem); err != nil {
+			return nil, err
+		}
+	}
+	for _, team := range ccs.Teams {
+		if err := add("teams", &team.Id, team); err != nil {
+			return nil, err
+		}
+	}
+	judgements := make(map[string]*foundationview.CcsJudgement, len(ccs.Judgements))
+	for _, judgement := range ccs.Judgements {
+		judgements[judgement.SubmissionId] = judgement
+	}
+	for _, submission := range ccs.Submissions {
+		if err := add("submissions", &submission.Id, submission); err != nil {
+			return nil, err
+		}
+		if judgement, ok := judgements[submission.Id]; ok {
+			if err := add("judgements", &judgement.Id, judgement); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for key := range f.sent {
+		if current[key] {
+			continue
+		}
+		delete(f.sent, key)
+		eventType, id, _ := strings.Cut(key, "/")
+		events = append(events, &foundationview.CcsEvent{Type: eventType, Id: &id})
+	}
+	return events, nil
+}
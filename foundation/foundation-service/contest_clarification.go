@@ -0,0 +1,203 @@
+package foundationservice
+
+import (
+	"context"
+	foundationerrorcode "foundation/error-code"
+	foundationauth "foundation/foundation-auth"
+	foundationdao "foundation/foundation-dao"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	"time"
+	weberrorcode "web/error-code"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckJuryAuth 检查答疑的裁判权限，比赛创建者和比赛管理员均为裁判
+func (s *ContestService) CheckJuryAuth(ctx *gin.Context, id int) (int, bool, error) {
+	userId, hasAuth, err := GetUserService().CheckUserAuth(ctx, foundationauth.AuthTypeManageContest)
+	if err != nil {
+		return userId, false, err
+	}
+	if userId <= 0 {
+		return userId, false, nil
+	}
+	if !hasAuth {
+		hasAuth, err = foundationdao.GetContestDao().CheckContestEditAuth(ctx, id, userId)
+		if err != nil {
+			return userId, false, err
+		}
+	}
+	return userId, hasAuth, nil
+}
+
+// getClarificationProblemId 将题目索引转换为题目Id，索引为0时为比赛的通用问题
+func getClarificationProblemId(ctx context.Context, id int, problemIndex int) (*int, error) {
+	if problemIndex <= 0 {
+		return nil, nil
+	}
+	problemId, err := foundationdao.GetContestProblemDao().GetProblemId(ctx, id, problemIndex)
+	if err != nil {
+		return nil, err
+	}
+	if problemId <= 0 {
+		return nil, metaerror.NewCode(foundationerrorcode.ParamError)
+	}
+	return &problemId, nil
+}
+
+// GetContestClarifications 裁判可以查看全部答疑，选手只能查看公开的和自己提出的
+func (s *ContestService) GetContestClarifications(
+	ctx context.Context,
+	id int,
+	userId int,
+	isJury bool,
+) ([]*foundationview.ContestClarification, error) {
+	clarifications, err := foundationdao.GetContestClarificationDao().GetContestClarifications(ctx, id, userId, isJury)
+	if err != nil {
+		return nil, err
+	}
+	if !isJury {
+		for _, clarification := range clarifications {
+			clarification.Answerer = nil
+		}
+	}
+	return clarifications, nil
+}
+
+// AskContestClarification 选手在比赛结束前提问，问题默认仅自己和裁判可见
+func (s *ContestService) AskContestClarification(
+	ctx context.Context,
+	id int,
+	userId int,
+	problemIndex int,
+	question string,
+	nowTime time.Time,
+) (*foundationmodel.ContestClarification, error) {
+	_, endTime, err := foundationdao.GetContestDao().GetContestTime(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endTime == nil || !nowTime.Before(*endTime) {
+		return nil, metaerror.NewCode(weberrorcode.ContestClarificationClosed)
+	}
+	problemId, err := getClarificationProblemId(ctx, id, problemIndex)
+	if err != nil {
+		return nil, err
+	}
+	clarification := foundationmodel.NewContestClarificationBuilder().
+		ContestId(id).
+		ProblemId(problemId).
+		Question(&question).
+		Inserter(userId).
+		InsertTime(nowTime).
+		Build()
+	if err := foundationdao.GetContestClarificationDao().InsertContestClarification(ctx, clarification); err != nil {
+		return nil, err
+	}
+	return clarification, nil
+}
+
+// AnswerContestClarification 裁判回复问题，public为true时广播给所有人
+func (s *ContestService) AnswerContestClarification(
+	ctx context.Context,
+	id int,
+	clarificationId int,
+	answer string,
+	public bool,
+	userId int,
+	nowTime time.Time,
+) error {
+	clarificationDao := foundationdao.GetContestClarificationDao()
+	clarification, err := clarificationDao.GetContestClarification(ctx, id, clarificationId)
+	if err != nil {
+		return err
+	}
+	if clarification == nil {
+		return metaerror.NewCode(weberrorcode.ContestClarificationNotFound)
+	}
+	return clarificationDao.AnswerContestClarification(ctx, id, clarificationId, answer, public, userId, nowTime)
+}
+
+// BroadcastContestClarification 裁判直接向所有人发布公告
+func (s *ContestService) BroadcastContestClarification(
+	ctx context.Context,
+	id int,
+	problemIndex int,
+	content string,
+	userId int,
+	nowTime time.Time,
+) (*foundationmodel.ContestClarification, error) {
+	problemId, err := getClarificationProblemId(ctx, id, problemIndex)
+	if err != nil {
+		return nil, err
+	}
+	clarification := foundationmodel.NewContestClarificationBuilder().
+		ContestId(id).
+		ProblemId(problemId).
+		Answer(&content).
+		Public(true).
+		Inserter(userId).
+		InsertTime(nowTime).
+		Answerer(&userId).
+		AnswerTime(&nowTime).
+		Build()
+	if err := foundationdao.GetContestClarificationDao().InsertContestClarification(ctx, clarification); err != nil {
+		return nil, err
+	}
+	return clarification, nil
+}
+
+func (s *ContestService) DeleteContestClarification(ctx context.Context, id int, clarificationId int) error {
+	return foundationdao.GetContestClarificationDao().DeleteContestClarification(ctx, id, clarificationId)
+}
+
+// GetContestClarificationUnread 选手为未读的回复数量，裁判为待回复的问题数量
+func (s *ContestService) GetContestClarificationUnread(
+	ctx context.Context,
+	id int,
+	userId int,
+	isJury bool,
+) (int, error) {
+	if isJury {
+		return foundationdao.GetContestClarificationDao().GetUnansweredCount(ctx, id)
+	}
+	if userId <= 0 {
+		return 0, nil
+	}
+	return foundationdao.GetContestClarificationDao().GetUnreadCount(ctx, id, userId)
+}
+
+func (s *ContestService) ReadContestClarifications(ctx context.Context, id int, userId int, nowTime time.Time) error {
+	return foundationdao.GetContestClarificationDao().UpdateReadTime(ctx, id, userId, nowTime)
+}
+
+func (s *ContestService) GetClarificationTemplates(
+	ctx context.Context,
+	userId int,
+) ([]*foundationmodel.ContestClarificationTemplate, error) {
+	return foundationdao.GetContestClarificationDao().GetTemplates(ctx, userId)
+}
+
+// SaveClarificationTemplate Id为0时创建模板，否则修改自己的模板
+func (s *ContestService) SaveClarificationTemplate(
+	ctx context.Context,
+	template *foundationmodel.ContestClarificationTemplate,
+) error {
+	if template.Id <= 0 {
+		return foundationdao.GetContestClarificationDao().InsertTemplate(ctx, template)
+	}
+	ok, err := foundationdao.GetContestClarificationDao().UpdateTemplate(ctx, template)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return metaerror.NewCode(foundationerrorcode.NotFound)
+	}
+	return nil
+}
+
+func (s *ContestService) DeleteClarificationTemplate(ctx context.Context, id int, userId int) error {
+	return foundationdao.GetContestClarificationDao().DeleteTemplate(ctx, id, userId)
+}
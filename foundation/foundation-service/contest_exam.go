@@ -0,0 +1,114 @@
+package foundationservice
+
+import (
+	"context"
+	foundationauth "foundation/foundation-auth"
+	foundationdao "foundation/foundation-dao"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metatime "meta/meta-time"
+	"net"
+	"strings"
+	weberrorcode "web/error-code"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseContestIpWhitelist 解析IP白名单，每行一个CIDR，单个IP视为只包含自身的网段
+func parseContestIpWhitelist(whitelist string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, line := range strings.FieldsFunc(
+		whitelist, func(r rune) bool {
+			return r == '\n' || r == ',' || r == ' ' || r == '\r'
+		},
+	) {
+		if !strings.Contains(line, "/") {
+			ip := net.ParseIP(line)
+			if ip == nil {
+				return nil, metaerror.New("invalid ip: %s", line)
+			}
+			if ip.To4() != nil {
+				line += "/32"
+			} else {
+				line += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(line)
+		if err != nil {
+			return nil, metaerror.Wrap(err, "invalid cidr: %s", line)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// checkContestExamAccess 考试模式下检查访问来源与登录会话，比赛结束后不再限制，裁判不受限制
+func (s *ContestService) checkContestExamAccess(ctx *gin.Context, id int, userId int) (bool, error) {
+	setting, err := foundationdao.GetContestDao().GetContestExamSetting(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if setting == nil {
+		return false, nil
+	}
+	hasWhitelist := setting.IpWhitelist != nil && strings.TrimSpace(*setting.IpWhitelist) != ""
+	if !setting.Exam && !hasWhitelist {
+		return true, nil
+	}
+	if !metatime.GetTimeNow().Before(setting.EndTime) {
+		return true, nil
+	}
+	if userId > 0 {
+		isJury, err := foundationdao.GetContestDao().CheckContestEditAuth(ctx, id, userId)
+		if err != nil {
+			return false, err
+		}
+		if isJury {
+			return true, nil
+		}
+	}
+	if hasWhitelist {
+		networks, err := parseContestIpWhitelist(*setting.IpWhitelist)
+		if err != nil {
+			return false, err
+		}
+		ip := net.ParseIP(ctx.ClientIP())
+		allowed := false
+		for _, network := range networks {
+			if ip != nil && network.Contains(ip) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false, metaerror.NewCode(weberrorcode.ContestExamIpDenied)
+		}
+	}
+	if setting.Exam {
+		if userId <= 0 {
+			return false, nil
+		}
+		// 新的登录会使之前签发的令牌失效
+		session := foundationauth.GetSessionFromContext(ctx)
+		latestSession, err := foundationdao.GetUserDao().GetLatestLoginSession(ctx, userId)
+		if err != nil {
+			return false, err
+		}
+		if session == "" || session != latestSession {
+			return false, metaerror.NewCode(weberrorcode.ContestExamSessionExpired)
+		}
+	}
+	return true, nil
+}
+
+// GetContestLoginReports 获取比赛期间从多个IP登录的成员，供监考人员复查
+func (s *ContestService) GetContestLoginReports(ctx context.Context, id int) ([]*foundationview.ContestLoginReport, error) {
+	setting, err := foundationdao.GetContestDao().GetContestExamSetting(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		return nil, nil
+	}
+	return foundationdao.GetContestDao().GetContestLoginReports(ctx, id, setting.StartTime, setting.EndTime)
+}
@@ -0,0 +1,260 @@
+package foundationservice
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	foundationerrorcode "foundation/error-code"
+	foundationcontest "foundation/foundation-contest"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationexport "foundation/foundation-export"
+	foundationjudge "foundation/foundation-judge"
+	foundationview "foundation/foundation-view"
+	"io"
+	metaerror "meta/meta-error"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getContestExportStatus 导出时使用的评测结果名称
+func getContestExportStatus(status foundationjudge.JudgeStatus) string {
+	if status == foundationjudge.JudgeStatusJudgeFail || status == foundationjudge.JudgeStatusSubmitFail {
+		return "JF"
+	}
+	if foundationjudge.IsJudgeStatusRunning(status) {
+		return "Pending"
+	}
+	if typeId := getCcsJudgementTypeId(status); typeId != "" {
+		return typeId
+	}
+	return "Unknown"
+}
+
+// getContestExportFileName 压缩包中的路径只保留安全的字符
+func getContestExportFileName(name string) string {
+	name = strings.Map(
+		func(r rune) rune {
+			if r < 32 || strings.ContainsRune(`/\:*?"<>|`, r) {
+				return '_'
+			}
+			return r
+		}, strings.TrimSpace(name),
+	)
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+// ExportContestStandings 按比赛规则导出最终榜单，锁榜时同样导出真实榜单
+func (s *ContestService) ExportContestStandings(
+	ctx context.Context,
+	id int,
+	nowTime time.Time,
+	format string,
+	w io.Writer,
+) error {
+	contest, ranks, _, err := s.GetContestRanks(ctx, id, nowTime, true)
+	if err != nil {
+		return err
+	}
+	if contest == nil {
+		return metaerror.NewCode(foundationerrorcode.NotFound)
+	}
+	problems, err := foundationdao.GetContestProblemDao().GetProblemsCcs(ctx, id)
+	if err != nil {
+		return err
+	}
+	members, err := foundationdao.GetContestMemberDao().GetUsersWithName(ctx, id)
+	if err != nil {
+		return err
+	}
+	contestNames := make(map[int]string, len(members))
+	for _, member := range members {
+		contestNames[member.Id] = member.ContestName
+	}
+	isAcm := contest.Type == foundationenum.ContestTypeAcm
+	showScore := !isAcm || contest.ScoreType != foundationenum.ContestScoreTypeNone
+
+	table, err := foundationexport.NewTableWriter(format, w, contest.Title, time.Local)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to create standings table, id:%d", id)
+	}
+	header := []interface{}{"Rank", "Username", "Nickname", "Name", "Team", "Solved"}
+	if isAcm {
+		header = append(header, "Penalty")
+	}
+	if showScore {
+		header = append(header, "Score")
+	}
+	for _, problem := range problems {
+		header = append(header, foundationcontest.GetContestProblemIndexStr(int(problem.Index)))
+	}
+	if err := table.WriteRow(header...); err != nil {
+		return metaerror.Wrap(err, "failed to write standings, id:%d", id)
+	}
+	for _, rank := range ranks {
+		var rankCell interface{} = rank.Rank
+		if rank.Ignore {
+			rankCell = "*"
+		}
+		var teamName interface{}
+		var username, nickname, contestName interface{}
+		if rank.TeamId > 0 {
+			teamName = rank.TeamName
+			names := make([]string, 0, len(rank.TeamMembers))
+			realNames := make([]string, 0, len(rank.TeamMembers))
+			for _, member := range rank.TeamMembers {
+				names = append(names, member.Username)
+				if name := contestNames[member.UserId]; name != "" {
+					realNames = append(realNames, name)
+				}
+			}
+			username = strings.Join(names, ", ")
+			contestName = strings.Join(realNames, ", ")
+		} else {
+			username = rank.InserterUsername
+			nickname = rank.InserterNickname
+			contestName = contestNames[rank.Inserter]
+		}
+		row := []interface{}{rankCell, username, nickname, contestName, teamName, rank.Solved}
+		if isAcm {
+			// 罚时按分钟导出，与常见的榜单一致
+			row = append(row, rank.Penalty/60)
+		}
+		if showScore {
+			row = append(row, rank.Score)
+		}
+		startTime := contest.StartTime
+		if rank.StartTime != nil {
+			startTime = *rank.StartTime
+		}
+		results := make(map[uint8]*foundationview.ContestRankProblem, len(rank.Problems))
+		for _, result := range rank.Problems {
+			results[result.Index] = result
+		}
+		for _, problem := range problems {
+			result, ok := results[problem.Index]
+			if !ok {
+				row = append(row, nil)
+				continue
+			}
+			if !isAcm {
+				row = append(row, result.Score)
+				continue
+			}
+			// ACM格式：通过为+错误次数(通过分钟)，未通过为-错误次数，评测中的次数以?表示
+			var cell string
+			if result.Ac != nil {
+				cell = "+"
+				if result.Attempt > 0 {
+					cell += strconv.Itoa(result.Attempt)
+				}
+				cell += fmt.Sprintf(" (%d)", int(result.Ac.Sub(startTime).Minutes()))
+			} else if result.Attempt > 0 {
+				cell = "-" + strconv.Itoa(result.Attempt)
+			}
+			if result.Lock > 0 {
+				cell = strings.TrimSpace(cell + " ?" + strconv.Itoa(result.Lock))
+			}
+			row = append(row, foundationexport.Plain(cell))
+		}
+		if err := table.WriteRow(row...); err != nil {
+			return metaerror.Wrap(err, "failed to write standings, id:%d", id)
+		}
+	}
+	if err := table.Close(); err != nil {
+		return metaerror.Wrap(err, "failed to write standings, id:%d", id)
+	}
+	return nil
+}
+
+// ExportContestSubmissions 按提交顺序导出比赛的全部提交记录，包括虚拟参赛的提交
+func (s *ContestService) ExportContestSubmissions(ctx context.Context, id int, format string, w io.Writer) error {
+	contest, err := foundationdao.GetContestDao().GetContestViewRank(ctx, id)
+	if err != nil {
+		return err
+	}
+	if contest == nil {
+		return metaerror.NewCode(foundationerrorcode.NotFound)
+	}
+	table, err := foundationexport.NewTableWriter(format, w, contest.Title, time.Local)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to create submissions table, id:%d", id)
+	}
+	err = table.WriteRow(
+		"Id", "Username", "Nickname", "Name", "Team", "Problem", "Language", "Status",
+		"Score", "Time(ms)", "Memory(KB)", "Code Length", "Submit Time", "Virtual",
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to write submissions, id:%d", id)
+	}
+	err = foundationdao.GetJudgeJobDao().ForeachContestExportSubmissions(
+		ctx, id, func(submission *foundationview.ContestExportSubmission) error {
+			var virtual interface{}
+			if submission.Virtual {
+				virtual = "Y"
+			}
+			return table.WriteRow(
+				submission.Id,
+				submission.Username,
+				submission.Nickname,
+				submission.ContestName,
+				submission.TeamName,
+				foundationcontest.GetContestProblemIndexStr(int(submission.ProblemIndex)),
+				foundationjudge.GetLanguageKey(submission.Language),
+				getContestExportStatus(submission.Status),
+				submission.Score,
+				submission.Time,
+				submission.Memory,
+				submission.CodeLength,
+				submission.InsertTime,
+				virtual,
+			)
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to write submissions, id:%d", id)
+	}
+	if err := table.Close(); err != nil {
+		return metaerror.Wrap(err, "failed to write submissions, id:%d", id)
+	}
+	return nil
+}
+
+// ExportContestCodes 导出每个用户每道题最后一次正式提交的代码
+// 压缩包内路径为“用户名/题目_评测结果_提交Id.扩展名”
+func (s *ContestService) ExportContestCodes(ctx context.Context, id int, w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
+	err := foundationdao.GetJudgeJobDao().ForeachContestExportCodes(
+		ctx, id, func(code *foundationview.ContestExportCode) error {
+			userName := strconv.Itoa(code.Inserter)
+			if code.Username != nil {
+				userName = *code.Username
+			}
+			fileName := fmt.Sprintf(
+				"%s/%s_%s_%d.%s",
+				getContestExportFileName(userName),
+				foundationcontest.GetContestProblemIndexStr(int(code.ProblemIndex)),
+				getContestExportStatus(code.Status),
+				code.Id,
+				foundationjudge.GetLanguageFileExtension(code.Language),
+			)
+			fileWriter, err := zipWriter.Create(fileName)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(fileWriter, code.Code)
+			return err
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to write contest codes, id:%d", id)
+	}
+	if err := zipWriter.Close(); err != nil {
+		return metaerror.Wrap(err, "failed to write contest codes, id:%d", id)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	"time"
+	weberrorcode "web/error-code"
+)
+
+// 每个用户同时等待评测的挑战数量上限，避免占满评测机
+const contestHackPendingLimit = 3
+
+// getContestHackSetting 获取开放挑战的比赛配置，只能在比赛时间内锁定题目与挑战
+func getContestHackSetting(ctx context.Context, id int, nowTime time.Time) (*foundationview.ContestHackSetting, error) {
+	setting, err := foundationdao.GetContestDao().GetContestHackSetting(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil || !setting.Hack {
+		return nil, metaerror.NewCode(weberrorcode.ContestHackDisabled)
+	}
+	if nowTime.Before(setting.StartTime) || !nowTime.Before(setting.EndTime) {
+		return nil, metaerror.NewCode(weberrorcode.ContestHackClosed)
+	}
+	return setting, nil
+}
+
+// getContestHackTarget 获取要查看或挑战的提交，需要已锁定该题且不能是自己的提交
+func getContestHackTarget(
+	ctx context.Context,
+	setting *foundationview.ContestHackSetting,
+	judgeId int,
+	userId int,
+) (*foundationview.ContestHackTarget, error) {
+	target, err := foundationdao.GetContestHackDao().GetContestHackTarget(
+		ctx,
+		setting.Id,
+		judgeId,
+		setting.StartTime,
+		setting.EndTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil || target.Inserter == userId {
+		return nil, metaerror.NewCode(weberrorcode.ContestHackTargetInvalid)
+	}
+	locked, err := foundationdao.GetContestHackDao().IsProblemLocked(ctx, setting.Id, target.ProblemId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, metaerror.NewCode(weberrorcode.ContestHackNotLocked)
+	}
+	return target, nil
+}
+
+// IsContestProblemLocked 锁定题目后不能再提交该题
+func (s *ContestService) IsContestProblemLocked(ctx context.Context, id int, problemId int, userId int) (bool, error) {
+	return foundationdao.GetContestHackDao().IsProblemLocked(ctx, id, problemId, userId)
+}
+
+func (s *ContestService) GetLockedProblemIds(ctx context.Context, id int, userId int) ([]int, error) {
+	return foundationdao.GetContestHackDao().GetLockedProblemIds(ctx, id, userId)
+}
+
+// LockContestProblem 锁定已通过的题目，锁定后不能再提交，但可以查看他人的代码并发起挑战
+func (s *ContestService) LockContestProblem(
+	ctx context.Context,
+	id int,
+	problemId int,
+	userId int,
+	nowTime time.Time,
+) error {
+	setting, err := getContestHackSetting(ctx, id, nowTime)
+	if err != nil {
+		return err
+	}
+	accepted, err := foundationdao.GetContestHackDao().HasContestProblemAccept(
+		ctx,
+		id,
+		problemId,
+		userId,
+		setting.StartTime,
+		setting.EndTime,
+	)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return metaerror.NewCode(weberrorcode.ContestProblemNotAccept)
+	}
+	lock := &foundationmodel.ContestProblemLock{
+		Id:         id,
+		ProblemId:  problemId,
+		UserId:     userId,
+		InsertTime: nowTime,
+	}
+	return foundationdao.GetContestHackDao().InsertContestProblemLock(ctx, lock)
+}
+
+// GetContestHackTargets 获取锁定题目后可以挑战的提交
+func (s *ContestService) GetContestHackTargets(
+	ctx context.Context,
+	id int,
+	problemId int,
+	userId int,
+	nowTime time.Time,
+) ([]*foundationview.ContestHackTarget, error) {
+	setting, err := getContestHackSetting(ctx, id, nowTime)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := foundationdao.GetContestHackDao().IsProblemLocked(ctx, id, problemId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, metaerror.NewCode(weberrorcode.ContestHackNotLocked)
+	}
+	return foundationdao.GetContestHackDao().GetContestHackTargets(
+		ctx,
+		id,
+		problemId,
+		userId,
+		setting.StartTime,
+		setting.EndTime,
+	)
+}
+
+// GetContestHackCode 查看要挑战的代码
+func (s *ContestService) GetContestHackCode(
+	ctx context.Context,
+	id int,
+	judgeId int,
+	userId int,
+	nowTime time.Time,
+) (*foundationview.ContestHackTarget, error) {
+	setting, err := getContestHackSetting(ctx, id, nowTime)
+	if err != nil {
+		return nil, err
+	}
+	return getContestHackTarget(ctx, setting, judgeId, userId)
+}
+
+// SubmitContestHack 提交挑战数据，由评测机校验输入并运行标准程序与被挑战的代码
+func (s *ContestService) SubmitContestHack(
+	ctx context.Context,
+	id int,
+	judgeId int,
+	userId int,
+	input string,
+	nowTime time.Time,
+) (*foundationmodel.ContestHack, error) {
+	setting, err := getContestHackSetting(ctx, id, nowTime)
+	if err != nil {
+		return nil, err
+	}
+	target, err := getContestHackTarget(ctx, setting, judgeId, userId)
+	if err != nil {
+		return nil, err
+	}
+	hack := foundationmodel.NewContestHackBuilder().
+		ContestId(id).
+		ProblemId(target.ProblemId).
+		JudgeId(target.JudgeId).
+		Hacker(userId).
+		Defendant(target.Inserter).
+		Input(input).
+		Status(foundationenum.ContestHackStatusPending).
+		InsertTime(nowTime).
+		Build()
+	duplicate, tooMany, err := foundationdao.GetContestHackDao().InsertContestHackWithLimit(
+		ctx,
+		hack,
+		contestHackPendingLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		return nil, metaerror.NewCode(weberrorcode.ContestHackDuplicate)
+	}
+	if tooMany {
+		return nil, metaerror.NewCode(weberrorcode.ContestHackTooMany)
+	}
+	return hack, nil
+}
+
+func (s *ContestService) GetContestHacks(
+	ctx context.Context,
+	id int,
+	problemId int,
+) ([]*foundationview.ContestHack, error) {
+	return foundationdao.GetContestHackDao().GetContestHacks(ctx, id, problemId)
+}
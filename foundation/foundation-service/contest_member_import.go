@@ -0,0 +1,232 @@
+package foundationservice
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	foundationdao "foundation/foundation-dao"
+	foundationmodel "foundation/foundation-model"
+	foundationrender "foundation/foundation-render"
+	foundationview "foundation/foundation-view"
+	"math/big"
+	metaerror "meta/meta-error"
+	"strings"
+	"time"
+	weberrorcode "web/error-code"
+)
+
+// 生成的密码不包含容易混淆的字符，方便现场抄写
+const contestMemberPasswordChars = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+const contestMemberPasswordLength = 8
+
+// ContestMemberImportInput 导入的一行成员信息，Username为空时自动生成账号
+type ContestMemberImportInput struct {
+	Username     string
+	Name         string
+	Organization string
+	Location     string
+}
+
+func generateContestMemberPassword() (string, error) {
+	password := make([]byte, contestMemberPasswordLength)
+	limit := big.NewInt(int64(len(contestMemberPasswordChars)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", metaerror.Wrap(err, "failed to generate password")
+		}
+		password[i] = contestMemberPasswordChars[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// ImportContestMembers 批量导入比赛成员
+// 指定的用户名已存在时复用该账号，不修改密码；不存在或未指定时创建账号并生成随机密码
+// 自动生成的用户名为prefix加三位以上的序号，contestOnly为true时新账号标记为该比赛的临时账号
+func (s *ContestService) ImportContestMembers(
+	ctx context.Context,
+	id int,
+	inputs []*ContestMemberImportInput,
+	prefix string,
+	contestOnly bool,
+	nowTime time.Time,
+) ([]*foundationview.ContestMemberCredential, error) {
+	var usernames []string
+	for _, input := range inputs {
+		if input.Username != "" {
+			usernames = append(usernames, input.Username)
+		}
+	}
+	users, err := foundationdao.GetUserDao().GetUserAccountInfosByUsername(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	existUsers := make(map[string]*foundationview.UserAccountInfo, len(users))
+	for _, user := range users {
+		existUsers[strings.ToLower(user.Username)] = user
+	}
+	usedUsernames := make(map[string]bool)
+	for _, username := range usernames {
+		if usedUsernames[strings.ToLower(username)] {
+			return nil, metaerror.NewCode(weberrorcode.ContestTeamUserConflict)
+		}
+		usedUsernames[strings.ToLower(username)] = true
+	}
+	prefixUsernames, err := foundationdao.GetUserDao().GetUsernamesByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, username := range prefixUsernames {
+		usedUsernames[strings.ToLower(username)] = true
+	}
+	var contestOnlyId *int
+	if contestOnly {
+		contestOnlyId = &id
+	}
+
+	sequence := 0
+	newUsers := make([]*foundationmodel.User, len(inputs))
+	members := make([]*foundationmodel.ContestMember, len(inputs))
+	credentials := make([]*foundationview.ContestMemberCredential, len(inputs))
+	for i, input := range inputs {
+		var location *string
+		if input.Location != "" {
+			location = &input.Location
+		}
+		members[i] = foundationmodel.NewContestMemberBuilder().
+			Id(id).
+			ContestName(input.Name).
+			Location(location).
+			Build()
+		credential := &foundationview.ContestMemberCredential{
+			Username:     input.Username,
+			Name:         input.Name,
+			Organization: input.Organization,
+			Location:     input.Location,
+		}
+		credentials[i] = credential
+		if user, ok := existUsers[strings.ToLower(input.Username)]; ok {
+			members[i].UserId = user.Id
+			credential.UserId = user.Id
+			credential.Username = user.Username
+			continue
+		}
+		if credential.Username == "" {
+			for {
+				sequence++
+				username := fmt.Sprintf("%s%03d", prefix, sequence)
+				if !usedUsernames[strings.ToLower(username)] {
+					credential.Username = username
+					break
+				}
+			}
+		}
+		password, err := generateContestMemberPassword()
+		if err != nil {
+			return nil, err
+		}
+		passwordEncode, err := GetUserService().GeneratePasswordEncode(password)
+		if err != nil {
+			return nil, metaerror.Wrap(err, "failed to encode password")
+		}
+		credential.Password = &password
+		var organization *string
+		if input.Organization != "" {
+			organization = &input.Organization
+		}
+		newUsers[i] = foundationmodel.NewUserBuilder().
+			Username(credential.Username).
+			Password(passwordEncode).
+			Nickname(input.Name).
+			Organization(organization).
+			ContestOnlyId(contestOnlyId).
+			InsertTime(nowTime).
+			ModifyTime(nowTime).
+			Level(1).
+			Experience(0).
+			Build()
+	}
+	err = foundationdao.GetContestMemberDao().ImportContestMembers(ctx, id, newUsers, members)
+	if err != nil {
+		return nil, err
+	}
+	for i, user := range newUsers {
+		if user != nil {
+			credentials[i].UserId = user.Id
+		}
+	}
+	return credentials, nil
+}
+
+// SetContestOnlyUsersDisabled 比赛结束后禁用为比赛生成的临时账号，返回修改的账号数
+func (s *ContestService) SetContestOnlyUsersDisabled(
+	ctx context.Context,
+	id int,
+	disabled bool,
+	nowTime time.Time,
+) (int, error) {
+	count, err := foundationdao.GetUserDao().UpdateContestOnlyUserDisabled(ctx, id, disabled, nowTime)
+	if err != nil {
+		return 0, err
+	}
+	GetUserService().clearUserDisabledCache()
+	return count, nil
+}
+
+// CheckContestMemberImportPdf 导出PDF时在创建账号前检查字体能否显示导入的信息
+// 账号创建后再拒绝生成会丢失只显示一次的密码
+func (s *ContestService) CheckContestMemberImportPdf(inputs []*ContestMemberImportInput, prefix string) error {
+	if !foundationrender.IsPdfTextSupported(prefix) {
+		return metaerror.NewCode(weberrorcode.ContestCredentialPdfUnsupported)
+	}
+	for _, input := range inputs {
+		text := input.Name + input.Organization + input.Location + input.Username
+		if !foundationrender.IsPdfTextSupported(text) {
+			return metaerror.NewCode(weberrorcode.ContestCredentialPdfUnsupported)
+		}
+	}
+	return nil
+}
+
+// RenderContestMemberCredentials 生成可裁剪的账号条，每个账号一段，以虚线分隔，同一账号不会跨页
+// 字体无法显示队名等信息时拒绝生成，避免打印出无法辨认的账号条，此时应导出CSV
+func (s *ContestService) RenderContestMemberCredentials(
+	title string,
+	credentials []*foundationview.ContestMemberCredential,
+) ([]byte, error) {
+	for _, credential := range credentials {
+		text := credential.Name + credential.Organization + credential.Location + credential.Username
+		if !foundationrender.IsPdfTextSupported(text) {
+			return nil, metaerror.NewCode(weberrorcode.ContestCredentialPdfUnsupported)
+		}
+	}
+	cutLine := strings.Repeat("- ", foundationrender.PdfTextColumns/2)
+	var pages [][]string
+	var page []string
+	for _, credential := range credentials {
+		password := "(existing account)"
+		if credential.Password != nil {
+			password = *credential.Password
+		}
+		var builder strings.Builder
+		builder.WriteString(cutLine + "\n\n")
+		_, _ = fmt.Fprintf(&builder, "  Team:     %s\n", credential.Name)
+		if credential.Organization != "" {
+			_, _ = fmt.Fprintf(&builder, "  School:   %s\n", credential.Organization)
+		}
+		if credential.Location != "" {
+			_, _ = fmt.Fprintf(&builder, "  Seat:     %s\n", credential.Location)
+		}
+		_, _ = fmt.Fprintf(&builder, "  Username: %s\n", credential.Username)
+		_, _ = fmt.Fprintf(&builder, "  Password: %s\n", password)
+		lines := foundationrender.WrapText(builder.String(), foundationrender.PdfTextColumns)
+		if len(page)+len(lines)+1 > foundationrender.PdfTextLines {
+			pages = append(pages, append(page, cutLine))
+			page = nil
+		}
+		page = append(page, lines...)
+	}
+	pages = append(pages, append(page, cutLine))
+	return foundationrender.RenderTextPdf(title, pages), nil
+}
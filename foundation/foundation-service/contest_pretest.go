@@ -0,0 +1,37 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	metaerror "meta/meta-error"
+	"time"
+	weberrorcode "web/error-code"
+)
+
+// StartContestSystemTest 比赛结束后开始系统测试，每个用户每道题最后一次通过预测试的提交重新评测全部数据
+func (s *ContestService) StartContestSystemTest(ctx context.Context, id int, nowTime time.Time) (int, error) {
+	setting, err := foundationdao.GetContestDao().GetContestSystemTestSetting(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if setting == nil || !setting.Pretest {
+		return 0, metaerror.NewCode(weberrorcode.ContestSystemTestDisabled)
+	}
+	if nowTime.Before(setting.EndTime) {
+		return 0, metaerror.NewCode(weberrorcode.ContestSystemTestNotEnd)
+	}
+	count, started, err := foundationdao.GetContestDao().StartContestSystemTest(
+		ctx,
+		id,
+		setting.StartTime,
+		setting.EndTime,
+		nowTime,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if !started {
+		return 0, metaerror.NewCode(weberrorcode.ContestSystemTestStarted)
+	}
+	return count, nil
+}
@@ -0,0 +1,232 @@
+package foundationservice
+
+import (
+	"context"
+	"fmt"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	foundationmodel "foundation/foundation-model"
+	foundationrender "foundation/foundation-render"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	"strings"
+	"time"
+	weberrorcode "web/error-code"
+)
+
+// getContestPrintUserIds 队伍成员共用打印配额，也可以互相查看打印任务
+func getContestPrintUserIds(ctx context.Context, id int, userId int) ([]int, error) {
+	userIds, err := foundationdao.GetContestTeamDao().GetContestTeamUserIds(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	if userIds == nil {
+		userIds = []int{userId}
+	}
+	return userIds, nil
+}
+
+// SubmitContestPrint 提交打印任务，页数按纯文本排版计算，超过单次上限或队伍配额时拒绝
+func (s *ContestService) SubmitContestPrint(
+	ctx context.Context,
+	id int,
+	userId int,
+	language foundationjudge.JudgeLanguage,
+	content string,
+	nowTime time.Time,
+) (*foundationmodel.ContestPrint, error) {
+	setting, err := foundationdao.GetContestDao().GetContestPrintSetting(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil || setting.PrintQuota == nil {
+		return nil, metaerror.NewCode(weberrorcode.ContestPrintDisabled)
+	}
+	if nowTime.Before(setting.StartTime) || !nowTime.Before(setting.EndTime) {
+		return nil, metaerror.NewCode(weberrorcode.ContestPrintClosed)
+	}
+	pages := len(
+		foundationrender.PaginateText(
+			content,
+			foundationrender.PdfTextColumns,
+			foundationrender.PdfTextLines,
+		),
+	)
+	if setting.PrintPageLimit != nil && pages > *setting.PrintPageLimit {
+		return nil, metaerror.NewCode(weberrorcode.ContestPrintPageLimit)
+	}
+	userIds, err := getContestPrintUserIds(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	contestPrint := foundationmodel.NewContestPrintBuilder().
+		ContestId(id).
+		UserId(userId).
+		Language(language).
+		Content(content).
+		Pages(pages).
+		InsertTime(nowTime).
+		Build()
+	inserted, err := foundationdao.GetContestPrintDao().InsertContestPrintWithQuota(
+		ctx,
+		contestPrint,
+		userIds,
+		*setting.PrintQuota,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !inserted {
+		return nil, metaerror.NewCode(weberrorcode.ContestPrintQuotaExceeded)
+	}
+	return contestPrint, nil
+}
+
+// GetUserContestPrints 获取用户（队伍）自己的打印任务
+func (s *ContestService) GetUserContestPrints(
+	ctx context.Context,
+	id int,
+	userId int,
+) ([]*foundationview.ContestPrint, error) {
+	userIds, err := getContestPrintUserIds(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return foundationdao.GetContestPrintDao().GetContestPrints(ctx, id, userIds, nil, "")
+}
+
+// GetContestPrintQueue 获取打印队列，按提交顺序排序
+func (s *ContestService) GetContestPrintQueue(
+	ctx context.Context,
+	id int,
+	status *foundationenum.ContestPrintStatus,
+	location string,
+) ([]*foundationview.ContestPrint, error) {
+	return foundationdao.GetContestPrintDao().GetContestPrints(ctx, id, nil, status, location)
+}
+
+// ClaimNextContestPrint 供打印程序轮询，领取下一个任务，没有任务时返回nil
+func (s *ContestService) ClaimNextContestPrint(
+	ctx context.Context,
+	id int,
+	userId int,
+	location string,
+	nowTime time.Time,
+) (*foundationview.ContestPrint, error) {
+	printId, err := foundationdao.GetContestPrintDao().ClaimNextContestPrint(ctx, id, userId, location, nowTime)
+	if err != nil {
+		return nil, err
+	}
+	if printId <= 0 {
+		return nil, nil
+	}
+	return foundationdao.GetContestPrintDao().GetContestPrint(ctx, id, printId)
+}
+
+func (s *ContestService) ClaimContestPrint(
+	ctx context.Context,
+	id int,
+	printId int,
+	userId int,
+	nowTime time.Time,
+) error {
+	ok, err := foundationdao.GetContestPrintDao().ClaimContestPrint(ctx, id, printId, userId, nowTime)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return metaerror.NewCode(weberrorcode.ContestPrintStatusError)
+	}
+	return nil
+}
+
+func (s *ContestService) ReleaseContestPrint(ctx context.Context, id int, printId int, userId int) error {
+	ok, err := foundationdao.GetContestPrintDao().ReleaseContestPrint(ctx, id, printId, userId)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return metaerror.NewCode(weberrorcode.ContestPrintStatusError)
+	}
+	return nil
+}
+
+func (s *ContestService) FinishContestPrint(
+	ctx context.Context,
+	id int,
+	printId int,
+	userId int,
+	nowTime time.Time,
+) error {
+	ok, err := foundationdao.GetContestPrintDao().FinishContestPrint(ctx, id, printId, userId, nowTime)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return metaerror.NewCode(weberrorcode.ContestPrintStatusError)
+	}
+	return nil
+}
+
+// getContestPrintHeader 打印页眉，便于志愿者将打印件送到对应的位置
+func getContestPrintHeader(title string, contestPrint *foundationview.ContestPrint) string {
+	var parts []string
+	parts = append(parts, title)
+	if contestPrint.TeamName != nil {
+		parts = append(parts, *contestPrint.TeamName)
+	} else if contestPrint.ContestName != nil && *contestPrint.ContestName != "" {
+		parts = append(parts, *contestPrint.ContestName)
+	}
+	if contestPrint.Username != nil {
+		parts = append(parts, *contestPrint.Username)
+	}
+	if contestPrint.Location != nil && *contestPrint.Location != "" {
+		parts = append(parts, *contestPrint.Location)
+	}
+	language := foundationjudge.GetLanguageKey(contestPrint.Language)
+	if language == "" {
+		language = "text"
+	}
+	parts = append(
+		parts,
+		fmt.Sprintf("#%d", contestPrint.Id),
+		language,
+		contestPrint.InsertTime.Format("2006-01-02 15:04:05"),
+	)
+	return strings.Join(parts, " | ")
+}
+
+// RenderContestPrint 生成打印文件，format为text时返回带页眉的纯文本，否则返回按语言加粗关键字的PDF
+func (s *ContestService) RenderContestPrint(
+	ctx context.Context,
+	id int,
+	printId int,
+	format string,
+) ([]byte, string, error) {
+	setting, err := foundationdao.GetContestDao().GetContestPrintSetting(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if setting == nil {
+		return nil, "", metaerror.NewCode(weberrorcode.ContestPrintNotFound)
+	}
+	contestPrint, err := foundationdao.GetContestPrintDao().GetContestPrint(ctx, id, printId)
+	if err != nil {
+		return nil, "", err
+	}
+	if contestPrint == nil {
+		return nil, "", metaerror.NewCode(weberrorcode.ContestPrintNotFound)
+	}
+	header := getContestPrintHeader(setting.Title, contestPrint)
+	if format == "text" {
+		return []byte(header + "\n\n" + contestPrint.Content), "text/plain; charset=utf-8", nil
+	}
+	pages := foundationrender.PaginateText(
+		contestPrint.Content,
+		foundationrender.PdfTextColumns,
+		foundationrender.PdfTextLines,
+	)
+	keywords := foundationjudge.GetLanguageKeywords(contestPrint.Language)
+	return foundationrender.RenderCodePdf(header, pages, keywords), "application/pdf", nil
+}
@@ -0,0 +1,466 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	"log/slog"
+	metaerror "meta/meta-error"
+	"meta/singleton"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// 未缓存比赛的变更在该时间内会被重复读取，正在加载的比赛可以补上加载期间的变更
+	contestRankChangeWindow = 10 * time.Second
+	// 序号空缺的最长等待时间，长事务提交前其变更的序号会空缺，超过后认为该事务已回滚
+	contestRankChangeGapTimeout = 10 * time.Minute
+	// 两次拉取变更的间隔超过该值时，可能遗漏了变更，重建全部缓存
+	contestRankChangeMaxGap = 5 * time.Second
+	// 变更记录的保留时间
+	contestRankChangeKeep = time.Hour
+	// 缓存多久未被访问后释放
+	contestRankCacheIdle = 30 * time.Minute
+)
+
+// contestRankSnapshot 某一时刻计算好的榜单，生成后不再修改，可以无锁读取
+type contestRankSnapshot struct {
+	contest  *foundationview.ContestRankDetail
+	lockTime *time.Time
+	frozen   []*foundationview.ContestRank // 锁榜时间之后的提交只计入未知次数
+	unfrozen []*foundationview.ContestRank // 全部提交的真实榜单
+}
+
+type contestRankCacheItem struct {
+	contestId int
+
+	mutex       sync.Mutex
+	option      ContestStandingsOption
+	problems    []*foundationview.ContestProblemRank
+	users       map[int]*foundationview.ContestRank
+	submissions map[int]*foundationview.ContestRankSubmission
+
+	snapshot   atomic.Pointer[contestRankSnapshot]
+	accessTime atomic.Int64
+}
+
+// ContestRankCacheService 进程内的比赛榜单缓存
+// 首次访问时从数据库加载，之后根据contest_rank_change中的变更增量更新
+type ContestRankCacheService struct {
+	cacheMap  sync.Map // contestId -> *contestRankCacheItem
+	loadMutex sync.Map // contestId -> *sync.Mutex，避免同一比赛并发加载
+
+	processMutex  sync.Mutex
+	changeCursor  int               // 序号不大于该值的变更都已处理
+	processedIds  map[int]bool      // 序号大于changeCursor且已处理的变更
+	skippedIds    map[int]time.Time // 比赛未缓存而跳过的变更，首次读到的时间
+	missingIds    map[int]time.Time // 空缺的序号，首次发现的时间
+	lastProcessed atomic.Int64      // 上次成功拉取变更的时间，UnixNano
+	lastCleanup   time.Time
+}
+
+var singletonContestRankCacheService = singleton.Singleton[ContestRankCacheService]{}
+
+func GetContestRankCacheService() *ContestRankCacheService {
+	return singletonContestRankCacheService.GetInstance(
+		func() *ContestRankCacheService {
+			return &ContestRankCacheService{
+				processedIds: make(map[int]bool),
+				skippedIds:   make(map[int]time.Time),
+				missingIds:   make(map[int]time.Time),
+			}
+		},
+	)
+}
+
+// isChangeProcessing 变更是否在持续拉取，否则缓存无法保证是最新的
+func (s *ContestRankCacheService) isChangeProcessing() bool {
+	lastProcessed := s.lastProcessed.Load()
+	return lastProcessed > 0 && time.Since(time.Unix(0, lastProcessed)) <= contestRankChangeMaxGap
+}
+
+// GetSnapshot 获取比赛榜单，未缓存时从数据库加载
+func (s *ContestRankCacheService) GetSnapshot(ctx context.Context, id int) (*contestRankSnapshot, error) {
+	if !s.isChangeProcessing() {
+		// 没有拉取变更时直接从数据库计算，不使用缓存
+		item := &contestRankCacheItem{contestId: id}
+		if err := item.load(ctx); err != nil {
+			return nil, err
+		}
+		return item.snapshot.Load(), nil
+	}
+	item, err := s.getItem(ctx, id)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	item.accessTime.Store(time.Now().Unix())
+	return item.snapshot.Load(), nil
+}
+
+func (s *ContestRankCacheService) getItem(ctx context.Context, id int) (*contestRankCacheItem, error) {
+	if val, ok := s.cacheMap.Load(id); ok {
+		return val.(*contestRankCacheItem), nil
+	}
+	lockVal, _ := s.loadMutex.LoadOrStore(id, &sync.Mutex{})
+	lock := lockVal.(*sync.Mutex)
+	lock.Lock()
+	defer lock.Unlock()
+	if val, ok := s.cacheMap.Load(id); ok {
+		return val.(*contestRankCacheItem), nil
+	}
+	item := &contestRankCacheItem{contestId: id}
+	if err := item.load(ctx); err != nil {
+		return nil, err
+	}
+	if item.snapshot.Load() == nil {
+		return nil, nil
+	}
+	s.cacheMap.Store(id, item)
+	return item, nil
+}
+
+// loadContestRankBase 加载计算榜单需要的比赛信息、题目与计算配置，比赛不存在时返回nil
+func loadContestRankBase(ctx context.Context, id int) (
+	*foundationview.ContestRankDetail,
+	[]*foundationview.ContestProblemRank,
+	*ContestStandingsOption,
+	error,
+) {
+	contest, err := foundationdao.GetContestDao().GetContestViewRank(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if contest == nil {
+		return nil, nil, nil, nil
+	}
+	contest.Members, err = foundationdao.GetContestMemberDao().GetUsersWithName(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	problems, err := foundationdao.GetContestProblemDao().GetProblemsRank(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sort.Slice(
+		problems, func(i, j int) bool {
+			return problems[i].Index < problems[j].Index
+		},
+	)
+	for _, problem := range problems {
+		contest.Problems = append(contest.Problems, int(problem.Index))
+	}
+	teams, err := foundationdao.GetContestTeamDao().GetContestTeams(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var lockTime *time.Time
+	if contest.LockRankDuration != nil && *contest.LockRankDuration > 0 {
+		t := contest.EndTime.Add(-*contest.LockRankDuration)
+		lockTime = &t
+	}
+	var memberStartTimes map[int]time.Time
+	if contest.PersonalDuration != nil {
+		memberStartTimes, err = foundationdao.GetContestMemberDao().GetStartTimes(ctx, id)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	var hackScores map[int]int
+	if contest.Hack {
+		hackCounts, err := foundationdao.GetContestHackDao().GetContestHackCounts(ctx, id)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		hackScores = make(map[int]int, len(hackCounts))
+		for _, hackCount := range hackCounts {
+			hackScores[hackCount.Hacker] = hackCount.Success*contest.HackScore - hackCount.Fail*contest.HackPenalty
+		}
+	}
+	option := &ContestStandingsOption{
+		Type:           contest.Type,
+		ScoreType:      contest.ScoreType,
+		StartTime:      contest.StartTime,
+		PenaltyMinutes: contest.PenaltyMinutes,
+		LockTime:       lockTime,
+		MembersIgnore:  contest.MembersIgnore,
+		Teams:          teams,
+
+		MemberStartTimes: memberStartTimes,
+
+		HackScores: hackScores,
+
+		SystemTested: contest.Pretest && contest.SystemTestTime != nil,
+	}
+	return contest, problems, option, nil
+}
+
+// load 从数据库完整加载比赛的榜单数据
+func (item *contestRankCacheItem) load(ctx context.Context) error {
+	contest, problems, option, err := loadContestRankBase(ctx, item.contestId)
+	if err != nil {
+		return err
+	}
+	if contest == nil {
+		return nil
+	}
+	users, err := foundationdao.GetJudgeJobDao().GetContestRankUsers(
+		ctx,
+		item.contestId,
+		contest.StartTime,
+		contest.EndTime,
+	)
+	if err != nil {
+		return err
+	}
+	submissions, err := foundationdao.GetJudgeJobDao().GetContestRankSubmissions(
+		ctx,
+		item.contestId,
+		contest.StartTime,
+		contest.EndTime,
+	)
+	if err != nil {
+		return err
+	}
+
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	item.option = *option
+	item.problems = problems
+	item.users = make(map[int]*foundationview.ContestRank, len(users))
+	for _, user := range users {
+		item.users[user.Inserter] = user
+	}
+	item.submissions = make(map[int]*foundationview.ContestRankSubmission, len(submissions))
+	for _, submission := range submissions {
+		item.submissions[submission.Id] = submission
+	}
+	item.rebuildSnapshot(contest)
+	return nil
+}
+
+// rebuildSnapshot 重新计算锁榜与不锁榜的榜单，需持有mutex
+func (item *contestRankCacheItem) rebuildSnapshot(contest *foundationview.ContestRankDetail) {
+	submissions := make([]*foundationview.ContestRankSubmission, 0, len(item.submissions))
+	for _, submission := range item.submissions {
+		submissions = append(submissions, submission)
+	}
+	sort.Slice(
+		submissions, func(i, j int) bool {
+			return submissions[i].Id < submissions[j].Id
+		},
+	)
+	users := make([]*foundationview.ContestRank, 0, len(item.users))
+	for _, user := range item.users {
+		users = append(users, user)
+	}
+
+	unfrozenOption := item.option
+	unfrozenOption.LockTime = nil
+	snapshot := &contestRankSnapshot{
+		contest:  contest,
+		lockTime: item.option.LockTime,
+		unfrozen: ComputeContestStandings(&unfrozenOption, item.problems, users, submissions),
+	}
+	if item.option.LockTime != nil {
+		snapshot.frozen = ComputeContestStandings(&item.option, item.problems, users, submissions)
+	} else {
+		snapshot.frozen = snapshot.unfrozen
+	}
+	item.snapshot.Store(snapshot)
+}
+
+// applySubmissions 合并最新的提交状态并重新计算榜单
+func (item *contestRankCacheItem) applySubmissions(
+	ctx context.Context,
+	submissions []*foundationview.ContestRankSubmission,
+) error {
+	snapshot := item.snapshot.Load()
+	if snapshot == nil {
+		return nil
+	}
+	contest := snapshot.contest
+
+	var newUserIds []int
+	item.mutex.Lock()
+	for _, submission := range submissions {
+		if submission.InsertTime.Before(contest.StartTime) || !submission.InsertTime.Before(contest.EndTime) {
+			continue
+		}
+		if _, ok := item.users[submission.Inserter]; !ok {
+			newUserIds = append(newUserIds, submission.Inserter)
+		}
+	}
+	item.mutex.Unlock()
+
+	newUsers, err := foundationdao.GetJudgeJobDao().GetContestRankUsersByIds(ctx, newUserIds)
+	if err != nil {
+		return err
+	}
+
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	for _, user := range newUsers {
+		item.users[user.Inserter] = user
+	}
+	for _, submission := range submissions {
+		if submission.InsertTime.Before(contest.StartTime) || !submission.InsertTime.Before(contest.EndTime) {
+			continue
+		}
+		item.submissions[submission.Id] = submission
+	}
+	item.rebuildSnapshot(contest)
+	return nil
+}
+
+// advanceChangeCursor 将游标推进到第一个仍需重新读取的序号之前
+// 序号在插入时分配，长事务中的变更可能在更大的序号之后才可见，因此空缺的序号需要等待
+func (s *ContestRankCacheService) advanceChangeCursor(
+	changes []*foundationmodel.ContestRankChange,
+	nowTime time.Time,
+) {
+	if len(changes) == 0 {
+		return
+	}
+	seenIds := make(map[int]bool, len(changes))
+	for _, change := range changes {
+		seenIds[change.Id] = true
+	}
+	maxId := changes[len(changes)-1].Id
+	for id := s.changeCursor + 1; id < maxId; id++ {
+		if _, ok := s.missingIds[id]; !ok && !seenIds[id] {
+			s.missingIds[id] = nowTime
+		}
+	}
+	for s.changeCursor < maxId {
+		id := s.changeCursor + 1
+		if seenIds[id] {
+			if skipTime, ok := s.skippedIds[id]; ok && nowTime.Sub(skipTime) <= contestRankChangeWindow {
+				break
+			}
+		} else if nowTime.Sub(s.missingIds[id]) <= contestRankChangeGapTimeout {
+			break
+		}
+		delete(s.processedIds, id)
+		delete(s.skippedIds, id)
+		delete(s.missingIds, id)
+		s.changeCursor = id
+	}
+}
+
+// ProcessChanges 拉取最近的榜单变更并更新已缓存的比赛
+func (s *ContestRankCacheService) ProcessChanges(ctx context.Context) error {
+	if !s.processMutex.TryLock() {
+		return nil
+	}
+	defer s.processMutex.Unlock()
+
+	nowTime := time.Now()
+	if s.lastProcessed.Load() > 0 && !s.isChangeProcessing() {
+		// 距离上次拉取太久，窗口之外的变更可能已经遗漏
+		slog.Warn("contest rank change gap too long, rebuild all")
+		s.cacheMap.Range(
+			func(key, value any) bool {
+				s.cacheMap.Delete(key)
+				return true
+			},
+		)
+	}
+
+	changes, err := foundationdao.GetContestRankChangeDao().GetContestRankChangesAfter(ctx, s.changeCursor)
+	if err != nil {
+		return err
+	}
+	s.lastProcessed.Store(nowTime.UnixNano())
+	if s.changeCursor == 0 && len(changes) > 0 {
+		// 启动时还没有缓存，从现有的第一条变更开始
+		s.changeCursor = changes[0].Id - 1
+	}
+
+	rebuildContests := make(map[int]bool)
+	jobContests := make(map[int]int)
+	for _, change := range changes {
+		if s.processedIds[change.Id] {
+			continue
+		}
+		// 未缓存的比赛不标记为已处理，正在加载中的比赛可以在下次拉取时补上加载期间的变更
+		if _, ok := s.cacheMap.Load(change.ContestId); !ok {
+			if _, ok := s.skippedIds[change.Id]; !ok {
+				s.skippedIds[change.Id] = nowTime
+			}
+			continue
+		}
+		delete(s.skippedIds, change.Id)
+		s.processedIds[change.Id] = true
+		if change.JudgeJobId == nil {
+			rebuildContests[change.ContestId] = true
+		} else {
+			jobContests[*change.JudgeJobId] = change.ContestId
+		}
+	}
+	s.advanceChangeCursor(changes, nowTime)
+
+	var finalErr error
+	// 需要重建的比赛直接丢弃缓存，下次访问时重新加载
+	for contestId := range rebuildContests {
+		s.cacheMap.Delete(contestId)
+	}
+	if len(jobContests) > 0 {
+		jobIds := make([]int, 0, len(jobContests))
+		for jobId, contestId := range jobContests {
+			if !rebuildContests[contestId] {
+				jobIds = append(jobIds, jobId)
+			}
+		}
+		submissions, err := foundationdao.GetJudgeJobDao().GetContestRankSubmissionsByIds(ctx, jobIds)
+		if err != nil {
+			// 无法增量更新时丢弃相关缓存，保证不会展示错误的榜单
+			for _, contestId := range jobContests {
+				s.cacheMap.Delete(contestId)
+			}
+			return err
+		}
+		contestSubmissions := make(map[int][]*foundationview.ContestRankSubmission)
+		for _, submission := range submissions {
+			contestId := jobContests[submission.Id]
+			contestSubmissions[contestId] = append(contestSubmissions[contestId], submission)
+		}
+		for contestId, list := range contestSubmissions {
+			val, ok := s.cacheMap.Load(contestId)
+			if !ok {
+				continue
+			}
+			if err := val.(*contestRankCacheItem).applySubmissions(ctx, list); err != nil {
+				s.cacheMap.Delete(contestId)
+				finalErr = metaerror.Wrap(err, "failed to apply contest rank change, contest:%d", contestId)
+			}
+		}
+	}
+
+	// 释放长时间未访问的缓存
+	idleBefore := nowTime.Add(-contestRankCacheIdle).Unix()
+	s.cacheMap.Range(
+		func(key, value any) bool {
+			if value.(*contestRankCacheItem).accessTime.Load() < idleBefore {
+				s.cacheMap.Delete(key)
+				s.loadMutex.Delete(key)
+			}
+			return true
+		},
+	)
+
+	if nowTime.Sub(s.lastCleanup) > time.Minute {
+		s.lastCleanup = nowTime
+		if err := foundationdao.GetContestRankChangeDao().DeleteContestRankChanges(
+			ctx,
+			contestRankChangeKeep,
+		); err != nil {
+			finalErr = err
+		}
+	}
+	return finalErr
+}
@@ -0,0 +1,156 @@
+package foundationservice
+
+import (
+	"context"
+	foundationcontest "foundation/foundation-contest"
+	foundationdao "foundation/foundation-dao"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	"time"
+)
+
+// getContestFinalStandings 计算不封榜的最终榜单，比赛不存在时返回nil
+func getContestFinalStandings(ctx context.Context, id int) ([]*foundationview.ContestRank, error) {
+	contest, problems, option, err := loadContestRankBase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if contest == nil {
+		return nil, nil
+	}
+	option.LockTime = nil
+	users, err := foundationdao.GetJudgeJobDao().GetContestRankUsers(ctx, id, contest.StartTime, contest.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := foundationdao.GetJudgeJobDao().GetContestRankSubmissions(
+		ctx,
+		id,
+		contest.StartTime,
+		contest.EndTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeContestStandings(option, problems, users, submissions), nil
+}
+
+// getContestRatingUserIds 榜单行对应的用户，队伍为全部成员
+func getContestRatingUserIds(rank *foundationview.ContestRank) []int {
+	if rank.TeamId <= 0 {
+		return []int{rank.Inserter}
+	}
+	userIds := make([]int, 0, len(rank.TeamMembers))
+	for _, member := range rank.TeamMembers {
+		userIds = append(userIds, member.UserId)
+	}
+	return userIds
+}
+
+// calculateContestRatings 根据最终榜单计算一场比赛的Rating变化，并更新ratings中的当前Rating
+// 只计入有提交的正式参赛者，忽略排名的成员与虚拟参赛不计入，队伍以成员的平均Rating参与计算
+func calculateContestRatings(
+	ctx context.Context,
+	id int,
+	fromTime time.Time,
+	ratings map[int]int,
+	nowTime time.Time,
+) ([]*foundationmodel.ContestRating, error) {
+	standings, err := getContestFinalStandings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var ranks []*foundationview.ContestRank
+	var missingIds []int
+	for _, rank := range standings {
+		if rank.Rank <= 0 || rank.Virtual || len(rank.Problems) == 0 || len(getContestRatingUserIds(rank)) == 0 {
+			continue
+		}
+		ranks = append(ranks, rank)
+		for _, userId := range getContestRatingUserIds(rank) {
+			if _, ok := ratings[userId]; !ok {
+				missingIds = append(missingIds, userId)
+			}
+		}
+	}
+	if len(ranks) == 0 {
+		return nil, nil
+	}
+	// 本轮之前的Rating从数据库加载，从未参加过的用户使用初始Rating
+	beforeRatings, err := foundationdao.GetContestRatingDao().GetUserRatingsBefore(ctx, missingIds, fromTime)
+	if err != nil {
+		return nil, err
+	}
+	for _, userId := range missingIds {
+		rating, ok := beforeRatings[userId]
+		if !ok {
+			rating = foundationcontest.RatingInitial
+		}
+		ratings[userId] = rating
+	}
+
+	// 并列的名次取并列区间的平均值
+	tieCount := make(map[int]int)
+	for _, rank := range ranks {
+		tieCount[rank.Rank]++
+	}
+	participants := make([]*foundationcontest.RatingParticipant, len(ranks))
+	for i, rank := range ranks {
+		userIds := getContestRatingUserIds(rank)
+		sum := 0
+		for _, userId := range userIds {
+			sum += ratings[userId]
+		}
+		participants[i] = &foundationcontest.RatingParticipant{
+			Rating: sum / len(userIds),
+			Rank:   float64(rank.Rank) + float64(tieCount[rank.Rank]-1)/2,
+		}
+	}
+	deltas := foundationcontest.CalculateRatingChanges(participants)
+
+	var results []*foundationmodel.ContestRating
+	for i, rank := range ranks {
+		for _, userId := range getContestRatingUserIds(rank) {
+			oldRating := ratings[userId]
+			ratings[userId] = oldRating + deltas[i]
+			results = append(
+				results, &foundationmodel.ContestRating{
+					ContestId:  id,
+					UserId:     userId,
+					Rank:       rank.Rank,
+					OldRating:  oldRating,
+					NewRating:  ratings[userId],
+					InsertTime: nowTime,
+				},
+			)
+		}
+	}
+	return results, nil
+}
+
+// ProcessContestRatings 重新计算有变化的计分比赛及其之后结束的全部计分比赛
+// Rating依赖之前的比赛结果，所以从最早变化的比赛开始按结束时间依次重算，重复执行的结果相同
+func (s *ContestService) ProcessContestRatings(ctx context.Context, nowTime time.Time) error {
+	ratingDao := foundationdao.GetContestRatingDao()
+	fromTime, err := ratingDao.GetContestRatingDirtyTime(ctx, nowTime)
+	if err != nil {
+		return err
+	}
+	if fromTime == nil {
+		return nil
+	}
+	contestIds, err := ratingDao.GetRatedContestIds(ctx, *fromTime, nowTime)
+	if err != nil {
+		return err
+	}
+	ratings := make(map[int]int)
+	var results []*foundationmodel.ContestRating
+	for _, contestId := range contestIds {
+		contestRatings, err := calculateContestRatings(ctx, contestId, *fromTime, ratings, nowTime)
+		if err != nil {
+			return err
+		}
+		results = append(results, contestRatings...)
+	}
+	return ratingDao.ReplaceContestRatings(ctx, *fromTime, contestIds, results, nowTime)
+}
@@ -0,0 +1,129 @@
+package foundationservice
+
+import (
+	"context"
+	foundationenum "foundation/foundation-enum"
+	foundationview "foundation/foundation-view"
+	"sort"
+)
+
+// resolverTeam 揭晓过程中的队伍状态
+type resolverTeam struct {
+	rank    *foundationview.ContestRank
+	solved  int
+	penalty int
+	pending []uint8 // 尚未揭晓的题目，按题目索引升序
+}
+
+// GetContestResolver 生成滚榜的揭晓步骤
+// 从锁榜的榜单开始，每次选择当前排名最靠后且仍有未揭晓题目的队伍，揭晓其索引最小的一道题，直到全部揭晓
+// 同样的榜单数据总是得到同样的步骤
+func (s *ContestService) GetContestResolver(ctx context.Context, id int) (*foundationview.ContestResolver, error) {
+	snapshot, err := GetContestRankCacheService().GetSnapshot(ctx, id)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+	resolver := &foundationview.ContestResolver{
+		Contest: snapshot.contest,
+		Ranks:   snapshot.frozen,
+	}
+	// 仅ACM比赛存在揭晓过程，未锁榜时没有需要揭晓的提交
+	if snapshot.contest.Type != foundationenum.ContestTypeAcm || snapshot.lockTime == nil {
+		return resolver, nil
+	}
+
+	finalMap := make(map[int]map[uint8]*foundationview.ContestRankProblem, len(snapshot.unfrozen))
+	for _, rank := range snapshot.unfrozen {
+		problems := make(map[uint8]*foundationview.ContestRankProblem, len(rank.Problems))
+		for _, problem := range rank.Problems {
+			problems[problem.Index] = problem
+		}
+		finalMap[getContestRankKey(rank)] = problems
+	}
+
+	teams := make([]*resolverTeam, 0, len(snapshot.frozen))
+	for _, rank := range snapshot.frozen {
+		team := &resolverTeam{
+			rank:    rank,
+			solved:  rank.Solved,
+			penalty: rank.Penalty,
+		}
+		for _, problem := range rank.Problems {
+			if problem.Ac == nil && problem.Lock > 0 {
+				team.pending = append(team.pending, problem.Index)
+			}
+		}
+		sort.Slice(
+			team.pending, func(i, j int) bool {
+				return team.pending[i] < team.pending[j]
+			},
+		)
+		teams = append(teams, team)
+	}
+	// 与榜单计算一致的排序，成绩相同时按榜单行的标识
+	less := func(a, b *resolverTeam) bool {
+		if a.solved != b.solved {
+			return a.solved > b.solved
+		}
+		if a.penalty != b.penalty {
+			return a.penalty < b.penalty
+		}
+		return getContestRankKey(a.rank) < getContestRankKey(b.rank)
+	}
+	sort.SliceStable(
+		teams, func(i, j int) bool {
+			return less(teams[i], teams[j])
+		},
+	)
+
+	penaltySeconds := snapshot.contest.PenaltyMinutes * 60
+	for {
+		position := -1
+		for i := len(teams) - 1; i >= 0; i-- {
+			if len(teams[i].pending) > 0 {
+				position = i
+				break
+			}
+		}
+		if position < 0 {
+			break
+		}
+		team := teams[position]
+		index := team.pending[0]
+		team.pending = team.pending[1:]
+
+		step := &foundationview.ContestResolverStep{
+			Inserter: team.rank.Inserter,
+			TeamId:   team.rank.TeamId,
+			Index:    index,
+			From:     position + 1,
+		}
+		final := finalMap[getContestRankKey(team.rank)][index]
+		if final != nil {
+			step.Attempt = final.Attempt
+			step.Ac = final.Ac
+			if final.Ac != nil {
+				// 个人比赛窗口下罚时从成员的开始时间计算
+				startTime := snapshot.contest.StartTime
+				if team.rank.StartTime != nil {
+					startTime = *team.rank.StartTime
+				}
+				team.solved++
+				team.penalty += int(final.Ac.Sub(startTime).Seconds()) + final.Attempt*penaltySeconds
+			}
+		}
+		step.Solved = team.solved
+		step.Penalty = team.penalty
+
+		// 通过后队伍只会上升，向前移动到正确的位置
+		newPosition := position
+		for newPosition > 0 && less(team, teams[newPosition-1]) {
+			teams[newPosition] = teams[newPosition-1]
+			newPosition--
+		}
+		teams[newPosition] = team
+		step.To = newPosition + 1
+		resolver.Steps = append(resolver.Steps, step)
+	}
+	return resolver, nil
+}
@@ -0,0 +1,320 @@
+package foundationservice
+
+import (
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	foundationview "foundation/foundation-view"
+	"sort"
+	"time"
+)
+
+// 未设置分数的题目按100分计算
+const contestProblemDefaultScore = 100
+
+// isContestRankIgnoredStatus 编译错误与评测系统的失败不计入尝试次数
+func isContestRankIgnoredStatus(status foundationjudge.JudgeStatus) bool {
+	switch status {
+	case foundationjudge.JudgeStatusCE,
+		foundationjudge.JudgeStatusJudgeFail,
+		foundationjudge.JudgeStatusSubmitFail:
+		return true
+	default:
+		return false
+	}
+}
+
+// getContestProblemPoint 根据比赛的计分方式计算一次提交在题目上的得分
+// 题目设置了衰减或扣分时，得分随比赛用时与之前的尝试次数降低，但不低于设置的最低比例
+func getContestProblemPoint(
+	scoreType foundationenum.ContestScoreType,
+	problem *foundationview.ContestProblemRank,
+	submission *foundationview.ContestRankSubmission,
+	startTime time.Time,
+	attempt int,
+) int {
+	fullScore := problem.Score
+	if fullScore <= 0 {
+		fullScore = contestProblemDefaultScore
+	}
+	var point int
+	if submission.Status == foundationjudge.JudgeStatusAC {
+		point = fullScore
+	} else if scoreType == foundationenum.ContestScoreTypeAccepted {
+		return 0
+	} else {
+		point = fullScore * submission.Score / 1000
+	}
+	if point <= 0 || (problem.ScoreDecay <= 0 && problem.ScorePenalty <= 0) {
+		return point
+	}
+	minutes := max(int(submission.InsertTime.Sub(startTime).Minutes()), 0)
+	decayed := point - point*problem.ScoreDecay*minutes/1000 - problem.ScorePenalty*attempt
+	return max(decayed, point*problem.ScoreMin/100, 0)
+}
+
+// ContestStandingsOption 计算榜单的比赛配置
+type ContestStandingsOption struct {
+	Type           foundationenum.ContestType
+	ScoreType      foundationenum.ContestScoreType
+	StartTime      time.Time
+	PenaltyMinutes int
+	LockTime       *time.Time // 不为空时该时间之后的提交只计入未知次数
+	MembersIgnore  []int
+	Teams          []*foundationview.ContestTeam // 队伍成员的提交计入队伍，在榜单中共用一行
+
+	MemberStartTimes map[int]time.Time // 个人比赛窗口下成员的开始时间，罚时从各自的开始时间计算
+
+	HackScores map[int]int // 用户挑战成功与失败的得分合计，计入总分，ACM比赛中用于通过题数与罚时相同时的排名
+
+	SystemTested bool // 系统测试已开始，未被重新评测的预测试通过记录视为跳过
+}
+
+// getContestRankStartTime 榜单行计算罚时的开始时间
+func getContestRankStartTime(option *ContestStandingsOption, rank *foundationview.ContestRank) time.Time {
+	if rank.StartTime != nil {
+		return *rank.StartTime
+	}
+	return option.StartTime
+}
+
+// getContestRankKey 榜单行的唯一标识，队伍使用负的队伍Id以区别于用户Id
+func getContestRankKey(rank *foundationview.ContestRank) int {
+	if rank.TeamId > 0 {
+		return -rank.TeamId
+	}
+	return rank.Inserter
+}
+
+// ComputeContestStandings 根据提交记录计算排序后的榜单
+// ACM：按通过题数降序、罚时升序，罚时为通过时间加上每次错误提交的罚时
+// OI：每题以最后一次提交为准，按总分降序
+// IOI：每题以最高分提交为准，按总分降序
+// 成绩完全相同时排名并列，忽略排名的成员仍按成绩排序展示，但不占用排名，队伍中有忽略排名的成员时整个队伍忽略排名
+func ComputeContestStandings(
+	option *ContestStandingsOption,
+	problems []*foundationview.ContestProblemRank,
+	users []*foundationview.ContestRank,
+	submissions []*foundationview.ContestRankSubmission,
+) []*foundationview.ContestRank {
+	problemMap := make(map[int]*foundationview.ContestProblemRank, len(problems))
+	for _, problem := range problems {
+		problemMap[problem.ProblemId] = problem
+	}
+	ignoreMap := make(map[int]bool, len(option.MembersIgnore))
+	for _, userId := range option.MembersIgnore {
+		ignoreMap[userId] = true
+	}
+
+	teamMap := make(map[int]*foundationview.ContestTeam)
+	for _, team := range option.Teams {
+		for _, member := range team.Members {
+			teamMap[member.UserId] = team
+		}
+	}
+
+	// 复制用户信息，同一份用户列表可以用于计算多个榜单
+	rankMap := make(map[int]*foundationview.ContestRank, len(users))
+	getRank := func(userId int) *foundationview.ContestRank {
+		key := userId
+		team, isTeam := teamMap[userId]
+		if isTeam {
+			key = -team.Id
+		}
+		rank, ok := rankMap[key]
+		if !ok {
+			if isTeam {
+				rank = &foundationview.ContestRank{
+					TeamId:      team.Id,
+					TeamName:    &team.Name,
+					TeamMembers: team.Members,
+				}
+				for _, member := range team.Members {
+					rank.Ignore = rank.Ignore || ignoreMap[member.UserId]
+					if startTime, ok := option.MemberStartTimes[member.UserId]; ok {
+						if rank.StartTime == nil || startTime.Before(*rank.StartTime) {
+							rank.StartTime = &startTime
+						}
+					}
+				}
+			} else {
+				rank = &foundationview.ContestRank{Inserter: userId, Ignore: ignoreMap[userId]}
+				if startTime, ok := option.MemberStartTimes[userId]; ok {
+					rank.StartTime = &startTime
+				}
+			}
+			rankMap[key] = rank
+		}
+		return rank
+	}
+	for _, user := range users {
+		rank := getRank(user.Inserter)
+		if rank.TeamId > 0 {
+			continue
+		}
+		rank.InserterUsername = user.InserterUsername
+		rank.InserterNickname = user.InserterNickname
+		rank.InserterEmail = user.InserterEmail
+		rank.Virtual = user.Virtual
+	}
+	// 每个用户（队伍）每道题的结果
+	resultMap := make(map[int]map[int]*foundationview.ContestRankProblem)
+	for _, submission := range submissions {
+		problem, ok := problemMap[submission.ProblemId]
+		if !ok || isContestRankIgnoredStatus(submission.Status) {
+			continue
+		}
+		if option.SystemTested && submission.Pretest && submission.Status == foundationjudge.JudgeStatusAC {
+			continue
+		}
+		rank := getRank(submission.Inserter)
+		if submission.Virtual {
+			rank.Virtual = true
+		}
+		key := getContestRankKey(rank)
+		userResults, ok := resultMap[key]
+		if !ok {
+			userResults = make(map[int]*foundationview.ContestRankProblem)
+			resultMap[key] = userResults
+		}
+		result, ok := userResults[submission.ProblemId]
+		if !ok {
+			result = &foundationview.ContestRankProblem{Index: problem.Index}
+			userResults[submission.ProblemId] = result
+		}
+		applyContestRankSubmission(option, problem, result, submission, getContestRankStartTime(option, rank))
+	}
+
+	ranks := make([]*foundationview.ContestRank, 0, len(rankMap))
+	for key, rank := range rankMap {
+		if rank.TeamId > 0 {
+			for _, member := range rank.TeamMembers {
+				rank.Hack += option.HackScores[member.UserId]
+			}
+		} else {
+			rank.Hack = option.HackScores[rank.Inserter]
+		}
+		rank.Score += rank.Hack
+		for _, result := range resultMap[key] {
+			rank.Problems = append(rank.Problems, result)
+			rank.Score += result.Score
+			if result.Ac == nil {
+				continue
+			}
+			rank.Solved++
+			if option.Type == foundationenum.ContestTypeAcm {
+				rank.Penalty += int(result.Ac.Sub(getContestRankStartTime(option, rank)).Seconds()) +
+					result.Attempt*option.PenaltyMinutes*60
+			}
+		}
+		sort.Slice(
+			rank.Problems, func(i, j int) bool {
+				return rank.Problems[i].Index < rank.Problems[j].Index
+			},
+		)
+		ranks = append(ranks, rank)
+	}
+
+	sort.SliceStable(
+		ranks, func(i, j int) bool {
+			if c := compareContestRank(option, ranks[i], ranks[j]); c != 0 {
+				return c < 0
+			}
+			return getContestRankKey(ranks[i]) < getContestRankKey(ranks[j])
+		},
+	)
+	position := 0
+	var last *foundationview.ContestRank
+	for _, rank := range ranks {
+		if rank.Ignore {
+			rank.Rank = 0
+			continue
+		}
+		position++
+		if last != nil && compareContestRank(option, last, rank) == 0 {
+			rank.Rank = last.Rank
+		} else {
+			rank.Rank = position
+		}
+		last = rank
+	}
+	return ranks
+}
+
+// applyContestRankSubmission 将一次提交按比赛规则合并到题目结果中，提交需按顺序传入
+func applyContestRankSubmission(
+	option *ContestStandingsOption,
+	problem *foundationview.ContestProblemRank,
+	result *foundationview.ContestRankProblem,
+	submission *foundationview.ContestRankSubmission,
+	startTime time.Time,
+) {
+	isLocked := option.LockTime != nil && !submission.InsertTime.Before(*option.LockTime)
+	isPending := foundationjudge.IsJudgeStatusRunning(submission.Status)
+	switch option.Type {
+	case foundationenum.ContestTypeOi:
+		if isLocked || isPending {
+			result.Lock++
+			return
+		}
+		// 扣分按本次提交之前的尝试次数计算
+		result.Score = getContestProblemPoint(option.ScoreType, problem, submission, startTime, result.Attempt)
+		result.Pretest = submission.Pretest
+		result.Attempt++
+		if submission.Status == foundationjudge.JudgeStatusAC {
+			insertTime := submission.InsertTime
+			result.Ac = &insertTime
+		} else {
+			result.Ac = nil
+		}
+	case foundationenum.ContestTypeIoi:
+		if isLocked || isPending {
+			result.Lock++
+			return
+		}
+		point := getContestProblemPoint(option.ScoreType, problem, submission, startTime, result.Attempt)
+		if point > result.Score {
+			result.Pretest = submission.Pretest
+		}
+		result.Score = max(result.Score, point)
+		result.Attempt++
+		if submission.Status == foundationjudge.JudgeStatusAC && result.Ac == nil {
+			insertTime := submission.InsertTime
+			result.Ac = &insertTime
+		}
+	default:
+		// ACM 通过后的提交不再影响结果
+		if result.Ac != nil {
+			return
+		}
+		if isLocked || isPending {
+			result.Lock++
+			return
+		}
+		if option.ScoreType != foundationenum.ContestScoreTypeNone {
+			point := getContestProblemPoint(option.ScoreType, problem, submission, startTime, result.Attempt)
+			result.Score = max(result.Score, point)
+		}
+		if submission.Status == foundationjudge.JudgeStatusAC {
+			insertTime := submission.InsertTime
+			result.Ac = &insertTime
+			result.Pretest = submission.Pretest
+			return
+		}
+		result.Attempt++
+	}
+}
+
+// compareContestRank 返回负数表示a排在b之前，0表示并列
+// ACM比赛中挑战得分在通过题数与罚时都相同时决定排名
+func compareContestRank(option *ContestStandingsOption, a, b *foundationview.ContestRank) int {
+	if option.Type == foundationenum.ContestTypeAcm {
+		if a.Solved != b.Solved {
+			return b.Solved - a.Solved
+		}
+		if a.Penalty != b.Penalty {
+			return a.Penalty - b.Penalty
+		}
+		return b.Hack - a.Hack
+	}
+	return b.Score - a.Score
+}
@@ -0,0 +1,369 @@
+package foundationservice
+
+import (
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	foundationview "foundation/foundation-view"
+	"testing"
+	"time"
+)
+
+var standingsStartTime = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// standingsSubmission 构造一条比赛开始后minutes分钟的提交
+func standingsSubmission(
+	inserter int,
+	problemId int,
+	minutes int,
+	status foundationjudge.JudgeStatus,
+	score int,
+) *foundationview.ContestRankSubmission {
+	return &foundationview.ContestRankSubmission{
+		Inserter:   inserter,
+		ProblemId:  problemId,
+		Status:     status,
+		Score:      score,
+		InsertTime: standingsStartTime.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+// standingsRow 榜单中一行的预期结果，Key与getContestRankKey一致，队伍为负的队伍Id
+type standingsRow struct {
+	Key     int
+	Rank    int
+	Solved  int
+	Penalty int
+	Score   int
+}
+
+// TestComputeContestStandings 测试各赛制下榜单的排序、罚时、并列与锁榜
+func TestComputeContestStandings(t *testing.T) {
+	problems := []*foundationview.ContestProblemRank{
+		{ProblemId: 1, Index: 1},
+		{ProblemId: 2, Index: 2, Score: 200},
+	}
+	users := []*foundationview.ContestRank{{Inserter: 1}, {Inserter: 2}, {Inserter: 3}}
+	lockTime := standingsStartTime.Add(60 * time.Minute)
+
+	cases := []struct {
+		name        string
+		option      *ContestStandingsOption
+		submissions []*foundationview.ContestRankSubmission
+		expected    []standingsRow
+	}{
+		{
+			name: "ACM按通过题数与罚时排序，编译错误不计罚时",
+			option: &ContestStandingsOption{
+				Type:           foundationenum.ContestTypeAcm,
+				StartTime:      standingsStartTime,
+				PenaltyMinutes: 20,
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 5, foundationjudge.JudgeStatusWA, 0),
+				standingsSubmission(1, 1, 10, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(2, 1, 15, foundationjudge.JudgeStatusCE, 0),
+				standingsSubmission(2, 1, 20, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(2, 1, 25, foundationjudge.JudgeStatusWA, 0),
+				standingsSubmission(3, 1, 1, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(3, 2, 30, foundationjudge.JudgeStatusAC, 1000),
+			},
+			expected: []standingsRow{
+				{Key: 3, Rank: 1, Solved: 2, Penalty: 31 * 60},
+				{Key: 2, Rank: 2, Solved: 1, Penalty: 20 * 60},
+				{Key: 1, Rank: 3, Solved: 1, Penalty: 10*60 + 20*60},
+			},
+		},
+		{
+			name: "ACM成绩相同时排名并列，后续排名跳过",
+			option: &ContestStandingsOption{
+				Type:           foundationenum.ContestTypeAcm,
+				StartTime:      standingsStartTime,
+				PenaltyMinutes: 20,
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 10, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(2, 1, 10, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(3, 1, 11, foundationjudge.JudgeStatusAC, 1000),
+			},
+			expected: []standingsRow{
+				{Key: 1, Rank: 1, Solved: 1, Penalty: 600},
+				{Key: 2, Rank: 1, Solved: 1, Penalty: 600},
+				{Key: 3, Rank: 3, Solved: 1, Penalty: 660},
+			},
+		},
+		{
+			name: "ACM锁榜后与评测中的提交不计入结果",
+			option: &ContestStandingsOption{
+				Type:           foundationenum.ContestTypeAcm,
+				StartTime:      standingsStartTime,
+				PenaltyMinutes: 20,
+				LockTime:       &lockTime,
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 30, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(2, 1, 70, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(3, 1, 40, foundationjudge.JudgeStatusRunning, 0),
+			},
+			expected: []standingsRow{
+				{Key: 1, Rank: 1, Solved: 1, Penalty: 30 * 60},
+				{Key: 2, Rank: 2},
+				{Key: 3, Rank: 2},
+			},
+		},
+		{
+			name: "忽略排名的成员不占用排名",
+			option: &ContestStandingsOption{
+				Type:           foundationenum.ContestTypeAcm,
+				StartTime:      standingsStartTime,
+				PenaltyMinutes: 20,
+				MembersIgnore:  []int{1},
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 5, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(2, 1, 10, foundationjudge.JudgeStatusAC, 1000),
+			},
+			expected: []standingsRow{
+				{Key: 1, Rank: 0, Solved: 1, Penalty: 5 * 60},
+				{Key: 2, Rank: 1, Solved: 1, Penalty: 10 * 60},
+				{Key: 3, Rank: 2},
+			},
+		},
+		{
+			name: "OI以最后一次提交为准，部分得分按比例计算",
+			option: &ContestStandingsOption{
+				Type:      foundationenum.ContestTypeOi,
+				ScoreType: foundationenum.ContestScoreTypePartial,
+				StartTime: standingsStartTime,
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 5, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(1, 1, 10, foundationjudge.JudgeStatusWA, 500),
+				standingsSubmission(2, 2, 10, foundationjudge.JudgeStatusWA, 300),
+				standingsSubmission(3, 1, 20, foundationjudge.JudgeStatusAC, 1000),
+			},
+			expected: []standingsRow{
+				{Key: 3, Rank: 1, Solved: 1, Score: 100},
+				{Key: 2, Rank: 2, Score: 60},
+				{Key: 1, Rank: 3, Score: 50},
+			},
+		},
+		{
+			name: "IOI以最高分提交为准，总分相同时并列",
+			option: &ContestStandingsOption{
+				Type:      foundationenum.ContestTypeIoi,
+				ScoreType: foundationenum.ContestScoreTypePartial,
+				StartTime: standingsStartTime,
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 5, foundationjudge.JudgeStatusWA, 300),
+				standingsSubmission(1, 1, 10, foundationjudge.JudgeStatusWA, 800),
+				standingsSubmission(1, 1, 15, foundationjudge.JudgeStatusWA, 500),
+				standingsSubmission(2, 2, 10, foundationjudge.JudgeStatusWA, 400),
+				standingsSubmission(3, 1, 20, foundationjudge.JudgeStatusAC, 1000),
+			},
+			expected: []standingsRow{
+				{Key: 3, Rank: 1, Solved: 1, Score: 100},
+				{Key: 1, Rank: 2, Score: 80},
+				{Key: 2, Rank: 2, Score: 80},
+			},
+		},
+		{
+			name: "队伍成员的提交合并为一行，挑战得分计入总分",
+			option: &ContestStandingsOption{
+				Type:           foundationenum.ContestTypeAcm,
+				ScoreType:      foundationenum.ContestScoreTypeAccepted,
+				StartTime:      standingsStartTime,
+				PenaltyMinutes: 20,
+				Teams: []*foundationview.ContestTeam{
+					{
+						Id:      7,
+						Name:    "team",
+						Members: []*foundationview.ContestTeamMember{{TeamId: 7, UserId: 1}, {TeamId: 7, UserId: 2}},
+					},
+				},
+				HackScores: map[int]int{1: 100, 2: -50},
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 10, foundationjudge.JudgeStatusWA, 0),
+				standingsSubmission(2, 1, 20, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(1, 2, 30, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(3, 1, 5, foundationjudge.JudgeStatusAC, 1000),
+			},
+			expected: []standingsRow{
+				{Key: -7, Rank: 1, Solved: 2, Penalty: 20*60 + 20*60 + 30*60, Score: 100 + 200 + 50},
+				{Key: 3, Rank: 2, Solved: 1, Penalty: 5 * 60, Score: 100},
+			},
+		},
+		{
+			name: "ACM通过题数与罚时相同时按挑战得分排名",
+			option: &ContestStandingsOption{
+				Type:           foundationenum.ContestTypeAcm,
+				StartTime:      standingsStartTime,
+				PenaltyMinutes: 20,
+				HackScores:     map[int]int{2: 100, 3: -50},
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 10, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(2, 1, 10, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(3, 1, 5, foundationjudge.JudgeStatusAC, 1000),
+			},
+			expected: []standingsRow{
+				{Key: 3, Rank: 1, Solved: 1, Penalty: 5 * 60, Score: -50},
+				{Key: 2, Rank: 2, Solved: 1, Penalty: 10 * 60, Score: 100},
+				{Key: 1, Rank: 3, Solved: 1, Penalty: 10 * 60},
+			},
+		},
+		{
+			name: "个人比赛窗口的罚时从各自的开始时间计算",
+			option: &ContestStandingsOption{
+				Type:           foundationenum.ContestTypeAcm,
+				StartTime:      standingsStartTime,
+				PenaltyMinutes: 20,
+				MemberStartTimes: map[int]time.Time{
+					2: standingsStartTime.Add(60 * time.Minute),
+				},
+			},
+			submissions: []*foundationview.ContestRankSubmission{
+				standingsSubmission(1, 1, 30, foundationjudge.JudgeStatusAC, 1000),
+				standingsSubmission(2, 1, 70, foundationjudge.JudgeStatusAC, 1000),
+			},
+			expected: []standingsRow{
+				{Key: 2, Rank: 1, Solved: 1, Penalty: 10 * 60},
+				{Key: 1, Rank: 2, Solved: 1, Penalty: 30 * 60},
+				{Key: 3, Rank: 3},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranks := ComputeContestStandings(tc.option, problems, users, tc.submissions)
+			if len(ranks) != len(tc.expected) {
+				t.Fatalf("ComputeContestStandings returned %d rows; want %d", len(ranks), len(tc.expected))
+			}
+			for i, rank := range ranks {
+				result := standingsRow{
+					Key:     getContestRankKey(rank),
+					Rank:    rank.Rank,
+					Solved:  rank.Solved,
+					Penalty: rank.Penalty,
+					Score:   rank.Score,
+				}
+				if result != tc.expected[i] {
+					t.Errorf("row %d = %+v; want %+v", i, result, tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestComputeContestStandingsLock 测试锁榜与评测中的提交计入未知次数
+func TestComputeContestStandingsLock(t *testing.T) {
+	problems := []*foundationview.ContestProblemRank{{ProblemId: 1, Index: 1}}
+	lockTime := standingsStartTime.Add(60 * time.Minute)
+	option := &ContestStandingsOption{
+		Type:           foundationenum.ContestTypeAcm,
+		StartTime:      standingsStartTime,
+		PenaltyMinutes: 20,
+		LockTime:       &lockTime,
+	}
+	submissions := []*foundationview.ContestRankSubmission{
+		standingsSubmission(1, 1, 10, foundationjudge.JudgeStatusWA, 0),
+		standingsSubmission(1, 1, 61, foundationjudge.JudgeStatusWA, 0),
+		standingsSubmission(1, 1, 62, foundationjudge.JudgeStatusAC, 1000),
+	}
+	ranks := ComputeContestStandings(option, problems, nil, submissions)
+	if len(ranks) != 1 || len(ranks[0].Problems) != 1 {
+		t.Fatalf("ComputeContestStandings returned %+v; want one row with one problem", ranks)
+	}
+	problem := ranks[0].Problems[0]
+	if problem.Attempt != 1 || problem.Lock != 2 || problem.Ac != nil {
+		t.Errorf("problem = %+v; want attempt 1, lock 2 and no ac", problem)
+	}
+}
+
+// TestComputeContestStandingsSystemTest 测试系统测试开始后预测试通过的提交不再计入
+func TestComputeContestStandingsSystemTest(t *testing.T) {
+	problems := []*foundationview.ContestProblemRank{{ProblemId: 1, Index: 1}}
+	pretest := standingsSubmission(1, 1, 10, foundationjudge.JudgeStatusAC, 1000)
+	pretest.Pretest = true
+	submissions := []*foundationview.ContestRankSubmission{pretest}
+
+	cases := []struct {
+		systemTested bool
+		solved       int
+		pretest      bool
+		name         string
+	}{
+		{false, 1, true, "系统测试前预测试通过计入榜单并标记"},
+		{true, 0, false, "系统测试后未重新评测的预测试通过不计入"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			option := &ContestStandingsOption{
+				Type:           foundationenum.ContestTypeAcm,
+				StartTime:      standingsStartTime,
+				PenaltyMinutes: 20,
+				SystemTested:   tc.systemTested,
+			}
+			ranks := ComputeContestStandings(option, problems, []*foundationview.ContestRank{{Inserter: 1}}, submissions)
+			if len(ranks) != 1 {
+				t.Fatalf("ComputeContestStandings returned %d rows; want 1", len(ranks))
+			}
+			if ranks[0].Solved != tc.solved {
+				t.Errorf("solved = %d; want %d", ranks[0].Solved, tc.solved)
+			}
+			pretest := len(ranks[0].Problems) > 0 && ranks[0].Problems[0].Pretest
+			if pretest != tc.pretest {
+				t.Errorf("pretest = %v; want %v", pretest, tc.pretest)
+			}
+		})
+	}
+}
+
+// TestGetContestProblemPoint 测试题目得分的衰减、扣分与最低比例
+func TestGetContestProblemPoint(t *testing.T) {
+	cases := []struct {
+		scoreType foundationenum.ContestScoreType
+		problem   *foundationview.ContestProblemRank
+		status    foundationjudge.JudgeStatus
+		score     int
+		minutes   int
+		attempt   int
+		expected  int
+		name      string
+	}{
+		{
+			foundationenum.ContestScoreTypeAccepted, &foundationview.ContestProblemRank{},
+			foundationjudge.JudgeStatusAC, 1000, 10, 0, 100, "未设置分数时按100分计算",
+		},
+		{
+			foundationenum.ContestScoreTypeAccepted, &foundationview.ContestProblemRank{Score: 500},
+			foundationjudge.JudgeStatusWA, 600, 10, 0, 0, "仅通过得分时未通过为0分",
+		},
+		{
+			foundationenum.ContestScoreTypePartial, &foundationview.ContestProblemRank{Score: 500},
+			foundationjudge.JudgeStatusWA, 600, 10, 0, 300, "部分得分按比例计算",
+		},
+		{
+			foundationenum.ContestScoreTypeAccepted,
+			&foundationview.ContestProblemRank{Score: 1000, ScoreDecay: 4, ScorePenalty: 50},
+			foundationjudge.JudgeStatusAC, 1000, 30, 2, 1000 - 120 - 100, "按分钟衰减并按之前的尝试扣分",
+		},
+		{
+			foundationenum.ContestScoreTypeAccepted,
+			&foundationview.ContestProblemRank{Score: 1000, ScoreDecay: 10, ScoreMin: 30},
+			foundationjudge.JudgeStatusAC, 1000, 200, 0, 300, "衰减后不低于最低比例",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			submission := standingsSubmission(1, 1, tc.minutes, tc.status, tc.score)
+			result := getContestProblemPoint(tc.scoreType, tc.problem, submission, standingsStartTime, tc.attempt)
+			if result != tc.expected {
+				t.Errorf("getContestProblemPoint() = %d; want %d", result, tc.expected)
+			}
+		})
+	}
+}
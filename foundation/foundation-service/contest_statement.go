@@ -0,0 +1,217 @@
+package foundationservice
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	foundationerrorcode "foundation/error-code"
+	foundationcontest "foundation/foundation-contest"
+	foundationdao "foundation/foundation-dao"
+	foundationrender "foundation/foundation-render"
+	foundationview "foundation/foundation-view"
+	"io"
+	metaerror "meta/meta-error"
+	"net/http"
+	neturl "net/url"
+	"path"
+	"strings"
+	"time"
+	"web/config"
+)
+
+const contestStatementImageMaxSize = 20 * 1024 * 1024
+
+var contestStatementClient = &http.Client{
+	Timeout: 30 * time.Second,
+	// 不跟随重定向，避免被引导到R2以外的地址
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// getContestStatementTimeLimit 时间限制为整秒时按秒显示
+func getContestStatementTimeLimit(timeLimit int) string {
+	if timeLimit%1000 == 0 {
+		return fmt.Sprintf("%d s", timeLimit/1000)
+	}
+	return fmt.Sprintf("%d ms", timeLimit)
+}
+
+// getContestStatementMemoryLimit 内存限制为整MB时按MB显示
+func getContestStatementMemoryLimit(memoryLimit int) string {
+	if memoryLimit%1024 == 0 {
+		return fmt.Sprintf("%d MB", memoryLimit/1024)
+	}
+	return fmt.Sprintf("%d KB", memoryLimit)
+}
+
+// isContestStatementImageAllowed 只下载R2上的图片，其他地址可能指向内网
+func isContestStatementImageAllowed(url string) bool {
+	r2Url, err := neturl.Parse(config.GetR2Url())
+	if err != nil || r2Url.Host == "" {
+		return false
+	}
+	imageUrl, err := neturl.Parse(url)
+	if err != nil {
+		return false
+	}
+	if imageUrl.Scheme != "http" && imageUrl.Scheme != "https" {
+		return false
+	}
+	return strings.EqualFold(imageUrl.Host, r2Url.Host)
+}
+
+// downloadContestStatementImage 下载题面中的图片
+func downloadContestStatementImage(ctx context.Context, url string) ([]byte, error) {
+	if !isContestStatementImageAllowed(url) {
+		return nil, metaerror.New("image url is not allowed, url:%s", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to create image request, url:%s", url)
+	}
+	resp, err := contestStatementClient.Do(req)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to download image, url:%s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, metaerror.New("failed to download image, url:%s, status:%d", url, resp.StatusCode)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, contestStatementImageMaxSize+1))
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to read image, url:%s", url)
+	}
+	if len(content) > contestStatementImageMaxSize {
+		return nil, metaerror.New("image too large, url:%s", url)
+	}
+	return content, nil
+}
+
+// renderContestStatementCover 封面包含比赛名称、时间与题目列表
+func renderContestStatementCover(
+	contest *foundationview.ContestDetail,
+	problems []*foundationview.ContestProblemStatement,
+) string {
+	var buf strings.Builder
+	buf.WriteString("\\begin{titlepage}\n\\centering\n\\vspace*{0.2\\textheight}\n")
+	buf.WriteString("{\\Huge\\bfseries " + foundationrender.EscapeLatex(contest.Title) + "\\par}\n")
+	buf.WriteString("\\vspace{2em}\n")
+	buf.WriteString(
+		fmt.Sprintf(
+			"{\\large %s -- %s\\par}\n",
+			contest.StartTime.In(time.Local).Format("2006-01-02 15:04"),
+			contest.EndTime.In(time.Local).Format("2006-01-02 15:04"),
+		),
+	)
+	buf.WriteString("\\vspace{3em}\n")
+	buf.WriteString("\\begin{tabular}{|c|l|c|c|}\n\\hline\n")
+	buf.WriteString("\\textbf{题号} & \\textbf{题目} & \\textbf{时间限制} & \\textbf{内存限制} \\\\\n\\hline\n")
+	for _, problem := range problems {
+		buf.WriteString(
+			fmt.Sprintf(
+				"%s & %s & %s & %s \\\\\n\\hline\n",
+				foundationcontest.GetContestProblemIndexStr(int(problem.Index)),
+				foundationrender.EscapeLatex(problem.Title),
+				getContestStatementTimeLimit(problem.TimeLimit),
+				getContestStatementMemoryLimit(problem.MemoryLimit),
+			),
+		)
+	}
+	buf.WriteString("\\end{tabular}\n")
+	buf.WriteString(fmt.Sprintf("\n\\vfill\n{\\large 共 %d 题\\par}\n", len(problems)))
+	buf.WriteString("\\end{titlepage}\n\n")
+	return buf.String()
+}
+
+// ExportContestStatements 导出比赛题面的LaTeX源文件与图片压缩包，使用xelatex编译后打印
+func (s *ContestService) ExportContestStatements(ctx context.Context, id int, w io.Writer) error {
+	contest, err := foundationdao.GetContestDao().GetContest(ctx, id)
+	if err != nil {
+		return err
+	}
+	if contest == nil {
+		return metaerror.NewCode(foundationerrorcode.NotFound)
+	}
+	problems, err := foundationdao.GetContestProblemDao().GetProblemsStatement(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// 图片放到压缩包的images目录，只保留R2上xelatex支持的格式
+	imagePaths := make(map[string]string)
+	var imageUrls []string
+	imagePath := func(url string) string {
+		if !isContestStatementImageAllowed(url) {
+			return ""
+		}
+		if existPath, ok := imagePaths[url]; ok {
+			return existPath
+		}
+		extension := strings.ToLower(path.Ext(strings.SplitN(url, "?", 2)[0]))
+		switch extension {
+		case ".png", ".jpg", ".jpeg", ".pdf":
+		default:
+			return ""
+		}
+		imageUrls = append(imageUrls, url)
+		imagePaths[url] = fmt.Sprintf("images/%d%s", len(imageUrls), extension)
+		return imagePaths[url]
+	}
+
+	var body strings.Builder
+	body.WriteString(renderContestStatementCover(contest, problems))
+	for _, problem := range problems {
+		body.WriteString("\\clearpage\n")
+		body.WriteString(
+			fmt.Sprintf(
+				"\\section*{%s. %s}\n\n",
+				foundationcontest.GetContestProblemIndexStr(int(problem.Index)),
+				foundationrender.EscapeLatex(problem.Title),
+			),
+		)
+		body.WriteString(
+			fmt.Sprintf(
+				"时间限制：%s \\qquad 内存限制：%s\n\n",
+				getContestStatementTimeLimit(problem.TimeLimit),
+				getContestStatementMemoryLimit(problem.MemoryLimit),
+			),
+		)
+		body.WriteString(foundationrender.MarkdownToLatex(problem.Description, imagePath))
+		if problem.Source != nil && *problem.Source != "" {
+			body.WriteString("\\subsection*{来源}\n\n" + foundationrender.EscapeLatex(*problem.Source) + "\n\n")
+		}
+	}
+	document := foundationrender.RenderLatexDocument(contest.Title, body.String())
+
+	// 先下载全部图片，失败时还没有输出内容，可以正常返回错误
+	images := make([][]byte, len(imageUrls))
+	for i, url := range imageUrls {
+		images[i], err = downloadContestStatementImage(ctx, url)
+		if err != nil {
+			return err
+		}
+	}
+
+	zipWriter := zip.NewWriter(w)
+	fileWriter, err := zipWriter.Create("statements.tex")
+	if err != nil {
+		return metaerror.Wrap(err, "failed to write contest statements, id:%d", id)
+	}
+	if _, err := io.WriteString(fileWriter, document); err != nil {
+		return metaerror.Wrap(err, "failed to write contest statements, id:%d", id)
+	}
+	for i, url := range imageUrls {
+		fileWriter, err := zipWriter.Create(imagePaths[url])
+		if err != nil {
+			return metaerror.Wrap(err, "failed to write contest statements, id:%d", id)
+		}
+		if _, err := fileWriter.Write(images[i]); err != nil {
+			return metaerror.Wrap(err, "failed to write contest statements, id:%d", id)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return metaerror.Wrap(err, "failed to write contest statements, id:%d", id)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	"strings"
+	"time"
+	weberrorcode "web/error-code"
+)
+
+// ContestTeamInput 创建或导入队伍时的队伍信息，成员为用户名
+type ContestTeamInput struct {
+	Name    string
+	Members []string
+}
+
+func (s *ContestService) GetContestTeams(ctx context.Context, id int) ([]*foundationview.ContestTeam, error) {
+	return foundationdao.GetContestTeamDao().GetContestTeams(ctx, id)
+}
+
+// getContestTeamMemberIds 将每个队伍的成员用户名转换为用户Id，同一用户不能出现在多个队伍中
+func (s *ContestService) getContestTeamMemberIds(
+	ctx context.Context,
+	teams []*ContestTeamInput,
+) ([][]int, error) {
+	var usernames []string
+	for _, team := range teams {
+		usernames = append(usernames, team.Members...)
+	}
+	users, err := foundationdao.GetUserDao().GetUserAccountInfosByUsername(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	userIdMap := make(map[string]int, len(users))
+	for _, user := range users {
+		userIdMap[strings.ToLower(user.Username)] = user.Id
+	}
+	members := make([][]int, 0, len(teams))
+	used := make(map[int]bool)
+	for _, team := range teams {
+		var userIds []int
+		for _, username := range team.Members {
+			userId, ok := userIdMap[strings.ToLower(username)]
+			if !ok {
+				return nil, metaerror.NewCode(weberrorcode.ContestTeamUserNotFound)
+			}
+			if used[userId] {
+				return nil, metaerror.NewCode(weberrorcode.ContestTeamUserConflict)
+			}
+			used[userId] = true
+			userIds = append(userIds, userId)
+		}
+		members = append(members, userIds)
+	}
+	return members, nil
+}
+
+// SaveContestTeam team.Id为0时创建队伍，否则修改队伍
+func (s *ContestService) SaveContestTeam(
+	ctx context.Context,
+	team *foundationmodel.ContestTeam,
+	input *ContestTeamInput,
+) error {
+	members, err := s.getContestTeamMemberIds(ctx, []*ContestTeamInput{input})
+	if err != nil {
+		return err
+	}
+	conflictIds, err := foundationdao.GetContestTeamDao().GetContestTeamConflictUserIds(
+		ctx,
+		team.ContestId,
+		team.Id,
+		members[0],
+	)
+	if err != nil {
+		return err
+	}
+	if len(conflictIds) > 0 {
+		return metaerror.NewCode(weberrorcode.ContestTeamUserConflict)
+	}
+	team.Name = input.Name
+	if team.Id > 0 {
+		return foundationdao.GetContestTeamDao().UpdateContestTeam(ctx, team, members[0])
+	}
+	return foundationdao.GetContestTeamDao().InsertContestTeam(ctx, team, members[0])
+}
+
+func (s *ContestService) DeleteContestTeam(ctx context.Context, id int, teamId int) error {
+	return foundationdao.GetContestTeamDao().DeleteContestTeam(ctx, id, teamId)
+}
+
+// ImportContestTeams 批量导入队伍，替换比赛原有的全部队伍
+func (s *ContestService) ImportContestTeams(
+	ctx context.Context,
+	id int,
+	userId int,
+	inputs []*ContestTeamInput,
+	nowTime time.Time,
+) error {
+	members, err := s.getContestTeamMemberIds(ctx, inputs)
+	if err != nil {
+		return err
+	}
+	teams := make([]*foundationmodel.ContestTeam, 0, len(inputs))
+	for _, input := range inputs {
+		teams = append(
+			teams, foundationmodel.NewContestTeamBuilder().
+				ContestId(id).
+				Name(input.Name).
+				Inserter(userId).
+				InsertTime(nowTime).
+				Build(),
+		)
+	}
+	return foundationdao.GetContestTeamDao().ReplaceContestTeams(ctx, id, teams, members)
+}
@@ -0,0 +1,159 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	"time"
+	weberrorcode "web/error-code"
+)
+
+// StartContestVirtual 在比赛结束后开始虚拟参赛，时长与原比赛相同
+// 已正式参赛或属于比赛队伍的用户不能虚拟参赛，以免与正式成绩混在同一行
+func (s *ContestService) StartContestVirtual(
+	ctx context.Context,
+	id int,
+	userId int,
+	nowTime time.Time,
+) (*foundationmodel.ContestVirtual, error) {
+	startTime, endTime, err := foundationdao.GetContestDao().GetContestTime(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !nowTime.After(*endTime) {
+		return nil, metaerror.NewCode(weberrorcode.ContestVirtualNotEnd)
+	}
+	participated, err := foundationdao.GetJudgeJobDao().HasContestRankSubmission(ctx, id, userId, *startTime, *endTime)
+	if err != nil {
+		return nil, err
+	}
+	if !participated {
+		teamUserIds, err := foundationdao.GetContestTeamDao().GetContestTeamUserIds(ctx, id, userId)
+		if err != nil {
+			return nil, err
+		}
+		participated = len(teamUserIds) > 0
+	}
+	if participated {
+		return nil, metaerror.NewCode(weberrorcode.ContestVirtualParticipated)
+	}
+	virtual, err := foundationdao.GetContestVirtualDao().GetContestVirtual(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	if virtual != nil {
+		return nil, metaerror.NewCode(weberrorcode.ContestVirtualExist)
+	}
+	virtual = foundationmodel.NewContestVirtualBuilder().
+		ContestId(id).
+		UserId(userId).
+		StartTime(nowTime).
+		EndTime(nowTime.Add(endTime.Sub(*startTime))).
+		InsertTime(nowTime).
+		Build()
+	if err := foundationdao.GetContestVirtualDao().InsertContestVirtual(ctx, virtual); err != nil {
+		return nil, err
+	}
+	return virtual, nil
+}
+
+func (s *ContestService) GetContestVirtual(
+	ctx context.Context,
+	id int,
+	userId int,
+) (*foundationmodel.ContestVirtual, error) {
+	return foundationdao.GetContestVirtualDao().GetContestVirtual(ctx, id, userId)
+}
+
+// GetActiveContestVirtualId 用户正在进行中的虚拟参赛记录Id，没有时返回0
+func (s *ContestService) GetActiveContestVirtualId(
+	ctx context.Context,
+	id int,
+	userId int,
+	nowTime time.Time,
+) (int, error) {
+	virtual, err := foundationdao.GetContestVirtualDao().GetContestVirtual(ctx, id, userId)
+	if err != nil {
+		return 0, err
+	}
+	if virtual == nil || nowTime.Before(virtual.StartTime) || !nowTime.Before(virtual.EndTime) {
+		return 0, nil
+	}
+	return virtual.Id, nil
+}
+
+// GetContestVirtualRanks 计算合并虚拟参赛的榜单，不经过榜单缓存
+// 当前用户正在虚拟参赛时，所有参赛者都只计算到与其相同的比赛进行时间，elapsed为该时间
+// 虚拟榜单在比赛结束后才能查看，除非比赛结束后仍保持锁榜，否则不锁榜
+func (s *ContestService) GetContestVirtualRanks(
+	ctx context.Context,
+	id int,
+	userId int,
+	nowTime time.Time,
+	filter foundationenum.ContestRankFilter,
+) (
+	contest *foundationview.ContestRankDetail,
+	ranks []*foundationview.ContestRank,
+	elapsed *time.Duration,
+	err error,
+) {
+	contest, problems, option, err := loadContestRankBase(ctx, id)
+	if err != nil || contest == nil {
+		return nil, nil, nil, err
+	}
+	if userId > 0 {
+		virtual, err := foundationdao.GetContestVirtualDao().GetContestVirtual(ctx, id, userId)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if virtual != nil && !nowTime.Before(virtual.StartTime) && nowTime.Before(virtual.EndTime) {
+			d := nowTime.Sub(virtual.StartTime)
+			elapsed = &d
+		}
+	}
+	if !contest.AlwaysLock {
+		option.LockTime = nil
+	}
+
+	var submissions []*foundationview.ContestRankSubmission
+	if filter != foundationenum.ContestRankFilterVirtual {
+		endTime := contest.EndTime
+		if elapsed != nil {
+			endTime = contest.StartTime.Add(*elapsed)
+		}
+		submissions, err = foundationdao.GetJudgeJobDao().GetContestRankSubmissions(ctx, id, contest.StartTime, endTime)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if filter != foundationenum.ContestRankFilterReal {
+		virtualSubmissions, err := foundationdao.GetJudgeJobDao().GetContestVirtualRankSubmissions(
+			ctx,
+			id,
+			contest.StartTime,
+			elapsed,
+		)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		submissions = append(submissions, virtualSubmissions...)
+	}
+
+	var userIds []int
+	userIdSet := make(map[int]bool)
+	for _, submission := range submissions {
+		if !userIdSet[submission.Inserter] {
+			userIdSet[submission.Inserter] = true
+			userIds = append(userIds, submission.Inserter)
+		}
+	}
+	users, err := foundationdao.GetJudgeJobDao().GetContestRankUsersByIds(ctx, userIds)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ranks = ComputeContestStandings(option, problems, users, submissions)
+	return contest, ranks, elapsed, nil
+}
@@ -0,0 +1,46 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	metaerror "meta/meta-error"
+	"time"
+	weberrorcode "web/error-code"
+)
+
+// StartContestWindow 在比赛开放时间内开始个人比赛窗口，个人时长从开始时刻计算，且不超过比赛结束时间
+// 已开始时返回原有的开始时间
+func (s *ContestService) StartContestWindow(
+	ctx context.Context,
+	id int,
+	userId int,
+	nowTime time.Time,
+) (*time.Time, error) {
+	contest, err := foundationdao.GetContestDao().GetContestViewLock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if contest == nil || contest.PersonalDuration == nil {
+		return nil, metaerror.NewCode(weberrorcode.ContestWindowNotSupport)
+	}
+	startTime, err := foundationdao.GetContestMemberDao().GetStartTime(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	if startTime != nil {
+		return startTime, nil
+	}
+	if nowTime.Before(contest.StartTime) || !nowTime.Before(contest.EndTime) {
+		return nil, metaerror.NewCode(weberrorcode.ContestWindowNotOpen)
+	}
+	return foundationdao.GetContestMemberDao().StartContestMember(ctx, id, userId, nowTime)
+}
+
+// GetContestWindowStartTime 用户个人比赛窗口的开始时间，未开始时返回nil
+func (s *ContestService) GetContestWindowStartTime(
+	ctx context.Context,
+	id int,
+	userId int,
+) (*time.Time, error) {
+	return foundationdao.GetContestMemberDao().GetStartTime(ctx, id, userId)
+}
@@ -179,6 +179,41 @@ func (s *JudgeService) GetJudgeList(
 		}
 	}
 
+	nowTime := metatime.GetTimeNow()
+
+	var contest *foundationview.ContestViewLock
+	hasAuth := false
+	if contestId > 0 {
+		contest, err = foundationdao.GetContestDao().GetContestViewLock(ctx, contestId)
+		if err != nil {
+			return nil, -1, err
+		}
+		if contest == nil {
+			return nil, -1, nil
+		}
+		hasAuth, err = GetUserService().CheckUserAuthsByUserId(
+			ctx,
+			userId,
+			[]foundationauth.AuthType{foundationauth.AuthTypeManageJudge, foundationauth.AuthTypeManageContest},
+		)
+		if err != nil {
+			return nil, -1, err
+		}
+		if !hasAuth {
+			hasAuth, err = foundationdao.GetContestDao().CheckContestEditAuth(ctx, contestId, userId)
+			if err != nil {
+				return nil, -1, err
+			}
+		}
+		// 个人比赛窗口的比赛结束前，成员的开始时间各不相同，只能查看自己的提交
+		if !hasAuth && contest.PersonalDuration != nil && nowTime.Before(contest.EndTime) {
+			if userId <= 0 || (searchUserId > 0 && searchUserId != userId) {
+				return nil, contest.Inserter, nil
+			}
+			searchUserId = userId
+		}
+	}
+
 	judgeJobs, err := foundationdao.GetJudgeJobDao().GetJudgeJobList(
 		ctx,
 		contestId,
@@ -193,34 +228,11 @@ func (s *JudgeService) GetJudgeList(
 		return nil, -1, err
 	}
 
-	nowTime := metatime.GetTimeNow()
-
 	contestInserter := -1
 
 	if len(judgeJobs) > 0 {
 		if contestId > 0 {
-			contest, err := foundationdao.GetContestDao().GetContestViewLock(ctx, contestId)
-			if err != nil {
-				return nil, -1, err
-			}
-			if contest == nil {
-				return nil, -1, nil
-			}
 			contestInserter = contest.Inserter
-			hasAuth, err := GetUserService().CheckUserAuthsByUserId(
-				ctx,
-				userId,
-				[]foundationauth.AuthType{foundationauth.AuthTypeManageJudge, foundationauth.AuthTypeManageContest},
-			)
-			if err != nil {
-				return nil, -1, err
-			}
-			if !hasAuth {
-				hasAuth, err = foundationdao.GetContestDao().CheckContestEditAuth(ctx, contestId, userId)
-				if err != nil {
-					return nil, -1, err
-				}
-			}
 			if !hasAuth {
 				for _, judgeJob := range judgeJobs {
 					judgeJob.ProblemId = 0
@@ -326,6 +338,11 @@ func (s *JudgeService) isContestJudgeHasViewAuth(
 		}
 		hasTaskAuth = false
 	}
+	// 个人比赛窗口的比赛结束前不能查看他人的评测结果
+	if contest.PersonalDuration != nil && !isEnd && authorId != userId {
+		hasStatusAuth = false
+		hasTaskAuth = false
+	}
 	if authorId != userId {
 		if isPrivate {
 			hasDetailAuth = false
@@ -400,6 +400,40 @@ func (s *ProblemService) UpdateProblem(
 	return foundationdao.GetProblemDao().UpdateProblem(ctx, problemId, problem, tags)
 }
 
+// getJudgeDataProgramLanguage 判题数据中特判等程序的语言，文件名不匹配时返回空
+func getJudgeDataProgramLanguage(fileName string, name string) string {
+	switch fileName {
+	case name + ".c":
+		return "c"
+	case name + ".cc", name + ".cpp":
+		return "cpp"
+	}
+	return ""
+}
+
+// findJudgeDataProgram 根据文件名查找判题数据中的特判、校验器或标准程序，不存在时返回nil
+func findJudgeDataProgram(unzipDir string, name string) *foundationjudge.SpecialJudgeConfig {
+	for _, fileName := range []string{name + ".c", name + ".cc", name + ".cpp"} {
+		_, err := os.Stat(path.Join(unzipDir, fileName))
+		if err == nil {
+			return &foundationjudge.SpecialJudgeConfig{
+				Language: getJudgeDataProgramLanguage(fileName, name),
+				Source:   fileName,
+			}
+		}
+	}
+	return nil
+}
+
+// isValidJudgeDataProgram 考虑编译机性能影响，暂时仅允许部分语言
+func isValidJudgeDataProgram(config *foundationjudge.SpecialJudgeConfig) bool {
+	language := foundationjudge.GetLanguageByKey(config.Language)
+	if !foundationjudge.IsValidJudgeLanguage(int(language)) {
+		return false
+	}
+	return foundationjudge.IsValidSpecialJudgeLanguage(language)
+}
+
 func (s *ProblemService) PostJudgeData(
 	ctx context.Context,
 	problemId int,
@@ -446,6 +480,11 @@ func (s *ProblemService) PostJudgeData(
 			if info.Name() == "spj.c" || info.Name() == "spj.cc" || info.Name() == "spj.cpp" {
 				return nil
 			}
+			// 比赛挑战使用的输入校验器与标准程序
+			if getJudgeDataProgramLanguage(info.Name(), "validator") != "" ||
+				getJudgeDataProgramLanguage(info.Name(), "std") != "" {
+				return nil
+			}
 			return metaerror.New("<UNK>: " + path + " is not a valid judge data file")
 		},
 	)
@@ -472,37 +511,27 @@ func (s *ProblemService) PostJudgeData(
 	}
 
 	if jobConfig.SpecialJudge == nil {
-		specialFiles := map[string]string{
-			"spj.c":   "c",
-			"spj.cc":  "cpp",
-			"spj.cpp": "cpp",
-		}
-		// 判断是否存在对应文件
-		for fileName, language := range specialFiles {
-			filePath := path.Join(unzipDir, fileName)
-			_, err := os.Stat(filePath)
-			if err == nil {
-				jobConfig.SpecialJudge = &foundationjudge.SpecialJudgeConfig{}
-				jobConfig.SpecialJudge.Language = language
-				jobConfig.SpecialJudge.Source = fileName
-				break
-			}
-		}
+		jobConfig.SpecialJudge = findJudgeDataProgram(unzipDir, "spj")
+	}
+	if jobConfig.Validator == nil {
+		jobConfig.Validator = findJudgeDataProgram(unzipDir, "validator")
+	}
+	if jobConfig.Standard == nil {
+		jobConfig.Standard = findJudgeDataProgram(unzipDir, "std")
 	}
 
 	if jobConfig.SpecialJudge != nil {
-
-		language := foundationjudge.GetLanguageByKey(jobConfig.SpecialJudge.Language)
-		if !foundationjudge.IsValidJudgeLanguage(int(language)) {
-			return metaerror.NewCode(weberrorcode.ProblemJudgeDataSpjLanguageNotValid)
-		}
-
-		// 考虑编译机性能影响，暂时仅允许部分语言
-		if !foundationjudge.IsValidSpecialJudgeLanguage(language) {
+		if !isValidJudgeDataProgram(jobConfig.SpecialJudge) {
 			return metaerror.NewCode(weberrorcode.ProblemJudgeDataSpjLanguageNotValid)
 		}
 		judgeType = foundationjudge.JudgeTypeSpecial
 	}
+	if jobConfig.Validator != nil && !isValidJudgeDataProgram(jobConfig.Validator) {
+		return metaerror.NewCode(weberrorcode.ProblemJudgeDataSpjLanguageNotValid)
+	}
+	if jobConfig.Standard != nil && !isValidJudgeDataProgram(jobConfig.Standard) {
+		return metaerror.NewCode(weberrorcode.ProblemJudgeDataSpjLanguageNotValid)
+	}
 
 	if len(jobConfig.Tasks) <= 0 {
 		// 如果没有rule.yaml文件，则根据文件生成Config信息
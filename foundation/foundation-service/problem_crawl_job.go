@@ -0,0 +1,85 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	metatime "meta/meta-time"
+	"meta/set"
+	"meta/singleton"
+)
+
+type ProblemCrawlJobService struct {
+}
+
+var singletonProblemCrawlJobService = singleton.Singleton[ProblemCrawlJobService]{}
+
+func GetProblemCrawlJobService() *ProblemCrawlJobService {
+	return singletonProblemCrawlJobService.GetInstance(
+		func() *ProblemCrawlJobService {
+			return &ProblemCrawlJobService{}
+		},
+	)
+}
+
+// InsertProblemCrawlJob 创建批量爬取任务，已经存在的远程题目直接标记为跳过
+func (s *ProblemCrawlJobService) InsertProblemCrawlJob(
+	ctx context.Context,
+	userId int,
+	originOj string,
+	originIds []string,
+) (*foundationmodel.ProblemCrawlJob, error) {
+	existIds, err := foundationdao.GetProblemDao().GetProblemRemoteOriginIds(ctx, originOj, originIds)
+	if err != nil {
+		return nil, err
+	}
+	existIdSet := set.FromSlice(existIds)
+
+	nowTime := metatime.GetTimeNow()
+	items := make([]*foundationmodel.ProblemCrawlJobItem, 0, len(originIds))
+	for _, originId := range originIds {
+		status := foundationenum.ProblemCrawlStatusInit
+		if existIdSet.Contains(originId) {
+			status = foundationenum.ProblemCrawlStatusSkip
+		}
+		items = append(
+			items, foundationmodel.NewProblemCrawlJobItemBuilder().
+				OriginId(originId).
+				Status(status).
+				ModifyTime(nowTime).
+				Build(),
+		)
+	}
+	job := foundationmodel.NewProblemCrawlJobBuilder().
+		OriginOj(originOj).
+		Total(len(items)).
+		Inserter(userId).
+		InsertTime(nowTime).
+		Build()
+	err = foundationdao.GetProblemCrawlJobDao().InsertProblemCrawlJob(ctx, job, items)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *ProblemCrawlJobService) GetProblemCrawlJob(
+	ctx context.Context,
+	id int,
+	statuses []foundationenum.ProblemCrawlStatus,
+) (*foundationview.ProblemCrawlJob, error) {
+	job, err := foundationdao.GetProblemCrawlJobDao().GetProblemCrawlJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, nil
+	}
+	job.Items, err = foundationdao.GetProblemCrawlJobDao().GetProblemCrawlJobItems(ctx, id, statuses)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
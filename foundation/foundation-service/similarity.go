@@ -0,0 +1,214 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationsimilarity "foundation/foundation-similarity"
+	foundationview "foundation/foundation-view"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	metatime "meta/meta-time"
+	"meta/singleton"
+	"time"
+)
+
+const (
+	// 至少有这么多份代码时，才把超过半数代码都出现的片段当作模板忽略
+	similarityTemplateMinCount = 4
+	// 一次检测最多比较的代码份数，两两比较的耗时随份数平方增长
+	similarityMaxSubmissions = 2000
+	// 结束报告使用独立的超时，检测被取消时也能写入结果
+	similarityFinishTimeout = 30 * time.Second
+)
+
+type SimilarityService struct {
+}
+
+var singletonSimilarityService = singleton.Singleton[SimilarityService]{}
+
+func GetSimilarityService() *SimilarityService {
+	return singletonSimilarityService.GetInstance(
+		func() *SimilarityService {
+			return &SimilarityService{}
+		},
+	)
+}
+
+func (s *SimilarityService) InsertSimilarityReport(
+	ctx context.Context,
+	userId int,
+	scopeType foundationenum.SimilarityScopeType,
+	scopeId int,
+	threshold int,
+) (*foundationmodel.SimilarityReport, error) {
+	report := foundationmodel.NewSimilarityReportBuilder().
+		ScopeType(scopeType).
+		ScopeId(scopeId).
+		Threshold(threshold).
+		Inserter(userId).
+		InsertTime(metatime.GetTimeNow()).
+		Build()
+	if err := foundationdao.GetSimilarityDao().InsertSimilarityReport(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *SimilarityService) GetSimilarityReport(ctx context.Context, id int) (*foundationview.SimilarityReport, error) {
+	return foundationdao.GetSimilarityDao().GetSimilarityReport(ctx, id)
+}
+
+func (s *SimilarityService) GetSimilarityReports(
+	ctx context.Context,
+	scopeType foundationenum.SimilarityScopeType,
+	scopeId int,
+) ([]*foundationview.SimilarityReport, error) {
+	return foundationdao.GetSimilarityDao().GetSimilarityReports(ctx, scopeType, scopeId)
+}
+
+func (s *SimilarityService) GetSimilarityPairs(ctx context.Context, reportId int) ([]*foundationview.SimilarityPair, error) {
+	return foundationdao.GetSimilarityDao().GetSimilarityPairs(ctx, reportId)
+}
+
+func (s *SimilarityService) GetSimilarityPairDetail(
+	ctx context.Context,
+	id int,
+) (*foundationview.SimilarityPairDetail, error) {
+	return foundationdao.GetSimilarityDao().GetSimilarityPairDetail(ctx, id)
+}
+
+// ProcessSimilarityReport 领取一个待检测的报告并执行，没有报告时返回false
+func (s *SimilarityService) ProcessSimilarityReport(ctx context.Context) (bool, error) {
+	report, err := foundationdao.GetSimilarityDao().RequestSimilarityReportPending(ctx)
+	if err != nil {
+		return false, err
+	}
+	if report == nil {
+		return false, nil
+	}
+	submissions, pairs, err := s.compareSubmissions(ctx, report)
+	finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), similarityFinishTimeout)
+	defer cancel()
+	if err != nil {
+		// 检测失败也要结束报告，避免一直占用队列
+		metapanic.ProcessError(err)
+		message := err.Error()
+		err = foundationdao.GetSimilarityDao().FinishSimilarityReport(
+			finishCtx,
+			report.Id,
+			foundationenum.SimilarityReportStatusFailed,
+			0,
+			nil,
+			&message,
+		)
+		return true, err
+	}
+	err = foundationdao.GetSimilarityDao().FinishSimilarityReport(
+		finishCtx,
+		report.Id,
+		foundationenum.SimilarityReportStatusFinished,
+		submissions,
+		pairs,
+		nil,
+	)
+	return true, err
+}
+
+func (s *SimilarityService) compareSubmissions(
+	ctx context.Context,
+	report *foundationmodel.SimilarityReport,
+) (int, []*foundationmodel.SimilarityPair, error) {
+	submissions, err := foundationdao.GetJudgeJobDao().GetSimilaritySubmissions(
+		ctx,
+		report.ScopeType,
+		report.ScopeId,
+		similarityMaxSubmissions+1,
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(submissions) > similarityMaxSubmissions {
+		return 0, nil, metaerror.New("too many submissions, limit:%d", similarityMaxSubmissions)
+	}
+	var problemIds []int
+	problemSubmissions := make(map[int][]*foundationview.SimilaritySubmission)
+	for _, submission := range submissions {
+		if _, ok := problemSubmissions[submission.ProblemId]; !ok {
+			problemIds = append(problemIds, submission.ProblemId)
+		}
+		problemSubmissions[submission.ProblemId] = append(problemSubmissions[submission.ProblemId], submission)
+	}
+	threshold := float64(report.Threshold) / 100
+	var pairs []*foundationmodel.SimilarityPair
+	for _, problemId := range problemIds {
+		if err := ctx.Err(); err != nil {
+			return 0, nil, metaerror.Wrap(err, "similarity report canceled, id:%d", report.Id)
+		}
+		items := problemSubmissions[problemId]
+		documents := make([]*foundationsimilarity.Document, len(items))
+		for i, item := range items {
+			documents[i] = foundationsimilarity.NewDocument(item.Language, item.Code)
+		}
+		ignore := getSimilarityTemplateHashes(documents)
+		for i := 0; i < len(items); i++ {
+			if err := ctx.Err(); err != nil {
+				return 0, nil, metaerror.Wrap(err, "similarity report canceled, id:%d", report.Id)
+			}
+			for j := i + 1; j < len(items); j++ {
+				similarity, regions := foundationsimilarity.Compare(documents[i], documents[j], ignore)
+				if similarity < threshold {
+					continue
+				}
+				left, right := items[i], items[j]
+				leftRegions := regions
+				if right.InsertTime.Before(left.InsertTime) {
+					left, right = right, left
+					leftRegions = make([]*foundationsimilarity.Region, len(regions))
+					for k, region := range regions {
+						leftRegions[k] = &foundationsimilarity.Region{
+							LeftStart:  region.RightStart,
+							LeftEnd:    region.RightEnd,
+							RightStart: region.LeftStart,
+							RightEnd:   region.LeftEnd,
+						}
+					}
+				}
+				pairs = append(
+					pairs, &foundationmodel.SimilarityPair{
+						ReportId:     report.Id,
+						ProblemId:    problemId,
+						LeftJudgeId:  left.Id,
+						LeftUserId:   left.Inserter,
+						RightJudgeId: right.Id,
+						RightUserId:  right.Inserter,
+						Similarity:   similarity,
+						Regions:      leftRegions,
+					},
+				)
+			}
+		}
+	}
+	return len(submissions), pairs, nil
+}
+
+// getSimilarityTemplateHashes 超过半数代码都包含的指纹视为模板代码，不计入相似度
+func getSimilarityTemplateHashes(documents []*foundationsimilarity.Document) map[uint64]bool {
+	if len(documents) < similarityTemplateMinCount {
+		return nil
+	}
+	counts := make(map[uint64]int)
+	for _, document := range documents {
+		for hash := range document.GetHashes() {
+			counts[hash]++
+		}
+	}
+	ignore := make(map[uint64]bool)
+	for hash, count := range counts {
+		if count*2 > len(documents) {
+			ignore[hash] = true
+		}
+	}
+	return ignore
+}
@@ -0,0 +1,67 @@
+package foundationservice
+
+import (
+	foundationjudge "foundation/foundation-judge"
+	foundationsimilarity "foundation/foundation-similarity"
+	"testing"
+)
+
+// TestGetSimilarityTemplateHashes 测试模板代码的判断阈值
+func TestGetSimilarityTemplateHashes(t *testing.T) {
+	// 模板与其他代码结构不同，能产生独立的指纹
+	const template = `int main() {
+    int n;
+    scanf("%d", &n);
+    for (int i = 0; i < n; i++) {
+        printf("%d\n", i);
+    }
+    return 0;
+}`
+	const other = `long long gcd(long long a, long long b) {
+    while (b) {
+        long long r = a % b;
+        a = b;
+        b = r;
+    }
+    return a;
+}`
+	cases := []struct {
+		withTemplate int // 包含模板的代码份数
+		total        int
+		expected     bool // 模板的指纹是否被忽略
+		name         string
+	}{
+		{3, 3, false, "代码少于4份时不忽略"},
+		{3, 4, true, "超过半数包含模板时忽略"},
+		{2, 4, false, "恰好半数包含模板时不忽略"},
+		{6, 10, true, "10份中6份包含模板时忽略"},
+		{5, 10, false, "10份中5份包含模板时不忽略"},
+	}
+
+	templateHashes := foundationsimilarity.NewDocument(foundationjudge.JudgeLanguageCpp, template).GetHashes()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			documents := make([]*foundationsimilarity.Document, tc.total)
+			for i := range documents {
+				code := other
+				if i < tc.withTemplate {
+					code = template + "\n" + other
+				}
+				documents[i] = foundationsimilarity.NewDocument(foundationjudge.JudgeLanguageCpp, code)
+			}
+			ignore := getSimilarityTemplateHashes(documents)
+			ignored := 0
+			for hash := range templateHashes {
+				if ignore[hash] {
+					ignored++
+				}
+			}
+			if result := ignored > 0; result != tc.expected {
+				t.Errorf(
+					"getSimilarityTemplateHashes(%d/%d) ignored %d of %d template hashes; want ignored = %v",
+					tc.withTemplate, tc.total, ignored, len(templateHashes), tc.expected,
+				)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,15 +19,26 @@ import (
 	foundationauth "foundation/foundation-auth"
 	foundationconfig "foundation/foundation-config"
 	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
 	foundationmodel "foundation/foundation-model"
 	foundationrequest "foundation/foundation-request"
 	foundationuser "foundation/foundation-user"
 	foundationview "foundation/foundation-view"
 	metaerror "meta/meta-error"
 	"meta/singleton"
+	weberrorcode "web/error-code"
 )
 
+// 禁用状态的缓存时间，令牌有效期内被禁用的账号最迟在这段时间后失效
+const userDisabledCacheDuration = 30 * time.Second
+
+type userDisabledCacheItem struct {
+	disabled   bool
+	expireTime time.Time
+}
+
 type UserService struct {
+	disabledCache sync.Map // userId -> *userDisabledCacheItem
 }
 
 var singletonUserService = singleton.Singleton[UserService]{}
@@ -39,6 +51,37 @@ func GetUserService() *UserService {
 	)
 }
 
+// IsUserDisabled 校验令牌时检查账号是否被禁用，结果缓存一小段时间，避免每个请求都查询数据库
+func (s *UserService) IsUserDisabled(ctx context.Context, userId int, nowTime time.Time) (bool, error) {
+	if value, ok := s.disabledCache.Load(userId); ok {
+		item := value.(*userDisabledCacheItem)
+		if nowTime.Before(item.expireTime) {
+			return item.disabled, nil
+		}
+	}
+	disabled, err := foundationdao.GetUserDao().IsUserDisabled(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	s.disabledCache.Store(
+		userId, &userDisabledCacheItem{
+			disabled:   disabled,
+			expireTime: nowTime.Add(userDisabledCacheDuration),
+		},
+	)
+	return disabled, nil
+}
+
+// clearUserDisabledCache 批量修改禁用状态后清空缓存，使本实例立即生效
+func (s *UserService) clearUserDisabledCache() {
+	s.disabledCache.Range(
+		func(key, _ any) bool {
+			s.disabledCache.Delete(key)
+			return true
+		},
+	)
+}
+
 func (s *UserService) GetModifyInfo(ctx context.Context, userId int) (*foundationview.UserModifyInfo, error) {
 	return foundationdao.GetUserDao().GetModifyInfo(ctx, userId)
 }
@@ -59,11 +102,15 @@ func (s *UserService) GetUserLoginResponse(
 	if resultUser == nil {
 		return nil, nil
 	}
-	token, err := s.GetTokenByUserId(resultUser.Id, nowTime, foundationconfig.GetJwtSecret())
+	if resultUser.Disabled {
+		return nil, metaerror.NewCode(weberrorcode.UserDisabled)
+	}
+	token, session, err := s.GetTokenByUserId(resultUser.Id, nowTime, foundationconfig.GetJwtSecret())
 	if err != nil {
 		return nil, err
 	}
 	resultUser.Token = token
+	resultUser.Session = session
 	resultUser.Roles, err = foundationdao.GetUserRoleDao().GetUserRoles(ctx, resultUser.Id)
 	if err != nil {
 		return nil, err
@@ -173,24 +220,35 @@ func (s *UserService) Login(ctx *gin.Context, username string, password string,
 			return nil, nil
 		}
 	}
+	// 密码正确后才提示账号被禁用，避免借此探测账号
+	if resultUser.Disabled {
+		return nil, metaerror.NewCode(weberrorcode.UserDisabled)
+	}
 	resultUser.Roles, err = foundationdao.GetUserRoleDao().GetUserRoles(ctx, resultUser.Id)
 	if err != nil {
 		return nil, err
 	}
-	token, err := s.GetTokenByUserId(resultUser.Id, nowTime, foundationconfig.GetJwtSecret())
+	token, session, err := s.GetTokenByUserId(resultUser.Id, nowTime, foundationconfig.GetJwtSecret())
 	if err != nil {
 		return nil, err
 	}
 	resultUser.Token = token
+	resultUser.Session = session
 	return resultUser, nil
 }
 
-func (s *UserService) GetTokenByUserId(userId int, nowTime time.Time, secret []byte) (*string, error) {
-	token, err := foundationauth.GetToken(userId, nowTime, secret)
+// GetTokenByUserId 签发令牌，每次签发生成新的会话标识
+func (s *UserService) GetTokenByUserId(userId int, nowTime time.Time, secret []byte) (*string, string, error) {
+	sessionBytes := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, sessionBytes); err != nil {
+		return nil, "", metaerror.Wrap(err, "failed to generate session")
+	}
+	session := hex.EncodeToString(sessionBytes)
+	token, err := foundationauth.GetToken(userId, session, nowTime, secret)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return token, nil
+	return token, session, nil
 }
 
 func (s *UserService) CheckUserAuth(ctx *gin.Context, auth foundationauth.AuthType) (int, bool, error) {
@@ -266,6 +324,14 @@ func (s *UserService) GetRankAcAll(ctx *gin.Context, page int, pageSize int) ([]
 	return foundationdao.GetUserDao().GetRankAcAll(ctx, page, pageSize)
 }
 
+func (s *UserService) GetRankRating(ctx *gin.Context, page int, pageSize int) ([]*foundationview.UserRank, int, error) {
+	return foundationdao.GetUserDao().GetRankRating(ctx, page, pageSize)
+}
+
+func (s *UserService) GetUserRatingHistory(ctx context.Context, userId int) ([]*foundationview.UserRatingHistory, error) {
+	return foundationdao.GetContestRatingDao().GetUserRatingHistory(ctx, userId)
+}
+
 func (s *UserService) FilterValidUserIds(ctx *gin.Context, userIds []int) ([]int, error) {
 	return foundationdao.GetUserDao().FilterValidUserIds(ctx, userIds)
 }
@@ -279,8 +345,15 @@ func (s *UserService) UpdateUserInfo(
 	return foundationdao.GetUserDao().UpdateUserInfo(ctx, userId, r, modifyTime)
 }
 
-func (s *UserService) UpdateUserVjudgeUsername(ctx *gin.Context, userId int, vjudgeId string, now time.Time) error {
-	return foundationdao.GetUserDao().UpdateUserVjudgeUsername(ctx, userId, vjudgeId, now)
+// UpdateUserExternalAccount 绑定验证过归属的外部账号，account为空时解绑
+func (s *UserService) UpdateUserExternalAccount(
+	ctx context.Context,
+	userId int,
+	source foundationenum.UserExternalSource,
+	account string,
+	now time.Time,
+) error {
+	return foundationdao.GetUserExternalSolveDao().UpdateUserExternalAccount(ctx, userId, source, account, now)
 }
 
 func (s *UserService) UpdateUserPassword(
@@ -363,8 +436,15 @@ func (s *UserService) AddExperienceForCheckIn(ctx context.Context, userId int, n
 	return award, nil
 }
 
-func (s *UserService) PostLoginLog(ctx context.Context, userId int, nowTime time.Time, ip string, agent string) error {
-	return foundationdao.GetUserDao().PostLoginLog(ctx, userId, nowTime, ip, agent)
+func (s *UserService) PostLoginLog(
+	ctx context.Context,
+	userId int,
+	nowTime time.Time,
+	ip string,
+	agent string,
+	session string,
+) error {
+	return foundationdao.GetUserDao().PostLoginLog(ctx, userId, nowTime, ip, agent, session)
 }
 
 // GetCheckinCount 获取指定日期的签到人数
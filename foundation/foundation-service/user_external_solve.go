@@ -0,0 +1,108 @@
+package foundationservice
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	foundationremote "foundation/foundation-remote"
+	foundationview "foundation/foundation-view"
+	metatime "meta/meta-time"
+	"meta/singleton"
+	"time"
+)
+
+type UserExternalSolveService struct {
+}
+
+var singletonUserExternalSolveService = singleton.Singleton[UserExternalSolveService]{}
+
+func GetUserExternalSolveService() *UserExternalSolveService {
+	return singletonUserExternalSolveService.GetInstance(
+		func() *UserExternalSolveService {
+			return &UserExternalSolveService{}
+		},
+	)
+}
+
+func (s *UserExternalSolveService) GetUserExternalSyncPending(
+	ctx context.Context,
+	source foundationenum.UserExternalSource,
+	before time.Time,
+	limit int,
+) ([]*foundationview.UserExternalSyncPending, error) {
+	return foundationdao.GetUserExternalSolveDao().GetUserExternalSyncPending(ctx, source, before, limit)
+}
+
+// SyncUserExternal 同步一个外部账号的AC记录，账号已解绑时清除之前的记录
+func (s *UserExternalSolveService) SyncUserExternal(
+	ctx context.Context,
+	pending *foundationview.UserExternalSyncPending,
+) error {
+	solveDao := foundationdao.GetUserExternalSolveDao()
+	if pending.Account == nil {
+		return solveDao.DeleteUserExternalSync(ctx, pending.UserId, pending.Source)
+	}
+	account := *pending.Account
+	nowTime := metatime.GetTimeNow()
+
+	remoteSolves, err := foundationremote.GetRemoteExternalSolveAgent().GetAcProblems(ctx, pending.Source, account)
+	if err != nil {
+		// 记录失败原因，等待下次同步时重试
+		return solveDao.MarkUserExternalSyncFail(ctx, pending.UserId, pending.Source, account, err.Error(), nowTime)
+	}
+
+	type solveKey struct {
+		originOj string
+		originId string
+	}
+	solveMap := make(map[solveKey]struct{}, len(remoteSolves))
+	solves := make([]*foundationmodel.UserExternalSolve, 0, len(remoteSolves))
+	for _, remoteSolve := range remoteSolves {
+		// 超出字段长度的题目无法对应到本站，直接忽略
+		if remoteSolve.OriginOj == "" || remoteSolve.OriginId == "" ||
+			len(remoteSolve.OriginOj) > 20 || len(remoteSolve.OriginId) > 20 {
+			continue
+		}
+		key := solveKey{originOj: remoteSolve.OriginOj, originId: remoteSolve.OriginId}
+		if _, ok := solveMap[key]; ok {
+			continue
+		}
+		solveMap[key] = struct{}{}
+		solves = append(
+			solves, foundationmodel.NewUserExternalSolveBuilder().
+				UserId(pending.UserId).
+				Source(pending.Source).
+				OriginOj(remoteSolve.OriginOj).
+				OriginId(remoteSolve.OriginId).
+				SolveTime(remoteSolve.SolveTime).
+				InsertTime(nowTime).
+				Build(),
+		)
+	}
+	return solveDao.ReplaceUserExternalSolves(ctx, pending.UserId, pending.Source, account, solves, nowTime)
+}
+
+func (s *UserExternalSolveService) GetUserExternalSolves(ctx context.Context, userId int) (
+	[]*foundationmodel.UserExternalSync,
+	[]*foundationview.UserExternalSolve,
+	error,
+) {
+	syncs, err := foundationdao.GetUserExternalSolveDao().GetUserExternalSyncs(ctx, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	solves, err := foundationdao.GetUserExternalSolveDao().GetUserExternalSolves(ctx, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return syncs, solves, nil
+}
+
+func (s *UserExternalSolveService) GetRankExternalSolve(
+	ctx context.Context,
+	page int,
+	pageSize int,
+) ([]*foundationview.UserRank, int, error) {
+	return foundationdao.GetUserExternalSolveDao().GetRankExternalSolve(ctx, page, pageSize)
+}
@@ -0,0 +1,292 @@
+package foundationsimilarity
+
+import (
+	foundationjudge "foundation/foundation-judge"
+	"strings"
+	"unicode"
+)
+
+// Token 归一化后的词法单元，标识符、数字与字符串只保留类别，以忽略改名与改常量
+type Token struct {
+	Text string
+	Line int // 从1开始的行号，用于标记相似区域
+}
+
+const (
+	tokenIdentifier = "$id"
+	tokenNumber     = "$num"
+	tokenString     = "$str"
+)
+
+type languageProfile struct {
+	lineComments    []string
+	blockComments   [][2]string
+	quotes          string
+	tripleQuotes    bool // Python的多行字符串
+	preprocessor    bool // C/C++忽略预处理指令，头文件在所有代码中都差不多
+	caseInsensitive bool
+	keywords        map[string]bool
+}
+
+func newKeywords(words string) map[string]bool {
+	keywords := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		keywords[word] = true
+	}
+	return keywords
+}
+
+var (
+	keywordsC = `auto break case char const continue default do double else enum extern float for goto if
+		inline int long register return short signed sizeof static struct switch typedef union unsigned void
+		volatile while bool true false`
+	keywordsCpp = keywordsC + ` class namespace using template typename public private protected virtual
+		operator new delete this friend const_cast static_cast dynamic_cast reinterpret_cast try catch throw
+		nullptr constexpr auto decltype mutable explicit noexcept`
+)
+
+var (
+	profileC = &languageProfile{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"'`,
+		preprocessor:  true,
+		keywords:      newKeywords(keywordsC),
+	}
+	profileCpp = &languageProfile{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"'`,
+		preprocessor:  true,
+		keywords:      newKeywords(keywordsCpp),
+	}
+	profileJava = &languageProfile{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"'`,
+		keywords: newKeywords(
+			`abstract boolean break byte case catch char class const continue default do double else enum
+			extends final finally float for if implements import instanceof int interface long new package
+			private protected public return short static super switch synchronized this throw throws try void
+			volatile while var true false null`,
+		),
+	}
+	profilePython = &languageProfile{
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+		tripleQuotes: true,
+		keywords: newKeywords(
+			`and as assert break class continue def del elif else except finally for from global if import in
+			is lambda nonlocal not or pass raise return try while with yield True False None print range len
+			input int str list dict set`,
+		),
+	}
+	profilePascal = &languageProfile{
+		lineComments:    []string{"//"},
+		blockComments:   [][2]string{{"{", "}"}, {"(*", "*)"}},
+		quotes:          `'`,
+		caseInsensitive: true,
+		keywords: newKeywords(
+			`and array begin case const div do downto else end file for function goto if in label mod nil not
+			of or packed procedure program record repeat set then to type until var while with integer
+			longint int64 real boolean char string true false read readln write writeln`,
+		),
+	}
+	profileGolang = &languageProfile{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        "\"'`",
+		keywords: newKeywords(
+			`break case chan const continue default defer else fallthrough for func go goto if import interface
+			map package range return select struct switch type var int int64 string bool byte rune float64
+			true false nil make len append`,
+		),
+	}
+	profileLua = &languageProfile{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"--[[", "]]"}},
+		quotes:        `"'`,
+		keywords: newKeywords(
+			`and break do else elseif end false for function goto if in local nil not or repeat return then
+			true until while`,
+		),
+	}
+	profileTypeScript = &languageProfile{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        "\"'`",
+		keywords: newKeywords(
+			`break case catch class const continue default delete do else enum export extends false finally for
+			function if import in instanceof let new null return super switch this throw true try typeof var
+			void while of number string boolean any interface type`,
+		),
+	}
+	profileRust = &languageProfile{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"`,
+		keywords: newKeywords(
+			`as break const continue else enum false fn for if impl in let loop match mod move mut pub ref return
+			self Self static struct trait true type use where while i32 i64 u32 u64 usize f64 bool char String
+			Vec`,
+		),
+	}
+)
+
+func getLanguageProfile(language foundationjudge.JudgeLanguage) *languageProfile {
+	switch language {
+	case foundationjudge.JudgeLanguageC:
+		return profileC
+	case foundationjudge.JudgeLanguageJava:
+		return profileJava
+	case foundationjudge.JudgeLanguagePython:
+		return profilePython
+	case foundationjudge.JudgeLanguagePascal:
+		return profilePascal
+	case foundationjudge.JudgeLanguageGolang:
+		return profileGolang
+	case foundationjudge.JudgeLanguageLua:
+		return profileLua
+	case foundationjudge.JudgeLanguageTypeScript:
+		return profileTypeScript
+	case foundationjudge.JudgeLanguageRust:
+		return profileRust
+	default:
+		return profileCpp
+	}
+}
+
+// 按长度从长到短匹配的运算符
+var operators = []string{
+	"<<=", ">>=", "...", "->", "=>", "::", ":=", "++", "--", "==", "!=", "<=", ">=", "&&", "||", "<<", ">>",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "..",
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Tokenize 按语言去掉注释与空白，并将标识符、数字、字符串归一化
+func Tokenize(language foundationjudge.JudgeLanguage, code string) []*Token {
+	profile := getLanguageProfile(language)
+	src := []rune(strings.ReplaceAll(code, "\r\n", "\n"))
+	var tokens []*Token
+	line := 1
+	lineStart := true
+	hasPrefix := func(i int, prefix string) bool {
+		p := []rune(prefix)
+		if i+len(p) > len(src) {
+			return false
+		}
+		for j, r := range p {
+			if src[i+j] != r {
+				return false
+			}
+		}
+		return true
+	}
+	// skipUntil 跳到结束标记之后，统计跳过的换行
+	skipUntil := func(i int, end string) int {
+		for i < len(src) && !hasPrefix(i, end) {
+			if src[i] == '\n' {
+				line++
+			}
+			i++
+		}
+		return min(i+len([]rune(end)), len(src))
+	}
+	i := 0
+scan:
+	for i < len(src) {
+		r := src[i]
+		if r == '\n' {
+			line++
+			lineStart = true
+			i++
+			continue
+		}
+		if unicode.IsSpace(r) {
+			i++
+			continue
+		}
+		if profile.preprocessor && lineStart && r == '#' {
+			// 预处理指令可能以反斜杠续行
+			for i < len(src) && (src[i] != '\n' || src[i-1] == '\\') {
+				if src[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			continue
+		}
+		lineStart = false
+		for _, comment := range profile.blockComments {
+			if hasPrefix(i, comment[0]) {
+				i = skipUntil(i+len([]rune(comment[0])), comment[1])
+				continue scan
+			}
+		}
+		for _, comment := range profile.lineComments {
+			if hasPrefix(i, comment) {
+				for i < len(src) && src[i] != '\n' {
+					i++
+				}
+				continue scan
+			}
+		}
+		startLine := line
+		switch {
+		case isIdentifierStart(r):
+			start := i
+			for i < len(src) && isIdentifierPart(src[i]) {
+				i++
+			}
+			word := string(src[start:i])
+			if profile.caseInsensitive {
+				word = strings.ToLower(word)
+			}
+			if !profile.keywords[word] {
+				word = tokenIdentifier
+			}
+			tokens = append(tokens, &Token{Text: word, Line: startLine})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(src) && unicode.IsDigit(src[i+1])):
+			for i < len(src) && (isIdentifierPart(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, &Token{Text: tokenNumber, Line: startLine})
+		case strings.ContainsRune(profile.quotes, r):
+			if profile.tripleQuotes && (hasPrefix(i, `"""`) || hasPrefix(i, `'''`)) {
+				quote := string(src[i : i+3])
+				i = skipUntil(i+3, quote)
+			} else {
+				i++
+				for i < len(src) && src[i] != r && (src[i] != '\n' || r == '`') {
+					if src[i] == '\\' {
+						i++
+					}
+					if i < len(src) && src[i] == '\n' {
+						line++
+					}
+					i++
+				}
+				i = min(i+1, len(src))
+			}
+			tokens = append(tokens, &Token{Text: tokenString, Line: startLine})
+		default:
+			text := string(r)
+			for _, operator := range operators {
+				if hasPrefix(i, operator) {
+					text = operator
+					break
+				}
+			}
+			i += len([]rune(text))
+			tokens = append(tokens, &Token{Text: text, Line: startLine})
+		}
+	}
+	return tokens
+}
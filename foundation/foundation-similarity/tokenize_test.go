@@ -0,0 +1,161 @@
+package foundationsimilarity
+
+import (
+	foundationjudge "foundation/foundation-judge"
+	"reflect"
+	"testing"
+)
+
+func getTokenTexts(tokens []*Token) []string {
+	texts := make([]string, len(tokens))
+	for i, token := range tokens {
+		texts[i] = token.Text
+	}
+	return texts
+}
+
+func getTokenLines(tokens []*Token) []int {
+	lines := make([]int, len(tokens))
+	for i, token := range tokens {
+		lines[i] = token.Line
+	}
+	return lines
+}
+
+// TestTokenize 测试词法单元的归一化结果
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		language foundationjudge.JudgeLanguage
+		code     string
+		expected []string
+		name     string
+	}{
+		{
+			foundationjudge.JudgeLanguageCpp,
+			"int main() { return 0; }",
+			[]string{"int", "$id", "(", ")", "{", "return", "$num", ";", "}"},
+			"关键字保留，标识符与数字归一化",
+		},
+		{
+			foundationjudge.JudgeLanguageCpp,
+			"#include <cstdio>\n#define N \\\n 100\nint a;",
+			[]string{"int", "$id", ";"},
+			"忽略预处理指令与续行",
+		},
+		{
+			foundationjudge.JudgeLanguageCpp,
+			"a = \"x // y\"; // comment\n/* b\n c */ b = 'z';",
+			[]string{"$id", "=", "$str", ";", "$id", "=", "$str", ";"},
+			"忽略注释，字符串中的注释标记不生效",
+		},
+		{
+			foundationjudge.JudgeLanguageCpp,
+			"a <<= b->c; i++;",
+			[]string{"$id", "<<=", "$id", "->", "$id", ";", "$id", "++", ";"},
+			"运算符按最长匹配",
+		},
+		{
+			foundationjudge.JudgeLanguageCpp,
+			"x = 1.5e3 + .5;",
+			[]string{"$id", "=", "$num", "+", "$num", ";"},
+			"小数与科学计数法为一个数字",
+		},
+		{
+			foundationjudge.JudgeLanguagePascal,
+			"BEGIN WriteLn(X); End.",
+			[]string{"begin", "writeln", "(", "$id", ")", ";", "end", "."},
+			"Pascal不区分大小写",
+		},
+		{
+			foundationjudge.JudgeLanguagePascal,
+			"{ comment } (* comment *) x := 1;",
+			[]string{"$id", ":=", "$num", ";"},
+			"Pascal的两种块注释",
+		},
+		{
+			foundationjudge.JudgeLanguagePython,
+			"# comment\ndef f(x):\n    return \"\"\"a\nb\"\"\"",
+			[]string{"def", "$id", "(", "$id", ")", ":", "return", "$str"},
+			"Python的注释与三引号字符串",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := getTokenTexts(Tokenize(tc.language, tc.code))
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Tokenize(%q) = %v; want %v", tc.code, result, tc.expected)
+			}
+		})
+	}
+}
+
+// TestTokenizeEquivalent 改名、调整空白与注释后词法单元不变
+func TestTokenizeEquivalent(t *testing.T) {
+	cases := []struct {
+		language foundationjudge.JudgeLanguage
+		left     string
+		right    string
+		name     string
+	}{
+		{
+			foundationjudge.JudgeLanguageCpp,
+			"int sum = 0;\nfor (int i = 0; i < n; i++) sum += a[i];",
+			"int total = 0;\nfor (int k = 0; k < cnt; k++) total += arr[k];",
+			"修改变量名",
+		},
+		{
+			foundationjudge.JudgeLanguageCpp,
+			"int main(){int a=1;return a;}",
+			"int main ( )\r\n{\n\tint a = 1 ;\n\n\treturn a ;\n}\n",
+			"修改空白与换行",
+		},
+		{
+			foundationjudge.JudgeLanguageCpp,
+			"int a = 1; // set a\nint b = 2;",
+			"/* init */ int a = 1;\nint b = 2; // done",
+			"增删注释",
+		},
+		{
+			foundationjudge.JudgeLanguageJava,
+			"String s = \"hello\"; int x = 42;",
+			"String t = \"world\"; int y = 7;",
+			"修改常量",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			left := getTokenTexts(Tokenize(tc.language, tc.left))
+			right := getTokenTexts(Tokenize(tc.language, tc.right))
+			if !reflect.DeepEqual(left, right) {
+				t.Errorf("Tokenize(%q) = %v; want %v", tc.right, right, left)
+			}
+		})
+	}
+}
+
+// TestTokenizeLine 行号跳过注释、字符串与预处理指令中的换行
+func TestTokenizeLine(t *testing.T) {
+	cases := []struct {
+		language foundationjudge.JudgeLanguage
+		code     string
+		expected []int
+		name     string
+	}{
+		{foundationjudge.JudgeLanguageCpp, "a\n\nb\r\nc", []int{1, 3, 4}, "普通换行"},
+		{foundationjudge.JudgeLanguageCpp, "a /* x\ny\n*/ b", []int{1, 3}, "块注释中的换行"},
+		{foundationjudge.JudgeLanguageCpp, "#define A \\\n 1\na", []int{3}, "预处理指令续行"},
+		{foundationjudge.JudgeLanguageGolang, "a = `x\ny`\nb", []int{1, 1, 1, 3}, "Go的多行字符串"},
+		{foundationjudge.JudgeLanguagePython, "a = '''x\ny'''\nb", []int{1, 1, 1, 3}, "Python的三引号字符串"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := getTokenLines(Tokenize(tc.language, tc.code))
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Tokenize(%q) lines = %v; want %v", tc.code, result, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package foundationsimilarity
+
+import (
+	foundationjudge "foundation/foundation-judge"
+	"hash/fnv"
+	"sort"
+)
+
+const (
+	KgramSize  = 12 // 每个指纹覆盖的连续词法单元数，小于该长度的匹配视为巧合
+	WindowSize = 8  // 每个窗口内至少选择一个指纹，保证长度不小于KgramSize+WindowSize-1的匹配一定能发现
+)
+
+// Fingerprint 选中的k-gram哈希，Start为k-gram第一个词法单元的下标
+type Fingerprint struct {
+	Hash  uint64
+	Start int
+}
+
+// Document 一份代码的词法单元与指纹
+type Document struct {
+	Tokens       []*Token
+	Fingerprints []*Fingerprint
+}
+
+// Region 两份代码中相互对应的相似区域，行号从1开始
+type Region struct {
+	LeftStart  int `json:"left_start"`
+	LeftEnd    int `json:"left_end"`
+	RightStart int `json:"right_start"`
+	RightEnd   int `json:"right_end"`
+}
+
+func hashKgram(tokens []*Token) uint64 {
+	hasher := fnv.New64a()
+	for _, token := range tokens {
+		_, _ = hasher.Write([]byte(token.Text))
+		_, _ = hasher.Write([]byte{0})
+	}
+	return hasher.Sum64()
+}
+
+// NewDocument 词法分析后使用winnowing算法选择指纹
+func NewDocument(language foundationjudge.JudgeLanguage, code string) *Document {
+	document := &Document{Tokens: Tokenize(language, code)}
+	if len(document.Tokens) < KgramSize {
+		return document
+	}
+	hashes := make([]uint64, len(document.Tokens)-KgramSize+1)
+	for i := range hashes {
+		hashes[i] = hashKgram(document.Tokens[i : i+KgramSize])
+	}
+	// 每个窗口选择最小的哈希，相同时取最右侧的，同一位置只记录一次
+	last := -1
+	windowCount := max(len(hashes)-WindowSize+1, 1)
+	for start := 0; start < windowCount; start++ {
+		end := min(start+WindowSize, len(hashes))
+		selected := start
+		for i := start; i < end; i++ {
+			if hashes[i] <= hashes[selected] {
+				selected = i
+			}
+		}
+		if selected != last {
+			document.Fingerprints = append(
+				document.Fingerprints, &Fingerprint{Hash: hashes[selected], Start: selected},
+			)
+			last = selected
+		}
+	}
+	return document
+}
+
+// GetHashes 文档中出现的不重复的指纹哈希
+func (d *Document) GetHashes() map[uint64]bool {
+	hashes := make(map[uint64]bool, len(d.Fingerprints))
+	for _, fingerprint := range d.Fingerprints {
+		hashes[fingerprint.Hash] = true
+	}
+	return hashes
+}
+
+// Compare 计算两份代码的相似度与相似区域，ignore中的指纹为题目模板等公共代码，不参与计算
+// 相似度为共同指纹数占两份代码指纹数平均值的比例，范围0到1
+func Compare(left *Document, right *Document, ignore map[uint64]bool) (float64, []*Region) {
+	leftMap := make(map[uint64]*Fingerprint)
+	for _, fingerprint := range left.Fingerprints {
+		if ignore[fingerprint.Hash] {
+			continue
+		}
+		if _, ok := leftMap[fingerprint.Hash]; !ok {
+			leftMap[fingerprint.Hash] = fingerprint
+		}
+	}
+	rightMap := make(map[uint64]*Fingerprint)
+	for _, fingerprint := range right.Fingerprints {
+		if ignore[fingerprint.Hash] {
+			continue
+		}
+		if _, ok := rightMap[fingerprint.Hash]; !ok {
+			rightMap[fingerprint.Hash] = fingerprint
+		}
+	}
+	if len(leftMap) == 0 || len(rightMap) == 0 {
+		return 0, nil
+	}
+	type match struct {
+		left  int
+		right int
+	}
+	var matches []match
+	for hash, leftFingerprint := range leftMap {
+		if rightFingerprint, ok := rightMap[hash]; ok {
+			matches = append(matches, match{left: leftFingerprint.Start, right: rightFingerprint.Start})
+		}
+	}
+	if len(matches) == 0 {
+		return 0, nil
+	}
+	similarity := float64(2*len(matches)) / float64(len(leftMap)+len(rightMap))
+
+	// 两侧位置都连续推进的匹配合并为一个区域
+	sort.Slice(
+		matches, func(i, j int) bool {
+			if matches[i].left != matches[j].left {
+				return matches[i].left < matches[j].left
+			}
+			return matches[i].right < matches[j].right
+		},
+	)
+	var regions []*Region
+	var current *Region
+	var lastMatch match
+	for _, m := range matches {
+		leftEnd := m.left + KgramSize - 1
+		rightEnd := m.right + KgramSize - 1
+		if current != nil &&
+			m.left-lastMatch.left <= KgramSize+WindowSize &&
+			m.right > lastMatch.right &&
+			m.right-lastMatch.right <= KgramSize+WindowSize {
+			current.LeftEnd = left.Tokens[leftEnd].Line
+			current.RightEnd = right.Tokens[rightEnd].Line
+		} else {
+			current = &Region{
+				LeftStart:  left.Tokens[m.left].Line,
+				LeftEnd:    left.Tokens[leftEnd].Line,
+				RightStart: right.Tokens[m.right].Line,
+				RightEnd:   right.Tokens[rightEnd].Line,
+			}
+			regions = append(regions, current)
+		}
+		lastMatch = m
+	}
+	return similarity, regions
+}
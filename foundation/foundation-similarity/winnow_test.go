@@ -0,0 +1,171 @@
+package foundationsimilarity
+
+import (
+	foundationjudge "foundation/foundation-judge"
+	"testing"
+)
+
+// 测试用的代码片段，行数固定以便检查相似区域的行号
+const (
+	// similarityCodeSort 冒泡排序，共9行
+	similarityCodeSort = `void sort(int a[], int n) {
+    for (int i = 0; i < n; i++) {
+        for (int j = 0; j + 1 < n - i; j++) {
+            if (a[j] > a[j + 1]) {
+                int t = a[j]; a[j] = a[j + 1]; a[j + 1] = t;
+            }
+        }
+    }
+}`
+	// similarityCodeSortRenamed 改名并调整空白后的冒泡排序，共6行
+	similarityCodeSortRenamed = `void bubble(int arr[],int len){
+  for(int x=0;x<len;x++){
+    for(int y=0;y+1<len-x;y++){
+      if(arr[y]>arr[y+1]){int tmp=arr[y];arr[y]=arr[y+1];arr[y+1]=tmp;}
+  }}
+}`
+	// similarityCodeGcd 与排序结构不同的代码，共11行
+	similarityCodeGcd = `long long gcd(long long a, long long b) {
+    while (b) {
+        long long r = a % b;
+        a = b;
+        b = r;
+    }
+    return a;
+}
+long long lcm(long long a, long long b) {
+    return a / gcd(a, b) * b;
+}`
+	// similarityCodeMain 读入与输出，共13行
+	similarityCodeMain = `int main() {
+    int n;
+    scanf("%d", &n);
+    static int a[100005];
+    for (int i = 0; i < n; i++) {
+        scanf("%d", &a[i]);
+    }
+    sort(a, n);
+    for (int i = 0; i < n; i++) {
+        printf("%d\n", a[i]);
+    }
+    return 0;
+}`
+)
+
+// TestCompare 测试改名、调整空白与不同代码的相似度
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		left     string
+		right    string
+		minValue float64
+		maxValue float64
+		name     string
+	}{
+		{similarityCodeSort, similarityCodeSort, 1, 1, "相同代码"},
+		{similarityCodeSort, similarityCodeSortRenamed, 1, 1, "改名并调整空白"},
+		{
+			similarityCodeSort + "\n" + similarityCodeMain,
+			"// solution\n" + similarityCodeSortRenamed + "\n\n" + similarityCodeMain,
+			1, 1,
+			"增加注释与空行",
+		},
+		{similarityCodeSort, similarityCodeGcd, 0, 0, "不同代码"},
+		{
+			similarityCodeSort + "\n" + similarityCodeMain,
+			similarityCodeGcd + "\n" + similarityCodeMain,
+			0.3, 0.8,
+			"部分相同",
+		},
+		{"int a;", "int a;", 0, 0, "短于k-gram的代码没有指纹"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			left := NewDocument(foundationjudge.JudgeLanguageCpp, tc.left)
+			right := NewDocument(foundationjudge.JudgeLanguageCpp, tc.right)
+			similarity, _ := Compare(left, right, nil)
+			if similarity < tc.minValue || similarity > tc.maxValue {
+				t.Errorf("Compare() = %.3f; want in [%.2f, %.2f]", similarity, tc.minValue, tc.maxValue)
+			}
+			reverse, _ := Compare(right, left, nil)
+			if reverse != similarity {
+				t.Errorf("Compare() reversed = %.3f; want %.3f", reverse, similarity)
+			}
+		})
+	}
+}
+
+// TestNewDocumentWindow 每个窗口内至少有一个指纹
+func TestNewDocumentWindow(t *testing.T) {
+	document := NewDocument(foundationjudge.JudgeLanguageCpp, similarityCodeSort+"\n"+similarityCodeMain)
+	if len(document.Fingerprints) == 0 {
+		t.Fatalf("NewDocument() has no fingerprints")
+	}
+	hashCount := len(document.Tokens) - KgramSize + 1
+	for i := 1; i < len(document.Fingerprints); i++ {
+		gap := document.Fingerprints[i].Start - document.Fingerprints[i-1].Start
+		if gap <= 0 || gap > WindowSize {
+			t.Errorf("fingerprint gap = %d at %d; want in [1, %d]", gap, i, WindowSize)
+		}
+	}
+	if first := document.Fingerprints[0].Start; first >= WindowSize {
+		t.Errorf("first fingerprint = %d; want < %d", first, WindowSize)
+	}
+	if last := document.Fingerprints[len(document.Fingerprints)-1].Start; last < hashCount-WindowSize {
+		t.Errorf("last fingerprint = %d; want >= %d", last, hashCount-WindowSize)
+	}
+}
+
+// TestCompareIgnore 忽略的指纹不计入相似度
+func TestCompareIgnore(t *testing.T) {
+	left := NewDocument(foundationjudge.JudgeLanguageCpp, similarityCodeSort+"\n"+similarityCodeMain)
+	right := NewDocument(foundationjudge.JudgeLanguageCpp, similarityCodeGcd+"\n"+similarityCodeMain)
+	template := NewDocument(foundationjudge.JudgeLanguageCpp, similarityCodeMain)
+
+	cases := []struct {
+		ignore   map[uint64]bool
+		minValue float64
+		maxValue float64
+		name     string
+	}{
+		{nil, 0.3, 0.8, "不忽略时部分相同"},
+		{template.GetHashes(), 0, 0.1, "忽略公共的读入输出代码"},
+		{left.GetHashes(), 0, 0, "忽略全部指纹"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			similarity, _ := Compare(left, right, tc.ignore)
+			if similarity < tc.minValue || similarity > tc.maxValue {
+				t.Errorf("Compare() = %.3f; want in [%.2f, %.2f]", similarity, tc.minValue, tc.maxValue)
+			}
+		})
+	}
+}
+
+// TestCompareRegion 相似区域的行号对应到两份代码中各自的位置
+func TestCompareRegion(t *testing.T) {
+	// 左侧排序在第1到9行，主函数在第10到22行
+	// 右侧gcd在第1到11行，排序在第12到17行，空行后主函数在第19到31行
+	// 最后一个指纹结束于主函数的return语句，因此区域结束于倒数第二行
+	left := NewDocument(foundationjudge.JudgeLanguageCpp, similarityCodeSort+"\n"+similarityCodeMain)
+	right := NewDocument(
+		foundationjudge.JudgeLanguageCpp,
+		similarityCodeGcd+"\n"+similarityCodeSortRenamed+"\n\n"+similarityCodeMain,
+	)
+	similarity, regions := Compare(left, right, nil)
+	if similarity < 0.5 {
+		t.Errorf("Compare() = %.3f; want >= 0.5", similarity)
+	}
+	expected := Region{LeftStart: 1, LeftEnd: 21, RightStart: 12, RightEnd: 30}
+	if len(regions) != 1 || *regions[0] != expected {
+		t.Fatalf("Compare() regions = %+v; want [%+v]", regions, expected)
+	}
+
+	// 交换两侧后区域的行号也随之交换
+	_, regions = Compare(right, left, nil)
+	expected = Region{LeftStart: 12, LeftEnd: 30, RightStart: 1, RightEnd: 21}
+	if len(regions) != 1 || *regions[0] != expected {
+		t.Errorf("Compare() reversed regions = %+v; want [%+v]", regions, expected)
+	}
+}
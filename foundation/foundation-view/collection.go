@@ -1,6 +1,7 @@
 package foundationview
 
 import (
+	foundationjudge "foundation/foundation-judge"
 	foundationmodel "foundation/foundation-model"
 	"time"
 )
@@ -25,4 +26,6 @@ type CollectionDetail struct {
 
 	Problems []int `json:"problems"`
 	Members  []int `json:"members"`
+
+	Languages []foundationjudge.JudgeLanguage `json:"languages,omitempty" gorm:"-"` // 允许使用的语言，为空则不限制
 }
@@ -1,12 +1,16 @@
 package foundationview
 
-import "time"
+import (
+	foundationjudge "foundation/foundation-judge"
+	"time"
+)
 
 type CollectionRankDetail struct {
 	StartTime *time.Time `json:"start_time"`
 	EndTime   *time.Time `json:"end_time"`
 
-	Problems []int `json:"problems" gorm:"-"` // 题目Id列表
+	Problems  []int                           `json:"problems" gorm:"-"`  // 题目Id列表
+	Languages []foundationjudge.JudgeLanguage `json:"languages" gorm:"-"` // 计入排名的语言，为空则不限制
 }
 
 type CollectionRank struct {
@@ -12,6 +12,10 @@ type ContestDetail struct {
 
 	Problems []*ContestProblemDetail `json:"problems" gorm:"-"` // 比赛题目列表
 
+	Languages []foundationjudge.JudgeLanguage `json:"languages,omitempty" gorm:"-"` // 允许使用的语言，为空则不限制
+
+	PersonalStartTime *time.Time `json:"personal_start_time,omitempty" gorm:"-"` // 个人比赛窗口下当前用户的开始时间
+
 	InserterUsername string `json:"inserter_username"`
 	InserterNickname string `json:"inserter_nickname"`
 	ModifierUsername string `json:"modifier_username"`
@@ -33,8 +37,13 @@ type ContestDetailClone struct {
 type ContestDetailEdit struct {
 	foundationmodel.Contest
 
-	Problems []int            `json:"problems" gorm:"-"` // 比赛题目列表
-	Members  []*ContestMember `json:"members" gorm:"-"`  // 比赛成员列表
+	Problems   []int            `json:"problems" gorm:"-"`   // 比赛题目列表
+	Members    []*ContestMember `json:"members" gorm:"-"`    // 比赛成员列表
+	Volunteers []int            `json:"volunteers" gorm:"-"` // 配送气球的志愿者列表
+
+	Languages []foundationjudge.JudgeLanguage `json:"languages,omitempty" gorm:"-"` // 允许使用的语言，为空则不限制
+
+	ProblemSettings []*ContestProblemSetting `json:"problem_settings,omitempty" gorm:"-"` // 题目的计分与限制设置
 
 	InserterUsername string `json:"inserter_username"`
 	InserterNickname string `json:"inserter_nickname"`
@@ -60,6 +69,8 @@ type ContestViewLock struct {
 
 	AlwaysLock       bool           `json:"always_lock"`                  // 比赛结束后是否锁定排名，如果锁定则需要手动关闭（关闭时此值设为false）
 	LockRankDuration *time.Duration `json:"lock_rank_duration,omitempty"` // 比赛结束前锁定排名的时长，空则不锁榜，锁榜期间榜单仅展示尝试次数，ACM模式下只可以查看自己的提交结果，OI模式下无法查看所有的提交结果
+
+	PersonalDuration *time.Duration `json:"personal_duration,omitempty"` // 个人比赛时长，空则所有人使用统一的比赛时间
 }
 
 type ContestList struct {
@@ -72,3 +83,99 @@ type ContestList struct {
 	InserterUsername string     `json:"inserter_username"`
 	InserterNickname string     `json:"inserter_nickname"`
 }
+
+// ContestBalloon 志愿者查看的气球配送任务
+type ContestBalloon struct {
+	Id                int                                 `json:"id"`
+	ProblemIndex      uint8                               `json:"problem_index"`
+	UserId            int                                 `json:"user_id"`
+	Username          *string                             `json:"username,omitempty"`
+	Nickname          *string                             `json:"nickname,omitempty"`
+	ContestName       *string                             `json:"contest_name,omitempty"`
+	Location          *string                             `json:"location,omitempty"`
+	TeamId            *int                                `json:"team_id,omitempty"`
+	TeamName          *string                             `json:"team_name,omitempty"`
+	AcTime            time.Time                           `json:"ac_time"`
+	FirstSolve        bool                                `json:"first_solve"` // 是否为该题全场首个通过
+	Status            foundationenum.ContestBalloonStatus `json:"status"`
+	Volunteer         *int                                `json:"volunteer,omitempty"`
+	VolunteerUsername *string                             `json:"volunteer_username,omitempty"`
+	ClaimTime         *time.Time                          `json:"claim_time,omitempty"`
+	DeliverTime       *time.Time                          `json:"deliver_time,omitempty"`
+}
+
+// ContestPrintSetting 提交打印时需要的比赛配置
+type ContestPrintSetting struct {
+	Id             int       `json:"id"`
+	Title          string    `json:"title"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	PrintQuota     *int      `json:"print_quota,omitempty"`
+	PrintPageLimit *int      `json:"print_page_limit,omitempty"`
+}
+
+// ContestHackSetting 锁定题目与挑战时需要的比赛配置
+type ContestHackSetting struct {
+	Id          int       `json:"id"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Hack        bool      `json:"hack"`
+	HackScore   int       `json:"hack_score"`
+	HackPenalty int       `json:"hack_penalty"`
+}
+
+// ContestSystemTestSetting 判断提交是否只评测预测试时需要的比赛配置
+type ContestSystemTestSetting struct {
+	Id             int        `json:"id"`
+	StartTime      time.Time  `json:"start_time"`
+	EndTime        time.Time  `json:"end_time"`
+	Pretest        bool       `json:"pretest"`
+	SystemTestTime *time.Time `json:"system_test_time,omitempty"`
+}
+
+// IsPretestOnly 系统测试开始前，比赛时间内的正式提交只评测预测试
+func (s *ContestSystemTestSetting) IsPretestOnly(insertTime time.Time, virtual bool) bool {
+	return s.Pretest && s.SystemTestTime == nil && !virtual && insertTime.Before(s.EndTime)
+}
+
+// ContestPrint 打印队列中的任务，Content仅在渲染时加载
+type ContestPrint struct {
+	Id          int                               `json:"id"`
+	UserId      int                               `json:"user_id"`
+	Username    *string                           `json:"username,omitempty"`
+	Nickname    *string                           `json:"nickname,omitempty"`
+	ContestName *string                           `json:"contest_name,omitempty"`
+	Location    *string                           `json:"location,omitempty"`
+	TeamId      *int                              `json:"team_id,omitempty"`
+	TeamName    *string                           `json:"team_name,omitempty"`
+	Language    foundationjudge.JudgeLanguage     `json:"language"`
+	Content     string                            `json:"content,omitempty"`
+	Pages       int                               `json:"pages"`
+	Status      foundationenum.ContestPrintStatus `json:"status"`
+	Printer     *int                              `json:"printer,omitempty"`
+	InsertTime  time.Time                         `json:"insert_time"`
+	ClaimTime   *time.Time                        `json:"claim_time,omitempty"`
+	FinishTime  *time.Time                        `json:"finish_time,omitempty"`
+}
+
+// ContestExamSetting 考试模式下访问比赛的限制
+type ContestExamSetting struct {
+	Id          int       `json:"id"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Exam        bool      `json:"exam"`
+	IpWhitelist *string   `json:"ip_whitelist,omitempty"`
+}
+
+// ContestLoginReport 比赛期间从多个IP登录的成员
+type ContestLoginReport struct {
+	UserId      int       `json:"user_id"`
+	Username    *string   `json:"username,omitempty"`
+	Nickname    *string   `json:"nickname,omitempty"`
+	ContestName *string   `json:"contest_name,omitempty"`
+	LoginCount  int       `json:"login_count"`
+	IpCount     int       `json:"ip_count"`
+	Ips         string    `json:"ips"` // 逗号分隔的IP列表
+	FirstTime   time.Time `json:"first_time"`
+	LastTime    time.Time `json:"last_time"`
+}
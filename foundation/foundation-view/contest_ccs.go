@@ -0,0 +1,99 @@
+package foundationview
+
+import (
+	foundationjudge "foundation/foundation-judge"
+	"time"
+)
+
+// ContestCcsSubmissionRow 导出CCS数据使用的提交记录
+type ContestCcsSubmissionRow struct {
+	Id         int                           `json:"id"`
+	Inserter   int                           `json:"inserter"`
+	ProblemId  int                           `json:"problem_id"`
+	Language   foundationjudge.JudgeLanguage `json:"language"`
+	Status     foundationjudge.JudgeStatus   `json:"status"`
+	Time       int                           `json:"time"` // 运行时间，单位ms
+	JudgeTime  *time.Time                    `json:"judge_time"`
+	InsertTime time.Time                     `json:"insert_time"`
+}
+
+// ContestCcsProblemRow 导出CCS数据使用的比赛题目
+type ContestCcsProblemRow struct {
+	ProblemId int    `json:"problem_id"`
+	Index     uint8  `json:"index"`
+	Title     string `json:"title"`
+	TimeLimit int    `json:"time_limit"` // 单位ms
+}
+
+// 以下为CLICS Contest API的对象格式，时间为带时区的ISO 8601格式，时长为h:mm:ss.uuu格式
+
+type CcsContest struct {
+	Id                       string  `json:"id"`
+	Name                     string  `json:"name"`
+	FormalName               string  `json:"formal_name"`
+	StartTime                string  `json:"start_time"`
+	Duration                 string  `json:"duration"`
+	ScoreboardFreezeDuration *string `json:"scoreboard_freeze_duration"`
+	ScoreboardType           string  `json:"scoreboard_type"` // pass-fail 或 score
+	PenaltyTime              int     `json:"penalty_time"`    // 单位分钟
+}
+
+type CcsJudgementType struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Penalty bool   `json:"penalty"`
+	Solved  bool   `json:"solved"`
+}
+
+type CcsProblem struct {
+	Id        string  `json:"id"`
+	Label     string  `json:"label"`
+	Name      string  `json:"name"`
+	Ordinal   int     `json:"ordinal"`
+	TimeLimit float64 `json:"time_limit"` // 单位秒
+}
+
+type CcsTeam struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Hidden      bool   `json:"hidden"`
+}
+
+type CcsSubmission struct {
+	Id          string `json:"id"`
+	LanguageId  string `json:"language_id"`
+	ProblemId   string `json:"problem_id"`
+	TeamId      string `json:"team_id"`
+	Time        string `json:"time"`
+	ContestTime string `json:"contest_time"`
+}
+
+type CcsJudgement struct {
+	Id               string   `json:"id"`
+	SubmissionId     string   `json:"submission_id"`
+	JudgementTypeId  *string  `json:"judgement_type_id"` // 评测完成前为空
+	StartTime        string   `json:"start_time"`
+	StartContestTime string   `json:"start_contest_time"`
+	EndTime          *string  `json:"end_time"`
+	EndContestTime   *string  `json:"end_contest_time"`
+	MaxRunTime       *float64 `json:"max_run_time,omitempty"` // 单位秒
+}
+
+// CcsEvent event-feed中的一行，单例对象的id为空
+type CcsEvent struct {
+	Type string  `json:"type"`
+	Id   *string `json:"id"`
+	Data any     `json:"data"`
+}
+
+// ContestCcs 一个比赛导出的全部CCS对象
+type ContestCcs struct {
+	Contest        *CcsContest         `json:"contest"`
+	JudgementTypes []*CcsJudgementType `json:"judgement_types"`
+	Problems       []*CcsProblem       `json:"problems"`
+	Teams          []*CcsTeam          `json:"teams"`
+	Submissions    []*CcsSubmission    `json:"submissions"`
+	Judgements     []*CcsJudgement     `json:"judgements"`
+	Finished       bool                `json:"finished"` // 比赛已结束且所有可见的提交均已评测完成
+}
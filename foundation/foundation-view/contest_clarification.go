@@ -0,0 +1,17 @@
+package foundationview
+
+import "time"
+
+type ContestClarification struct {
+	Id               int        `json:"id"`
+	ProblemIndex     *uint8     `json:"problem_index,omitempty"` // 题目索引，为空时为比赛的通用问题
+	Question         *string    `json:"question,omitempty"`      // 为空时为裁判发布的公告
+	Answer           *string    `json:"answer,omitempty"`
+	Public           bool       `json:"public"`
+	Inserter         int        `json:"inserter"`
+	InserterUsername *string    `json:"inserter_username,omitempty"`
+	InserterNickname *string    `json:"inserter_nickname,omitempty"`
+	InsertTime       time.Time  `json:"insert_time"`
+	Answerer         *int       `json:"answerer,omitempty"` // 仅裁判可见
+	AnswerTime       *time.Time `json:"answer_time,omitempty"`
+}
@@ -0,0 +1,36 @@
+package foundationview
+
+import (
+	foundationjudge "foundation/foundation-judge"
+	"time"
+)
+
+// ContestExportSubmission 导出的提交记录
+type ContestExportSubmission struct {
+	Id           int                           `json:"id"`
+	Inserter     int                           `json:"inserter"`
+	Username     *string                       `json:"username"`
+	Nickname     *string                       `json:"nickname"`
+	ContestName  *string                       `json:"contest_name"`
+	TeamName     *string                       `json:"team_name"`
+	ProblemIndex uint8                         `json:"problem_index"`
+	Language     foundationjudge.JudgeLanguage `json:"language"`
+	Status       foundationjudge.JudgeStatus   `json:"status"`
+	Score        int                           `json:"score"`
+	Time         int                           `json:"time"`
+	Memory       int                           `json:"memory"`
+	CodeLength   int                           `json:"code_length"`
+	InsertTime   time.Time                     `json:"insert_time"`
+	Virtual      bool                          `json:"virtual"`
+}
+
+// ContestExportCode 导出的最终提交代码
+type ContestExportCode struct {
+	Id           int                           `json:"id"`
+	Inserter     int                           `json:"inserter"`
+	Username     *string                       `json:"username"`
+	ProblemIndex uint8                         `json:"problem_index"`
+	Language     foundationjudge.JudgeLanguage `json:"language"`
+	Status       foundationjudge.JudgeStatus   `json:"status"`
+	Code         string                        `json:"code"`
+}
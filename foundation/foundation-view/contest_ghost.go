@@ -0,0 +1,20 @@
+package foundationview
+
+type ContestGhostProblem struct {
+	GhostId      int  `json:"-"`
+	ProblemIndex int  `json:"index"`
+	Accepted     bool `json:"accepted,omitempty"`
+	Attempt      int  `json:"attempt,omitempty"`
+	Time         int  `json:"time,omitempty"`
+	Score        int  `json:"score,omitempty"`
+}
+
+// ContestGhost 原比赛榜单中的一行
+type ContestGhost struct {
+	Id       int                    `json:"id"`
+	Name     string                 `json:"name"`
+	Rank     int                    `json:"rank"`
+	Score    int                    `json:"score"`
+	Penalty  int                    `json:"penalty"`
+	Problems []*ContestGhostProblem `json:"problems,omitempty" gorm:"-"`
+}
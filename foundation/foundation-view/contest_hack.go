@@ -0,0 +1,52 @@
+package foundationview
+
+import (
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	"time"
+)
+
+// ContestHackTarget 可以被挑战的提交，每个用户取最后一次通过的代码
+type ContestHackTarget struct {
+	JudgeId     int                           `json:"judge_id"`
+	ProblemId   int                           `json:"problem_id"`
+	Inserter    int                           `json:"inserter"`
+	Username    *string                       `json:"username,omitempty"`
+	Nickname    *string                       `json:"nickname,omitempty"`
+	ContestName *string                       `json:"contest_name,omitempty"`
+	Language    foundationjudge.JudgeLanguage `json:"language"`
+	Code        string                        `json:"code,omitempty"`
+	CodeLength  int                           `json:"code_length"`
+	InsertTime  time.Time                     `json:"insert_time"`
+}
+
+// ContestHack 挑战记录，列表中不返回输入数据
+type ContestHack struct {
+	Id                int                              `json:"id"`
+	ProblemId         int                              `json:"problem_id"`
+	JudgeId           int                              `json:"judge_id"`
+	Hacker            int                              `json:"hacker"`
+	HackerUsername    *string                          `json:"hacker_username,omitempty"`
+	HackerNickname    *string                          `json:"hacker_nickname,omitempty"`
+	Defendant         int                              `json:"defendant"`
+	DefendantUsername *string                          `json:"defendant_username,omitempty"`
+	DefendantNickname *string                          `json:"defendant_nickname,omitempty"`
+	Status            foundationenum.ContestHackStatus `json:"status"`
+	Message           *string                          `json:"message,omitempty"`
+	InsertTime        time.Time                        `json:"insert_time"`
+	JudgeTime         *time.Time                       `json:"judge_time,omitempty"`
+}
+
+// ContestHackTest 挑战成功的输入与标准输出，系统测试时追加到题目的测试数据中
+type ContestHackTest struct {
+	Id     int    `json:"id"`
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+// ContestHackCount 用户挑战成功与失败的次数，用于计算榜单上的挑战得分
+type ContestHackCount struct {
+	Hacker  int `json:"hacker"`
+	Success int `json:"success"`
+	Fail    int `json:"fail"`
+}
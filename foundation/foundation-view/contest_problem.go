@@ -19,4 +19,36 @@ type ContestProblemRank struct {
 	ProblemId int   `json:"problem_id" gorm:"column:problem_id;primaryKey"`
 	Index     uint8 `json:"index" gorm:"column:index;type:tinyint(1) unsigned;"`
 	Score     int   `json:"score" gorm:"column:score;"`
+
+	ScoreDecay   int `json:"score_decay,omitempty" gorm:"column:score_decay;"`
+	ScorePenalty int `json:"score_penalty,omitempty" gorm:"column:score_penalty;"`
+	ScoreMin     int `json:"score_min,omitempty" gorm:"column:score_min;"`
+}
+
+// ContestProblemSetting 比赛中题目的计分与限制设置
+type ContestProblemSetting struct {
+	ProblemId    int  `json:"problem_id" gorm:"column:problem_id"`
+	Score        int  `json:"score,omitempty" gorm:"column:score"`
+	ScoreDecay   int  `json:"score_decay,omitempty" gorm:"column:score_decay"`
+	ScorePenalty int  `json:"score_penalty,omitempty" gorm:"column:score_penalty"`
+	ScoreMin     int  `json:"score_min,omitempty" gorm:"column:score_min"`
+	TimeLimit    *int `json:"time_limit,omitempty" gorm:"column:time_limit"`
+	MemoryLimit  *int `json:"memory_limit,omitempty" gorm:"column:memory_limit"`
+}
+
+// ContestProblemLimit 比赛中覆盖的评测限制，为空时使用题目本身的限制
+type ContestProblemLimit struct {
+	TimeLimit   *int `json:"time_limit,omitempty" gorm:"column:time_limit"`
+	MemoryLimit *int `json:"memory_limit,omitempty" gorm:"column:memory_limit"`
+}
+
+// ContestProblemStatement 打印题面使用的题目内容，限制已合并比赛中的覆盖值
+type ContestProblemStatement struct {
+	ProblemId   int     `json:"problem_id" gorm:"column:problem_id"`
+	Index       uint8   `json:"index" gorm:"column:index"`
+	Title       string  `json:"title" gorm:"column:title"`
+	Description string  `json:"description" gorm:"column:description"`
+	Source      *string `json:"source,omitempty" gorm:"column:source"`
+	TimeLimit   int     `json:"time_limit" gorm:"column:time_limit"`
+	MemoryLimit int     `json:"memory_limit" gorm:"column:memory_limit"`
 }
@@ -1,13 +1,15 @@
 package foundationview
 
 import (
-	metamysql "meta/meta-sql"
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
 	"time"
 )
 
 type ContestMember struct {
-	Id          int    `json:"id,omitempty"`
-	ContestName string `json:"contest_name,omitempty"`
+	Id          int     `json:"id,omitempty"`
+	ContestName string  `json:"contest_name,omitempty"`
+	Location    *string `json:"location,omitempty"` // 仅编辑比赛时返回
 }
 
 type ContestRankDetail struct {
@@ -18,18 +20,44 @@ type ContestRankDetail struct {
 	LockRankDuration *time.Duration `json:"lock_rank_duration,omitempty" bson:"lock_rank_duration,omitempty"` // 比赛结束前锁定排名的时长，空则不锁榜，锁榜期间ACM模式下只可以查看自己的提交结果，OI模式下无法查看所有的提交结果
 	AlwaysLock       bool           `json:"always_lock" bson:"always_lock"`                                   // 比赛结束后是否锁定排名，如果锁定则需要手动关闭（关闭时此值设为false）
 
+	Type           foundationenum.ContestType      `json:"type"`
+	ScoreType      foundationenum.ContestScoreType `json:"score_type,omitempty"`
+	PenaltyMinutes int                             `json:"penalty_minutes"` // 每次错误提交的罚时分钟数
+
+	PersonalDuration *time.Duration `json:"personal_duration,omitempty"` // 个人比赛时长，非空时罚时从成员各自的开始时间计算
+
+	Hack        bool `json:"hack,omitempty"`         // 是否开放挑战
+	HackScore   int  `json:"hack_score,omitempty"`   // 每次挑战成功获得的分数
+	HackPenalty int  `json:"hack_penalty,omitempty"` // 每次挑战失败扣除的分数
+
+	Pretest        bool       `json:"pretest,omitempty"`          // 比赛期间只评测预测试
+	SystemTestTime *time.Time `json:"system_test_time,omitempty"` // 系统测试开始时间，空则尚未开始
+
 	Problems []int            `json:"problems,omitempty" gorm:"-"` // 题目Id列表
 	Members  []*ContestMember `json:"members,omitempty" gorm:"-"`  // 成员列表
 
 	MembersIgnore []int `json:"members_ignore,omitempty" gorm:"-"` // 忽略排名成员列表
 }
 
+// ContestRankSubmission 计算榜单使用的提交记录
+type ContestRankSubmission struct {
+	Id         int                         `json:"id"`
+	Inserter   int                         `json:"inserter"`
+	ProblemId  int                         `json:"problem_id"`
+	Status     foundationjudge.JudgeStatus `json:"status"`
+	Score      int                         `json:"score"` // 评测得分，满分1000
+	InsertTime time.Time                   `json:"insert_time"`
+	Virtual    bool                        `json:"virtual,omitempty"` // 虚拟参赛的提交，InsertTime已换算为比赛中的相对时间
+	Pretest    bool                        `json:"pretest,omitempty"` // 结果仅来自预测试
+}
+
 type ContestRankProblem struct {
-	Id      int                  `json:"id,omitempty"`
-	Index   uint8                `json:"index,omitempty"`   // 题目索引
-	Attempt int                  `json:"attempt,omitempty"` // 尝试次数（截止到首次AC）
-	Ac      *metamysql.MysqlTime `json:"ac,omitempty"`      // 首次AC时间
-	Lock    int                  `json:"lock,omitempty"`    // 未知的尝试次数（可能是锁榜期间的尝试次数）
+	Index   uint8      `json:"index,omitempty"`   // 题目索引
+	Attempt int        `json:"attempt,omitempty"` // 尝试次数（ACM截止到首次AC，不含编译错误等）
+	Ac      *time.Time `json:"ac,omitempty"`      // ACM、IOI为首次AC时间，OI为最后一次提交AC的时间
+	Lock    int        `json:"lock,omitempty"`    // 未知的尝试次数（锁榜期间或尚在评测中的尝试次数）
+	Score   int        `json:"score,omitempty"`   // 题目得分
+	Pretest bool       `json:"pretest,omitempty"` // 通过或得分仅来自预测试，系统测试后才是最终结果
 }
 
 type ContestRank struct {
@@ -38,5 +66,40 @@ type ContestRank struct {
 	InserterNickname *string `json:"inserter_nickname,omitempty"` // 提交者昵称
 	InserterEmail    *string `json:"inserter_email,omitempty"`    // 提交者邮箱
 
-	Problems []*ContestRankProblem `json:"problems,omitempty" gorm:"-"` // 题目提交情况
+	TeamId      int                  `json:"team_id,omitempty" gorm:"-"`      // 队伍Id，为队伍时Inserter为0
+	TeamName    *string              `json:"team_name,omitempty" gorm:"-"`    // 队伍名称
+	TeamMembers []*ContestTeamMember `json:"team_members,omitempty" gorm:"-"` // 队伍成员
+
+	Virtual bool `json:"virtual,omitempty" gorm:"-"` // 是否为虚拟参赛
+
+	StartTime *time.Time `json:"start_time,omitempty" gorm:"-"` // 个人比赛窗口的开始时间，队伍取成员中最早的开始时间
+
+	Rank    int  `json:"rank" gorm:"-"`              // 排名，并列时相同，忽略排名的成员为0
+	Ignore  bool `json:"ignore,omitempty" gorm:"-"`  // 是否为忽略排名的成员
+	Solved  int  `json:"solved" gorm:"-"`            // 通过题数
+	Score   int  `json:"score,omitempty" gorm:"-"`   // 总分
+	Penalty int  `json:"penalty,omitempty" gorm:"-"` // 总罚时，单位秒
+	Hack    int  `json:"hack,omitempty" gorm:"-"`    // 挑战得分，已计入总分
+
+	Problems []*ContestRankProblem `json:"problems,omitempty" gorm:"-"` // 题目提交情况，按题目索引排序
+}
+
+// ContestResolverStep 滚榜中揭晓一道题的结果
+type ContestResolverStep struct {
+	Inserter int        `json:"inserter"`
+	TeamId   int        `json:"team_id,omitempty"`
+	Index    uint8      `json:"index"`             // 题目索引
+	Attempt  int        `json:"attempt,omitempty"` // 揭晓后的尝试次数
+	Ac       *time.Time `json:"ac,omitempty"`      // 揭晓后的首次AC时间，为空表示未通过
+	Solved   int        `json:"solved"`            // 揭晓后的通过题数
+	Penalty  int        `json:"penalty"`           // 揭晓后的总罚时，单位秒
+	From     int        `json:"from"`              // 揭晓前在榜单中的位置，从1开始
+	To       int        `json:"to"`                // 揭晓后在榜单中的位置，从1开始
+}
+
+// ContestResolver 滚榜数据，从锁榜的榜单开始依次回放揭晓步骤
+type ContestResolver struct {
+	Contest *ContestRankDetail     `json:"contest"`
+	Ranks   []*ContestRank         `json:"ranks"` // 锁榜时的榜单
+	Steps   []*ContestResolverStep `json:"steps"`
 }
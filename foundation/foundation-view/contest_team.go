@@ -0,0 +1,25 @@
+package foundationview
+
+type ContestTeamMember struct {
+	TeamId   int     `json:"-"`
+	UserId   int     `json:"user_id"`
+	Username string  `json:"username"`
+	Nickname *string `json:"nickname,omitempty"`
+}
+
+// ContestTeam 比赛队伍及其成员
+type ContestTeam struct {
+	Id      int                  `json:"id"`
+	Name    string               `json:"name"`
+	Members []*ContestTeamMember `json:"members,omitempty" gorm:"-"`
+}
+
+// ContestMemberCredential 批量导入成员后的账号信息，密码只在导入时返回一次
+type ContestMemberCredential struct {
+	UserId       int     `json:"user_id"`
+	Username     string  `json:"username"`
+	Password     *string `json:"password,omitempty"` // 复用已有账号时为空
+	Name         string  `json:"name"`
+	Organization string  `json:"organization,omitempty"`
+	Location     string  `json:"location,omitempty"`
+}
@@ -0,0 +1,27 @@
+package foundationview
+
+import (
+	foundationenum "foundation/foundation-enum"
+	foundationmodel "foundation/foundation-model"
+	"time"
+)
+
+type ProblemCrawlJob struct {
+	Id               int       `json:"id"`
+	OriginOj         string    `json:"origin_oj"`
+	Total            int       `json:"total"`
+	Inserter         int       `json:"inserter"`
+	InserterUsername string    `json:"inserter_username"`
+	InserterNickname string    `json:"inserter_nickname"`
+	InsertTime       time.Time `json:"insert_time"`
+
+	Statistics map[foundationenum.ProblemCrawlStatus]int `json:"statistics" gorm:"-"` // 各状态的题目数量
+
+	Items []*foundationmodel.ProblemCrawlJobItem `json:"items,omitempty" gorm:"-"`
+}
+
+type ProblemCrawlJobItemPending struct {
+	Id       int    `json:"id"`
+	OriginOj string `json:"origin_oj"`
+	OriginId string `json:"origin_id"`
+}
@@ -1,5 +1,7 @@
 package foundationview
 
+import "time"
+
 type UserRank struct {
 	Id           int    `json:"id"`                // 对应 author_id
 	Username     string `json:"username"`          // user.username
@@ -9,4 +11,15 @@ type UserRank struct {
 	ProblemCount int    `json:"problem_count"`     // 统计 count
 	Accept       int    `json:"accept,omitempty"`  // AC次数
 	Attempt      int    `json:"attempt,omitempty"` // 尝试次数
+	Rating       int    `json:"rating,omitempty"`  // 比赛Rating
+}
+
+// UserRatingHistory 用户在每场计分比赛中的Rating变化
+type UserRatingHistory struct {
+	ContestId    int       `json:"contest_id"`
+	ContestTitle string    `json:"contest_title"`
+	EndTime      time.Time `json:"end_time"`
+	Rank         int       `json:"rank"`
+	OldRating    int       `json:"old_rating"`
+	NewRating    int       `json:"new_rating"`
 }
@@ -0,0 +1,45 @@
+package foundationview
+
+import (
+	foundationjudge "foundation/foundation-judge"
+	foundationmodel "foundation/foundation-model"
+	"time"
+)
+
+// SimilaritySubmission 参与相似度检测的代码，每个用户每道题只取最后一次通过的代码
+type SimilaritySubmission struct {
+	Id         int                           `json:"id"`
+	ProblemId  int                           `json:"problem_id"`
+	Inserter   int                           `json:"inserter"`
+	Language   foundationjudge.JudgeLanguage `json:"language"`
+	Code       string                        `json:"code"`
+	InsertTime time.Time                     `json:"insert_time"`
+}
+
+type SimilarityReport struct {
+	foundationmodel.SimilarityReport
+
+	InserterUsername *string `json:"inserter_username,omitempty"`
+	InserterNickname *string `json:"inserter_nickname,omitempty"`
+}
+
+// SimilarityPair 检测结果中的代码对，列表中不包含相似区域
+type SimilarityPair struct {
+	foundationmodel.SimilarityPair
+
+	ProblemKey    *string `json:"problem_key,omitempty"`
+	LeftUsername  *string `json:"left_username,omitempty"`
+	LeftNickname  *string `json:"left_nickname,omitempty"`
+	RightUsername *string `json:"right_username,omitempty"`
+	RightNickname *string `json:"right_nickname,omitempty"`
+}
+
+// SimilarityPairDetail 对比查看一对代码
+type SimilarityPairDetail struct {
+	SimilarityPair
+
+	LeftLanguage  foundationjudge.JudgeLanguage `json:"left_language"`
+	LeftCode      string                        `json:"left_code"`
+	RightLanguage foundationjudge.JudgeLanguage `json:"right_language"`
+	RightCode     string                        `json:"right_code"`
+}
@@ -0,0 +1,21 @@
+package foundationview
+
+import (
+	foundationenum "foundation/foundation-enum"
+	"time"
+)
+
+// UserExternalSolve 合并各来源后的外部AC题目
+type UserExternalSolve struct {
+	OriginOj   string     `json:"origin_oj"`
+	OriginId   string     `json:"origin_id"`
+	ProblemKey *string    `json:"problem_key,omitempty"` // 对应的本站远程题目
+	SolveTime  *time.Time `json:"solve_time,omitempty"`
+}
+
+// UserExternalSyncPending 需要同步的外部账号，Account为空表示账号已解绑
+type UserExternalSyncPending struct {
+	UserId  int                               `json:"user_id"`
+	Source  foundationenum.UserExternalSource `json:"source"`
+	Account *string                           `json:"account"`
+}
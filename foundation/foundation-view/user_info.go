@@ -10,9 +10,11 @@ type UserLogin struct {
 	Username string `json:"username"`           // 对用户展示的唯一标识
 	Nickname string `json:"nickname,omitempty"` // 显示的昵称
 	Password string `json:"password"`           // 密码
+	Disabled bool   `json:"-"`                  // 被禁用的账号不能登录
 
-	Token *string  `json:"token,omitempty"`          // 登录令牌
-	Roles []string `json:"roles,omitempty" gorm:"-"` // 角色
+	Token   *string  `json:"token,omitempty"`          // 登录令牌
+	Session string   `json:"-" gorm:"-"`               // 令牌中的会话标识，记录在登录日志中
+	Roles   []string `json:"roles,omitempty" gorm:"-"` // 角色
 }
 
 type UserInfo struct {
@@ -30,6 +32,7 @@ type UserInfo struct {
 	VjudgeId     *string                   `json:"vjudge_id,omitempty" gorm:"type:varchar(15);comment:VjudgeId"`
 	Github       *string                   `json:"github,omitempty" gorm:"type:varchar(15);comment:Github"`
 	Codeforces   *string                   `json:"codeforces,omitempty" gorm:"type:varchar(20)"`
+	Hdu          *string                   `json:"hdu,omitempty" gorm:"type:varchar(20)"`
 	CheckInCount int                       `json:"check_in_count,omitempty" gorm:"comment:签到次数"`
 	InsertTime   time.Time                 `json:"insert_time" gorm:"type:datetime;not null"`
 	ModifyTime   time.Time                 `json:"modify_time" gorm:"type:datetime;not null"`
@@ -38,6 +41,7 @@ type UserInfo struct {
 	Level        int                       `json:"level,omitempty" gorm:"comment:用户等级"`
 	Experience   int                       `json:"experience,omitempty" gorm:"comment:用户经验值"`
 	Coin         int                       `json:"coin,omitempty" gorm:"comment:用户金币"`
+	Rating       *int                      `json:"rating,omitempty" gorm:"comment:比赛Rating"`
 
 	ExperienceUpgrade      int `json:"experience_upgrade,omitempty" gorm:"-;comment:当前等级升级所需总经验"`
 	ExperienceCurrentLevel int `json:"experience_current_level,omitempty" gorm:"-;comment:当前等级段已积攒经验"`
@@ -64,4 +68,5 @@ type UserModifyInfo struct {
 	VjudgeId     *string                   `json:"vjudge_id,omitempty" gorm:"type:varchar(15);comment:VjudgeId"`
 	Github       *string                   `json:"github,omitempty" gorm:"type:varchar(15);comment:Github"`
 	Codeforces   *string                   `json:"codeforces,omitempty" gorm:"type:varchar(20)"`
+	Hdu          *string                   `json:"hdu,omitempty" gorm:"type:varchar(20)"`
 }
@@ -60,6 +60,61 @@ MAXVALUE 9223372036854775807
 START 1
 CACHE 1;
 
+-- ----------------------------
+-- Sequence structure for contest_balloon_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_balloon_id_seq";
+CREATE SEQUENCE "didaoj"."contest_balloon_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
+-- ----------------------------
+-- Sequence structure for contest_clarification_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_clarification_id_seq";
+CREATE SEQUENCE "didaoj"."contest_clarification_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
+-- ----------------------------
+-- Sequence structure for contest_clarification_template_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_clarification_template_id_seq";
+CREATE SEQUENCE "didaoj"."contest_clarification_template_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
+-- ----------------------------
+-- Sequence structure for contest_ghost_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_ghost_id_seq";
+CREATE SEQUENCE "didaoj"."contest_ghost_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
+-- ----------------------------
+-- Sequence structure for contest_hack_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_hack_id_seq";
+CREATE SEQUENCE "didaoj"."contest_hack_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
 -- ----------------------------
 -- Sequence structure for contest_id_seq
 -- ----------------------------
@@ -71,6 +126,50 @@ MAXVALUE 9223372036854775807
 START 1
 CACHE 1;
 
+-- ----------------------------
+-- Sequence structure for contest_print_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_print_id_seq";
+CREATE SEQUENCE "didaoj"."contest_print_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
+-- ----------------------------
+-- Sequence structure for contest_rank_change_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_rank_change_id_seq";
+CREATE SEQUENCE "didaoj"."contest_rank_change_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
+-- ----------------------------
+-- Sequence structure for contest_team_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_team_id_seq";
+CREATE SEQUENCE "didaoj"."contest_team_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
+-- ----------------------------
+-- Sequence structure for contest_virtual_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."contest_virtual_id_seq";
+CREATE SEQUENCE "didaoj"."contest_virtual_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
 -- ----------------------------
 -- Sequence structure for discuss_comment_id_seq
 -- ----------------------------
@@ -104,6 +203,17 @@ MAXVALUE 9223372036854775807
 START 1
 CACHE 1;
 
+-- ----------------------------
+-- Sequence structure for problem_crawl_job_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."problem_crawl_job_id_seq";
+CREATE SEQUENCE "didaoj"."problem_crawl_job_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
 -- ----------------------------
 -- Sequence structure for problem_id_seq
 -- ----------------------------
@@ -148,6 +258,28 @@ MAXVALUE 9223372036854775807
 START 1
 CACHE 1;
 
+-- ----------------------------
+-- Sequence structure for similarity_pair_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."similarity_pair_id_seq";
+CREATE SEQUENCE "didaoj"."similarity_pair_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
+-- ----------------------------
+-- Sequence structure for similarity_report_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "didaoj"."similarity_report_id_seq";
+CREATE SEQUENCE "didaoj"."similarity_report_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
 -- ----------------------------
 -- Sequence structure for tag_id_seq
 -- ----------------------------
@@ -284,6 +416,16 @@ CREATE TABLE "didaoj"."collection" (
 )
 ;
 
+-- ----------------------------
+-- Table structure for collection_language
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."collection_language";
+CREATE TABLE "didaoj"."collection_language" (
+  "id" int8 NOT NULL,
+  "language" varchar(10) COLLATE "pg_catalog"."default" NOT NULL
+)
+;
+
 -- ----------------------------
 -- Table structure for collection_member
 -- ----------------------------
@@ -328,7 +470,131 @@ CREATE TABLE "didaoj"."contest" (
   "lock_rank_duration" int8,
   "always_lock" bool,
   "discuss_type" int2,
-  "notification_version" int4 NOT NULL
+  "notification_version" int4 NOT NULL,
+  "penalty_minutes" int4 NOT NULL DEFAULT 20,
+  "personal_duration" int8,
+  "print_quota" int4,
+  "print_page_limit" int4,
+  "rated" bool NOT NULL DEFAULT false,
+  "rating_time" timestamptz(6),
+  "exam" bool NOT NULL DEFAULT false,
+  "ip_whitelist" text COLLATE "pg_catalog"."default",
+  "hack" bool NOT NULL DEFAULT false,
+  "hack_score" int4 NOT NULL DEFAULT 0,
+  "hack_penalty" int4 NOT NULL DEFAULT 0,
+  "pretest" bool NOT NULL DEFAULT false,
+  "system_test_time" timestamptz(6)
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_balloon
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_balloon";
+CREATE TABLE "didaoj"."contest_balloon" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_balloon_id_seq'::regclass),
+  "contest_id" int8 NOT NULL,
+  "problem_id" int8 NOT NULL,
+  "owner_id" int8 NOT NULL,
+  "user_id" int8 NOT NULL,
+  "team_id" int8,
+  "judge_job_id" int8 NOT NULL,
+  "ac_time" timestamptz(6) NOT NULL,
+  "status" int2 NOT NULL DEFAULT 0,
+  "volunteer" int8,
+  "claim_time" timestamptz(6),
+  "deliver_time" timestamptz(6)
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_clarification
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_clarification";
+CREATE TABLE "didaoj"."contest_clarification" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_clarification_id_seq'::regclass),
+  "contest_id" int8 NOT NULL,
+  "problem_id" int8,
+  "question" text COLLATE "pg_catalog"."default",
+  "answer" text COLLATE "pg_catalog"."default",
+  "public" bool NOT NULL DEFAULT false,
+  "inserter" int8 NOT NULL,
+  "insert_time" timestamptz(6) NOT NULL,
+  "answerer" int8,
+  "answer_time" timestamptz(6)
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_clarification_read
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_clarification_read";
+CREATE TABLE "didaoj"."contest_clarification_read" (
+  "contest_id" int8 NOT NULL,
+  "user_id" int8 NOT NULL,
+  "read_time" timestamptz(6) NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_clarification_template
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_clarification_template";
+CREATE TABLE "didaoj"."contest_clarification_template" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_clarification_template_id_seq'::regclass),
+  "title" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "content" text COLLATE "pg_catalog"."default" NOT NULL,
+  "inserter" int8 NOT NULL,
+  "insert_time" timestamptz(6) NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_ghost
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_ghost";
+CREATE TABLE "didaoj"."contest_ghost" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_ghost_id_seq'::regclass),
+  "contest_id" int8 NOT NULL,
+  "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "rank" int4 NOT NULL,
+  "score" int4 NOT NULL,
+  "penalty" int4 NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_ghost_problem
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_ghost_problem";
+CREATE TABLE "didaoj"."contest_ghost_problem" (
+  "ghost_id" int8 NOT NULL,
+  "problem_index" int4 NOT NULL,
+  "accepted" bool NOT NULL,
+  "attempt" int4 NOT NULL,
+  "time" int4 NOT NULL,
+  "score" int4 NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_hack
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_hack";
+CREATE TABLE "didaoj"."contest_hack" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_hack_id_seq'::regclass),
+  "contest_id" int8 NOT NULL,
+  "problem_id" int8 NOT NULL,
+  "judge_id" int8 NOT NULL,
+  "hacker" int8 NOT NULL,
+  "defendant" int8 NOT NULL,
+  "input" text COLLATE "pg_catalog"."default" NOT NULL,
+  "output" text COLLATE "pg_catalog"."default",
+  "status" int2 NOT NULL DEFAULT 0,
+  "message" text COLLATE "pg_catalog"."default",
+  "judger" varchar(10) COLLATE "pg_catalog"."default",
+  "insert_time" timestamptz(6) NOT NULL,
+  "judge_time" timestamptz(6)
 )
 ;
 
@@ -349,7 +615,9 @@ DROP TABLE IF EXISTS "didaoj"."contest_member";
 CREATE TABLE "didaoj"."contest_member" (
   "id" int8 NOT NULL,
   "user_id" int8 NOT NULL,
-  "contest_name" varchar(20) COLLATE "pg_catalog"."default"
+  "contest_name" varchar(20) COLLATE "pg_catalog"."default",
+  "start_time" timestamptz(6),
+  "location" varchar(50) COLLATE "pg_catalog"."default"
 )
 ;
 
@@ -393,6 +661,25 @@ CREATE TABLE "didaoj"."contest_member_volunteer" (
 )
 ;
 
+-- ----------------------------
+-- Table structure for contest_print
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_print";
+CREATE TABLE "didaoj"."contest_print" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_print_id_seq'::regclass),
+  "contest_id" int8 NOT NULL,
+  "user_id" int8 NOT NULL,
+  "language" int2 NOT NULL,
+  "content" text COLLATE "pg_catalog"."default" NOT NULL,
+  "pages" int4 NOT NULL,
+  "status" int2 NOT NULL DEFAULT 0,
+  "printer" int8,
+  "insert_time" timestamptz(6) NOT NULL,
+  "claim_time" timestamptz(6),
+  "finish_time" timestamptz(6)
+)
+;
+
 -- ----------------------------
 -- Table structure for contest_problem
 -- ----------------------------
@@ -402,7 +689,88 @@ CREATE TABLE "didaoj"."contest_problem" (
   "problem_id" int8 NOT NULL,
   "index" int8,
   "view_id" int8,
-  "score" int8
+  "score" int8,
+  "score_decay" int4 NOT NULL DEFAULT 0,
+  "score_penalty" int4 NOT NULL DEFAULT 0,
+  "score_min" int4 NOT NULL DEFAULT 0,
+  "time_limit" int4,
+  "memory_limit" int4
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_problem_lock
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_problem_lock";
+CREATE TABLE "didaoj"."contest_problem_lock" (
+  "id" int8 NOT NULL,
+  "problem_id" int8 NOT NULL,
+  "user_id" int8 NOT NULL,
+  "insert_time" timestamptz(6) NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_rank_change
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_rank_change";
+CREATE TABLE "didaoj"."contest_rank_change" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_rank_change_id_seq'::regclass),
+  "contest_id" int8 NOT NULL,
+  "judge_job_id" int8,
+  "insert_time" timestamptz(6) NOT NULL DEFAULT now()
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_rating
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_rating";
+CREATE TABLE "didaoj"."contest_rating" (
+  "contest_id" int8 NOT NULL,
+  "user_id" int8 NOT NULL,
+  "rank" int4 NOT NULL,
+  "old_rating" int4 NOT NULL,
+  "new_rating" int4 NOT NULL,
+  "insert_time" timestamptz(6) NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_team
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_team";
+CREATE TABLE "didaoj"."contest_team" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_team_id_seq'::regclass),
+  "contest_id" int8 NOT NULL,
+  "name" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "inserter" int8 NOT NULL,
+  "insert_time" timestamptz(6) NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_team_member
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_team_member";
+CREATE TABLE "didaoj"."contest_team_member" (
+  "team_id" int8 NOT NULL,
+  "contest_id" int8 NOT NULL,
+  "user_id" int8 NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for contest_virtual
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."contest_virtual";
+CREATE TABLE "didaoj"."contest_virtual" (
+  "id" int8 NOT NULL DEFAULT nextval('contest_virtual_id_seq'::regclass),
+  "contest_id" int8 NOT NULL,
+  "user_id" int8 NOT NULL,
+  "start_time" timestamptz(6) NOT NULL,
+  "end_time" timestamptz(6) NOT NULL,
+  "insert_time" timestamptz(6) NOT NULL
 )
 ;
 
@@ -478,7 +846,9 @@ CREATE TABLE "didaoj"."judge_job" (
   "remote_judge_id" varchar(20) COLLATE "pg_catalog"."default",
   "remote_account_id" varchar(20) COLLATE "pg_catalog"."default",
   "inserter" int8 NOT NULL,
-  "insert_time" timestamptz(6) NOT NULL
+  "insert_time" timestamptz(6) NOT NULL,
+  "virtual_id" int8,
+  "pretest" bool NOT NULL DEFAULT false
 )
 ;
 
@@ -492,6 +862,22 @@ CREATE TABLE "didaoj"."judge_job_compile" (
 )
 ;
 
+-- ----------------------------
+-- Table structure for judge_job_remote_submit
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."judge_job_remote_submit";
+CREATE TABLE "didaoj"."judge_job_remote_submit" (
+  "id" int8 NOT NULL,
+  "origin_oj" varchar(10) COLLATE "pg_catalog"."default" NOT NULL,
+  "fingerprint" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "attempt" int4 NOT NULL,
+  "submit_time" timestamptz(6) NOT NULL,
+  "run_id" varchar(20) COLLATE "pg_catalog"."default",
+  "account" varchar(20) COLLATE "pg_catalog"."default",
+  "modify_time" timestamptz(6) NOT NULL
+)
+;
+
 -- ----------------------------
 -- Table structure for judge_task
 -- ----------------------------
@@ -562,6 +948,33 @@ CREATE TABLE "didaoj"."problem" (
 )
 ;
 
+-- ----------------------------
+-- Table structure for problem_crawl_job
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."problem_crawl_job";
+CREATE TABLE "didaoj"."problem_crawl_job" (
+  "id" int8 NOT NULL DEFAULT nextval('problem_crawl_job_id_seq'::regclass),
+  "origin_oj" varchar(10) COLLATE "pg_catalog"."default" NOT NULL,
+  "total" int8 NOT NULL,
+  "inserter" int8 NOT NULL,
+  "insert_time" timestamptz(6) NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for problem_crawl_job_item
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."problem_crawl_job_item";
+CREATE TABLE "didaoj"."problem_crawl_job_item" (
+  "id" int8 NOT NULL,
+  "origin_id" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "status" int2 NOT NULL,
+  "problem_key" varchar(18) COLLATE "pg_catalog"."default",
+  "message" text COLLATE "pg_catalog"."default",
+  "modify_time" timestamptz(6) NOT NULL
+)
+;
+
 -- ----------------------------
 -- Table structure for problem_daily
 -- ----------------------------
@@ -618,7 +1031,7 @@ CREATE TABLE "didaoj"."problem_remote" (
   "id" int8 NOT NULL DEFAULT nextval('problem_remote_id_seq'::regclass),
   "problem_id" int8 NOT NULL,
   "origin_oj" varchar(10) COLLATE "pg_catalog"."default" NOT NULL,
-  "origin_id" varchar(8) COLLATE "pg_catalog"."default" NOT NULL,
+  "origin_id" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
   "origin_url" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
   "origin_author" varchar(255) COLLATE "pg_catalog"."default"
 )
@@ -654,6 +1067,42 @@ CREATE TABLE "didaoj"."run_job" (
 )
 ;
 
+-- ----------------------------
+-- Table structure for similarity_pair
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."similarity_pair";
+CREATE TABLE "didaoj"."similarity_pair" (
+  "id" int8 NOT NULL DEFAULT nextval('similarity_pair_id_seq'::regclass),
+  "report_id" int8 NOT NULL,
+  "problem_id" int8 NOT NULL,
+  "left_judge_id" int8 NOT NULL,
+  "left_user_id" int8 NOT NULL,
+  "right_judge_id" int8 NOT NULL,
+  "right_user_id" int8 NOT NULL,
+  "similarity" float8 NOT NULL,
+  "regions" jsonb
+)
+;
+
+-- ----------------------------
+-- Table structure for similarity_report
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."similarity_report";
+CREATE TABLE "didaoj"."similarity_report" (
+  "id" int8 NOT NULL DEFAULT nextval('similarity_report_id_seq'::regclass),
+  "scope_type" int2 NOT NULL,
+  "scope_id" int8 NOT NULL,
+  "threshold" int4 NOT NULL,
+  "status" int2 NOT NULL DEFAULT 0,
+  "submission_count" int4 NOT NULL DEFAULT 0,
+  "pair_count" int4 NOT NULL DEFAULT 0,
+  "message" text COLLATE "pg_catalog"."default",
+  "inserter" int8 NOT NULL,
+  "insert_time" timestamptz(6) NOT NULL,
+  "finish_time" timestamptz(6)
+)
+;
+
 -- ----------------------------
 -- Table structure for tag
 -- ----------------------------
@@ -691,7 +1140,11 @@ CREATE TABLE "didaoj"."user" (
   "blog" varchar(100) COLLATE "pg_catalog"."default",
   "level" int4,
   "experience" int4,
-  "coin" int4 NOT NULL DEFAULT 0
+  "coin" int4 NOT NULL DEFAULT 0,
+  "hdu" varchar(20) COLLATE "pg_catalog"."default",
+  "rating" int4,
+  "contest_only_id" int8,
+  "disabled" bool NOT NULL DEFAULT false
 )
 ;
 
@@ -721,6 +1174,35 @@ CREATE TABLE "didaoj"."user_experience" (
 )
 ;
 
+-- ----------------------------
+-- Table structure for user_external_solve
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."user_external_solve";
+CREATE TABLE "didaoj"."user_external_solve" (
+  "user_id" int8 NOT NULL,
+  "source" varchar(10) COLLATE "pg_catalog"."default" NOT NULL,
+  "origin_oj" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "origin_id" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "solve_time" timestamptz(6),
+  "insert_time" timestamptz(6) NOT NULL
+)
+;
+
+-- ----------------------------
+-- Table structure for user_external_sync
+-- ----------------------------
+DROP TABLE IF EXISTS "didaoj"."user_external_sync";
+CREATE TABLE "didaoj"."user_external_sync" (
+  "user_id" int8 NOT NULL,
+  "source" varchar(10) COLLATE "pg_catalog"."default" NOT NULL,
+  "account" varchar(30) COLLATE "pg_catalog"."default" NOT NULL,
+  "status" int2 NOT NULL,
+  "message" varchar(255) COLLATE "pg_catalog"."default",
+  "solve_count" int4 NOT NULL,
+  "sync_time" timestamptz(6) NOT NULL
+)
+;
+
 -- ----------------------------
 -- Table structure for user_login
 -- ----------------------------
@@ -730,7 +1212,8 @@ CREATE TABLE "didaoj"."user_login" (
   "user_id" int4 NOT NULL,
   "insert_time" timestamptz(6) NOT NULL,
   "ip" inet NOT NULL,
-  "user_agent" text COLLATE "pg_catalog"."default"
+  "user_agent" text COLLATE "pg_catalog"."default",
+  "session" varchar(32) COLLATE "pg_catalog"."default"
 )
 ;
 
@@ -772,6 +1255,41 @@ ALTER SEQUENCE "didaoj"."collection_id_seq"
 OWNED BY "didaoj"."collection"."id";
 SELECT setval('"didaoj"."collection_id_seq"', 1, false);
 
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_balloon_id_seq"
+OWNED BY "didaoj"."contest_balloon"."id";
+SELECT setval('"didaoj"."contest_balloon_id_seq"', 1, false);
+
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_clarification_id_seq"
+OWNED BY "didaoj"."contest_clarification"."id";
+SELECT setval('"didaoj"."contest_clarification_id_seq"', 1, false);
+
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_clarification_template_id_seq"
+OWNED BY "didaoj"."contest_clarification_template"."id";
+SELECT setval('"didaoj"."contest_clarification_template_id_seq"', 1, false);
+
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_ghost_id_seq"
+OWNED BY "didaoj"."contest_ghost"."id";
+SELECT setval('"didaoj"."contest_ghost_id_seq"', 1, false);
+
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_hack_id_seq"
+OWNED BY "didaoj"."contest_hack"."id";
+SELECT setval('"didaoj"."contest_hack_id_seq"', 1, false);
+
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
@@ -779,6 +1297,34 @@ ALTER SEQUENCE "didaoj"."contest_id_seq"
 OWNED BY "didaoj"."contest"."id";
 SELECT setval('"didaoj"."contest_id_seq"', 1, false);
 
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_print_id_seq"
+OWNED BY "didaoj"."contest_print"."id";
+SELECT setval('"didaoj"."contest_print_id_seq"', 1, false);
+
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_rank_change_id_seq"
+OWNED BY "didaoj"."contest_rank_change"."id";
+SELECT setval('"didaoj"."contest_rank_change_id_seq"', 1, false);
+
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_team_id_seq"
+OWNED BY "didaoj"."contest_team"."id";
+SELECT setval('"didaoj"."contest_team_id_seq"', 1, false);
+
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."contest_virtual_id_seq"
+OWNED BY "didaoj"."contest_virtual"."id";
+SELECT setval('"didaoj"."contest_virtual_id_seq"', 1, false);
+
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
@@ -800,6 +1346,13 @@ ALTER SEQUENCE "didaoj"."judge_job_id_seq"
 OWNED BY "didaoj"."judge_job"."id";
 SELECT setval('"didaoj"."judge_job_id_seq"', 1, false);
 
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."problem_crawl_job_id_seq"
+OWNED BY "didaoj"."problem_crawl_job"."id";
+SELECT setval('"didaoj"."problem_crawl_job_id_seq"', 1, false);
+
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
@@ -828,6 +1381,20 @@ ALTER SEQUENCE "didaoj"."run_job_id_seq"
 OWNED BY "didaoj"."run_job"."id";
 SELECT setval('"didaoj"."run_job_id_seq"', 1, true);
 
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."similarity_pair_id_seq"
+OWNED BY "didaoj"."similarity_pair"."id";
+SELECT setval('"didaoj"."similarity_pair_id_seq"', 1, false);
+
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "didaoj"."similarity_report_id_seq"
+OWNED BY "didaoj"."similarity_report"."id";
+SELECT setval('"didaoj"."similarity_report_id_seq"', 1, false);
+
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
@@ -909,6 +1476,11 @@ ALTER TABLE "didaoj"."bot_replay" ADD CONSTRAINT "bot_replay_pk" PRIMARY KEY ("i
 -- ----------------------------
 ALTER TABLE "didaoj"."collection" ADD CONSTRAINT "collection_id_idx" PRIMARY KEY ("id");
 
+-- ----------------------------
+-- Primary Key structure for table collection_language
+-- ----------------------------
+ALTER TABLE "didaoj"."collection_language" ADD CONSTRAINT "collection_language_pk" PRIMARY KEY ("id", "language");
+
 -- ----------------------------
 -- Primary Key structure for table collection_member
 -- ----------------------------
@@ -924,6 +1496,77 @@ ALTER TABLE "didaoj"."collection_problem" ADD CONSTRAINT "collection_problem_pk"
 -- ----------------------------
 ALTER TABLE "didaoj"."contest" ADD CONSTRAINT "contest_pk" PRIMARY KEY ("id");
 
+-- ----------------------------
+-- Uniques structure for table contest_balloon
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_balloon" ADD CONSTRAINT "contest_balloon_owner_uk" UNIQUE ("contest_id", "problem_id", "owner_id");
+
+-- ----------------------------
+-- Primary Key structure for table contest_balloon
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_balloon" ADD CONSTRAINT "contest_balloon_pk" PRIMARY KEY ("id");
+
+-- ----------------------------
+-- Indexes structure for table contest_clarification
+-- ----------------------------
+CREATE INDEX "contest_clarification_contest_id_idx" ON "didaoj"."contest_clarification" USING btree (
+  "contest_id" int8_ops ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table contest_clarification
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_clarification" ADD CONSTRAINT "contest_clarification_pk" PRIMARY KEY ("id");
+
+-- ----------------------------
+-- Primary Key structure for table contest_clarification_read
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_clarification_read" ADD CONSTRAINT "contest_clarification_read_pk" PRIMARY KEY ("contest_id", "user_id");
+
+-- ----------------------------
+-- Indexes structure for table contest_clarification_template
+-- ----------------------------
+CREATE INDEX "contest_clarification_template_inserter_idx" ON "didaoj"."contest_clarification_template" USING btree (
+  "inserter" int8_ops ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table contest_clarification_template
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_clarification_template" ADD CONSTRAINT "contest_clarification_template_pk" PRIMARY KEY ("id");
+
+-- ----------------------------
+-- Indexes structure for table contest_ghost
+-- ----------------------------
+CREATE INDEX "contest_ghost_contest_id_index" ON "didaoj"."contest_ghost" USING btree (
+  "contest_id" "pg_catalog"."int8_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table contest_ghost
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_ghost" ADD CONSTRAINT "contest_ghost_pk" PRIMARY KEY ("id");
+
+-- ----------------------------
+-- Primary Key structure for table contest_ghost_problem
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_ghost_problem" ADD CONSTRAINT "contest_ghost_problem_pk" PRIMARY KEY ("ghost_id", "problem_index");
+
+-- ----------------------------
+-- Indexes structure for table contest_hack
+-- ----------------------------
+CREATE INDEX "contest_hack_contest_id_idx" ON "didaoj"."contest_hack" USING btree (
+  "contest_id" "pg_catalog"."int8_ops" ASC NULLS LAST
+);
+CREATE INDEX "contest_hack_status_idx" ON "didaoj"."contest_hack" USING btree (
+  "status" "pg_catalog"."int2_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table contest_hack
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_hack" ADD CONSTRAINT "contest_hack_pk" PRIMARY KEY ("id");
+
 -- ----------------------------
 -- Primary Key structure for table contest_language
 -- ----------------------------
@@ -954,11 +1597,84 @@ ALTER TABLE "didaoj"."contest_member_ignore" ADD CONSTRAINT "contest_member_igno
 -- ----------------------------
 ALTER TABLE "didaoj"."contest_member_volunteer" ADD CONSTRAINT "contest_member_volunteer_pk" PRIMARY KEY ("user_id", "id");
 
+-- ----------------------------
+-- Indexes structure for table contest_print
+-- ----------------------------
+CREATE INDEX "contest_print_contest_id_idx" ON "didaoj"."contest_print" USING btree (
+  "contest_id" int8_ops ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table contest_print
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_print" ADD CONSTRAINT "contest_print_pk" PRIMARY KEY ("id");
+
 -- ----------------------------
 -- Primary Key structure for table contest_problem
 -- ----------------------------
 ALTER TABLE "didaoj"."contest_problem" ADD CONSTRAINT "contest_problem_pk" PRIMARY KEY ("problem_id", "id");
 
+-- ----------------------------
+-- Primary Key structure for table contest_problem_lock
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_problem_lock" ADD CONSTRAINT "contest_problem_lock_pk" PRIMARY KEY ("id", "problem_id", "user_id");
+
+-- ----------------------------
+-- Indexes structure for table contest_rank_change
+-- ----------------------------
+CREATE INDEX "contest_rank_change_insert_time_index" ON "didaoj"."contest_rank_change" USING btree (
+  "insert_time" "pg_catalog"."timestamptz_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table contest_rank_change
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_rank_change" ADD CONSTRAINT "contest_rank_change_pk" PRIMARY KEY ("id");
+
+-- ----------------------------
+-- Indexes structure for table contest_rating
+-- ----------------------------
+CREATE INDEX "contest_rating_user_id_idx" ON "didaoj"."contest_rating" USING btree (
+  "user_id" int8_ops ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table contest_rating
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_rating" ADD CONSTRAINT "contest_rating_pk" PRIMARY KEY ("contest_id", "user_id");
+
+-- ----------------------------
+-- Indexes structure for table contest_team
+-- ----------------------------
+CREATE INDEX "contest_team_contest_id_index" ON "didaoj"."contest_team" USING btree (
+  "contest_id" "pg_catalog"."int8_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table contest_team
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_team" ADD CONSTRAINT "contest_team_pk" PRIMARY KEY ("id");
+
+-- ----------------------------
+-- Uniques structure for table contest_team_member
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_team_member" ADD CONSTRAINT "contest_team_member_contest_user_unique" UNIQUE ("contest_id", "user_id");
+
+-- ----------------------------
+-- Primary Key structure for table contest_team_member
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_team_member" ADD CONSTRAINT "contest_team_member_pk" PRIMARY KEY ("team_id", "user_id");
+
+-- ----------------------------
+-- Uniques structure for table contest_virtual
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_virtual" ADD CONSTRAINT "contest_virtual_contest_user_unique" UNIQUE ("contest_id", "user_id");
+
+-- ----------------------------
+-- Primary Key structure for table contest_virtual
+-- ----------------------------
+ALTER TABLE "didaoj"."contest_virtual" ADD CONSTRAINT "contest_virtual_pk" PRIMARY KEY ("id");
+
 -- ----------------------------
 -- Indexes structure for table discuss
 -- ----------------------------
@@ -1029,6 +1745,18 @@ ALTER TABLE "didaoj"."judge_job" ADD CONSTRAINT "judge_job_pk" PRIMARY KEY ("id"
 -- ----------------------------
 ALTER TABLE "didaoj"."judge_job_compile" ADD CONSTRAINT "judge_job_compile_pk" PRIMARY KEY ("id");
 
+-- ----------------------------
+-- Indexes structure for table judge_job_remote_submit
+-- ----------------------------
+CREATE INDEX "judge_job_remote_submit_submit_time_index" ON "didaoj"."judge_job_remote_submit" USING btree (
+  "submit_time" "pg_catalog"."timestamptz_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table judge_job_remote_submit
+-- ----------------------------
+ALTER TABLE "didaoj"."judge_job_remote_submit" ADD CONSTRAINT "judge_job_remote_submit_pk" PRIMARY KEY ("id");
+
 -- ----------------------------
 -- Primary Key structure for table judge_task
 -- ----------------------------
@@ -1077,6 +1805,23 @@ ALTER TABLE "didaoj"."problem" ADD CONSTRAINT "problem_pk_2" UNIQUE ("key");
 -- ----------------------------
 ALTER TABLE "didaoj"."problem" ADD CONSTRAINT "problem_pk" PRIMARY KEY ("id");
 
+-- ----------------------------
+-- Primary Key structure for table problem_crawl_job
+-- ----------------------------
+ALTER TABLE "didaoj"."problem_crawl_job" ADD CONSTRAINT "problem_crawl_job_pk" PRIMARY KEY ("id");
+
+-- ----------------------------
+-- Indexes structure for table problem_crawl_job_item
+-- ----------------------------
+CREATE INDEX "problem_crawl_job_item_status_index" ON "didaoj"."problem_crawl_job_item" USING btree (
+  "status" "pg_catalog"."int2_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table problem_crawl_job_item
+-- ----------------------------
+ALTER TABLE "didaoj"."problem_crawl_job_item" ADD CONSTRAINT "problem_crawl_job_item_pk" PRIMARY KEY ("id", "origin_id");
+
 -- ----------------------------
 -- Indexes structure for table problem_daily
 -- ----------------------------
@@ -1146,6 +1891,30 @@ CREATE INDEX "idx_run_job_user_id" ON "didaoj"."run_job" USING btree (
 -- ----------------------------
 ALTER TABLE "didaoj"."run_job" ADD CONSTRAINT "run_job_pkey" PRIMARY KEY ("id");
 
+-- ----------------------------
+-- Indexes structure for table similarity_pair
+-- ----------------------------
+CREATE INDEX "similarity_pair_report_id_idx" ON "didaoj"."similarity_pair" USING btree (
+  "report_id" int8_ops ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table similarity_pair
+-- ----------------------------
+ALTER TABLE "didaoj"."similarity_pair" ADD CONSTRAINT "similarity_pair_pk" PRIMARY KEY ("id");
+
+-- ----------------------------
+-- Indexes structure for table similarity_report
+-- ----------------------------
+CREATE INDEX "similarity_report_scope_id_idx" ON "didaoj"."similarity_report" USING btree (
+  "scope_id" int8_ops ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table similarity_report
+-- ----------------------------
+ALTER TABLE "didaoj"."similarity_report" ADD CONSTRAINT "similarity_report_pk" PRIMARY KEY ("id");
+
 -- ----------------------------
 -- Indexes structure for table tag
 -- ----------------------------
@@ -1169,6 +1938,9 @@ ALTER TABLE "didaoj"."tag" ADD CONSTRAINT "tag_pk" PRIMARY KEY ("id");
 CREATE UNIQUE INDEX "idx_user_username_lower" ON "didaoj"."user" USING btree (
   lower(username::text) COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST
 );
+CREATE INDEX "user_contest_only_id_idx" ON "didaoj"."user" USING btree (
+  "contest_only_id" "pg_catalog"."int8_ops" ASC NULLS LAST
+);
 
 -- ----------------------------
 -- Uniques structure for table user
@@ -1192,6 +1964,16 @@ CREATE INDEX "user_coin_user_id_index" ON "didaoj"."user_coin" USING btree (
 -- ----------------------------
 ALTER TABLE "didaoj"."user_experience" ADD CONSTRAINT "user_experience_pk" UNIQUE ("type", "user_id", "param");
 
+-- ----------------------------
+-- Primary Key structure for table user_external_solve
+-- ----------------------------
+ALTER TABLE "didaoj"."user_external_solve" ADD CONSTRAINT "user_external_solve_pk" PRIMARY KEY ("user_id", "source", "origin_oj", "origin_id");
+
+-- ----------------------------
+-- Primary Key structure for table user_external_sync
+-- ----------------------------
+ALTER TABLE "didaoj"."user_external_sync" ADD CONSTRAINT "user_external_sync_pk" PRIMARY KEY ("user_id", "source");
+
 -- ----------------------------
 -- Indexes structure for table user_login
 -- ----------------------------
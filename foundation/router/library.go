@@ -3,14 +3,27 @@ package router
 import (
 	foundationauth "foundation/foundation-auth"
 	foundationconfig "foundation/foundation-config"
+	foundationservice "foundation/foundation-service"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"log/slog"
 	"meta/auth"
+	metapanic "meta/meta-panic"
 	"meta/meta-response"
+	metatime "meta/meta-time"
 	"net/http"
 )
 
+// isUserEnabled 令牌有效期较长，每次请求都要确认账号没有被禁用，查询失败时按禁用处理
+func isUserEnabled(c *gin.Context, userId int) bool {
+	disabled, err := foundationservice.GetUserService().IsUserDisabled(c, userId, metatime.GetTimeNow())
+	if err != nil {
+		metapanic.ProcessError(err)
+		return false
+	}
+	return !disabled
+}
+
 func AuthMiddlewareOptional() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.Request.Header.Get("X-Token")
@@ -23,7 +36,8 @@ func AuthMiddlewareOptional() gin.HandlerFunc {
 				},
 			)
 			if err == nil {
-				if jwtClaims.IsValid() {
+				// 被禁用的账号按未登录处理
+				if jwtClaims.IsValid() && isUserEnabled(c, jwtClaims.UserId) {
 					c.Set("claims", jwtClaims)
 				}
 			}
@@ -58,6 +72,11 @@ func TokenAuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if !isUserEnabled(c, jwtClaims.UserId) {
+			metaresponse.NewResponse(c, http.StatusUnauthorized, nil)
+			c.Abort()
+			return
+		}
 		c.Set("claims", jwtClaims)
 		c.Next()
 	}
@@ -53,6 +53,11 @@ func (s *Subsystem) startSubSystem() error {
 		return err
 	}
 
+	err = service.GetHackService().Start()
+	if err != nil {
+		return err
+	}
+
 	err = service.GetBotService().Start()
 	if err != nil {
 		return err
@@ -0,0 +1,558 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	foundationmodel "foundation/foundation-model"
+	foundationview "foundation/foundation-view"
+	"judge/config"
+	gojudge "judge/go-judge"
+	"log/slog"
+	"meta/cron"
+	metaerror "meta/meta-error"
+	metahttp "meta/meta-http"
+	metapanic "meta/meta-panic"
+	metastring "meta/meta-string"
+	"meta/metaroutine"
+	"meta/singleton"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// hackProgramOutLimit 校验器、标准程序与被挑战代码的输出上限
+	hackProgramOutLimit = 64 * 1024 * 1024
+	// hackOutputMaxSize 标准输出会保存并加入之后每次评测，过大的数据不作为挑战数据
+	hackOutputMaxSize = 1024 * 1024
+)
+
+// hackRunResult 在GoJudge中运行一次程序的结果
+type hackRunResult struct {
+	Status     gojudge.Status `json:"status"`
+	ExitStatus int            `json:"exitStatus"`
+	Files      struct {
+		Stderr string `json:"stderr"`
+		Stdout string `json:"stdout"`
+	} `json:"files"`
+	Time   int `json:"time"`
+	Memory int `json:"memory"`
+}
+
+// HackService 处理比赛中的挑战，校验输入后分别运行标准程序与被挑战的代码
+type HackService struct {
+	requestMutex sync.Mutex
+	runningTasks atomic.Int32
+
+	goJudgeClient *http.Client
+}
+
+var singletonHackService = singleton.Singleton[HackService]{}
+
+func GetHackService() *HackService {
+	return singletonHackService.GetInstance(
+		func() *HackService {
+			s := &HackService{}
+			s.goJudgeClient = &http.Client{
+				Transport: &http.Transport{
+					MaxIdleConns:        100,
+					MaxIdleConnsPerHost: 100,
+					MaxConnsPerHost:     100,
+					IdleConnTimeout:     90 * time.Second,
+				},
+				Timeout: 60 * time.Second, // 请求整体超时
+			}
+			return s
+		},
+	)
+}
+
+func (s *HackService) Start() error {
+
+	c := cron.NewWithSeconds()
+	_, err := c.AddFunc(
+		"* * * * * ?", func() {
+			// 每秒检查一次是否有新的挑战
+			s.checkStartHack()
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "error adding function to cron")
+	}
+
+	c.Start()
+
+	return nil
+}
+
+func (s *HackService) checkStartHack() {
+	err := s.handleStart()
+	if err != nil {
+		metapanic.ProcessError(err)
+	}
+}
+
+func (s *HackService) handleStart() error {
+
+	// 如果没开启评测，停止处理
+	if !GetStatusService().IsEnableJudge() {
+		return nil
+	}
+	// 如果上报状态报错，停止处理
+	if GetStatusService().IsReportError() {
+		return nil
+	}
+
+	// 保证同时只有一个handleStart
+	if !s.requestMutex.TryLock() {
+		return nil
+	}
+	defer s.requestMutex.Unlock()
+
+	// 挑战与运行任务一样是一次性的运行，共用并发配置
+	maxJob := config.GetConfig().MaxJobRun
+	runningCount := int(s.runningTasks.Load())
+	if runningCount >= maxJob {
+		return nil
+	}
+	ctx := context.Background()
+	hacks, err := foundationdao.GetContestHackDao().RequestContestHackListPending(
+		ctx,
+		maxJob-runningCount,
+		config.GetConfig().Judger.Key,
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to get contest hack list")
+	}
+	if len(hacks) == 0 {
+		return nil
+	}
+
+	slog.Info("get contest hack list", "runningCount", runningCount, "maxJob", maxJob, "count", len(hacks))
+
+	s.runningTasks.Add(int32(len(hacks)))
+
+	for _, hack := range hacks {
+		metaroutine.SafeGo(
+			fmt.Sprintf("RunningContestHack_%d", hack.Id), func() error {
+				defer s.checkStartHack()
+
+				defer func() {
+					slog.Info(fmt.Sprintf("ContestHack_%d end", hack.Id))
+					s.runningTasks.Add(-1)
+				}()
+
+				slog.Info(fmt.Sprintf("ContestHack_%d start", hack.Id))
+				err := s.startHack(ctx, hack)
+				if err != nil {
+					s.finishHack(ctx, hack, foundationenum.ContestHackStatusError, nil, err.Error())
+					return err
+				}
+				return nil
+			},
+		)
+	}
+	return nil
+}
+
+func (s *HackService) finishHack(
+	ctx context.Context,
+	hack *foundationmodel.ContestHack,
+	status foundationenum.ContestHackStatus,
+	output *string,
+	message string,
+) {
+	err := foundationdao.GetContestHackDao().FinishContestHack(
+		ctx,
+		hack,
+		config.GetConfig().Judger.Key,
+		status,
+		output,
+		metastring.GetTextEllipsis(message, 1000),
+	)
+	if err != nil {
+		metapanic.ProcessError(err)
+	}
+}
+
+func (s *HackService) startHack(ctx context.Context, hack *foundationmodel.ContestHack) error {
+	jobKey := fmt.Sprintf("hack-%d", hack.Id)
+
+	target, err := foundationdao.GetJudgeJobDao().GetJudgeJob(
+		ctx,
+		hack.JudgeId,
+		[]string{"id", "status", "language", "code"},
+	)
+	if err != nil {
+		return err
+	}
+	if target == nil || target.Id == 0 || target.Status != foundationjudge.JudgeStatusAC {
+		// 同一份代码可能已经被其他人挑战成功
+		s.finishHack(ctx, hack, foundationenum.ContestHackStatusError, nil, "target submission is not accepted")
+		return nil
+	}
+
+	problem, err := foundationdao.GetProblemDao().GetProblemViewForLocalJudge(ctx, hack.ProblemId)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to get problem")
+	}
+	if problem == nil || problem.JudgeMd5 == nil {
+		return metaerror.New("problem judge data not found: %d", hack.ProblemId)
+	}
+	err = applyContestProblemLimit(ctx, hack.ContestId, problem)
+	if err != nil {
+		return err
+	}
+	err = GetJudgeService().updateJudgeData(ctx, problem.Id, *problem.JudgeMd5)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to update judge data")
+	}
+	judgeDataDir := path.Join(".judge_data", strconv.Itoa(problem.Id), *problem.JudgeMd5)
+
+	var jobConfig foundationjudge.JudgeJobConfig
+	yamlFile, err := os.ReadFile(path.Join(judgeDataDir, "rule.yaml"))
+	if err == nil {
+		err = yaml.Unmarshal(yamlFile, &jobConfig)
+		if err != nil {
+			return metaerror.Wrap(err, "Unmarshal config file error")
+		}
+	}
+	if jobConfig.Validator == nil || jobConfig.Standard == nil {
+		s.finishHack(ctx, hack, foundationenum.ContestHackStatusError, nil, "validator or standard solution not found")
+		return nil
+	}
+
+	// 校验输入，校验器返回0表示输入合法
+	result, err := s.runJudgeDataProgram(jobKey, judgeDataDir, jobConfig.Validator, hack.Input)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to run validator")
+	}
+	if result.Status != gojudge.StatusAccepted {
+		message := result.Files.Stderr
+		if message == "" {
+			message = string(result.Status)
+		}
+		s.finishHack(ctx, hack, foundationenum.ContestHackStatusInvalid, nil, message)
+		return nil
+	}
+
+	// 标准程序的输出作为正确答案
+	result, err = s.runJudgeDataProgram(jobKey, judgeDataDir, jobConfig.Standard, hack.Input)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to run standard solution")
+	}
+	if result.Status != gojudge.StatusAccepted {
+		s.finishHack(
+			ctx, hack, foundationenum.ContestHackStatusError, nil,
+			fmt.Sprintf("standard solution failed: %s", result.Status),
+		)
+		return nil
+	}
+	rightOutContent := result.Files.Stdout
+	if len(rightOutContent) > hackOutputMaxSize {
+		s.finishHack(
+			ctx, hack, foundationenum.ContestHackStatusInvalid, nil,
+			fmt.Sprintf("standard output too large, limit:%d bytes", hackOutputMaxSize),
+		)
+		return nil
+	}
+
+	status, message, err := s.runTarget(
+		jobKey,
+		hack,
+		&target.JudgeJob,
+		problem,
+		&jobConfig,
+		rightOutContent,
+	)
+	if err != nil {
+		return err
+	}
+	s.finishHack(ctx, hack, status, &rightOutContent, message)
+	return nil
+}
+
+// runTarget 运行被挑战的代码并与标准输出比较，未能正确通过时挑战成功
+func (s *HackService) runTarget(
+	jobKey string,
+	hack *foundationmodel.ContestHack,
+	target *foundationmodel.JudgeJob,
+	problem *foundationview.ProblemForLocalJudge,
+	jobConfig *foundationjudge.JudgeJobConfig,
+	rightOutContent string,
+) (foundationenum.ContestHackStatus, string, error) {
+	var execFileIds map[string]string
+	if foundationjudge.IsLanguageNeedCompile(target.Language) {
+		runUrl := metahttp.UrlJoin(config.GetConfig().GoJudge.Url, "run")
+		var extraMessage string
+		var compileStatus foundationjudge.JudgeStatus
+		var err error
+		execFileIds, extraMessage, compileStatus, err = foundationjudge.CompileCode(
+			s.goJudgeClient,
+			jobKey,
+			runUrl,
+			target.Language,
+			target.Code,
+			GetJudgeService().configFileIds,
+			false,
+			false,
+		)
+		defer s.deleteFiles(jobKey, execFileIds)
+		if err != nil {
+			return foundationenum.ContestHackStatusError, "", err
+		}
+		if compileStatus != foundationjudge.JudgeStatusAC {
+			return foundationenum.ContestHackStatusError, extraMessage, nil
+		}
+	}
+	args, copyIns, err := getHackTargetArgs(target, execFileIds)
+	if err != nil {
+		return foundationenum.ContestHackStatusError, "", err
+	}
+
+	cpuLimit := problem.TimeLimit * 1000000
+	memoryLimit := problem.MemoryLimit * 1024
+	if target.Language == foundationjudge.JudgeLanguageJava {
+		cpuLimit = cpuLimit + 2000*1000000
+		memoryLimit = memoryLimit + 1024*1024*64
+	}
+	outLimit := int64(len(rightOutContent))*2 + 1024
+	result, err := s.runProgram(jobKey, args, copyIns, hack.Input, cpuLimit, memoryLimit, outLimit)
+	if err != nil {
+		return foundationenum.ContestHackStatusError, "", err
+	}
+	if result.Status != gojudge.StatusAccepted {
+		if result.Status == gojudge.StatusInternalError {
+			return foundationenum.ContestHackStatusError, string(result.Status), nil
+		}
+		return foundationenum.ContestHackStatusSuccess, string(result.Status), nil
+	}
+	userAnsContent := result.Files.Stdout
+
+	if jobConfig.SpecialJudge == nil {
+		// 与判题一致，忽略空白字符比较
+		if strings.Join(strings.Fields(rightOutContent), " ") != strings.Join(strings.Fields(userAnsContent), " ") {
+			return foundationenum.ContestHackStatusSuccess, "Wrong Answer", nil
+		}
+		return foundationenum.ContestHackStatusFailed, "", nil
+	}
+
+	specialFileId, err := GetJudgeService().compileSpecialJudge(
+		foundationmodel.NewJudgeJobBuilder().Id(hack.JudgeId).ProblemId(hack.ProblemId).Build(),
+		*problem.JudgeMd5,
+		jobConfig,
+	)
+	if err != nil {
+		return foundationenum.ContestHackStatusError, "", metaerror.Wrap(err, "failed to compile special judge")
+	}
+	result, err = s.runProgram(
+		jobKey,
+		[]string{"spj", "test.in", "user.out", "test.out"},
+		map[string]interface{}{
+			"spj":      map[string]interface{}{"fileId": specialFileId},
+			"test.in":  map[string]interface{}{"content": hack.Input},
+			"test.out": map[string]interface{}{"content": rightOutContent},
+			"user.out": map[string]interface{}{"content": userAnsContent},
+		},
+		hack.Input,
+		30000000000,   // 提供30秒给spj
+		512*1024*1024, // 提供512MB给spj
+		10240,
+	)
+	if err != nil {
+		return foundationenum.ContestHackStatusError, "", err
+	}
+	if result.Status == gojudge.StatusAccepted {
+		return foundationenum.ContestHackStatusFailed, "", nil
+	}
+	if result.Status == gojudge.StatusNonzeroExit {
+		switch result.ExitStatus {
+		case int(foundationjudge.SpecialJudgeExitCodeWA):
+			return foundationenum.ContestHackStatusSuccess, "Wrong Answer", nil
+		case int(foundationjudge.SpecialJudgeExitCodePE):
+			return foundationenum.ContestHackStatusSuccess, "Presentation Error", nil
+		}
+	}
+	return foundationenum.ContestHackStatusError, "special judge failed: " + string(result.Status), nil
+}
+
+// runJudgeDataProgram 编译并运行判题数据中的校验器或标准程序
+func (s *HackService) runJudgeDataProgram(
+	jobKey string,
+	judgeDataDir string,
+	program *foundationjudge.SpecialJudgeConfig,
+	input string,
+) (*hackRunResult, error) {
+	language := foundationjudge.GetLanguageByKey(program.Language)
+	if !foundationjudge.IsValidSpecialJudgeLanguage(language) {
+		return nil, metaerror.New("language %s not valid special language", program.Language)
+	}
+	codeContent, err := metastring.GetStringFromOpenFile(path.Join(judgeDataDir, program.Source))
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to read program file: %s", program.Source)
+	}
+	runUrl := metahttp.UrlJoin(config.GetConfig().GoJudge.Url, "run")
+	execFileIds, extraMessage, compileStatus, err := foundationjudge.CompileCode(
+		s.goJudgeClient,
+		jobKey,
+		runUrl,
+		language,
+		codeContent,
+		GetJudgeService().configFileIds,
+		true,
+		false,
+	)
+	defer s.deleteFiles(jobKey, execFileIds)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to compile program: %s", program.Source)
+	}
+	if compileStatus != foundationjudge.JudgeStatusAC {
+		return nil, metaerror.New("compile program %s failed: %s", program.Source, extraMessage)
+	}
+	fileId, ok := execFileIds["a"]
+	if !ok {
+		return nil, metaerror.New("program compile failed, fileId not found")
+	}
+	return s.runProgram(
+		jobKey,
+		[]string{"a"},
+		map[string]interface{}{
+			"a": map[string]interface{}{"fileId": fileId},
+		},
+		input,
+		30000000000,   // 提供30秒
+		512*1024*1024, // 提供512MB
+		hackProgramOutLimit,
+	)
+}
+
+func (s *HackService) runProgram(
+	jobKey string,
+	args []string,
+	copyIns map[string]interface{},
+	input string,
+	cpuLimit int,
+	memoryLimit int,
+	outLimit int64,
+) (*hackRunResult, error) {
+	data := map[string]interface{}{
+		"cmd": []map[string]interface{}{
+			{
+				"args": args,
+				"env":  []string{"PATH=/usr/bin:/bin"},
+				"files": []map[string]interface{}{
+					{"content": input},
+					{"name": "stdout", "max": outLimit},
+					{"name": "stderr", "max": 10240},
+				},
+				"cpuLimit":    cpuLimit,
+				"memoryLimit": memoryLimit,
+				"procLimit":   50,
+				"copyIn":      copyIns,
+			},
+		},
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, metaerror.Wrap(err)
+	}
+	runUrl := metahttp.UrlJoin(config.GetConfig().GoJudge.Url, "run")
+	_, respBody, err := metahttp.SendRequestRetry(
+		s.goJudgeClient,
+		jobKey,
+		6,
+		time.Second*10,
+		http.MethodPost, runUrl,
+		nil,
+		bytes.NewBuffer(jsonData),
+		true,
+	)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to send request to GoJudge")
+	}
+	var responseDataList []*hackRunResult
+	err = json.Unmarshal(respBody, &responseDataList)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to decode response")
+	}
+	if len(responseDataList) != 1 {
+		return nil, metaerror.New("unexpected response length: %d", len(responseDataList))
+	}
+	return responseDataList[0], nil
+}
+
+func (s *HackService) deleteFiles(jobKey string, fileIds map[string]string) {
+	for _, fileId := range fileIds {
+		deleteUrl := metahttp.UrlJoin(config.GetConfig().GoJudge.Url, "file", fileId)
+		err := foundationjudge.DeleteFile(s.goJudgeClient, jobKey, deleteUrl)
+		if err != nil {
+			metapanic.ProcessError(err)
+		}
+	}
+}
+
+// getHackTargetArgs 被挑战代码的运行参数，与判题时保持一致
+func getHackTargetArgs(
+	target *foundationmodel.JudgeJob,
+	execFileIds map[string]string,
+) ([]string, map[string]interface{}, error) {
+	switch target.Language {
+	case foundationjudge.JudgeLanguageC, foundationjudge.JudgeLanguageCpp,
+		foundationjudge.JudgeLanguagePascal, foundationjudge.JudgeLanguageGolang,
+		foundationjudge.JudgeLanguageRust:
+		fileId, ok := execFileIds["a"]
+		if !ok {
+			return nil, nil, metaerror.New("fileId not found")
+		}
+		return []string{"a"}, map[string]interface{}{
+			"a": map[string]interface{}{"fileId": fileId},
+		}, nil
+	case foundationjudge.JudgeLanguageJava:
+		className := foundationjudge.GetJavaClass(target.Code)
+		if className == "" {
+			return nil, nil, metaerror.New("class name not found")
+		}
+		qualifiedName := className
+		if packageName := foundationjudge.GetJavaPackage(target.Code); packageName != "" {
+			qualifiedName = packageName + "." + className
+		}
+		jarFileName := className + ".jar"
+		fileId, ok := execFileIds[jarFileName]
+		if !ok {
+			return nil, nil, metaerror.New("fileId not found")
+		}
+		return []string{"java", "-Dfile.encoding=UTF-8", "-cp", jarFileName, qualifiedName},
+			map[string]interface{}{
+				jarFileName: map[string]interface{}{"fileId": fileId},
+			}, nil
+	case foundationjudge.JudgeLanguagePython:
+		return []string{"python3", "a.py"}, map[string]interface{}{
+			"a.py": map[string]interface{}{"content": target.Code},
+		}, nil
+	case foundationjudge.JudgeLanguageLua:
+		return []string{"luajit", "a.lua"}, map[string]interface{}{
+			"a.lua": map[string]interface{}{"content": target.Code},
+		}, nil
+	case foundationjudge.JudgeLanguageTypeScript:
+		fileId, ok := execFileIds["a.js"]
+		if !ok {
+			return nil, nil, metaerror.New("fileId not found")
+		}
+		return []string{"node", "a.js"}, map[string]interface{}{
+			"a.js": map[string]interface{}{"fileId": fileId},
+		}, nil
+	}
+	return nil, nil, metaerror.New("language not support: %d", target.Language)
+}
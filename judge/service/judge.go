@@ -308,6 +308,12 @@ func (s *JudgeService) startJudgeJob(job *foundationmodel.JudgeJob) error {
 	if problem.JudgeMd5 == nil {
 		return metaerror.New("problem judge md5 is nil: %d", job.ProblemId)
 	}
+	if job.ContestId != nil {
+		err = applyContestProblemLimit(ctx, *job.ContestId, problem)
+		if err != nil {
+			return err
+		}
+	}
 	err = s.updateJudgeData(ctx, problem.Id, *problem.JudgeMd5)
 	if err != nil {
 		return metaerror.Wrap(err, "failed to update judge data")
@@ -367,6 +373,21 @@ func (s *JudgeService) startJudgeJob(job *foundationmodel.JudgeJob) error {
 	return err
 }
 
+// applyContestProblemLimit 比赛中设置了单独的限制时使用比赛的限制
+func applyContestProblemLimit(ctx context.Context, contestId int, problem *foundationview.ProblemForLocalJudge) error {
+	limit, err := foundationdao.GetContestProblemDao().GetProblemLimit(ctx, contestId, problem.Id)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to get contest problem limit")
+	}
+	if limit != nil && limit.TimeLimit != nil {
+		problem.TimeLimit = *limit.TimeLimit
+	}
+	if limit != nil && limit.MemoryLimit != nil {
+		problem.MemoryLimit = *limit.MemoryLimit
+	}
+	return nil
+}
+
 func (s *JudgeService) updateJudgeData(ctx context.Context, problemId int, md5 string) error {
 	val, _ := s.problemMutexMap.LoadOrStore(problemId, &judgeMutexEntry{})
 	e := val.(*judgeMutexEntry)
@@ -779,6 +800,27 @@ func (s *JudgeService) runJudgeJob(
 		}
 	}
 
+	pretestOnly := false
+	if job.ContestId != nil {
+		setting, err := foundationdao.GetContestDao().GetContestSystemTestSetting(ctx, *job.ContestId)
+		if err != nil {
+			return metaerror.Wrap(err, "failed to get contest system test setting")
+		}
+		pretestOnly = setting != nil && setting.IsPretestOnly(job.InsertTime, job.VirtualId != nil)
+	}
+	if pretestOnly {
+		// 没有标记预测试时全部数据都作为预测试
+		var pretestTasks []*foundationjudge.JudgeTaskConfig
+		for _, taskConfig := range jobConfig.Tasks {
+			if taskConfig.Pretest {
+				pretestTasks = append(pretestTasks, taskConfig)
+			}
+		}
+		if len(pretestTasks) > 0 {
+			jobConfig.Tasks = pretestTasks
+		}
+	}
+
 	taskCount = len(jobConfig.Tasks)
 
 	if taskCount == 0 {
@@ -802,6 +844,36 @@ func (s *JudgeService) runJudgeJob(
 		leftScore--
 	}
 
+	if job.ContestId != nil {
+		// 比赛中挑战成功的数据追加为不计分的测试点，未通过时整体结果不再是AC
+		// 预测试阶段只评测挑战本提交成功的数据，避免被挑战的提交重判后恢复为AC
+		var hackTests []*foundationview.ContestHackTest
+		if pretestOnly {
+			hackTests, err = foundationdao.GetContestHackDao().GetJudgeJobHackTests(ctx, job.Id)
+		} else {
+			hackTests, err = foundationdao.GetContestHackDao().GetContestHackTests(ctx, *job.ContestId, problemId)
+		}
+		if err != nil {
+			return metaerror.Wrap(err, "failed to get contest hack tests")
+		}
+		for _, hackTest := range hackTests {
+			jobConfig.Tasks = append(
+				jobConfig.Tasks, &foundationjudge.JudgeTaskConfig{
+					Key:        fmt.Sprintf("hack-%d", hackTest.Id),
+					InContent:  hackTest.Input,
+					OutContent: hackTest.Output,
+					OutLimit:   metamath.Max(int64(len(hackTest.Output))*2, 1024),
+				},
+			)
+		}
+		taskCount = len(jobConfig.Tasks)
+	}
+
+	err = foundationdao.GetJudgeJobDao().MarkJudgeJobPretest(ctx, job.Id, config.GetConfig().Judger.Key, pretestOnly)
+	if err != nil {
+		metapanic.ProcessError(err)
+	}
+
 	err = foundationdao.GetJudgeJobDao().MarkJudgeJobTaskTotal(ctx, job.Id, config.GetConfig().Judger.Key, taskCount)
 	if err != nil {
 		metapanic.ProcessError(err)
@@ -892,7 +964,7 @@ func (s *JudgeService) runJudgeTask(
 	goJudgeUrl := config.GetConfig().GoJudge.Url
 	runUrl := metahttp.UrlJoin(goJudgeUrl, "run")
 
-	var inContent string
+	inContent := taskConfig.InContent
 
 	if taskConfig.InFile != "" {
 		inContent, err = metastring.GetStringFromOpenFile(path.Join(judgeDataDir, taskConfig.InFile))
@@ -1140,7 +1212,7 @@ func (s *JudgeService) runJudgeTask(
 		}
 		return finalStatus, sumTime, sumMemory, finalScore, nil
 	}
-	var rightOutContent string
+	rightOutContent := taskConfig.OutContent
 	if taskConfig.OutFile != "" {
 		rightOutContent, err = metastring.GetStringFromOpenFile(path.Join(judgeDataDir, taskConfig.OutFile))
 		if err != nil {
@@ -36,6 +36,9 @@ type RemoteService struct {
 	// 有些时候同一个问题只能有一个逻辑去处理
 	problemMutexMap sync.Map
 
+	// 远程OJ账号对应的状态轮询
+	statusPollerMap sync.Map
+
 	// 题目号对应的特判程序文件ID
 	specialFileIds map[int]string
 	// 配置静态文件标识与文件ID的映射
@@ -200,7 +203,7 @@ func (s *RemoteService) startRemoteTask(job *foundationmodel.JudgeJob) error {
 	agent := foundationremote.GetRemoteAgent(oj)
 
 	// 提交远程判题
-	remoteId, remoteAccount, err := agent.PostSubmitJudgeJob(ctx, problem.OriginId, job.Language, job.Code)
+	remoteId, remoteAccount, err := s.submitRemoteJudgeJob(ctx, job, problem.OriginOj, problem.OriginId, agent)
 	if err != nil {
 		markErr := foundationdao.GetJudgeJobDao().MarkJudgeJobJudgeFinalStatus(
 			ctx, jobId, judgerKey,
@@ -219,10 +222,15 @@ func (s *RemoteService) startRemoteTask(job *foundationmodel.JudgeJob) error {
 
 	slog.Info("Remote job submitted", "jobId", jobId, "remoteId", remoteId, "remoteAccount", remoteAccount)
 
-	ticker := time.NewTicker(3 * time.Second)
-	defer ticker.Stop()
+	err = foundationdao.GetJudgeJobDao().MarkJudgeJobRemoteSubmit(ctx, jobId, judgerKey, remoteId, remoteAccount)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to mark remote submit")
+	}
+
+	updates, cancelWatch := s.getStatusPoller(oj, agent, remoteAccount).Watch(remoteId)
+	defer cancelWatch()
 
-	currentStatus := foundationjudge.JudgeStatusQueuing
+	currentStatus := foundationjudge.JudgeStatusCompiling
 
 	for {
 		select {
@@ -232,11 +240,8 @@ func (s *RemoteService) startRemoteTask(job *foundationmodel.JudgeJob) error {
 			} else {
 				return metaerror.Wrap(ctx.Err(), "job=%d cancelled", jobId)
 			}
-		case <-ticker.C:
-			status, score, finalTime, finalMemory, err := agent.GetJudgeJobStatus(ctx, remoteId)
-			if err != nil {
-				return metaerror.Wrap(err, "failed to get job status")
-			}
+		case result := <-updates:
+			status, score, finalTime, finalMemory := result.Status, result.Score, result.Time, result.Memory
 			slog.Info(
 				"refresh job status:",
 				"job",
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	foundationenum "foundation/foundation-enum"
+	foundationjudge "foundation/foundation-judge"
+	foundationremote "foundation/foundation-remote"
+	"log/slog"
+	metapanic "meta/meta-panic"
+	"meta/metaroutine"
+	"sync"
+	"time"
+)
+
+const (
+	// 有状态变化或新提交时的轮询间隔
+	remoteStatusPollMinInterval = 1 * time.Second
+	// 状态长时间没有变化时的最大轮询间隔
+	remoteStatusPollMaxInterval = 10 * time.Second
+	// 请求出错时的最大轮询间隔
+	remoteStatusPollErrorInterval = 30 * time.Second
+	// 连续多少次在状态表中找不到时，改为单独查询该RunId
+	remoteStatusPollMissLimit = 3
+)
+
+type remoteStatusWatcher struct {
+	runId     string
+	updates   chan *foundationremote.RemoteJudgeStatus
+	status    foundationjudge.JudgeStatus
+	missCount int
+}
+
+// RemoteStatusPoller 同一个远程OJ账号共用一个轮询，一次拉取状态表后分发给所有等待中的任务
+type RemoteStatusPoller struct {
+	name    string
+	agent   foundationremote.RemoteAgentBase
+	account string
+
+	mutex    sync.Mutex
+	watchers map[string]*remoteStatusWatcher
+	running  bool
+
+	// 有新任务加入时唤醒轮询并恢复到最小间隔
+	wakeup chan struct{}
+}
+
+func (s *RemoteService) getStatusPoller(
+	oj foundationenum.RemoteJudgeType,
+	agent foundationremote.RemoteAgentBase,
+	account string,
+) *RemoteStatusPoller {
+	name := fmt.Sprintf("%s_%s", oj, account)
+	val, _ := s.statusPollerMap.LoadOrStore(
+		name, &RemoteStatusPoller{
+			name:     name,
+			agent:    agent,
+			account:  account,
+			watchers: make(map[string]*remoteStatusWatcher),
+			wakeup:   make(chan struct{}, 1),
+		},
+	)
+	return val.(*RemoteStatusPoller)
+}
+
+// Watch 等待RunId的状态变化，每次状态变化都会推送最新的状态，推送最终状态后不再推送
+// 返回的函数用于取消等待
+func (p *RemoteStatusPoller) Watch(runId string) (<-chan *foundationremote.RemoteJudgeStatus, func()) {
+	watcher := &remoteStatusWatcher{
+		runId:   runId,
+		updates: make(chan *foundationremote.RemoteJudgeStatus, 1),
+		status:  foundationjudge.JudgeStatusInit,
+	}
+
+	p.mutex.Lock()
+	p.watchers[runId] = watcher
+	if !p.running {
+		p.running = true
+		metaroutine.SafeGo(
+			fmt.Sprintf("RemoteStatusPoller_%s", p.name), func() error {
+				p.run()
+				return nil
+			},
+		)
+	} else {
+		select {
+		case p.wakeup <- struct{}{}:
+		default:
+		}
+	}
+	p.mutex.Unlock()
+
+	return watcher.updates, func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		if p.watchers[runId] == watcher {
+			delete(p.watchers, runId)
+		}
+	}
+}
+
+func (p *RemoteStatusPoller) run() {
+	slog.Info("remote status poller start", "name", p.name)
+	defer slog.Info("remote status poller end", "name", p.name)
+
+	interval := remoteStatusPollMinInterval
+	for {
+		select {
+		case <-time.After(interval):
+		case <-p.wakeup:
+			interval = remoteStatusPollMinInterval
+			continue
+		}
+
+		p.mutex.Lock()
+		if len(p.watchers) == 0 {
+			p.running = false
+			p.mutex.Unlock()
+			return
+		}
+		runIds := make([]string, 0, len(p.watchers))
+		var missIds []string
+		for runId, watcher := range p.watchers {
+			runIds = append(runIds, runId)
+			if watcher.missCount >= remoteStatusPollMissLimit {
+				missIds = append(missIds, runId)
+			}
+		}
+		p.mutex.Unlock()
+
+		changed, err := p.poll(runIds, missIds)
+		if err != nil {
+			metapanic.ProcessError(err)
+			interval = min(interval*2, remoteStatusPollErrorInterval)
+		} else if changed {
+			interval = remoteStatusPollMinInterval
+		} else {
+			interval = min(interval*3/2, remoteStatusPollMaxInterval)
+		}
+	}
+}
+
+func (p *RemoteStatusPoller) poll(runIds []string, missIds []string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := p.agent.GetJudgeJobStatusList(ctx, p.account, runIds)
+	if err != nil {
+		return false, err
+	}
+	// 状态表中长期找不到的RunId单独查询，避免任务一直等待到超时
+	for _, runId := range missIds {
+		if _, ok := result[runId]; ok {
+			continue
+		}
+		status, score, finalTime, finalMemory, err := p.agent.GetJudgeJobStatus(ctx, runId)
+		if err != nil {
+			// 单个查询失败不影响其他任务，下一轮再重试
+			slog.Error("get remote judge status failed", "name", p.name, "runId", runId, "error", err)
+			continue
+		}
+		result[runId] = &foundationremote.RemoteJudgeStatus{
+			RunId:  runId,
+			Status: status,
+			Score:  score,
+			Time:   finalTime,
+			Memory: finalMemory,
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	changed := false
+	for runId, watcher := range p.watchers {
+		row, ok := result[runId]
+		if !ok {
+			watcher.missCount++
+			continue
+		}
+		watcher.missCount = 0
+		isFinal := !foundationjudge.IsJudgeStatusRunning(row.Status)
+		if row.Status == watcher.status && !isFinal {
+			continue
+		}
+		changed = true
+		watcher.status = row.Status
+		// 只保留最新的状态
+		select {
+		case <-watcher.updates:
+		default:
+		}
+		watcher.updates <- row
+		if isFinal {
+			delete(p.watchers, runId)
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	foundationdao "foundation/foundation-dao"
+	foundationjudge "foundation/foundation-judge"
+	foundationmodel "foundation/foundation-model"
+	foundationremote "foundation/foundation-remote"
+	"log/slog"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	"time"
+)
+
+const (
+	// 单次评测最多提交的次数
+	remoteSubmitMaxAttempt = 3
+	// 两次提交之间的等待时间，按次数递增
+	remoteSubmitRetryInterval = 10 * time.Second
+	// 找回RunId时允许的远程时钟误差
+	remoteSubmitClockSkew = 2 * time.Minute
+)
+
+func getRemoteSubmitFingerprint(
+	originOj string,
+	originId string,
+	language foundationjudge.JudgeLanguage,
+	code string,
+) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%d\n%s", originOj, originId, language, code)))
+	return hex.EncodeToString(hash[:])
+}
+
+// submitRemoteJudgeJob 幂等地提交远程评测
+// 同样的代码已有RunId时直接复用，提交结果未知时先从远程提交记录中找回RunId，找不到再有限次重试
+func (s *RemoteService) submitRemoteJudgeJob(
+	ctx context.Context,
+	job *foundationmodel.JudgeJob,
+	originOj string,
+	originId string,
+	agent foundationremote.RemoteAgentBase,
+) (string, string, error) {
+	submitDao := foundationdao.GetJudgeJobRemoteSubmitDao()
+	fingerprint := getRemoteSubmitFingerprint(originOj, originId, job.Language, job.Code)
+
+	submit, err := submitDao.GetJudgeJobRemoteSubmit(ctx, job.Id)
+	if err != nil {
+		return "", "", err
+	}
+	if submit != nil && submit.Fingerprint == fingerprint {
+		if submit.RunId != nil && submit.Account != nil {
+			// 重判等情况下代码没有变化，直接复用之前的提交
+			slog.Info("Remote job reuse submit", "jobId", job.Id, "remoteId", *submit.RunId)
+			return *submit.RunId, *submit.Account, nil
+		}
+		// 上次提交结果未知，先尝试找回
+		runId, account, err := s.recoverRemoteRunId(ctx, job, originOj, originId, agent, submit.SubmitTime)
+		if err != nil {
+			metapanic.ProcessError(err)
+		} else if runId != "" {
+			return runId, account, nil
+		}
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= remoteSubmitMaxAttempt; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return "", "", metaerror.Wrap(ctx.Err(), "job=%d cancelled", job.Id)
+			case <-time.After(time.Duration(attempt-1) * remoteSubmitRetryInterval):
+			}
+		}
+		submitTime := time.Now()
+		// 提交前先记录指纹，即使进程中途退出也能知道这份代码可能已经提交过
+		err := submitDao.StartJudgeJobRemoteSubmit(ctx, job.Id, originOj, fingerprint, submitTime)
+		if err != nil {
+			return "", "", err
+		}
+		remoteId, remoteAccount, err := agent.PostSubmitJudgeJob(ctx, originId, job.Language, job.Code)
+		if err == nil && remoteId != "" {
+			err = submitDao.MarkJudgeJobRemoteSubmitRunId(ctx, job.Id, remoteId, remoteAccount)
+			if err != nil {
+				return "", "", err
+			}
+			return remoteId, remoteAccount, nil
+		}
+		if err == nil {
+			err = metaerror.New("remote run id is empty")
+		}
+		lastErr = err
+		slog.Warn("Remote job submit failed", "jobId", job.Id, "attempt", attempt, "error", err)
+
+		// 远程可能已经收到了代码，只是获取RunId失败，找回后就不需要再次提交
+		runId, account, recoverErr := s.recoverRemoteRunId(ctx, job, originOj, originId, agent, submitTime)
+		if recoverErr != nil {
+			metapanic.ProcessError(recoverErr)
+			continue
+		}
+		if runId != "" {
+			return runId, account, nil
+		}
+	}
+	return "", "", metaerror.Wrap(lastErr, "remote submit failed after %d attempts", remoteSubmitMaxAttempt)
+}
+
+// recoverRemoteRunId 在远程账号的最近提交中查找提交时间之后的同题同语言提交，并排除已被其他评测占用的RunId
+func (s *RemoteService) recoverRemoteRunId(
+	ctx context.Context,
+	job *foundationmodel.JudgeJob,
+	originOj string,
+	originId string,
+	agent foundationremote.RemoteAgentBase,
+	submitTime time.Time,
+) (string, string, error) {
+	submitDao := foundationdao.GetJudgeJobRemoteSubmitDao()
+	since := submitTime.Add(-remoteSubmitClockSkew)
+	excludeIds, err := submitDao.GetJudgeJobRemoteSubmitRunIds(ctx, originOj, since, job.Id)
+	if err != nil {
+		return "", "", err
+	}
+	runId, account, err := agent.FindSubmitRunId(ctx, originId, job.Language, since, excludeIds)
+	if err != nil {
+		return "", "", metaerror.Wrap(err, "failed to find remote run id")
+	}
+	if runId == "" {
+		return "", "", nil
+	}
+	slog.Info("Remote job run id recovered", "jobId", job.Id, "remoteId", runId)
+	err = submitDao.MarkJudgeJobRemoteSubmitRunId(ctx, job.Id, runId, account)
+	if err != nil {
+		return "", "", err
+	}
+	return runId, account, nil
+}
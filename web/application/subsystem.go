@@ -3,6 +3,7 @@ package application
 import (
 	"meta/engine"
 	"meta/subsystem"
+	"web/service"
 )
 
 type Subsystem struct {
@@ -29,5 +30,33 @@ func (s *Subsystem) Start() error {
 }
 
 func (s *Subsystem) startSubSystem() error {
+
+	var err error
+
+	err = service.GetProblemCrawlService().Start()
+	if err != nil {
+		return err
+	}
+
+	err = service.GetUserExternalSyncService().Start()
+	if err != nil {
+		return err
+	}
+
+	err = service.GetContestRankService().Start()
+	if err != nil {
+		return err
+	}
+
+	err = service.GetContestRatingService().Start()
+	if err != nil {
+		return err
+	}
+
+	err = service.GetSimilarityService().Start()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -29,6 +29,10 @@ type Config struct {
 	Template map[string]string `yaml:"template"`
 
 	JudgeDataMaxSize int64 `yaml:"judge-data-max-size"` // 题目评测数据最大大小，单位为字节
+
+	CrawlInterval int `yaml:"crawl-interval"` // 批量爬取远程题目的间隔，单位为秒
+
+	PdfFont string `yaml:"pdf-font"` // 生成PDF时嵌入的TrueType字体文件，用于输出中文
 }
 
 type Subsystem struct {
@@ -96,6 +100,28 @@ func GetPostgreSqlConfig() map[string]*metapostgresql.Config {
 	return configSubsystem.config.PostgreSql
 }
 
+func GetPdfFont() string {
+	configSubsystem := GetSubsystem()
+	if configSubsystem == nil {
+		return ""
+	}
+	if configSubsystem.config == nil {
+		return ""
+	}
+	return configSubsystem.config.PdfFont
+}
+
+func GetR2Url() string {
+	configSubsystem := GetSubsystem()
+	if configSubsystem == nil {
+		return ""
+	}
+	if configSubsystem.config == nil {
+		return ""
+	}
+	return configSubsystem.config.R2Url
+}
+
 func GetOjTemplateContent(oj string) string {
 	configSubsystem := GetSubsystem()
 	if configSubsystem == nil {
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"encoding/json"
+	foundationservice "foundation/foundation-service"
+	foundationview "foundation/foundation-view"
+	metacontroller "meta/controller"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	metatime "meta/meta-time"
+	"net/http"
+	"strconv"
+	"time"
+	weberrorcode "web/error-code"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// event-feed 检查数据变化的间隔，同一比赛的连接共用一份数据，不会各自查询数据库
+	ccsEventFeedInterval = 5 * time.Second
+	// event-feed 无事件时发送空行保持连接的间隔
+	ccsEventFeedKeepAlive = 120 * time.Second
+)
+
+// CcsController 只读的CLICS Contest API，供ICPC Resolver、CDS等工具使用
+// 与其他接口不同，按照CLICS的约定直接返回对象，错误通过HTTP状态码表示
+type CcsController struct {
+	metacontroller.Controller
+}
+
+// checkContestCcsAuth 检查查看权限，失败时已写入响应
+func (c *CcsController) checkContestCcsAuth(ctx *gin.Context) (contestId int, isAdmin bool, ok bool) {
+	contestId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || contestId <= 0 {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	_, hasAuth, err := contestService.CheckViewAuth(ctx, contestId)
+	if err != nil {
+		// 考试模式拒绝访问时按无权限处理
+		code := metaerror.GetErrorCodeFromError(err)
+		if code == weberrorcode.ContestExamIpDenied || code == weberrorcode.ContestExamSessionExpired {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		metapanic.ProcessError(err)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	if !hasAuth {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	_, isAdmin, err = contestService.CheckEditAuth(ctx, contestId)
+	if err != nil {
+		metapanic.ProcessError(err)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	return contestId, isAdmin, true
+}
+
+// getContestCcs 检查权限并导出比赛数据，失败时已写入响应
+func (c *CcsController) getContestCcs(ctx *gin.Context) *foundationview.ContestCcs {
+	contestId, isAdmin, ok := c.checkContestCcsAuth(ctx)
+	if !ok {
+		return nil
+	}
+	ccs, err := foundationservice.GetContestCcsService().GetContestCcs(ctx, contestId, metatime.GetTimeNow(), isAdmin)
+	if err != nil {
+		metapanic.ProcessError(err)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return nil
+	}
+	return ccs
+}
+
+func (c *CcsController) GetContest(ctx *gin.Context) {
+	ccs := c.getContestCcs(ctx)
+	if ccs == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, ccs.Contest)
+}
+
+func (c *CcsController) GetJudgementTypes(ctx *gin.Context) {
+	ccs := c.getContestCcs(ctx)
+	if ccs == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, ccs.JudgementTypes)
+}
+
+func (c *CcsController) GetProblems(ctx *gin.Context) {
+	ccs := c.getContestCcs(ctx)
+	if ccs == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, nonNilSlice(ccs.Problems))
+}
+
+func (c *CcsController) GetTeams(ctx *gin.Context) {
+	ccs := c.getContestCcs(ctx)
+	if ccs == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, nonNilSlice(ccs.Teams))
+}
+
+func (c *CcsController) GetSubmissions(ctx *gin.Context) {
+	ccs := c.getContestCcs(ctx)
+	if ccs == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, nonNilSlice(ccs.Submissions))
+}
+
+func (c *CcsController) GetJudgements(ctx *gin.Context) {
+	ccs := c.getContestCcs(ctx)
+	if ccs == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, nonNilSlice(ccs.Judgements))
+}
+
+// GetEventFeed 以NDJSON格式输出事件，stream=false时输出当前全部事件后结束
+// 否则持续推送变化，直到比赛结束且可见的提交均已评测完成
+func (c *CcsController) GetEventFeed(ctx *gin.Context) {
+	contestId, isAdmin, ok := c.checkContestCcsAuth(ctx)
+	if !ok {
+		return
+	}
+	stream := ctx.DefaultQuery("stream", "true") != "false"
+	ccsService := foundationservice.GetContestCcsService()
+	ccs, err := ccsService.GetSharedContestCcs(ctx, contestId, metatime.GetTimeNow(), isAdmin)
+	if err != nil {
+		metapanic.ProcessError(err)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Status(http.StatusOK)
+	feed := foundationservice.NewContestCcsEventFeed()
+	encoder := json.NewEncoder(ctx.Writer)
+	lastWrite := time.Now()
+	for {
+		events, err := feed.Next(ccs)
+		if err != nil {
+			metapanic.ProcessError(err)
+			return
+		}
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		}
+		if len(events) > 0 {
+			lastWrite = time.Now()
+		} else if time.Since(lastWrite) >= ccsEventFeedKeepAlive {
+			if _, err := ctx.Writer.Write([]byte("\n")); err != nil {
+				return
+			}
+			lastWrite = time.Now()
+		}
+		ctx.Writer.Flush()
+		if !stream || ccs.Finished {
+			return
+		}
+
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-time.After(ccsEventFeedInterval):
+		}
+		ccs, err = ccsService.GetSharedContestCcs(ctx, contestId, metatime.GetTimeNow(), isAdmin)
+		if err != nil {
+			metapanic.ProcessError(err)
+			return
+		}
+	}
+}
+
+// nonNilSlice 保证空列表输出为[]而不是null
+func nonNilSlice[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
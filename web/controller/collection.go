@@ -186,13 +186,14 @@ func (c *CollectionController) PostCreate(ctx *gin.Context) {
 		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
 		return
 	}
-	if requestData.Title == "" {
-		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
 		return
 	}
 	collectionService := foundationservice.GetCollectionService()
 	// 控制创建时的标题唯一，一定程度上防止误重复创建
-	ok, err := collectionService.HasCollectionTitle(ctx, userId, requestData.Title)
+	ok, err = collectionService.HasCollectionTitle(ctx, userId, requestData.Title)
 	if err != nil {
 		metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
 		return
@@ -244,7 +245,13 @@ func (c *CollectionController) PostCreate(ctx *gin.Context) {
 		ModifyTime(nowTime).
 		Build()
 
-	err = collectionService.InsertCollection(ctx, collection, realProblemIds, memberIds)
+	err = collectionService.InsertCollection(
+		ctx,
+		collection,
+		realProblemIds,
+		requestData.GetLanguageKeys(),
+		memberIds,
+	)
 	if err != nil {
 		metaresponse.NewResponseError(ctx, err)
 		return
@@ -264,8 +271,9 @@ func (c *CollectionController) PostEdit(ctx *gin.Context) {
 		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
 		return
 	}
-	if requestData.Title == "" {
-		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
 		return
 	}
 	collectionService := foundationservice.GetCollectionService()
@@ -313,7 +321,13 @@ func (c *CollectionController) PostEdit(ctx *gin.Context) {
 		ModifyTime(nowTime).
 		Build()
 
-	err = collectionService.UpdateCollection(ctx, collection, realProblemIds, memberIds)
+	err = collectionService.UpdateCollection(
+		ctx,
+		collection,
+		realProblemIds,
+		requestData.GetLanguageKeys(),
+		memberIds,
+	)
 	if err != nil {
 		metaresponse.NewResponseError(ctx, err)
 		return
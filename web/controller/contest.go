@@ -9,6 +9,7 @@ import (
 	foundationjudge "foundation/foundation-judge"
 	foundationmodel "foundation/foundation-model"
 	foundationr2 "foundation/foundation-r2"
+	foundationremote "foundation/foundation-remote"
 	foundationservice "foundation/foundation-service"
 	foundationview "foundation/foundation-view"
 	"io"
@@ -56,7 +57,7 @@ func (c *ContestController) Get(ctx *gin.Context) {
 	nowTime := metatime.GetTimeNow()
 	contest, hasAuth, needPassword, attemptStatus, err := contestService.GetContest(ctx, id, nowTime)
 	if err != nil {
-		metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
+		metaresponse.NewResponseError(ctx, err)
 		return
 	}
 	if contest == nil {
@@ -349,7 +350,7 @@ func (c *ContestController) GetProblemList(ctx *gin.Context) {
 
 	_, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
 	if err != nil {
-		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		metaresponse.NewResponseError(ctx, err)
 		return
 	}
 	if !hasAuth {
@@ -381,7 +382,7 @@ func (c *ContestController) GetRank(ctx *gin.Context) {
 	}
 	_, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
 	if err != nil {
-		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		metaresponse.NewResponseError(ctx, err)
 		return
 	}
 	if !hasAuth {
@@ -396,6 +397,16 @@ func (c *ContestController) GetRank(ctx *gin.Context) {
 
 	nowTime := metatime.GetTimeNow()
 
+	// 比赛管理者可以在锁榜期间查看真实榜单
+	showUnfrozen := false
+	if ctx.Query("unfrozen") == "1" {
+		_, showUnfrozen, err = foundationservice.GetContestService().CheckEditAuth(ctx, contestId)
+		if err != nil {
+			metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+			return
+		}
+	}
+
 	var contest *foundationview.ContestRankDetail
 	var ranks []*foundationview.ContestRank
 	var isLocked bool
@@ -403,6 +414,7 @@ func (c *ContestController) GetRank(ctx *gin.Context) {
 		ctx,
 		contestId,
 		nowTime,
+		showUnfrozen,
 	)
 	if err != nil {
 		metaresponse.NewResponseError(ctx, err)
@@ -417,7 +429,7 @@ func (c *ContestController) GetRank(ctx *gin.Context) {
 		Now      time.Time                         `json:"now"`
 		IsLocked bool                              `json:"is_locked"` // 是否锁榜状态
 		Contest  *foundationview.ContestRankDetail `json:"contest"`
-		Ranks    []*foundationview.ContestRank     `json:"ranks"` // 排行榜
+		Ranks    []*foundationview.ContestRank     `json:"ranks"` // 排行榜，已按排名排序
 	}{
 		HasAuth:  true,
 		Now:      nowTime,
@@ -428,6 +440,192 @@ func (c *ContestController) GetRank(ctx *gin.Context) {
 	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
 }
 
+func (c *ContestController) GetRankVirtual(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	filter, err := strconv.Atoi(ctx.DefaultQuery("filter", "0"))
+	if err != nil ||
+		foundationenum.ContestRankFilter(filter) < foundationenum.ContestRankFilterAll ||
+		foundationenum.ContestRankFilter(filter) > foundationenum.ContestRankFilterVirtual {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	nowTime := metatime.GetTimeNow()
+	_, endTime, err := foundationservice.GetContestService().GetContestTime(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if nowTime.Before(*endTime) {
+		metaresponse.NewResponse(ctx, weberrorcode.ContestVirtualNotEnd, nil)
+		return
+	}
+	contest, ranks, elapsed, err := foundationservice.GetContestService().GetContestVirtualRanks(
+		ctx,
+		contestId,
+		userId,
+		nowTime,
+		foundationenum.ContestRankFilter(filter),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if contest == nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NotFound, nil)
+		return
+	}
+	var elapsedSeconds *int64
+	if elapsed != nil {
+		seconds := int64(elapsed.Seconds())
+		elapsedSeconds = &seconds
+	}
+	responseData := struct {
+		Now     time.Time                         `json:"now"`
+		Elapsed *int64                            `json:"elapsed,omitempty"` // 正在虚拟参赛时的比赛进行时间，单位秒
+		Contest *foundationview.ContestRankDetail `json:"contest"`
+		Ranks   []*foundationview.ContestRank     `json:"ranks"`
+	}{
+		Now:     nowTime,
+		Elapsed: elapsedSeconds,
+		Contest: contest,
+		Ranks:   ranks,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+func (c *ContestController) GetVirtual(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, err := foundationauth.GetUserIdFromContext(ctx)
+	if err != nil || userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	virtual, err := foundationservice.GetContestService().GetContestVirtual(ctx, contestId, userId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, virtual)
+}
+
+func (c *ContestController) GetGhost(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	_, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	ghosts, err := foundationservice.GetContestService().GetContestGhosts(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Ghosts []*foundationview.ContestGhost `json:"ghosts"`
+	}{
+		Ghosts: ghosts,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+func (c *ContestController) GetResolver(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	_, hasAuth, err := foundationservice.GetContestService().CheckEditAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	_, endTime, err := contestService.GetContestTime(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if endTime == nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NotFound, nil)
+		return
+	}
+	if metatime.GetTimeNow().Before(*endTime) {
+		metaresponse.NewResponse(ctx, weberrorcode.ContestResolverNotEnd, nil)
+		return
+	}
+	resolver, err := contestService.GetContestResolver(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if resolver == nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NotFound, nil)
+		return
+	}
+	if resolver.Contest.Type != foundationenum.ContestTypeAcm {
+		metaresponse.NewResponse(ctx, weberrorcode.ContestResolverNotSupport, nil)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, resolver)
+}
+
+func (c *ContestController) GetTeamList(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	_, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	teams, err := foundationservice.GetContestService().GetContestTeams(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Teams []*foundationview.ContestTeam `json:"teams"`
+	}{
+		Teams: teams,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
 func (c *ContestController) GetStatistics(ctx *gin.Context) {
 	contestService := foundationservice.GetContestService()
 	idStr := ctx.Query("id")
@@ -442,7 +640,7 @@ func (c *ContestController) GetStatistics(ctx *gin.Context) {
 	}
 	_, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
 	if err != nil {
-		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		metaresponse.NewResponseError(ctx, err)
 		return
 	}
 	if !hasAuth {
@@ -654,11 +852,17 @@ func (c *ContestController) PostCreate(ctx *gin.Context) {
 
 	var problems []*foundationmodel.ContestProblem
 	for _, problemId := range realProblemIds {
+		setting := requestData.GetProblemSetting(problemId)
 		problems = append(
 			problems, foundationmodel.NewContestProblemBuilder().
 				ProblemId(problemId).
-				ViewId(nil).                   // 题目描述Id，默认为nil
-				Score(0).                      // 分数默认为0
+				ViewId(nil).          // 题目描述Id，默认为nil
+				Score(setting.Score). // 分数为0时按默认分数计算
+				ScoreDecay(setting.ScoreDecay).
+				ScorePenalty(setting.ScorePenalty).
+				ScoreMin(setting.ScoreMin).
+				TimeLimit(setting.TimeLimit).
+				MemoryLimit(setting.MemoryLimit).
 				Index(uint8(len(problems)+1)). // 索引从1开始
 				Build(),
 		)
@@ -693,23 +897,59 @@ func (c *ContestController) PostCreate(ctx *gin.Context) {
 		LockRankDuration(lockRankDuration).
 		AlwaysLock(requestData.AlwaysLock).
 		SubmitAnytime(requestData.SubmitAnytime).
+		PenaltyMinutes(requestData.GetPenaltyMinutes()).
+		PersonalDuration(requestData.GetPersonalDuration()).
+		PrintQuota(requestData.PrintQuota).
+		PrintPageLimit(requestData.PrintPageLimit).
+		Rated(requestData.Rated).
+		Exam(requestData.Exam).
+		IpWhitelist(requestData.GetIpWhitelist()).
+		Hack(requestData.Hack).
+		HackScore(requestData.GetHackScore()).
+		HackPenalty(requestData.GetHackPenalty()).
+		Pretest(requestData.Pretest).
 		Build()
 
 	// 创建ContestMember对象，使用请求中的contest_name
 	members := make([]*foundationmodel.ContestMember, 0, len(memberIds))
 	// 创建用户ID到ContestName的映射
 	memberNameMap := make(map[int]string)
+	memberLocationMap := make(map[int]*string)
 	for _, member := range requestData.Members {
 		memberNameMap[member.Id] = member.ContestName
+		memberLocationMap[member.Id] = member.Location
 	}
 	for _, uid := range memberIds {
 		members = append(members, foundationmodel.NewContestMemberBuilder().
 			UserId(uid).
 			ContestName(memberNameMap[uid]).
+			Location(memberLocationMap[uid]).
 			Build())
 	}
 
-	err = contestService.InsertContest(ctx, contest, problems, nil, nil, members, nil, nil, nil)
+	var volunteerIds []int
+	if len(requestData.Volunteers) > 0 {
+		volunteerIds, err = foundationservice.GetUserService().FilterValidUserIds(
+			ctx,
+			metaslice.RemoveDuplicate(requestData.Volunteers),
+		)
+		if err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+	}
+
+	err = contestService.InsertContest(
+		ctx,
+		contest,
+		problems,
+		requestData.GetLanguageKeys(),
+		nil,
+		members,
+		nil,
+		nil,
+		volunteerIds,
+	)
 	if err != nil {
 		metaresponse.NewResponseError(ctx, err)
 		return
@@ -744,6 +984,64 @@ func (c *ContestController) PostCreate(ctx *gin.Context) {
 	metaresponse.NewResponse(ctx, metaerrorcode.Success, contest)
 }
 
+// PostMirror 镜像Codeforces/AtCoder的比赛，可选导入原比赛榜单用于对比
+func (c *ContestController) PostMirror(ctx *gin.Context) {
+	var requestData request.ContestMirror
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, hasAuth, err := foundationservice.GetUserService().CheckUserAuth(ctx, foundationauth.AuthTypeManageProblem)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	remoteType := foundationremote.GetRemoteTypeByString(requestData.Oj)
+	if foundationremote.GetRemoteContestAgent(remoteType) == nil {
+		metaresponse.NewResponse(ctx, weberrorcode.ContestMirrorNotSupport, nil)
+		return
+	}
+
+	var password *string
+	if !metastring.IsEmpty(requestData.Password) {
+		password = requestData.Password
+	}
+	nowTime := metatime.GetTimeNow()
+	contest := foundationmodel.NewContestBuilder().
+		Title(requestData.Title).
+		NotificationVersion(0).
+		StartTime(requestData.StartTime).
+		Inserter(userId).
+		InsertTime(nowTime).
+		Modifier(userId).
+		ModifyTime(nowTime).
+		Private(requestData.Private).
+		Password(password).
+		PenaltyMinutes(request.ContestDefaultPenaltyMinutes).
+		Build()
+	err = foundationservice.GetContestService().MirrorRemoteContest(
+		ctx,
+		remoteType,
+		requestData.ContestId,
+		contest,
+		requestData.GhostLimit,
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, contest)
+}
+
 func (c *ContestController) PostEdit(ctx *gin.Context) {
 	var requestData request.ContestEdit
 	if err := ctx.ShouldBindJSON(&requestData); err != nil {
@@ -867,11 +1165,17 @@ func (c *ContestController) PostEdit(ctx *gin.Context) {
 
 	var problems []*foundationmodel.ContestProblem
 	for _, problemId := range realProblemIds {
+		setting := requestData.GetProblemSetting(problemId)
 		problems = append(
 			problems, foundationmodel.NewContestProblemBuilder().
 				ProblemId(problemId).
-				ViewId(nil).                   // 题目描述Id，默认为nil
-				Score(0).                      // 分数默认为0
+				ViewId(nil).          // 题目描述Id，默认为nil
+				Score(setting.Score). // 分数为0时按默认分数计算
+				ScoreDecay(setting.ScoreDecay).
+				ScorePenalty(setting.ScorePenalty).
+				ScoreMin(setting.ScoreMin).
+				TimeLimit(setting.TimeLimit).
+				MemoryLimit(setting.MemoryLimit).
 				Index(uint8(len(problems)+1)). // 索引从1开始
 				Build(),
 		)
@@ -904,23 +1208,59 @@ func (c *ContestController) PostEdit(ctx *gin.Context) {
 		LockRankDuration(lockRankDuration).
 		AlwaysLock(requestData.AlwaysLock).
 		SubmitAnytime(requestData.SubmitAnytime).
+		PenaltyMinutes(requestData.GetPenaltyMinutes()).
+		PersonalDuration(requestData.GetPersonalDuration()).
+		PrintQuota(requestData.PrintQuota).
+		PrintPageLimit(requestData.PrintPageLimit).
+		Rated(requestData.Rated).
+		Exam(requestData.Exam).
+		IpWhitelist(requestData.GetIpWhitelist()).
+		Hack(requestData.Hack).
+		HackScore(requestData.GetHackScore()).
+		HackPenalty(requestData.GetHackPenalty()).
+		Pretest(requestData.Pretest).
 		Build()
 
 	// 创建ContestMember对象，使用请求中的contest_name
 	members := make([]*foundationmodel.ContestMember, 0, len(memberIds))
 	// 创建用户ID到ContestName的映射
 	memberNameMap := make(map[int]string)
+	memberLocationMap := make(map[int]*string)
 	for _, member := range requestData.Members {
 		memberNameMap[member.Id] = member.ContestName
+		memberLocationMap[member.Id] = member.Location
 	}
 	for _, uid := range memberIds {
 		members = append(members, foundationmodel.NewContestMemberBuilder().
 			UserId(uid).
 			ContestName(memberNameMap[uid]).
+			Location(memberLocationMap[uid]).
 			Build())
 	}
 
-	err = contestService.UpdateContest(ctx, contest, problems, nil, nil, members, nil, nil, nil)
+	var volunteerIds []int
+	if len(requestData.Volunteers) > 0 {
+		volunteerIds, err = foundationservice.GetUserService().FilterValidUserIds(
+			ctx,
+			metaslice.RemoveDuplicate(requestData.Volunteers),
+		)
+		if err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+	}
+
+	err = contestService.UpdateContest(
+		ctx,
+		contest,
+		problems,
+		requestData.GetLanguageKeys(),
+		nil,
+		members,
+		nil,
+		nil,
+		volunteerIds,
+	)
 	if err != nil {
 		metaresponse.NewResponseError(ctx, err)
 		return
@@ -943,15 +1283,85 @@ func (c *ContestController) PostEdit(ctx *gin.Context) {
 	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
 }
 
-func (c *ContestController) PostDolos(ctx *gin.Context) {
+func (c *ContestController) PostTeamEdit(ctx *gin.Context) {
+	var requestData request.ContestTeamEdit
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	userId, hasAuth, err := foundationservice.GetContestService().CheckEditAuth(ctx, requestData.ContestId)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	team := foundationmodel.NewContestTeamBuilder().
+		Id(requestData.Id).
+		ContestId(requestData.ContestId).
+		Inserter(userId).
+		InsertTime(metatime.GetTimeNow()).
+		Build()
+	err = foundationservice.GetContestService().SaveContestTeam(
+		ctx,
+		team,
+		&foundationservice.ContestTeamInput{
+			Name:    requestData.Name,
+			Members: requestData.Members,
+		},
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, team.Id)
+}
+
+func (c *ContestController) PostTeamDelete(ctx *gin.Context) {
 	var requestData struct {
-		Id int `json:"id" binding:"required"`
+		ContestId int `json:"contest_id" binding:"required"`
+		Id        int `json:"id" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&requestData); err != nil {
 		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
 		return
 	}
-	_, hasAuth, err := foundationservice.GetContestService().CheckEditAuth(ctx, requestData.Id)
+	_, hasAuth, err := foundationservice.GetContestService().CheckEditAuth(ctx, requestData.ContestId)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	err = foundationservice.GetContestService().DeleteContestTeam(ctx, requestData.ContestId, requestData.Id)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+func (c *ContestController) PostTeamImport(ctx *gin.Context) {
+	var requestData request.ContestTeamImport
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	userId, hasAuth, err := foundationservice.GetContestService().CheckEditAuth(ctx, requestData.ContestId)
 	if err != nil {
 		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
 		return
@@ -960,14 +1370,95 @@ func (c *ContestController) PostDolos(ctx *gin.Context) {
 		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
 		return
 	}
+	inputs := make([]*foundationservice.ContestTeamInput, 0, len(requestData.Teams))
+	for _, item := range requestData.Teams {
+		inputs = append(
+			inputs, &foundationservice.ContestTeamInput{
+				Name:    item.Name,
+				Members: item.Members,
+			},
+		)
+	}
+	err = foundationservice.GetContestService().ImportContestTeams(
+		ctx,
+		requestData.ContestId,
+		userId,
+		inputs,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, len(inputs))
+}
 
-	url, err := foundationservice.GetContestService().DolosContest(ctx, requestData.Id)
+func (c *ContestController) PostVirtual(ctx *gin.Context) {
+	var requestData struct {
+		Id int `json:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, requestData.Id)
 	if err != nil {
 		metaresponse.NewResponseError(ctx, err)
 		return
 	}
+	if userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	virtual, err := foundationservice.GetContestService().StartContestVirtual(
+		ctx,
+		requestData.Id,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, virtual)
+}
 
-	metaresponse.NewResponse(ctx, metaerrorcode.Success, url)
+func (c *ContestController) PostStart(ctx *gin.Context) {
+	var requestData struct {
+		Id int `json:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, hasAuth, err := foundationservice.GetContestService().CheckViewAuthWithoutStartTime(ctx, requestData.Id)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	startTime, err := foundationservice.GetContestService().StartContestWindow(
+		ctx,
+		requestData.Id,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, startTime)
 }
 
 func (c *ContestController) PostPassword(ctx *gin.Context) {
@@ -1020,7 +1511,7 @@ func (c *ContestController) PostMemberEditSelf(ctx *gin.Context) {
 	}
 	userId, hasAuth, err := foundationservice.GetContestService().CheckViewAuthWithoutStartTime(ctx, requestData.Id)
 	if err != nil {
-		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		metaresponse.NewResponseError(ctx, err)
 		return
 	}
 	if !hasAuth {
@@ -1039,3 +1530,30 @@ func (c *ContestController) PostMemberEditSelf(ctx *gin.Context) {
 	}
 	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
 }
+
+// PostSystemTest 比赛结束后开始系统测试，返回重新评测的提交数量
+func (c *ContestController) PostSystemTest(ctx *gin.Context) {
+	var requestData struct {
+		ContestId int `json:"contest_id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	_, hasAuth, err := contestService.CheckEditAuth(ctx, requestData.ContestId)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	count, err := contestService.StartContestSystemTest(ctx, requestData.ContestId, metatime.GetTimeNow())
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, count)
+}
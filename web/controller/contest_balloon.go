@@ -0,0 +1,129 @@
+package controller
+
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationenum "foundation/foundation-enum"
+	foundationservice "foundation/foundation-service"
+	foundationview "foundation/foundation-view"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+	metatime "meta/meta-time"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type contestBalloonRequest struct {
+	ContestId int `json:"contest_id" binding:"required"`
+	Id        int `json:"id" binding:"required"`
+}
+
+// checkContestBalloonRequest 解析请求并检查配送权限，失败时已写入响应
+func (c *ContestController) checkContestBalloonRequest(ctx *gin.Context) (*contestBalloonRequest, int, bool) {
+	var requestData contestBalloonRequest
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return nil, 0, false
+	}
+	userId, hasAuth, err := foundationservice.GetContestService().CheckVolunteerAuth(ctx, requestData.ContestId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return nil, 0, false
+	}
+	return &requestData, userId, true
+}
+
+func (c *ContestController) GetBalloonList(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	var status *foundationenum.ContestBalloonStatus
+	if statusStr := ctx.Query("status"); statusStr != "" {
+		statusInt, err := strconv.Atoi(statusStr)
+		if err != nil {
+			metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+			return
+		}
+		balloonStatus := foundationenum.ContestBalloonStatus(statusInt)
+		status = &balloonStatus
+	}
+	_, hasAuth, err := foundationservice.GetContestService().CheckVolunteerAuth(ctx, contestId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	balloons, err := foundationservice.GetContestService().GetContestBalloons(
+		ctx,
+		contestId,
+		status,
+		ctx.Query("location"),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Balloons []*foundationview.ContestBalloon `json:"balloons"`
+	}{
+		Balloons: balloons,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+func (c *ContestController) PostBalloonClaim(ctx *gin.Context) {
+	requestData, userId, ok := c.checkContestBalloonRequest(ctx)
+	if !ok {
+		return
+	}
+	err := foundationservice.GetContestService().ClaimContestBalloon(
+		ctx,
+		requestData.ContestId,
+		requestData.Id,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+func (c *ContestController) PostBalloonRelease(ctx *gin.Context) {
+	requestData, userId, ok := c.checkContestBalloonRequest(ctx)
+	if !ok {
+		return
+	}
+	err := foundationservice.GetContestService().ReleaseContestBalloon(
+		ctx,
+		requestData.ContestId,
+		requestData.Id,
+		userId,
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+func (c *ContestController) PostBalloonDeliver(ctx *gin.Context) {
+	requestData, userId, ok := c.checkContestBalloonRequest(ctx)
+	if !ok {
+		return
+	}
+	err := foundationservice.GetContestService().DeliverContestBalloon(
+		ctx,
+		requestData.ContestId,
+		requestData.Id,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
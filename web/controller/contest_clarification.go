@@ -0,0 +1,306 @@
+package controller
+
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationauth "foundation/foundation-auth"
+	foundationmodel "foundation/foundation-model"
+	foundationservice "foundation/foundation-service"
+	foundationview "foundation/foundation-view"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+	metatime "meta/meta-time"
+	"strconv"
+	"web/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *ContestController) GetClarificationList(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	userId, hasAuth, err := contestService.CheckViewAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	_, isJury, err := contestService.CheckJuryAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	clarifications, err := contestService.GetContestClarifications(ctx, contestId, userId, isJury)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	// 查看列表即视为已读
+	if userId > 0 && !isJury {
+		err = contestService.ReadContestClarifications(ctx, contestId, userId, metatime.GetTimeNow())
+		if err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+	}
+	responseData := struct {
+		Clarifications []*foundationview.ContestClarification `json:"clarifications"`
+		IsJury         bool                                   `json:"is_jury"`
+	}{
+		Clarifications: clarifications,
+		IsJury:         isJury,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+func (c *ContestController) GetClarificationUnread(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	userId, hasAuth, err := contestService.CheckViewAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	_, isJury, err := contestService.CheckJuryAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	count, err := contestService.GetContestClarificationUnread(ctx, contestId, userId, isJury)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Count  int  `json:"count"`
+		IsJury bool `json:"is_jury"`
+	}{
+		Count:  count,
+		IsJury: isJury,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+func (c *ContestController) PostClarificationAsk(ctx *gin.Context) {
+	var requestData request.ContestClarificationAsk
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	userId, hasAuth, err := contestService.CheckViewAuth(ctx, requestData.ContestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	clarification, err := contestService.AskContestClarification(
+		ctx,
+		requestData.ContestId,
+		userId,
+		requestData.ProblemIndex,
+		requestData.Question,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, clarification.Id)
+}
+
+func (c *ContestController) PostClarificationAnswer(ctx *gin.Context) {
+	var requestData request.ContestClarificationAnswer
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	userId, hasAuth, err := contestService.CheckJuryAuth(ctx, requestData.ContestId)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	err = contestService.AnswerContestClarification(
+		ctx,
+		requestData.ContestId,
+		requestData.Id,
+		requestData.Answer,
+		requestData.Public,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+func (c *ContestController) PostClarificationBroadcast(ctx *gin.Context) {
+	var requestData request.ContestClarificationBroadcast
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	userId, hasAuth, err := contestService.CheckJuryAuth(ctx, requestData.ContestId)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	clarification, err := contestService.BroadcastContestClarification(
+		ctx,
+		requestData.ContestId,
+		requestData.ProblemIndex,
+		requestData.Content,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, clarification.Id)
+}
+
+func (c *ContestController) PostClarificationDelete(ctx *gin.Context) {
+	var requestData struct {
+		ContestId int `json:"contest_id" binding:"required"`
+		Id        int `json:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	_, hasAuth, err := contestService.CheckJuryAuth(ctx, requestData.ContestId)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	err = contestService.DeleteContestClarification(ctx, requestData.ContestId, requestData.Id)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+func (c *ContestController) GetClarificationTemplateList(ctx *gin.Context) {
+	userId, err := foundationauth.GetUserIdFromContext(ctx)
+	if err != nil || userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	templates, err := foundationservice.GetContestService().GetClarificationTemplates(ctx, userId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Templates []*foundationmodel.ContestClarificationTemplate `json:"templates"`
+	}{
+		Templates: templates,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+func (c *ContestController) PostClarificationTemplateEdit(ctx *gin.Context) {
+	var requestData request.ContestClarificationTemplateEdit
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	userId, err := foundationauth.GetUserIdFromContext(ctx)
+	if err != nil || userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	template := foundationmodel.NewContestClarificationTemplateBuilder().
+		Id(requestData.Id).
+		Title(requestData.Title).
+		Content(requestData.Content).
+		Inserter(userId).
+		InsertTime(metatime.GetTimeNow()).
+		Build()
+	if err := foundationservice.GetContestService().SaveClarificationTemplate(ctx, template); err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, template.Id)
+}
+
+func (c *ContestController) PostClarificationTemplateDelete(ctx *gin.Context) {
+	var requestData struct {
+		Id int `json:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, err := foundationauth.GetUserIdFromContext(ctx)
+	if err != nil || userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	err = foundationservice.GetContestService().DeleteClarificationTemplate(ctx, requestData.Id, userId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationservice "foundation/foundation-service"
+	foundationview "foundation/foundation-view"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetExamReport 监考人员查看比赛期间从多个IP登录的成员
+func (c *ContestController) GetExamReport(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	_, hasAuth, err := contestService.CheckJuryAuth(ctx, contestId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	reports, err := contestService.GetContestLoginReports(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Reports []*foundationview.ContestLoginReport `json:"reports"`
+	}{
+		Reports: reports,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"fmt"
+	foundationerrorcode "foundation/error-code"
+	foundationexport "foundation/foundation-export"
+	foundationservice "foundation/foundation-service"
+	metapanic "meta/meta-panic"
+	metaresponse "meta/meta-response"
+	metatime "meta/meta-time"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkExportAuth 导出数据需要比赛的编辑权限
+func (c *ContestController) checkExportAuth(ctx *gin.Context) (int, bool) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return 0, false
+	}
+	_, hasAuth, err := foundationservice.GetContestService().CheckEditAuth(ctx, contestId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return 0, false
+	}
+	return contestId, true
+}
+
+// writeExport 边生成边输出文件，开始输出后出错只能中断响应
+func (c *ContestController) writeExport(ctx *gin.Context, contentType string, fileName string, export func() error) {
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	ctx.Status(http.StatusOK)
+	err := export()
+	if err == nil {
+		return
+	}
+	if !ctx.Writer.Written() {
+		// 还没有输出内容时仍可以按普通接口返回错误
+		ctx.Header("Content-Type", "")
+		ctx.Header("Content-Disposition", "")
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metapanic.ProcessError(err)
+	ctx.Abort()
+}
+
+// GetExportStandings 导出榜单，format为csv或xlsx
+func (c *ContestController) GetExportStandings(ctx *gin.Context) {
+	contestId, ok := c.checkExportAuth(ctx)
+	if !ok {
+		return
+	}
+	format := ctx.Query("format")
+	contentType, extension := foundationexport.GetTableContentType(format)
+	c.writeExport(
+		ctx, contentType, fmt.Sprintf("contest-%d-standings.%s", contestId, extension), func() error {
+			return foundationservice.GetContestService().ExportContestStandings(
+				ctx,
+				contestId,
+				metatime.GetTimeNow(),
+				format,
+				ctx.Writer,
+			)
+		},
+	)
+}
+
+// GetExportSubmissions 导出全部提交记录，format为csv或xlsx
+func (c *ContestController) GetExportSubmissions(ctx *gin.Context) {
+	contestId, ok := c.checkExportAuth(ctx)
+	if !ok {
+		return
+	}
+	format := ctx.Query("format")
+	contentType, extension := foundationexport.GetTableContentType(format)
+	c.writeExport(
+		ctx, contentType, fmt.Sprintf("contest-%d-submissions.%s", contestId, extension), func() error {
+			return foundationservice.GetContestService().ExportContestSubmissions(ctx, contestId, format, ctx.Writer)
+		},
+	)
+}
+
+// GetExportCodes 导出每个用户每道题最终提交的代码压缩包
+func (c *ContestController) GetExportCodes(ctx *gin.Context) {
+	contestId, ok := c.checkExportAuth(ctx)
+	if !ok {
+		return
+	}
+	c.writeExport(
+		ctx, "application/zip", fmt.Sprintf("contest-%d-codes.zip", contestId), func() error {
+			return foundationservice.GetContestService().ExportContestCodes(ctx, contestId, ctx.Writer)
+		},
+	)
+}
+
+// GetExportStatements 导出用于打印的题面LaTeX源文件与图片压缩包
+func (c *ContestController) GetExportStatements(ctx *gin.Context) {
+	contestId, ok := c.checkExportAuth(ctx)
+	if !ok {
+		return
+	}
+	c.writeExport(
+		ctx, "application/zip", fmt.Sprintf("contest-%d-statements.zip", contestId), func() error {
+			return foundationservice.GetContestService().ExportContestStatements(ctx, contestId, ctx.Writer)
+		},
+	)
+}
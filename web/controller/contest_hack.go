@@ -0,0 +1,218 @@
+package controller
+
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationservice "foundation/foundation-service"
+	foundationview "foundation/foundation-view"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+	metatime "meta/meta-time"
+	"strconv"
+	"web/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkContestHackAuth 挑战相关的接口需要登录且有比赛的查看权限，失败时已写入响应
+func (c *ContestController) checkContestHackAuth(ctx *gin.Context, contestId int) (int, bool) {
+	userId, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return 0, false
+	}
+	if userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return 0, false
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return 0, false
+	}
+	return userId, true
+}
+
+// PostProblemLock 锁定已通过的题目，锁定后不能再提交该题
+func (c *ContestController) PostProblemLock(ctx *gin.Context) {
+	var requestData request.ContestProblemLock
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	userId, ok := c.checkContestHackAuth(ctx, requestData.ContestId)
+	if !ok {
+		return
+	}
+	err := foundationservice.GetContestService().LockContestProblem(
+		ctx,
+		requestData.ContestId,
+		requestData.ProblemId,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+// GetProblemLock 获取自己已经锁定的题目
+func (c *ContestController) GetProblemLock(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, ok := c.checkContestHackAuth(ctx, contestId)
+	if !ok {
+		return
+	}
+	problemIds, err := foundationservice.GetContestService().GetLockedProblemIds(ctx, contestId, userId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Problems []int `json:"problems"`
+	}{
+		Problems: problemIds,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+// GetHackTargets 获取锁定题目后可以挑战的提交
+func (c *ContestController) GetHackTargets(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	problemId, err := strconv.Atoi(ctx.Query("problem_id"))
+	if err != nil || problemId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, ok := c.checkContestHackAuth(ctx, contestId)
+	if !ok {
+		return
+	}
+	targets, err := foundationservice.GetContestService().GetContestHackTargets(
+		ctx,
+		contestId,
+		problemId,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Targets []*foundationview.ContestHackTarget `json:"targets"`
+	}{
+		Targets: targets,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+// GetHackCode 查看要挑战的代码
+func (c *ContestController) GetHackCode(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	judgeId, err := strconv.Atoi(ctx.Query("judge_id"))
+	if err != nil || judgeId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, ok := c.checkContestHackAuth(ctx, contestId)
+	if !ok {
+		return
+	}
+	target, err := foundationservice.GetContestService().GetContestHackCode(
+		ctx,
+		contestId,
+		judgeId,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, target)
+}
+
+// PostHack 提交挑战数据，结果由评测机异步给出
+func (c *ContestController) PostHack(ctx *gin.Context) {
+	var requestData request.ContestHack
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	userId, ok := c.checkContestHackAuth(ctx, requestData.ContestId)
+	if !ok {
+		return
+	}
+	hack, err := foundationservice.GetContestService().SubmitContestHack(
+		ctx,
+		requestData.ContestId,
+		requestData.JudgeId,
+		userId,
+		requestData.Input,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Id int `json:"id"`
+	}{
+		Id: hack.Id,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+// GetHackList 查看比赛的挑战记录，problem_id为空时返回全部题目
+func (c *ContestController) GetHackList(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	problemId := 0
+	if problemIdStr := ctx.Query("problem_id"); problemIdStr != "" {
+		problemId, err = strconv.Atoi(problemIdStr)
+		if err != nil || problemId <= 0 {
+			metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+			return
+		}
+	}
+	if _, ok := c.checkContestHackAuth(ctx, contestId); !ok {
+		return
+	}
+	hacks, err := foundationservice.GetContestService().GetContestHacks(ctx, contestId, problemId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Hacks []*foundationview.ContestHack `json:"hacks"`
+	}{
+		Hacks: hacks,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	foundationerrorcode "foundation/error-code"
+	foundationauth "foundation/foundation-auth"
+	foundationexport "foundation/foundation-export"
+	foundationservice "foundation/foundation-service"
+	foundationview "foundation/foundation-view"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+	metatime "meta/meta-time"
+	"net/http"
+	"web/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkMemberImportAuth 批量创建账号影响整个站点，除比赛编辑权限外还需要比赛管理员权限
+func (c *ContestController) checkMemberImportAuth(ctx *gin.Context, contestId int) bool {
+	_, hasAuth, err := foundationservice.GetUserService().CheckUserAuth(ctx, foundationauth.AuthTypeManageContest)
+	if err == nil && hasAuth {
+		_, hasAuth, err = foundationservice.GetContestService().CheckEditAuth(ctx, contestId)
+	}
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return false
+	}
+	return true
+}
+
+// PostMemberImport 批量导入现场赛成员，返回包含初始密码的账号表，密码只在此时返回一次
+func (c *ContestController) PostMemberImport(ctx *gin.Context) {
+	var requestData request.ContestMemberImport
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	if !c.checkMemberImportAuth(ctx, requestData.ContestId) {
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	inputs := make([]*foundationservice.ContestMemberImportInput, 0, len(requestData.Members))
+	for _, item := range requestData.Members {
+		inputs = append(
+			inputs, &foundationservice.ContestMemberImportInput{
+				Username:     item.Username,
+				Name:         item.Name,
+				Organization: item.Organization,
+				Location:     item.Location,
+			},
+		)
+	}
+	if requestData.Format == "pdf" {
+		if err := contestService.CheckContestMemberImportPdf(inputs, requestData.Prefix); err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+	}
+	credentials, err := contestService.ImportContestMembers(
+		ctx,
+		requestData.ContestId,
+		inputs,
+		requestData.Prefix,
+		requestData.ContestOnly,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	fileName := fmt.Sprintf("contest-%d-accounts.%s", requestData.ContestId, requestData.Format)
+	switch requestData.Format {
+	case "csv":
+		var buf bytes.Buffer
+		table, err := foundationexport.NewCsvTableWriter(&buf)
+		if err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+		_ = table.WriteRow("Name", "School", "Seat", "Username", "Password")
+		for _, credential := range credentials {
+			_ = table.WriteRow(
+				credential.Name,
+				credential.Organization,
+				credential.Location,
+				credential.Username,
+				credential.Password,
+			)
+		}
+		if err := table.Close(); err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+		ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+		ctx.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	case "pdf":
+		data, err := contestService.RenderContestMemberCredentials(
+			fmt.Sprintf("Contest %d accounts", requestData.ContestId),
+			credentials,
+		)
+		if err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+		ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+		ctx.Data(http.StatusOK, "application/pdf", data)
+	default:
+		responseData := struct {
+			Credentials []*foundationview.ContestMemberCredential `json:"credentials"`
+		}{
+			Credentials: credentials,
+		}
+		metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+	}
+}
+
+// PostMemberDisable 禁用或重新启用为比赛生成的临时账号
+func (c *ContestController) PostMemberDisable(ctx *gin.Context) {
+	var requestData struct {
+		ContestId int  `json:"contest_id" binding:"required"`
+		Disabled  bool `json:"disabled"`
+	}
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	if !c.checkMemberImportAuth(ctx, requestData.ContestId) {
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	count, err := contestService.SetContestOnlyUsersDisabled(
+		ctx,
+		requestData.ContestId,
+		requestData.Disabled,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, count)
+}
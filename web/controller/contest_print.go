@@ -0,0 +1,272 @@
+package controller
+
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationenum "foundation/foundation-enum"
+	foundationservice "foundation/foundation-service"
+	foundationview "foundation/foundation-view"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+	metatime "meta/meta-time"
+	"net/http"
+	"strconv"
+	"web/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+type contestPrintRequest struct {
+	ContestId int `json:"contest_id" binding:"required"`
+	Id        int `json:"id" binding:"required"`
+}
+
+// checkContestPrintRequest 解析请求并检查打印队列的处理权限，失败时已写入响应
+func (c *ContestController) checkContestPrintRequest(ctx *gin.Context) (*contestPrintRequest, int, bool) {
+	var requestData contestPrintRequest
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return nil, 0, false
+	}
+	userId, hasAuth, err := foundationservice.GetContestService().CheckVolunteerAuth(ctx, requestData.ContestId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return nil, 0, false
+	}
+	return &requestData, userId, true
+}
+
+func (c *ContestController) PostPrint(ctx *gin.Context) {
+	var requestData request.ContestPrint
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	userId, hasAuth, err := contestService.CheckViewAuth(ctx, requestData.ContestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	contestPrint, err := contestService.SubmitContestPrint(
+		ctx,
+		requestData.ContestId,
+		userId,
+		requestData.Language,
+		requestData.Content,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Id    int `json:"id"`
+		Pages int `json:"pages"`
+	}{
+		Id:    contestPrint.Id,
+		Pages: contestPrint.Pages,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+// GetPrintList 选手查看自己（队伍）的打印任务
+func (c *ContestController) GetPrintList(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	contestService := foundationservice.GetContestService()
+	userId, hasAuth, err := contestService.CheckViewAuth(ctx, contestId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	prints, err := contestService.GetUserContestPrints(ctx, contestId, userId)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Prints []*foundationview.ContestPrint `json:"prints"`
+	}{
+		Prints: prints,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+// GetPrintQueue 志愿者查看打印队列
+func (c *ContestController) GetPrintQueue(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	var status *foundationenum.ContestPrintStatus
+	if statusStr := ctx.Query("status"); statusStr != "" {
+		statusInt, err := strconv.Atoi(statusStr)
+		if err != nil {
+			metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+			return
+		}
+		printStatus := foundationenum.ContestPrintStatus(statusInt)
+		status = &printStatus
+	}
+	_, hasAuth, err := foundationservice.GetContestService().CheckVolunteerAuth(ctx, contestId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	prints, err := foundationservice.GetContestService().GetContestPrintQueue(
+		ctx,
+		contestId,
+		status,
+		ctx.Query("location"),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Prints []*foundationview.ContestPrint `json:"prints"`
+	}{
+		Prints: prints,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+// PostPrintNext 打印程序领取下一个任务，没有任务时返回空
+func (c *ContestController) PostPrintNext(ctx *gin.Context) {
+	var requestData struct {
+		ContestId int    `json:"contest_id" binding:"required"`
+		Location  string `json:"location"`
+	}
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	userId, hasAuth, err := foundationservice.GetContestService().CheckVolunteerAuth(ctx, requestData.ContestId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	contestPrint, err := foundationservice.GetContestService().ClaimNextContestPrint(
+		ctx,
+		requestData.ContestId,
+		userId,
+		requestData.Location,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, contestPrint)
+}
+
+func (c *ContestController) PostPrintClaim(ctx *gin.Context) {
+	requestData, userId, ok := c.checkContestPrintRequest(ctx)
+	if !ok {
+		return
+	}
+	err := foundationservice.GetContestService().ClaimContestPrint(
+		ctx,
+		requestData.ContestId,
+		requestData.Id,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+func (c *ContestController) PostPrintRelease(ctx *gin.Context) {
+	requestData, userId, ok := c.checkContestPrintRequest(ctx)
+	if !ok {
+		return
+	}
+	err := foundationservice.GetContestService().ReleaseContestPrint(
+		ctx,
+		requestData.ContestId,
+		requestData.Id,
+		userId,
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+func (c *ContestController) PostPrintFinish(ctx *gin.Context) {
+	requestData, userId, ok := c.checkContestPrintRequest(ctx)
+	if !ok {
+		return
+	}
+	err := foundationservice.GetContestService().FinishContestPrint(
+		ctx,
+		requestData.ContestId,
+		requestData.Id,
+		userId,
+		metatime.GetTimeNow(),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, nil)
+}
+
+// GetPrintFile 下载打印文件，format=text时返回纯文本，默认返回PDF
+func (c *ContestController) GetPrintFile(ctx *gin.Context) {
+	contestId, err := strconv.Atoi(ctx.Query("contest_id"))
+	if err != nil || contestId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	printId, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || printId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	_, hasAuth, err := foundationservice.GetContestService().CheckVolunteerAuth(ctx, contestId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	data, contentType, err := foundationservice.GetContestService().RenderContestPrint(
+		ctx,
+		contestId,
+		printId,
+		ctx.Query("format"),
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, data)
+}
@@ -167,7 +167,7 @@ func (c *DiscussController) GetCommentImageToken(ctx *gin.Context) {
 	}
 	userId, hasAuth, err := foundationservice.GetDiscussService().CheckViewAuth(ctx, id)
 	if err != nil {
-		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		metaresponse.NewResponseError(ctx, err)
 		return
 	}
 	if !hasAuth {
@@ -269,7 +269,7 @@ func (c *DiscussController) GetList(ctx *gin.Context) {
 		contestId, err = strconv.Atoi(contestIdStr)
 		_, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
 		if err != nil {
-			metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
+			metaresponse.NewResponseError(ctx, err)
 			return
 		}
 		if !hasAuth {
@@ -407,7 +407,7 @@ func (c *DiscussController) PostCreate(ctx *gin.Context) {
 		contestService := foundationservice.GetContestService()
 		_, hasAuth, err := contestService.CheckViewAuth(ctx, requestData.ContestId)
 		if err != nil {
-			metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
+			metaresponse.NewResponseError(ctx, err)
 			return
 		}
 		if !hasAuth {
@@ -646,7 +646,7 @@ func (c *DiscussController) PostCommentCreate(ctx *gin.Context) {
 
 	userId, hasAuth, err := discussService.CheckViewAuth(ctx, discussId)
 	if err != nil {
-		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		metaresponse.NewResponseError(ctx, err)
 		return
 	}
 	if !hasAuth {
@@ -78,6 +78,7 @@ func (c *JudgeController) Get(ctx *gin.Context) {
 		"time",
 		"memory",
 		"private",
+		"pretest",
 	}
 	judgeJob, err := judgeService.GetJudge(ctx, id, fields)
 	if err != nil {
@@ -189,7 +190,7 @@ func (c *JudgeController) GetList(ctx *gin.Context) {
 		}
 		_, hasAuth, err := foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
 		if err != nil {
-			metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
+			metaresponse.NewResponseError(ctx, err)
 			return
 		}
 		if !hasAuth {
@@ -298,10 +299,11 @@ func (c *JudgeController) PostApprove(ctx *gin.Context) {
 	}
 	var userId int
 	var hasAuth bool
+	var virtualId int
 	if problemId > 0 {
 		userId, hasAuth, err = foundationservice.GetProblemService().CheckSubmitAuth(ctx, problemId)
 		if err != nil {
-			metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+			metaresponse.NewResponseError(ctx, err)
 			return
 		}
 		if userId <= 0 {
@@ -329,20 +331,67 @@ func (c *JudgeController) PostApprove(ctx *gin.Context) {
 			metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
 			return
 		}
-		userId, hasAuth, err = foundationservice.GetContestService().CheckSubmitAuth(
+		// 虚拟参赛期间的提交标记为虚拟提交
+		virtualId, err = foundationservice.GetContestService().GetActiveContestVirtualId(
 			ctx,
 			contestId,
+			userId,
+			metatime.GetTimeNow(),
 		)
 		if err != nil {
-			metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+			metaresponse.NewResponseError(ctx, err)
 			return
 		}
+		if virtualId > 0 {
+			hasAuth = true
+		} else {
+			userId, hasAuth, err = foundationservice.GetContestService().CheckSubmitAuth(
+				ctx,
+				contestId,
+			)
+			if err != nil {
+				metaresponse.NewResponseError(ctx, err)
+				return
+			}
+		}
 	}
 	if !hasAuth {
 		metaresponse.NewResponse(ctx, weberrorcode.JudgeJobCannotApprove, nil)
 		return
 	}
 
+	// 比赛与题集可以限制提交的语言
+	languageAllowed := true
+	if contestId > 0 {
+		languageAllowed, err = foundationservice.GetContestService().CheckLanguage(ctx, contestId, language)
+	} else if judgeApprove.CollectionId > 0 {
+		languageAllowed, err = foundationservice.GetCollectionService().CheckLanguage(
+			ctx,
+			judgeApprove.CollectionId,
+			language,
+		)
+	}
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if !languageAllowed {
+		metaresponse.NewResponse(ctx, weberrorcode.JudgeApproveLanguageNotAllowed, nil)
+		return
+	}
+	// 锁定题目后不能再提交，虚拟参赛不受影响
+	if contestId > 0 && virtualId <= 0 {
+		locked, err := foundationservice.GetContestService().IsContestProblemLocked(ctx, contestId, problemId, userId)
+		if err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+		if locked {
+			metaresponse.NewResponse(ctx, weberrorcode.ContestProblemLocked, nil)
+			return
+		}
+	}
+
 	problem, err := foundationservice.GetProblemService().GetProblemViewApproveJudge(ctx, problemId)
 	if err != nil {
 		metaresponse.NewResponseError(ctx, err)
@@ -371,6 +420,7 @@ func (c *JudgeController) PostApprove(ctx *gin.Context) {
 	judgeJob := foundationmodel.NewJudgeJobBuilder().
 		ProblemId(problemId).
 		ContestId(contestId).
+		VirtualId(virtualId).
 		Inserter(userId).
 		InsertTime(nowTime).
 		Language(language).
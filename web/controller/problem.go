@@ -21,6 +21,7 @@ import (
 	metahttp "meta/meta-http"
 	metapanic "meta/meta-panic"
 	metaresponse "meta/meta-response"
+	metaslice "meta/meta-slice"
 	metastring "meta/meta-string"
 	metatime "meta/meta-time"
 	metazip "meta/meta-zip"
@@ -56,6 +57,7 @@ func (c *ProblemController) Get(ctx *gin.Context) {
 	problemService := foundationservice.GetProblemService()
 	problemKey := ctx.Query("key")
 	isContest := false
+	contestId := 0
 	problemId := 0
 	var userId int
 	var hasAuth bool
@@ -65,7 +67,7 @@ func (c *ProblemController) Get(ctx *gin.Context) {
 			metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
 			return
 		}
-		contestId, err := strconv.Atoi(contestIdStr)
+		contestId, err = strconv.Atoi(contestIdStr)
 		if err != nil || contestId <= 0 {
 			metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
 			return
@@ -88,7 +90,7 @@ func (c *ProblemController) Get(ctx *gin.Context) {
 		// 判断是否有
 		userId, hasAuth, err = foundationservice.GetContestService().CheckViewAuth(ctx, contestId)
 		if err != nil {
-			metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
+			metaresponse.NewResponseError(ctx, err)
 			return
 		}
 		if !hasAuth {
@@ -133,6 +135,18 @@ func (c *ProblemController) Get(ctx *gin.Context) {
 		problem.OriginOj = nil
 		problem.OriginId = nil
 		problem.OriginUrl = nil
+		// 比赛中设置了单独的限制时展示比赛的限制
+		limit, err := foundationservice.GetContestService().GetProblemLimit(ctx, contestId, problemId)
+		if err != nil {
+			metaresponse.NewResponseError(ctx, err)
+			return
+		}
+		if limit != nil && limit.TimeLimit != nil {
+			problem.TimeLimit = *limit.TimeLimit
+		}
+		if limit != nil && limit.MemoryLimit != nil {
+			problem.MemoryLimit = *limit.MemoryLimit
+		}
 	} else {
 		tags, err = problemService.GetProblemTags(ctx, problemId)
 		if err != nil {
@@ -728,6 +742,106 @@ func (c *ProblemController) PostCrawl(ctx *gin.Context) {
 	metaresponse.NewResponse(ctx, metaerrorcode.Success, newId)
 }
 
+func (c *ProblemController) GetCrawlJob(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	_, hasAuth, err := foundationservice.GetUserService().CheckUserAuth(ctx, foundationauth.AuthTypeManageProblem)
+	if err != nil {
+		metapanic.ProcessError(err)
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	var statuses []foundationenum.ProblemCrawlStatus
+	statusStr := ctx.Query("status")
+	if statusStr != "" {
+		status, err := strconv.Atoi(statusStr)
+		if err != nil {
+			metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+			return
+		}
+		statuses = append(statuses, foundationenum.ProblemCrawlStatus(status))
+	}
+	job, err := foundationservice.GetProblemCrawlJobService().GetProblemCrawlJob(ctx, id, statuses)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if job == nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NotFound, nil)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, job)
+}
+
+func (c *ProblemController) PostCrawlJob(ctx *gin.Context) {
+	var requestData request.ProblemCrawlJob
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	if ok, errorCode := requestData.CheckRequest(); !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	userId, hasAuth, err := foundationservice.GetUserService().CheckUserAuth(
+		ctx,
+		foundationauth.AuthTypeManageProblem,
+	)
+	if err != nil {
+		metapanic.ProcessError(err)
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	remoteType := foundationremote.GetRemoteTypeByString(requestData.OJ)
+	if foundationremote.GetRemoteAgent(remoteType) == nil {
+		metaresponse.NewResponse(ctx, weberrorcode.ProblemCrawlCannotOriginOj, nil)
+		return
+	}
+	const maxCrawlCount = 5000
+	var originIds []string
+	if requestData.Start > 0 && requestData.End >= requestData.Start {
+		if requestData.End-requestData.Start+1 > maxCrawlCount {
+			metaresponse.NewResponse(ctx, weberrorcode.ProblemCrawlJobTooManyProblem, nil)
+			return
+		}
+		for id := requestData.Start; id <= requestData.End; id++ {
+			originIds = append(originIds, strconv.Itoa(id))
+		}
+	}
+	originIds = append(originIds, requestData.Ids...)
+	originIds = metaslice.RemoveDuplicate(originIds)
+	if len(originIds) == 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	if len(originIds) > maxCrawlCount {
+		metaresponse.NewResponse(ctx, weberrorcode.ProblemCrawlJobTooManyProblem, nil)
+		return
+	}
+	job, err := foundationservice.GetProblemCrawlJobService().InsertProblemCrawlJob(
+		ctx,
+		userId,
+		string(remoteType),
+		originIds,
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, job)
+}
+
 func (c *ProblemController) PostJudgeData(ctx *gin.Context) {
 	problemIdStr := ctx.PostForm("id")
 	if problemIdStr == "" {
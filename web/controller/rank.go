@@ -53,6 +53,76 @@ func (c *RankController) GetAcAll(ctx *gin.Context) {
 	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
 }
 
+// GetRating 按比赛Rating排名
+func (c *RankController) GetRating(ctx *gin.Context) {
+	pageStr := ctx.DefaultQuery("page", "1")
+	pageSizeStr := ctx.DefaultQuery("page_size", "50")
+	page, err := strconv.Atoi(pageStr)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	pageSize, err := strconv.Atoi(pageSizeStr)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	if pageSize != 50 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	list, totalCount, err := foundationservice.GetUserService().GetRankRating(ctx, page, pageSize)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Time       time.Time                  `json:"time"`
+		TotalCount int                        `json:"total_count"`
+		List       []*foundationview.UserRank `json:"list"`
+	}{
+		Time:       metatime.GetTimeNow(),
+		TotalCount: totalCount,
+		List:       list,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+// GetExternalAc 按外部账号同步的AC题数排名，与本站AC统计分开
+func (c *RankController) GetExternalAc(ctx *gin.Context) {
+	pageStr := ctx.DefaultQuery("page", "1")
+	pageSizeStr := ctx.DefaultQuery("page_size", "50")
+	page, err := strconv.Atoi(pageStr)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	pageSize, err := strconv.Atoi(pageSizeStr)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	if pageSize != 50 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	list, totalCount, err := foundationservice.GetUserExternalSolveService().GetRankExternalSolve(ctx, page, pageSize)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Time       time.Time                  `json:"time"`
+		TotalCount int                        `json:"total_count"`
+		List       []*foundationview.UserRank `json:"list"`
+	}{
+		Time:       metatime.GetTimeNow(),
+		TotalCount: totalCount,
+		List:       list,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
 func (c *RankController) GetAcProblem(ctx *gin.Context) {
 	pageStr := ctx.DefaultQuery("page", "1")
 	pageSizeStr := ctx.DefaultQuery("page_size", "50")
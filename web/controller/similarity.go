@@ -0,0 +1,175 @@
+package controller
+
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationenum "foundation/foundation-enum"
+	foundationservice "foundation/foundation-service"
+	foundationview "foundation/foundation-view"
+	metaerrorcode "meta/error-code"
+	metaerror "meta/meta-error"
+	metaresponse "meta/meta-response"
+	"strconv"
+	weberrorcode "web/error-code"
+	"web/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SimilarityController struct {
+}
+
+// checkScopeAuth 题目与题集需要编辑权限，比赛需要裁判权限
+func (c *SimilarityController) checkScopeAuth(
+	ctx *gin.Context,
+	scopeType foundationenum.SimilarityScopeType,
+	scopeId int,
+) (int, bool, error) {
+	switch scopeType {
+	case foundationenum.SimilarityScopeTypeProblem:
+		return foundationservice.GetProblemService().CheckEditAuth(ctx, scopeId)
+	case foundationenum.SimilarityScopeTypeCollection:
+		return foundationservice.GetCollectionService().CheckEditAuth(ctx, scopeId)
+	case foundationenum.SimilarityScopeTypeContest:
+		return foundationservice.GetContestService().CheckJuryAuth(ctx, scopeId)
+	}
+	return 0, false, nil
+}
+
+// getReportWithAuth 获取报告并检查当前用户对其范围的权限，失败时已写入响应
+func (c *SimilarityController) getReportWithAuth(ctx *gin.Context, id int) *foundationview.SimilarityReport {
+	similarityService := foundationservice.GetSimilarityService()
+	report, err := similarityService.GetSimilarityReport(ctx, id)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return nil
+	}
+	if report == nil {
+		metaresponse.NewResponseError(ctx, metaerror.NewCode(weberrorcode.SimilarityReportNotFound))
+		return nil
+	}
+	_, hasAuth, err := c.checkScopeAuth(ctx, report.ScopeType, report.ScopeId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return nil
+	}
+	return report
+}
+
+func (c *SimilarityController) Get(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	report := c.getReportWithAuth(ctx, id)
+	if report == nil {
+		return
+	}
+	pairs, err := foundationservice.GetSimilarityService().GetSimilarityPairs(ctx, id)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		Report *foundationview.SimilarityReport `json:"report"`
+		Pairs  []*foundationview.SimilarityPair `json:"pairs"`
+	}{
+		Report: report,
+		Pairs:  pairs,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+func (c *SimilarityController) GetList(ctx *gin.Context) {
+	scopeType, err := strconv.Atoi(ctx.Query("scope_type"))
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	scopeId, err := strconv.Atoi(ctx.Query("scope_id"))
+	if err != nil || scopeId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	_, hasAuth, err := c.checkScopeAuth(ctx, foundationenum.SimilarityScopeType(scopeType), scopeId)
+	if err != nil || !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	reports, err := foundationservice.GetSimilarityService().GetSimilarityReports(
+		ctx,
+		foundationenum.SimilarityScopeType(scopeType),
+		scopeId,
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	responseData := struct {
+		List []*foundationview.SimilarityReport `json:"list"`
+	}{
+		List: reports,
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
+}
+
+// GetPair 对比查看一对代码以及相似区域
+func (c *SimilarityController) GetPair(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	pair, err := foundationservice.GetSimilarityService().GetSimilarityPairDetail(ctx, id)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	if pair == nil {
+		metaresponse.NewResponseError(ctx, metaerror.NewCode(weberrorcode.SimilarityReportNotFound))
+		return
+	}
+	if c.getReportWithAuth(ctx, pair.ReportId) == nil {
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, pair)
+}
+
+// PostCreate 创建相似度检测，检测在后台排队执行
+func (c *SimilarityController) PostCreate(ctx *gin.Context) {
+	var requestData request.SimilarityReport
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	ok, errorCode := requestData.CheckRequest()
+	if !ok {
+		metaresponse.NewResponse(ctx, errorCode, nil)
+		return
+	}
+	userId, hasAuth, err := c.checkScopeAuth(ctx, requestData.ScopeType, requestData.ScopeId)
+	if err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	if userId <= 0 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.NeedLogin, nil)
+		return
+	}
+	if !hasAuth {
+		metaresponse.NewResponse(ctx, foundationerrorcode.AuthError, nil)
+		return
+	}
+	report, err := foundationservice.GetSimilarityService().InsertSimilarityReport(
+		ctx,
+		userId,
+		requestData.ScopeType,
+		requestData.ScopeId,
+		requestData.Threshold,
+	)
+	if err != nil {
+		metaresponse.NewResponseError(ctx, err)
+		return
+	}
+	metaresponse.NewResponse(ctx, metaerrorcode.Success, report)
+}
@@ -7,11 +7,11 @@ import (
 	foundationdao "foundation/foundation-dao"
 	foundationenum "foundation/foundation-enum"
 	foundationmodel "foundation/foundation-model"
+	foundationremote "foundation/foundation-remote"
 	foundationrequest "foundation/foundation-request"
 	foundationservice "foundation/foundation-service"
 	foundationuser "foundation/foundation-user"
 	foundationview "foundation/foundation-view"
-	"io"
 	metacontroller "meta/controller"
 	metaerrorcode "meta/error-code"
 	metaemail "meta/meta-email"
@@ -22,7 +22,6 @@ import (
 	metastring "meta/meta-string"
 	metatime "meta/meta-time"
 	"meta/recaptcha"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -78,16 +77,37 @@ func (c *UserController) GetInfo(ctx *gin.Context) {
 		return
 	}
 
+	externalSyncs, externalSolves, err := foundationservice.GetUserExternalSolveService().GetUserExternalSolves(
+		ctx,
+		userInfo.Id,
+	)
+	if err != nil {
+		metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
+		return
+	}
+
+	ratingHistory, err := foundationservice.GetUserService().GetUserRatingHistory(ctx, userInfo.Id)
+	if err != nil {
+		metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
+		return
+	}
+
 	responseData := struct {
 		User           *foundationview.UserInfo               `json:"user"`
 		ProblemsAc     []*foundationview.ProblemViewKey       `json:"problems_ac"`
 		ProblemAttempt []*foundationview.ProblemViewKey       `json:"problems_attempt"`
 		Statics        []*foundationview.JudgeJobCountStatics `json:"statics"`
+		ExternalSyncs  []*foundationmodel.UserExternalSync    `json:"external_syncs,omitempty"`
+		ExternalSolves []*foundationview.UserExternalSolve    `json:"external_solves,omitempty"`
+		RatingHistory  []*foundationview.UserRatingHistory    `json:"rating_history,omitempty"`
 	}{
 		User:           userInfo,
 		ProblemsAc:     acProblems,
 		ProblemAttempt: attemptProblems,
 		Statics:        userStatic,
+		ExternalSyncs:  externalSyncs,
+		ExternalSolves: externalSolves,
+		RatingHistory:  ratingHistory,
 	}
 	metaresponse.NewResponse(ctx, metaerrorcode.Success, responseData)
 }
@@ -423,9 +443,50 @@ func (c *UserController) PostModifyVjudge(ctx *gin.Context) {
 		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
 		return
 	}
+	c.modifyExternalAccount(ctx, userId, foundationenum.UserExternalSourceVjudge, username, requestData.Approved)
+}
 
+// PostModifyExternal 绑定Codeforces或HDU账号，外部AC记录会计入公开排行，因此需要验证归属
+func (c *UserController) PostModifyExternal(ctx *gin.Context) {
+	userId, err := foundationauth.GetUserIdFromContext(ctx)
+	if err != nil {
+		metaresponse.NewResponse(ctx, weberrorcode.UserNeedLogin, nil)
+		return
+	}
+	var requestData foundationrequest.UserModifyExternal
+	if err := ctx.ShouldBindJSON(&requestData); err != nil {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	source := foundationenum.UserExternalSource(strings.ToUpper(requestData.Source))
+	if source != foundationenum.UserExternalSourceCodeforces && source != foundationenum.UserExternalSourceHdu {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	username := requestData.Username
+	if len(username) > 20 {
+		metaresponse.NewResponse(ctx, foundationerrorcode.ParamError, nil)
+		return
+	}
+	c.modifyExternalAccount(ctx, userId, source, username, requestData.Approved)
+}
+
+// modifyExternalAccount 先下发验证码，用户将其填写到外部账号的公开资料中后再确认绑定，用户名为空时直接解绑
+func (c *UserController) modifyExternalAccount(
+	ctx *gin.Context,
+	userId int,
+	source foundationenum.UserExternalSource,
+	username string,
+	approved bool,
+) {
 	if username == "" {
-		err = foundationservice.GetUserService().UpdateUserVjudgeUsername(ctx, userId, username, metatime.GetTimeNow())
+		err := foundationservice.GetUserService().UpdateUserExternalAccount(
+			ctx,
+			userId,
+			source,
+			username,
+			metatime.GetTimeNow(),
+		)
 		if err != nil {
 			metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
 			return
@@ -435,9 +496,9 @@ func (c *UserController) PostModifyVjudge(ctx *gin.Context) {
 	}
 
 	kvStoreDao := foundationdao.GetKVStoreDao()
-	codeKey := fmt.Sprintf("modify_vjudge_%d", userId)
+	codeKey := fmt.Sprintf("modify_%s_%d", strings.ToLower(string(source)), userId)
 
-	if requestData.Approved {
+	if approved {
 		randomKeyBytes, err := kvStoreDao.GetValue(ctx, codeKey)
 		if err != nil {
 			metaresponse.NewResponse(ctx, weberrorcode.UserModifyVjudgeReload, nil)
@@ -452,30 +513,24 @@ func (c *UserController) PostModifyVjudge(ctx *gin.Context) {
 			metaresponse.NewResponse(ctx, weberrorcode.UserModifyVjudgeReload, nil)
 			return
 		}
-		vjudgeUrl := fmt.Sprintf("https://vjudge.net/user/%s", username)
 		// 请求页面信息是否包含randomKey
-		response, err := http.Get(vjudgeUrl)
+		owned, err := foundationremote.GetRemoteExternalSolveAgent().IsAccountOwned(ctx, source, username, randomKey)
 		if err != nil {
+			metapanic.ProcessError(err)
 			metaresponse.NewResponse(ctx, weberrorcode.UserModifyVjudgeCannotGet, nil)
 			return
 		}
-		defer func(Body io.ReadCloser) {
-			err := Body.Close()
-			if err != nil {
-				metapanic.ProcessError(metaerror.Wrap(err, "defer http body close failed"))
-				return
-			}
-		}(response.Body)
-		body, err := io.ReadAll(response.Body)
-		if err != nil {
-			metaresponse.NewResponse(ctx, weberrorcode.UserModifyVjudgeReload, nil)
-			return
-		}
-		if !strings.Contains(string(body), randomKey) {
+		if !owned {
 			metaresponse.NewResponse(ctx, weberrorcode.UserModifyVjudgeVerifyFail, nil)
 			return
 		}
-		err = foundationservice.GetUserService().UpdateUserVjudgeUsername(ctx, userId, username, metatime.GetTimeNow())
+		err = foundationservice.GetUserService().UpdateUserExternalAccount(
+			ctx,
+			userId,
+			source,
+			username,
+			metatime.GetTimeNow(),
+		)
 		if err != nil {
 			metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
 			return
@@ -483,7 +538,7 @@ func (c *UserController) PostModifyVjudge(ctx *gin.Context) {
 		metaresponse.NewResponse(ctx, metaerrorcode.Success)
 	} else {
 		randomString := metastring.GetRandomString(16)
-		err = kvStoreDao.SetValue(ctx, codeKey, randomString, 10*time.Minute)
+		err := kvStoreDao.SetValue(ctx, codeKey, randomString, 10*time.Minute)
 		if err != nil {
 			metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
 			return
@@ -868,6 +923,7 @@ func (c *UserController) PostLoginRefresh(ctx *gin.Context) {
 		nowTime,
 		ctx.ClientIP(),
 		ctx.Request.UserAgent(),
+		loginResponse.Session,
 	)
 	if err != nil {
 		metapanic.ProcessError(err)
@@ -956,7 +1012,7 @@ func (c *UserController) PostLogin(ctx *gin.Context) {
 		nowTime,
 	)
 	if err != nil {
-		metaresponse.NewResponse(ctx, metaerrorcode.CommonError, nil)
+		metaresponse.NewResponseError(ctx, err, nil)
 		return
 	}
 	if loginResponse == nil {
@@ -970,6 +1026,7 @@ func (c *UserController) PostLogin(ctx *gin.Context) {
 		nowTime,
 		ctx.ClientIP(),
 		ctx.Request.UserAgent(),
+		loginResponse.Session,
 	)
 	if err != nil {
 		metapanic.ProcessError(err)
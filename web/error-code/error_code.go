@@ -57,4 +57,59 @@ const (
 	ContestDurationTooLong        metaerrorcode.ErrorCode = 100051
 	ContestCannotEditStartTime    metaerrorcode.ErrorCode = 100052
 	ContestCannotEditEndTime      metaerrorcode.ErrorCode = 100053
+
+	ProblemCrawlJobTooManyProblem metaerrorcode.ErrorCode = 100054
+
+	ContestMirrorNotSupport metaerrorcode.ErrorCode = 100055
+
+	ContestResolverNotSupport metaerrorcode.ErrorCode = 100056
+	ContestResolverNotEnd     metaerrorcode.ErrorCode = 100057
+
+	ContestTeamUserNotFound metaerrorcode.ErrorCode = 100058
+	ContestTeamUserConflict metaerrorcode.ErrorCode = 100059
+
+	ContestVirtualNotEnd       metaerrorcode.ErrorCode = 100060
+	ContestVirtualParticipated metaerrorcode.ErrorCode = 100061
+	ContestVirtualExist        metaerrorcode.ErrorCode = 100062
+
+	ContestWindowNotSupport metaerrorcode.ErrorCode = 100063
+	ContestWindowNotOpen    metaerrorcode.ErrorCode = 100064
+
+	ContestClarificationClosed   metaerrorcode.ErrorCode = 100065
+	ContestClarificationNotFound metaerrorcode.ErrorCode = 100066
+
+	ContestBalloonStatusError metaerrorcode.ErrorCode = 100067
+
+	ContestPrintDisabled      metaerrorcode.ErrorCode = 100068
+	ContestPrintClosed        metaerrorcode.ErrorCode = 100069
+	ContestPrintPageLimit     metaerrorcode.ErrorCode = 100070
+	ContestPrintQuotaExceeded metaerrorcode.ErrorCode = 100071
+	ContestPrintStatusError   metaerrorcode.ErrorCode = 100072
+	ContestPrintNotFound      metaerrorcode.ErrorCode = 100073
+
+	ContestExamIpDenied       metaerrorcode.ErrorCode = 100074
+	ContestExamSessionExpired metaerrorcode.ErrorCode = 100075
+
+	SimilarityReportNotFound metaerrorcode.ErrorCode = 100076
+
+	UserDisabled metaerrorcode.ErrorCode = 100077
+
+	JudgeApproveLanguageNotAllowed metaerrorcode.ErrorCode = 100078
+
+	ContestHackDisabled      metaerrorcode.ErrorCode = 100079
+	ContestHackClosed        metaerrorcode.ErrorCode = 100080
+	ContestHackNotLocked     metaerrorcode.ErrorCode = 100081
+	ContestHackTargetInvalid metaerrorcode.ErrorCode = 100082
+	ContestHackInputTooLarge metaerrorcode.ErrorCode = 100083
+	ContestProblemLocked     metaerrorcode.ErrorCode = 100084
+	ContestProblemNotAccept  metaerrorcode.ErrorCode = 100085
+
+	ContestSystemTestDisabled metaerrorcode.ErrorCode = 100086
+	ContestSystemTestNotEnd   metaerrorcode.ErrorCode = 100087
+	ContestSystemTestStarted  metaerrorcode.ErrorCode = 100088
+
+	ContestCredentialPdfUnsupported metaerrorcode.ErrorCode = 100089 // 没有配置中文字体，账号中有PDF无法显示的字符
+
+	ContestHackDuplicate metaerrorcode.ErrorCode = 100090 // 已经用相同的数据挑战过该提交
+	ContestHackTooMany   metaerrorcode.ErrorCode = 100091 // 等待评测的挑战过多
 )
@@ -1,6 +1,11 @@
 package request
 
-import "time"
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationjudge "foundation/foundation-judge"
+	metaerrorcode "meta/error-code"
+	"time"
+)
 
 type CollectionEdit struct {
 	Id          int        `json:"id"`                        // 题集Id
@@ -11,4 +16,21 @@ type CollectionEdit struct {
 	Problems    []int      `json:"problems"`   // 题目列表，逗号分隔的题目Id列表
 	Private     bool       `json:"private"`    // 是否私有题集
 	Members     []int      `json:"members"`    // 题集成员列表，逗号分隔的用户Id列表
+
+	Languages []foundationjudge.JudgeLanguage `json:"languages,omitempty"` // 允许提交的语言，空则不限制
+}
+
+// GetLanguageKeys 允许提交的语言标识，未设置时为空
+func (r *CollectionEdit) GetLanguageKeys() []string {
+	return getJudgeLanguageKeys(r.Languages)
+}
+
+func (r *CollectionEdit) CheckRequest() (bool, int) {
+	if r.Title == "" {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if !checkJudgeLanguages(r.Languages) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
 }
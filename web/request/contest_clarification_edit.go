@@ -0,0 +1,78 @@
+package request
+
+import (
+	foundationerrorcode "foundation/error-code"
+	metaerrorcode "meta/error-code"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	contestClarificationMaxLength      = 2000
+	contestClarificationTitleMaxLength = 50
+)
+
+func checkContestClarificationContent(content string) bool {
+	return content != "" && utf8.RuneCountInString(content) <= contestClarificationMaxLength
+}
+
+type ContestClarificationAsk struct {
+	ContestId    int    `json:"contest_id" validate:"required"`
+	ProblemIndex int    `json:"problem_index"` // 题目索引，为0时为比赛的通用问题
+	Question     string `json:"question" validate:"required"`
+}
+
+func (r *ContestClarificationAsk) CheckRequest() (bool, int) {
+	r.Question = strings.TrimSpace(r.Question)
+	if r.ContestId <= 0 || r.ProblemIndex < 0 || !checkContestClarificationContent(r.Question) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
+}
+
+type ContestClarificationAnswer struct {
+	ContestId int    `json:"contest_id" validate:"required"`
+	Id        int    `json:"id" validate:"required"`
+	Answer    string `json:"answer" validate:"required"`
+	Public    bool   `json:"public"` // 是否广播给所有人
+}
+
+func (r *ContestClarificationAnswer) CheckRequest() (bool, int) {
+	r.Answer = strings.TrimSpace(r.Answer)
+	if r.ContestId <= 0 || r.Id <= 0 || !checkContestClarificationContent(r.Answer) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
+}
+
+type ContestClarificationBroadcast struct {
+	ContestId    int    `json:"contest_id" validate:"required"`
+	ProblemIndex int    `json:"problem_index"` // 题目索引，为0时为比赛的通用公告
+	Content      string `json:"content" validate:"required"`
+}
+
+func (r *ContestClarificationBroadcast) CheckRequest() (bool, int) {
+	r.Content = strings.TrimSpace(r.Content)
+	if r.ContestId <= 0 || r.ProblemIndex < 0 || !checkContestClarificationContent(r.Content) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
+}
+
+type ContestClarificationTemplateEdit struct {
+	Id      int    `json:"id"` // 模板Id，为0时创建模板
+	Title   string `json:"title" validate:"required"`
+	Content string `json:"content" validate:"required"`
+}
+
+func (r *ContestClarificationTemplateEdit) CheckRequest() (bool, int) {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Content = strings.TrimSpace(r.Content)
+	if r.Id < 0 || r.Title == "" || utf8.RuneCountInString(r.Title) > contestClarificationTitleMaxLength {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if !checkContestClarificationContent(r.Content) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
+}
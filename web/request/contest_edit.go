@@ -1,14 +1,38 @@
 package request
 
 import (
+	foundationerrorcode "foundation/error-code"
+	foundationjudge "foundation/foundation-judge"
 	metaerrorcode "meta/error-code"
+	"net"
+	"strings"
 	"time"
+	"unicode/utf8"
 	weberrorcode "web/error-code"
 )
 
+const (
+	ContestDefaultPenaltyMinutes   = 20
+	ContestDefaultHackScore        = 100
+	ContestDefaultHackPenalty      = 50
+	contestMemberLocationMaxLength = 50
+)
+
 type ContestMember struct {
-	Id          int    `json:"id,omitempty"`
-	ContestName string `json:"contest_name,omitempty"`
+	Id          int     `json:"id,omitempty"`
+	ContestName string  `json:"contest_name,omitempty"`
+	Location    *string `json:"location,omitempty"` // 现场赛的房间或座位，用于配送气球
+}
+
+// ContestProblemSetting 比赛中题目的计分与限制设置，未设置的题目使用默认值
+type ContestProblemSetting struct {
+	ProblemId    int  `json:"problem_id"`
+	Score        int  `json:"score,omitempty"`         // 题目满分，空则按默认分数计算
+	ScoreDecay   int  `json:"score_decay,omitempty"`   // 每分钟衰减得分的千分比
+	ScorePenalty int  `json:"score_penalty,omitempty"` // 每次错误提交扣除的分数
+	ScoreMin     int  `json:"score_min,omitempty"`     // 衰减与扣分后至少保留得分的百分比
+	TimeLimit    *int `json:"time_limit,omitempty"`    // 覆盖题目的时间限制，单位ms
+	MemoryLimit  *int `json:"memory_limit,omitempty"`  // 覆盖题目的内存限制，单位KB
 }
 
 type ContestEdit struct {
@@ -20,14 +44,95 @@ type ContestEdit struct {
 	EndTime      time.Time `json:"end_time" validate:"required"`   // 比赛结束时间
 	Problems     []int     `json:"problems" validate:"required"`   // 题目列表，逗号分隔的题目Id列表
 
+	ProblemSettings []ContestProblemSetting `json:"problem_settings,omitempty"` // 题目的计分与限制设置
+
 	Private  bool            `json:"private"`
 	Password *string         `json:"password,omitempty"` // 比赛密码，私有比赛时需要
 	Members  []ContestMember `json:"members"`            // 成员列表，包含用户Id和比赛名称
 
+	Volunteers []int `json:"volunteers"` // 配送气球的志愿者用户Id列表
+
 	LockRankDuration int64 `json:"lock_rank_duration,omitempty"` // 锁榜时长，空则不锁榜（单位秒）
 	AlwaysLock       bool  `json:"always_lock"`                  // 比赛结束后是否锁定排名，如果锁定则需要手动关闭（关闭时此值设为false）
 
 	SubmitAnytime bool `json:"submit_anytime,omitempty"`
+
+	PenaltyMinutes *int `json:"penalty_minutes,omitempty"` // 每次错误提交的罚时分钟数，空则为默认值
+
+	PersonalDuration int64 `json:"personal_duration,omitempty"` // 个人比赛时长，非空时成员在开放时间内自行开始（单位秒）
+
+	PrintQuota     *int `json:"print_quota,omitempty"`      // 每个队伍可打印的总页数，空则不开放打印
+	PrintPageLimit *int `json:"print_page_limit,omitempty"` // 单次打印的页数上限，空则不限制
+
+	Rated bool `json:"rated,omitempty"` // 是否计算Rating，比赛结束后计算
+
+	Exam        bool     `json:"exam,omitempty"`         // 考试模式，比赛期间新的登录会使旧的登录失效
+	IpWhitelist []string `json:"ip_whitelist,omitempty"` // 允许访问的IP或CIDR，空则不限制
+
+	Languages []foundationjudge.JudgeLanguage `json:"languages,omitempty"` // 允许提交的语言，空则不限制
+
+	Hack        bool `json:"hack,omitempty"`         // 是否开放挑战，锁定题目后可以挑战他人已通过的代码，ACM比赛中挑战得分只在通过题数与罚时相同时影响排名
+	HackScore   int  `json:"hack_score,omitempty"`   // 挑战成功获得的分数，空则为默认值
+	HackPenalty *int `json:"hack_penalty,omitempty"` // 挑战失败扣除的分数，空则为默认值
+
+	Pretest bool `json:"pretest,omitempty"` // 比赛期间只评测预测试，结束后进行系统测试
+}
+
+// GetPenaltyMinutes 未设置时使用ACM的默认罚时
+func (r *ContestEdit) GetPenaltyMinutes() int {
+	if r.PenaltyMinutes == nil {
+		return ContestDefaultPenaltyMinutes
+	}
+	return *r.PenaltyMinutes
+}
+
+// GetPersonalDuration 未设置时所有人使用统一的比赛时间
+func (r *ContestEdit) GetPersonalDuration() *time.Duration {
+	if r.PersonalDuration <= 0 {
+		return nil
+	}
+	duration := time.Duration(r.PersonalDuration) * time.Second
+	return &duration
+}
+
+// GetHackScore 未设置时使用默认的挑战得分
+func (r *ContestEdit) GetHackScore() int {
+	if r.HackScore <= 0 {
+		return ContestDefaultHackScore
+	}
+	return r.HackScore
+}
+
+// GetHackPenalty 未设置时使用默认的挑战扣分
+func (r *ContestEdit) GetHackPenalty() int {
+	if r.HackPenalty == nil {
+		return ContestDefaultHackPenalty
+	}
+	return *r.HackPenalty
+}
+
+// GetLanguageKeys 允许提交的语言标识，未设置时为空
+func (r *ContestEdit) GetLanguageKeys() []string {
+	return getJudgeLanguageKeys(r.Languages)
+}
+
+// GetIpWhitelist 合并为每行一个的文本，未设置时为空
+func (r *ContestEdit) GetIpWhitelist() *string {
+	if len(r.IpWhitelist) == 0 {
+		return nil
+	}
+	whitelist := strings.Join(r.IpWhitelist, "\n")
+	return &whitelist
+}
+
+// GetProblemSetting 获取题目的设置，未设置时返回默认值
+func (r *ContestEdit) GetProblemSetting(problemId int) ContestProblemSetting {
+	for _, setting := range r.ProblemSettings {
+		if setting.ProblemId == problemId {
+			return setting
+		}
+	}
+	return ContestProblemSetting{ProblemId: problemId}
 }
 
 func (r *ContestEdit) CheckRequest() (bool, int) {
@@ -42,9 +147,63 @@ func (r *ContestEdit) CheckRequest() (bool, int) {
 	if r.EndTime.Before(r.StartTime) {
 		return false, int(weberrorcode.ContestEndTimeBeforeStartTime)
 	}
-	// 判断时长是否超过了30天
-	if r.EndTime.Sub(r.StartTime) > time.Hour*24*30 {
+	// 判断时长是否超过了30天，个人比赛窗口的开放时间可以到90天
+	maxDuration := time.Hour * 24 * 30
+	if r.PersonalDuration > 0 {
+		maxDuration = time.Hour * 24 * 90
+	}
+	if r.EndTime.Sub(r.StartTime) > maxDuration {
 		return false, int(weberrorcode.ContestDurationTooLong)
 	}
+	if r.PersonalDuration < 0 || time.Duration(r.PersonalDuration)*time.Second > r.EndTime.Sub(r.StartTime) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if r.PenaltyMinutes != nil && (*r.PenaltyMinutes < 0 || *r.PenaltyMinutes > 1440) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if r.PrintQuota != nil && *r.PrintQuota <= 0 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if r.PrintPageLimit != nil && *r.PrintPageLimit <= 0 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if r.HackScore < 0 || r.HackScore > 10000 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if r.HackPenalty != nil && (*r.HackPenalty < 0 || *r.HackPenalty > 10000) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if !checkJudgeLanguages(r.Languages) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	for i, item := range r.IpWhitelist {
+		item = strings.TrimSpace(item)
+		if _, _, err := net.ParseCIDR(item); err != nil && net.ParseIP(item) == nil {
+			return false, int(foundationerrorcode.ParamError)
+		}
+		r.IpWhitelist[i] = item
+	}
+	for _, setting := range r.ProblemSettings {
+		if setting.Score < 0 || setting.Score > 10000 {
+			return false, int(foundationerrorcode.ParamError)
+		}
+		if setting.ScoreDecay < 0 || setting.ScoreDecay > 1000 || setting.ScorePenalty < 0 {
+			return false, int(foundationerrorcode.ParamError)
+		}
+		if setting.ScoreMin < 0 || setting.ScoreMin > 100 {
+			return false, int(foundationerrorcode.ParamError)
+		}
+		if setting.TimeLimit != nil && (*setting.TimeLimit <= 0 || *setting.TimeLimit > 30000) {
+			return false, int(foundationerrorcode.ParamError)
+		}
+		if setting.MemoryLimit != nil && (*setting.MemoryLimit <= 0 || *setting.MemoryLimit > 1024*1024) {
+			return false, int(foundationerrorcode.ParamError)
+		}
+	}
+	for _, member := range r.Members {
+		if member.Location != nil && utf8.RuneCountInString(*member.Location) > contestMemberLocationMaxLength {
+			return false, int(foundationerrorcode.ParamError)
+		}
+	}
 	return true, int(metaerrorcode.Success)
 }
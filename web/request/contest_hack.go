@@ -0,0 +1,37 @@
+package request
+
+import (
+	foundationerrorcode "foundation/error-code"
+	metaerrorcode "meta/error-code"
+	weberrorcode "web/error-code"
+)
+
+const contestHackInputMaxLength = 256 * 1024
+
+type ContestProblemLock struct {
+	ContestId int `json:"contest_id" validate:"required"`
+	ProblemId int `json:"problem_id" validate:"required"`
+}
+
+func (r *ContestProblemLock) CheckRequest() (bool, int) {
+	if r.ContestId <= 0 || r.ProblemId <= 0 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
+}
+
+type ContestHack struct {
+	ContestId int    `json:"contest_id" validate:"required"`
+	JudgeId   int    `json:"judge_id" validate:"required"` // 被挑战的提交
+	Input     string `json:"input" validate:"required"`    // 挑战数据，需要通过题目的校验器
+}
+
+func (r *ContestHack) CheckRequest() (bool, int) {
+	if r.ContestId <= 0 || r.JudgeId <= 0 || r.Input == "" {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if len(r.Input) > contestHackInputMaxLength {
+		return false, int(weberrorcode.ContestHackInputTooLarge)
+	}
+	return true, int(metaerrorcode.Success)
+}
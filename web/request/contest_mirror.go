@@ -0,0 +1,31 @@
+package request
+
+import (
+	foundationerrorcode "foundation/error-code"
+	metaerrorcode "meta/error-code"
+	"time"
+	weberrorcode "web/error-code"
+)
+
+type ContestMirror struct {
+	Oj         string    `json:"oj" validate:"required"`         // 远程OJ，目前支持Codeforces与AtCoder
+	ContestId  string    `json:"contest_id" validate:"required"` // 远程比赛Id
+	StartTime  time.Time `json:"start_time" validate:"required"` // 比赛开启时间，结束时间按原比赛时长计算
+	Title      string    `json:"title"`                          // 比赛标题，空则使用原比赛标题
+	Private    bool      `json:"private"`
+	Password   *string   `json:"password,omitempty"`
+	GhostLimit int       `json:"ghost_limit,omitempty"` // 导入原比赛榜单的行数，0则不导入
+}
+
+func (r *ContestMirror) CheckRequest() (bool, int) {
+	if r.Oj == "" || r.ContestId == "" || len(r.ContestId) > 30 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if r.StartTime.Before(time.Now()) {
+		return false, int(weberrorcode.ContestStartTimeBeforeNow)
+	}
+	if r.GhostLimit < 0 || r.GhostLimit > 5000 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
+}
@@ -0,0 +1,26 @@
+package request
+
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationjudge "foundation/foundation-judge"
+	metaerrorcode "meta/error-code"
+	"strings"
+)
+
+const contestPrintMaxLength = 256 * 1024
+
+type ContestPrint struct {
+	ContestId int                           `json:"contest_id" validate:"required"`
+	Language  foundationjudge.JudgeLanguage `json:"language"` // 用于代码高亮，不支持的语言按纯文本打印
+	Content   string                        `json:"content" validate:"required"`
+}
+
+func (r *ContestPrint) CheckRequest() (bool, int) {
+	if r.ContestId <= 0 || strings.TrimSpace(r.Content) == "" || len(r.Content) > contestPrintMaxLength {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if !foundationjudge.IsValidJudgeLanguage(int(r.Language)) {
+		r.Language = foundationjudge.JudgeLanguageUnknown
+	}
+	return true, int(metaerrorcode.Success)
+}
@@ -0,0 +1,163 @@
+package request
+
+import (
+	"encoding/csv"
+	"fmt"
+	foundationerrorcode "foundation/error-code"
+	foundationuser "foundation/foundation-user"
+	metaerrorcode "meta/error-code"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	contestTeamNameMaxLength = 50
+	contestTeamMemberMax     = 10
+	contestTeamImportMax     = 2000
+)
+
+type ContestTeamEdit struct {
+	ContestId int      `json:"contest_id" validate:"required"`
+	Id        int      `json:"id"` // 队伍Id，为0时创建队伍
+	Name      string   `json:"name" validate:"required"`
+	Members   []string `json:"members" validate:"required"` // 成员用户名
+}
+
+func checkContestTeam(name string, members []string) bool {
+	if name == "" || utf8.RuneCountInString(name) > contestTeamNameMaxLength {
+		return false
+	}
+	return len(members) > 0 && len(members) <= contestTeamMemberMax
+}
+
+func (r *ContestTeamEdit) CheckRequest() (bool, int) {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.ContestId <= 0 || r.Id < 0 || !checkContestTeam(r.Name, r.Members) {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
+}
+
+type ContestTeamImportItem struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+// ContestTeamImport 批量导入队伍，会替换比赛原有的全部队伍
+// 可以直接传入队伍列表，也可以传入每行一个队伍的文本，格式为：队伍名称,用户名1,用户名2...
+type ContestTeamImport struct {
+	ContestId int                      `json:"contest_id" validate:"required"`
+	Teams     []*ContestTeamImportItem `json:"teams"`
+	Content   string                   `json:"content"`
+}
+
+func (r *ContestTeamImport) CheckRequest() (bool, int) {
+	if r.ContestId <= 0 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	for _, line := range strings.Split(r.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		item := &ContestTeamImportItem{Name: fields[0]}
+		for _, field := range fields[1:] {
+			if field = strings.TrimSpace(field); field != "" {
+				item.Members = append(item.Members, field)
+			}
+		}
+		r.Teams = append(r.Teams, item)
+	}
+	if len(r.Teams) > contestTeamImportMax {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	for _, item := range r.Teams {
+		item.Name = strings.TrimSpace(item.Name)
+		if !checkContestTeam(item.Name, item.Members) {
+			return false, int(foundationerrorcode.ParamError)
+		}
+	}
+	return true, int(metaerrorcode.Success)
+}
+
+const (
+	contestMemberNameMaxLength         = 20
+	contestMemberOrganizationMaxLength = 80
+	contestMemberPrefixMaxLength       = 16
+)
+
+type ContestMemberImportItem struct {
+	Name         string `json:"name"`
+	Organization string `json:"organization"`
+	Location     string `json:"location"`
+	Username     string `json:"username"` // 为空时自动生成账号
+}
+
+// ContestMemberImport 批量导入现场赛成员并生成账号
+// 可以直接传入成员列表，也可以传入CSV文本，每行格式为：队伍名称,学校,座位[,用户名]
+type ContestMemberImport struct {
+	ContestId   int                        `json:"contest_id" validate:"required"`
+	Members     []*ContestMemberImportItem `json:"members"`
+	Content     string                     `json:"content"`
+	Prefix      string                     `json:"prefix"`       // 自动生成用户名的前缀，默认为c比赛Id_
+	ContestOnly bool                       `json:"contest_only"` // 新账号是否为仅用于本场比赛的临时账号
+	Format      string                     `json:"format"`       // 账号表的格式，可选csv、pdf，默认返回json
+}
+
+func (r *ContestMemberImport) CheckRequest() (bool, int) {
+	if r.ContestId <= 0 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if strings.TrimSpace(r.Content) != "" {
+		reader := csv.NewReader(strings.NewReader(r.Content))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return false, int(foundationerrorcode.ParamError)
+		}
+		for _, record := range records {
+			item := &ContestMemberImportItem{Name: record[0]}
+			if len(record) > 1 {
+				item.Organization = record[1]
+			}
+			if len(record) > 2 {
+				item.Location = record[2]
+			}
+			if len(record) > 3 {
+				item.Username = record[3]
+			}
+			r.Members = append(r.Members, item)
+		}
+	}
+	if len(r.Members) == 0 || len(r.Members) > contestTeamImportMax {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	r.Prefix = strings.TrimSpace(r.Prefix)
+	if r.Prefix == "" {
+		r.Prefix = fmt.Sprintf("c%d_", r.ContestId)
+	}
+	if len(r.Prefix) > contestMemberPrefixMaxLength || !foundationuser.IsValidUsername(r.Prefix+"000") {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	for _, item := range r.Members {
+		item.Name = strings.TrimSpace(item.Name)
+		item.Organization = strings.TrimSpace(item.Organization)
+		item.Location = strings.TrimSpace(item.Location)
+		item.Username = strings.TrimSpace(item.Username)
+		if item.Name == "" ||
+			utf8.RuneCountInString(item.Name) > contestMemberNameMaxLength ||
+			utf8.RuneCountInString(item.Organization) > contestMemberOrganizationMaxLength ||
+			utf8.RuneCountInString(item.Location) > contestMemberLocationMaxLength {
+			return false, int(foundationerrorcode.ParamError)
+		}
+		if item.Username != "" && !foundationuser.IsValidUsername(item.Username) {
+			return false, int(foundationerrorcode.ParamError)
+		}
+	}
+	if r.Format != "" && r.Format != "csv" && r.Format != "pdf" {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	return true, int(metaerrorcode.Success)
+}
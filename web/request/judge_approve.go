@@ -5,6 +5,7 @@ import foundationjudge "foundation/foundation-judge"
 type JudgeApprove struct {
 	ProblemId    int                           `json:"problem_id"`
 	ContestId    int                           `json:"contest_id"`
+	CollectionId int                           `json:"collection_id,omitempty"` // 在题集中提交时需要遵循题集的语言限制
 	ProblemIndex int                           `json:"problem_index"`
 	Language     foundationjudge.JudgeLanguage `json:"language"`
 	Code         string                        `json:"code"`
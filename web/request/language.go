@@ -0,0 +1,28 @@
+package request
+
+import foundationjudge "foundation/foundation-judge"
+
+// checkJudgeLanguages 判断允许的语言是否都是有效的评测语言
+func checkJudgeLanguages(languages []foundationjudge.JudgeLanguage) bool {
+	for _, language := range languages {
+		if !foundationjudge.IsValidJudgeLanguage(int(language)) {
+			return false
+		}
+	}
+	return true
+}
+
+// getJudgeLanguageKeys 转换为去重后的语言标识，用于保存允许的语言
+func getJudgeLanguageKeys(languages []foundationjudge.JudgeLanguage) []string {
+	var keys []string
+	exists := make(map[string]bool, len(languages))
+	for _, language := range languages {
+		key := foundationjudge.GetLanguageKey(language)
+		if key == "" || exists[key] {
+			continue
+		}
+		exists[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
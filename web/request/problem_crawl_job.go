@@ -0,0 +1,50 @@
+package request
+
+import (
+	foundationerrorcode "foundation/error-code"
+	metaerrorcode "meta/error-code"
+	"strings"
+)
+
+// problemCrawlOriginIdMaxLength 与problem_crawl_job_item.origin_id的长度一致
+const problemCrawlOriginIdMaxLength = 20
+
+type ProblemCrawlJob struct {
+	OJ    string   `json:"oj" binding:"required"`
+	Start int      `json:"start"` // 按Id区间爬取时的起始Id（包含）
+	End   int      `json:"end"`   // 按Id区间爬取时的结束Id（包含）
+	Ids   []string `json:"ids"`   // 按列表爬取时的题目Id
+}
+
+// isValidProblemCrawlOriginId 远程题目Id只允许字母、数字、下划线与短横线
+func isValidProblemCrawlOriginId(id string) bool {
+	if id == "" || len(id) > problemCrawlOriginIdMaxLength {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckRequest 去掉空白的Id，任意一个Id不合法时整个请求无效
+func (r *ProblemCrawlJob) CheckRequest() (bool, int) {
+	if r.Start < 0 || r.End < 0 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	ids := make([]string, 0, len(r.Ids))
+	for _, id := range r.Ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !isValidProblemCrawlOriginId(id) {
+			return false, int(foundationerrorcode.ParamError)
+		}
+		ids = append(ids, id)
+	}
+	r.Ids = ids
+	return true, int(metaerrorcode.Success)
+}
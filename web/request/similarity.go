@@ -0,0 +1,30 @@
+package request
+
+import (
+	foundationerrorcode "foundation/error-code"
+	foundationenum "foundation/foundation-enum"
+	metaerrorcode "meta/error-code"
+)
+
+type SimilarityReport struct {
+	ScopeType foundationenum.SimilarityScopeType `json:"scope_type"`
+	ScopeId   int                                `json:"scope_id" validate:"required"`
+	Threshold int                                `json:"threshold"` // 百分比，为0时使用默认值
+}
+
+func (r *SimilarityReport) CheckRequest() (bool, int) {
+	if r.ScopeId <= 0 || r.Threshold < 0 || r.Threshold > 100 {
+		return false, int(foundationerrorcode.ParamError)
+	}
+	switch r.ScopeType {
+	case foundationenum.SimilarityScopeTypeProblem,
+		foundationenum.SimilarityScopeTypeCollection,
+		foundationenum.SimilarityScopeTypeContest:
+	default:
+		return false, int(foundationerrorcode.ParamError)
+	}
+	if r.Threshold == 0 {
+		r.Threshold = 60
+	}
+	return true, int(metaerrorcode.Success)
+}
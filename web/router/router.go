@@ -61,5 +61,17 @@ func RegisterRoutes(r *gin.Engine) {
 	metahttp.AutoRegisterRoute(r, "/system", new(controller.SystemController), metahttp.AuthMiddlewareTypeOptional)
 	metahttp.AutoRegisterRoute(r, "/run", new(controller.RunController), metahttp.AuthMiddlewareTypeRequire)
 	metahttp.AutoRegisterRoute(r, "/bot", new(controller.BotController), metahttp.AuthMiddlewareTypeOptional)
+	metahttp.AutoRegisterRoute(r, "/similarity", new(controller.SimilarityController), metahttp.AuthMiddlewareTypeOptional)
+
+	// CLICS Contest API 的路径带有比赛Id参数，单独注册
+	ccsController := new(controller.CcsController)
+	ccsGroup := r.Group("/ccs/contests/:id", metahttp.AuthMiddlewareOptional)
+	ccsGroup.GET("", ccsController.GetContest)
+	ccsGroup.GET("/judgement-types", ccsController.GetJudgementTypes)
+	ccsGroup.GET("/problems", ccsController.GetProblems)
+	ccsGroup.GET("/teams", ccsController.GetTeams)
+	ccsGroup.GET("/submissions", ccsController.GetSubmissions)
+	ccsGroup.GET("/judgements", ccsController.GetJudgements)
+	ccsGroup.GET("/event-feed", ccsController.GetEventFeed)
 
 }
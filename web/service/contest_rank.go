@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	foundationservice "foundation/foundation-service"
+	"meta/cron"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	"meta/singleton"
+	"time"
+)
+
+// ContestRankService 定时拉取榜单变更，增量更新本进程的榜单缓存
+type ContestRankService struct {
+}
+
+var singletonContestRankService = singleton.Singleton[ContestRankService]{}
+
+func GetContestRankService() *ContestRankService {
+	return singletonContestRankService.GetInstance(
+		func() *ContestRankService {
+			return &ContestRankService{}
+		},
+	)
+}
+
+func (s *ContestRankService) Start() error {
+	c := cron.NewWithSeconds()
+	_, err := c.AddFunc(
+		"* * * * * ?", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			err := foundationservice.GetContestRankCacheService().ProcessChanges(ctx)
+			if err != nil {
+				metapanic.ProcessError(err)
+			}
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "error adding function to cron")
+	}
+
+	c.Start()
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	foundationservice "foundation/foundation-service"
+	"meta/cron"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	metatime "meta/meta-time"
+	"meta/singleton"
+	"sync"
+	"time"
+)
+
+// ContestRatingService 定时检查结束或重判的计分比赛，重新计算Rating
+type ContestRatingService struct {
+	mutex sync.Mutex
+}
+
+var singletonContestRatingService = singleton.Singleton[ContestRatingService]{}
+
+func GetContestRatingService() *ContestRatingService {
+	return singletonContestRatingService.GetInstance(
+		func() *ContestRatingService {
+			return &ContestRatingService{}
+		},
+	)
+}
+
+func (s *ContestRatingService) Start() error {
+	c := cron.NewWithSeconds()
+	_, err := c.AddFunc(
+		"0 * * * * ?", func() {
+			// 比赛较多时可能超过一分钟，上一次未完成时跳过
+			if !s.mutex.TryLock() {
+				return
+			}
+			defer s.mutex.Unlock()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			err := foundationservice.GetContestService().ProcessContestRatings(ctx, metatime.GetTimeNow())
+			if err != nil {
+				metapanic.ProcessError(err)
+			}
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "error adding function to cron")
+	}
+
+	c.Start()
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationenum "foundation/foundation-enum"
+	foundationremote "foundation/foundation-remote"
+	"log/slog"
+	"meta/cron"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	"meta/singleton"
+	"sync"
+	"time"
+	"web/config"
+)
+
+const (
+	// 爬取单道题目的超时时间
+	problemCrawlTimeout = 2 * time.Minute
+	// 领取任务与标记结果的超时时间，与爬取分开计算，爬取超时后仍能标记结果
+	problemCrawlStatusTimeout = 10 * time.Second
+)
+
+// ProblemCrawlService 后台按固定频率处理批量爬取任务，避免对远程OJ造成压力
+type ProblemCrawlService struct {
+	requestMutex sync.Mutex
+
+	lastCrawlTime time.Time
+}
+
+var singletonProblemCrawlService = singleton.Singleton[ProblemCrawlService]{}
+
+func GetProblemCrawlService() *ProblemCrawlService {
+	return singletonProblemCrawlService.GetInstance(
+		func() *ProblemCrawlService {
+			return &ProblemCrawlService{}
+		},
+	)
+}
+
+func (s *ProblemCrawlService) Start() error {
+	// 上次退出时正在爬取的题目重新放回队列
+	err := foundationdao.GetProblemCrawlJobDao().ResetProblemCrawlJobItemRunning(context.Background())
+	if err != nil {
+		return err
+	}
+
+	c := cron.NewWithSeconds()
+	_, err = c.AddFunc(
+		"* * * * * ?", func() {
+			// 每秒检查一次是否可以爬取下一道题目
+			err := s.handleStart()
+			if err != nil {
+				metapanic.ProcessError(err)
+			}
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "error adding function to cron")
+	}
+
+	c.Start()
+
+	return nil
+}
+
+func (s *ProblemCrawlService) getCrawlInterval() time.Duration {
+	interval := config.GetConfig().CrawlInterval
+	if interval <= 0 {
+		interval = 3
+	}
+	return time.Duration(interval) * time.Second
+}
+
+func (s *ProblemCrawlService) handleStart() error {
+	// 保证同时只有一个爬取在进行
+	if !s.requestMutex.TryLock() {
+		return nil
+	}
+	defer s.requestMutex.Unlock()
+
+	if time.Since(s.lastCrawlTime) < s.getCrawlInterval() {
+		return nil
+	}
+
+	requestCtx, requestCancel := context.WithTimeout(context.Background(), problemCrawlStatusTimeout)
+	item, err := foundationdao.GetProblemCrawlJobDao().RequestProblemCrawlJobItemPending(requestCtx)
+	requestCancel()
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return nil
+	}
+
+	crawlCtx, crawlCancel := context.WithTimeout(context.Background(), problemCrawlTimeout)
+	status, problemKey, message := s.crawlProblem(crawlCtx, item.OriginOj, item.OriginId)
+	crawlCancel()
+
+	slog.Info(
+		"problem crawl item finish",
+		"job", item.Id,
+		"oj", item.OriginOj,
+		"originId", item.OriginId,
+		"status", status,
+	)
+
+	// 爬取超时时上下文已失效，使用新的上下文标记结果，否则题目会一直处于爬取中
+	ctx, cancel := context.WithTimeout(context.Background(), problemCrawlStatusTimeout)
+	defer cancel()
+	return foundationdao.GetProblemCrawlJobDao().MarkProblemCrawlJobItemStatus(
+		ctx,
+		item.Id,
+		item.OriginId,
+		status,
+		problemKey,
+		message,
+	)
+}
+
+func (s *ProblemCrawlService) crawlProblem(ctx context.Context, originOj string, originId string) (
+	foundationenum.ProblemCrawlStatus,
+	*string,
+	*string,
+) {
+	// 任务创建后可能已经被单独爬取过了，这里再检查一次
+	existIds, err := foundationdao.GetProblemDao().GetProblemRemoteOriginIds(ctx, originOj, []string{originId})
+	if err != nil {
+		message := err.Error()
+		return foundationenum.ProblemCrawlStatusFail, nil, &message
+	}
+	if len(existIds) > 0 {
+		return foundationenum.ProblemCrawlStatusSkip, nil, nil
+	}
+
+	agent := foundationremote.GetRemoteAgent(foundationremote.GetRemoteTypeByString(originOj))
+	if agent == nil {
+		message := "remote oj not support crawl"
+		return foundationenum.ProblemCrawlStatusFail, nil, &message
+	}
+
+	// 只有真正请求了远程OJ才计入限速
+	s.lastCrawlTime = time.Now()
+
+	problemKey, err := agent.PostCrawlProblem(ctx, originId)
+	if err != nil {
+		message := err.Error()
+		return foundationenum.ProblemCrawlStatusFail, nil, &message
+	}
+	if problemKey == nil {
+		message := "problem not found"
+		return foundationenum.ProblemCrawlStatusFail, nil, &message
+	}
+	return foundationenum.ProblemCrawlStatusSuccess, problemKey, nil
+}
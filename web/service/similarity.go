@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	foundationdao "foundation/foundation-dao"
+	foundationservice "foundation/foundation-service"
+	"meta/cron"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	"meta/singleton"
+	"sync"
+	"time"
+)
+
+// SimilarityService 后台依次执行代码相似度检测，同一时间只检测一个报告
+type SimilarityService struct {
+	mutex sync.Mutex
+}
+
+var singletonSimilarityService = singleton.Singleton[SimilarityService]{}
+
+func GetSimilarityService() *SimilarityService {
+	return singletonSimilarityService.GetInstance(
+		func() *SimilarityService {
+			return &SimilarityService{}
+		},
+	)
+}
+
+func (s *SimilarityService) Start() error {
+	// 上次退出时正在检测的报告重新放回队列
+	err := foundationdao.GetSimilarityDao().ResetSimilarityReportRunning(context.Background())
+	if err != nil {
+		return err
+	}
+
+	c := cron.NewWithSeconds()
+	_, err = c.AddFunc(
+		"*/5 * * * * ?", func() {
+			if !s.mutex.TryLock() {
+				return
+			}
+			defer s.mutex.Unlock()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+			_, err := foundationservice.GetSimilarityService().ProcessSimilarityReport(ctx)
+			if err != nil {
+				metapanic.ProcessError(err)
+			}
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "error adding function to cron")
+	}
+
+	c.Start()
+
+	return nil
+}
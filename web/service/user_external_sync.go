@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	foundationenum "foundation/foundation-enum"
+	foundationservice "foundation/foundation-service"
+	"log/slog"
+	"meta/cron"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	metatime "meta/meta-time"
+	"meta/singleton"
+	"sync"
+	"time"
+)
+
+const (
+	// 同一个外部账号的同步间隔
+	userExternalSyncInterval = 24 * time.Hour
+	// 每轮每个来源最多同步的账号数
+	userExternalSyncBatch = 5
+)
+
+// UserExternalSyncService 定时同步用户绑定的外部账号的AC记录
+type UserExternalSyncService struct {
+	requestMutex sync.Mutex
+}
+
+var singletonUserExternalSyncService = singleton.Singleton[UserExternalSyncService]{}
+
+func GetUserExternalSyncService() *UserExternalSyncService {
+	return singletonUserExternalSyncService.GetInstance(
+		func() *UserExternalSyncService {
+			return &UserExternalSyncService{}
+		},
+	)
+}
+
+func (s *UserExternalSyncService) Start() error {
+	c := cron.NewWithSeconds()
+	_, err := c.AddFunc(
+		"0 * * * * ?", func() {
+			// 每分钟同步一批账号
+			err := s.handleStart()
+			if err != nil {
+				metapanic.ProcessError(err)
+			}
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "error adding function to cron")
+	}
+
+	c.Start()
+
+	return nil
+}
+
+func (s *UserExternalSyncService) handleStart() error {
+	if !s.requestMutex.TryLock() {
+		return nil
+	}
+	defer s.requestMutex.Unlock()
+
+	sources := []foundationenum.UserExternalSource{
+		foundationenum.UserExternalSourceCodeforces,
+		foundationenum.UserExternalSourceVjudge,
+		foundationenum.UserExternalSourceHdu,
+	}
+	before := metatime.GetTimeNow().Add(-userExternalSyncInterval)
+	for _, source := range sources {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		pendingList, err := foundationservice.GetUserExternalSolveService().GetUserExternalSyncPending(
+			ctx,
+			source,
+			before,
+			userExternalSyncBatch,
+		)
+		if err != nil {
+			cancel()
+			return err
+		}
+		for _, pending := range pendingList {
+			slog.Info("user external sync", "user", pending.UserId, "source", pending.Source)
+			err := foundationservice.GetUserExternalSolveService().SyncUserExternal(ctx, pending)
+			if err != nil {
+				metapanic.ProcessError(err)
+			}
+		}
+		cancel()
+	}
+	return nil
+}